@@ -0,0 +1,84 @@
+// Команда audit-verify проверяет цепочку хешей журнала аудита.
+// Завершается с кодом 1, если цепочка нарушена.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"adminkaback/internal/repository"
+	"adminkaback/internal/usecase"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/config"
+	"adminkaback/pkg/fieldcrypt"
+	"adminkaback/pkg/jwt"
+	"adminkaback/pkg/mailer"
+	"adminkaback/pkg/signedtoken"
+	"adminkaback/pkg/webhook"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, cfg.Database.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	cipher, err := fieldcrypt.New(cfg.PII.MasterKeys(), cfg.PII.IndexKey)
+	if err != nil {
+		log.Fatalf("Failed to load PII keys: %v", err)
+	}
+
+	repo := repository.NewRepository(pool, cipher)
+	jwtMgr := jwt.NewManager(cfg.JWT.Secret, cfg.JWT.AccessTTL, cfg.JWT.RefreshTTL)
+	signer := signedtoken.NewSigner(cfg.Users.VerificationSecret)
+	mail := mailer.NewMailer(mailer.Config{
+		Host:     cfg.Mail.Host,
+		Port:     cfg.Mail.Port,
+		Username: cfg.Mail.Username,
+		Password: cfg.Mail.Password,
+		From:     cfg.Mail.From,
+	})
+	hooks := webhook.NewClient(cfg.Users.StatusMachine.WebhookSecret, 5*time.Second)
+	uc := usecase.NewUseCase(repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, mail, hooks, jwtMgr, signer, cfg)
+
+	if code := verify(ctx, uc, log.Default()); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// auditVerifier проверяет цепочку хешей журнала аудита.
+type auditVerifier interface {
+	VerifyAuditLog(ctx context.Context) (*usecasemodels.VerifyAuditLogResponse, error)
+}
+
+// verify проверяет журнал аудита и пишет результат в logger. Возвращает код завершения команды.
+func verify(ctx context.Context, verifier auditVerifier, logger *log.Logger) int {
+	result, err := verifier.VerifyAuditLog(ctx)
+	if err != nil {
+		logger.Printf("Failed to verify audit log: %v", err)
+
+		return 1
+	}
+
+	if !result.Valid {
+		logger.Printf("Audit log is broken at seq %d: %s (%d entries verified before it)", *result.BrokenSeq, result.Reason, result.Checked)
+
+		return 1
+	}
+
+	logger.Printf("Audit log is intact: %d entries verified", result.Checked)
+
+	return 0
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+// testVerifier возвращает заданный результат проверки журнала.
+type testVerifier struct {
+	result *usecasemodels.VerifyAuditLogResponse
+	err    error
+}
+
+func (v testVerifier) VerifyAuditLog(context.Context) (*usecasemodels.VerifyAuditLogResponse, error) {
+	return v.result, v.err
+}
+
+func TestVerify(t *testing.T) {
+	brokenSeq := int64(42)
+
+	tests := []struct {
+		name     string
+		verifier testVerifier
+		wantCode int
+		wantLog  string
+	}{
+		{
+			name:     "intact",
+			verifier: testVerifier{result: &usecasemodels.VerifyAuditLogResponse{Valid: true, Checked: 10}},
+			wantCode: 0,
+			wantLog:  "Audit log is intact: 10 entries verified",
+		},
+		{
+			name: "broken",
+			verifier: testVerifier{result: &usecasemodels.VerifyAuditLogResponse{
+				Checked: 41, BrokenSeq: &brokenSeq, Reason: "hash does not match entry contents",
+			}},
+			wantCode: 1,
+			wantLog:  "Audit log is broken at seq 42: hash does not match entry contents (41 entries verified before it)",
+		},
+		{
+			name:     "error",
+			verifier: testVerifier{err: errors.New("connection refused")},
+			wantCode: 1,
+			wantLog:  "Failed to verify audit log: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			if code := verify(context.Background(), tt.verifier, log.New(&buf, "", 0)); code != tt.wantCode {
+				t.Fatalf("verify() = %d, want %d", code, tt.wantCode)
+			}
+
+			if got := strings.TrimSpace(buf.String()); got != tt.wantLog {
+				t.Fatalf("verify() log = %q, want %q", got, tt.wantLog)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Команда pii-rotate перешифровывает email и телефон пользователей и их копии в истории изменений
+// и запросах на смену email текущим мастер-ключом (PII_MASTER_KEY) и пересчитывает слепые индексы.
+// Значения, зашифрованные прежними ключами из PII_PREVIOUS_MASTER_KEYS, и открытые значения
+// шифруются заново. С флагом -decrypt
+// значения расшифровываются и записываются открыто, например перед отключением шифрования.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"adminkaback/internal/repository"
+	"adminkaback/internal/usecase"
+	"adminkaback/pkg/config"
+	"adminkaback/pkg/fieldcrypt"
+	"adminkaback/pkg/jwt"
+	"adminkaback/pkg/mailer"
+	"adminkaback/pkg/signedtoken"
+	"adminkaback/pkg/webhook"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func main() {
+	batchSize := flag.Int("batch", 500, "number of users re-encrypted in one transaction")
+	decrypt := flag.Bool("decrypt", false, "store email and phone in plaintext")
+	flag.Parse()
+
+	if *batchSize <= 0 {
+		log.Fatalf("-batch must be positive")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, cfg.Database.DSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	cipher, err := fieldcrypt.New(cfg.PII.MasterKeys(), cfg.PII.IndexKey)
+	if err != nil {
+		log.Fatalf("Failed to load PII keys: %v", err)
+	}
+
+	if !*decrypt && !cipher.CanEncrypt() {
+		log.Fatalf("PII_MASTER_KEY is required to encrypt users")
+	}
+
+	repo := repository.NewRepository(pool, cipher)
+	jwtMgr := jwt.NewManager(cfg.JWT.Secret, cfg.JWT.AccessTTL, cfg.JWT.RefreshTTL)
+	signer := signedtoken.NewSigner(cfg.Users.VerificationSecret)
+	mail := mailer.NewMailer(mailer.Config{
+		Host:     cfg.Mail.Host,
+		Port:     cfg.Mail.Port,
+		Username: cfg.Mail.Username,
+		Password: cfg.Mail.Password,
+		From:     cfg.Mail.From,
+	})
+	hooks := webhook.NewClient(cfg.Users.StatusMachine.WebhookSecret, 5*time.Second)
+	uc := usecase.NewUseCase(repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, mail, hooks, jwtMgr, signer, cfg)
+
+	result, err := uc.ReencryptUsers(ctx, *batchSize, *decrypt)
+	if err != nil {
+		log.Fatalf("Failed to re-encrypt users (%d checked, %d updated): %v", result.Checked, result.Updated, err)
+	}
+
+	if *decrypt {
+		log.Printf("Users decrypted: %d checked, %d updated, %d history versions, %d email changes",
+			result.Checked, result.Updated, result.HistoryVersions, result.EmailChanges)
+
+		return
+	}
+
+	log.Printf("Users re-encrypted with key %s: %d checked, %d updated, %d history versions, %d email changes, %d import jobs masked",
+		cipher.CurrentKeyID(), result.Checked, result.Updated, result.HistoryVersions, result.EmailChanges, result.ImportJobs)
+}
@@ -13,7 +13,12 @@ import (
 	"adminkaback/internal/service"
 	"adminkaback/internal/usecase"
 	"adminkaback/pkg/config"
+	"adminkaback/pkg/fieldcrypt"
 	"adminkaback/pkg/jwt"
+	"adminkaback/pkg/mailer"
+	"adminkaback/pkg/scheduler"
+	"adminkaback/pkg/signedtoken"
+	"adminkaback/pkg/webhook"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -36,11 +41,35 @@ func main() {
 		log.Fatalf("Failed to ping database: %v", err)
 	}
 
-	repo := repository.NewRepository(pool)
+	cipher, err := fieldcrypt.New(cfg.PII.MasterKeys(), cfg.PII.IndexKey)
+	if err != nil {
+		log.Fatalf("Failed to load PII keys: %v", err)
+	}
+
+	repo := repository.NewRepository(pool, cipher)
 	jwtMgr := jwt.NewManager(cfg.JWT.Secret, cfg.JWT.AccessTTL, cfg.JWT.RefreshTTL)
-	uc := usecase.NewUseCase(repo, repo, jwtMgr, cfg)
+	signer := signedtoken.NewSigner(cfg.Users.VerificationSecret)
+	mail := mailer.NewMailer(mailer.Config{
+		Host:     cfg.Mail.Host,
+		Port:     cfg.Mail.Port,
+		Username: cfg.Mail.Username,
+		Password: cfg.Mail.Password,
+		From:     cfg.Mail.From,
+	})
+	hooks := webhook.NewClient(cfg.Users.StatusMachine.WebhookSecret, 5*time.Second)
+	uc := usecase.NewUseCase(repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, repo, mail, hooks, jwtMgr, signer, cfg)
 	svc := service.NewService(uc, cfg)
 
+	if err := uc.FailInterruptedUserImports(ctx); err != nil {
+		log.Fatalf("Failed to mark interrupted user imports: %v", err)
+	}
+
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+
+	go scheduler.Run(schedulerCtx, "lift expired bans", cfg.Users.BanExpiryInterval, uc.LiftExpiredBans)
+	go scheduler.Run(schedulerCtx, "refresh dashboard stats", cfg.Analytics.StatsRefreshInterval, uc.RefreshDashboardStats)
+
 	srv := &http.Server{
 		Addr:    cfg.Server.Host + ":" + cfg.Server.HTTPPort,
 		Handler: svc.Handler(),
@@ -60,6 +89,8 @@ func main() {
 
 	log.Println("Shutting down server...")
 
+	stopScheduler()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
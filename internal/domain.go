@@ -2,20 +2,30 @@ package internal
 
 import (
 	"context"
+	"io"
+	"time"
 
 	repositorymodels "adminkaback/internal/repository/models"
 	usecasemodels "adminkaback/internal/usecase/models"
 )
 
+// TxManager определяет интерфейс для выполнения операций в транзакции.
+type TxManager interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 // AuthRepository определяет интерфейс для работы с аутентификацией в БД.
 type AuthRepository interface {
 	CreateAdmin(ctx context.Context, admin *repositorymodels.Admin) error
 	GetAdminByEmail(ctx context.Context, email string) (*repositorymodels.Admin, error)
 	GetAdminByID(ctx context.Context, id string) (*repositorymodels.Admin, error)
+	GetAdminsByIDs(ctx context.Context, ids []string) ([]repositorymodels.Admin, error)
 	CreateRefreshToken(ctx context.Context, token *repositorymodels.RefreshToken) error
 	GetRefreshToken(ctx context.Context, token string) (*repositorymodels.RefreshToken, error)
 	DeleteRefreshToken(ctx context.Context, token string) error
 	DeleteRefreshTokensByAdminID(ctx context.Context, adminID string) error
+	GetAdminPreferences(ctx context.Context, adminID string) (*repositorymodels.AdminPreferences, error)
+	SaveAdminPreferences(ctx context.Context, preferences *repositorymodels.AdminPreferences, expectedVersion int) error
 }
 
 // AuthUseCase определяет интерфейс для бизнес-логики аутентификации.
@@ -25,16 +35,161 @@ type AuthUseCase interface {
 	RefreshToken(ctx context.Context, refreshToken string) (*usecasemodels.RefreshTokenResponse, error)
 	Logout(ctx context.Context, refreshToken string) error
 	GetCurrentAdmin(ctx context.Context, adminID string) (*usecasemodels.AdminResponse, error)
+	GetAdminPreferences(ctx context.Context, adminID string) (*usecasemodels.AdminPreferencesResponse, error)
+	UpdateAdminPreferences(ctx context.Context, adminID string, req *usecasemodels.UpdateAdminPreferencesRequest) (*usecasemodels.AdminPreferencesResponse, error)
 }
 
 // UserRepository определяет интерфейс для работы с пользователями в БД.
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *repositorymodels.User) error
 	GetUserByID(ctx context.Context, id string) (*repositorymodels.User, error)
+	GetUserByIDForUpdate(ctx context.Context, id string) (*repositorymodels.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*repositorymodels.User, error)
 	GetUsers(ctx context.Context, req *usecasemodels.GetUsersRequest) ([]repositorymodels.User, int, error)
+	GetUserIDsByFilter(ctx context.Context, filter *usecasemodels.UserFilter, limit int) ([]string, error)
+	CountUsers(ctx context.Context, filter *usecasemodels.UserFilter) (int, error)
+	StreamUsers(ctx context.Context, req *usecasemodels.GetUsersRequest, batchSize int, fn func(user *repositorymodels.User) error) error
 	UpdateUser(ctx context.Context, id string, user *repositorymodels.User) error
+	RevokeUserSessions(ctx context.Context, id string) error
 	DeleteUser(ctx context.Context, id string) error
+	GetUsersByIDs(ctx context.Context, ids []string) ([]repositorymodels.User, error)
+	FindUserDuplicates(ctx context.Context, req *usecasemodels.FindUserDuplicatesRequest) ([]repositorymodels.UserDuplicate, int, error)
+	MarkUserMerged(ctx context.Context, loserID, survivorID string) error
+	MoveUserRelations(ctx context.Context, fromID, toID string) error
+	GetUserByIDWithDeleted(ctx context.Context, id string) (*repositorymodels.User, error)
+	GetMergedUsers(ctx context.Context, id string) ([]repositorymodels.User, error)
+	EraseUsers(ctx context.Context, ids []string, erasedAt time.Time) (*repositorymodels.UserErasure, error)
+	ReencryptUsers(ctx context.Context, afterID string, limit int, decrypt bool) (*repositorymodels.UserReencryption, error)
+}
+
+// UserImportRepository определяет интерфейс для работы с задачами импорта пользователей в БД.
+type UserImportRepository interface {
+	CreateUserImportJob(ctx context.Context, job *repositorymodels.UserImportJob) error
+	UpdateUserImportJob(ctx context.Context, job *repositorymodels.UserImportJob) error
+	GetUserImportJob(ctx context.Context, id string) (*repositorymodels.UserImportJob, error)
+	FailUnfinishedUserImportJobs(ctx context.Context, message string) (int64, error)
+	MaskUserImportJobs(ctx context.Context) (int, error)
+}
+
+// UserHistoryRepository определяет интерфейс для работы с историей изменений пользователей в БД.
+type UserHistoryRepository interface {
+	CreateUserHistory(ctx context.Context, history *repositorymodels.UserHistory) error
+	GetUserHistory(ctx context.Context, userID string, page, limit int) ([]repositorymodels.UserHistory, int, error)
+	GetUserHistoryVersion(ctx context.Context, userID string, version int) (*repositorymodels.UserHistory, error)
+}
+
+// AuditRepository определяет интерфейс для работы с журналом аудита в БД.
+type AuditRepository interface {
+	LockAuditLog(ctx context.Context) error
+	GetLastAuditHash(ctx context.Context) (string, error)
+	CreateAuditEntry(ctx context.Context, entry *repositorymodels.AuditEntry) error
+	GetAuditEntries(ctx context.Context, req *usecasemodels.GetAuditLogRequest) ([]repositorymodels.AuditEntry, int, error)
+	GetAuditEntriesAfter(ctx context.Context, afterSeq int64, limit int) ([]repositorymodels.AuditEntry, error)
+	GetAuditEntriesByResourceIDs(ctx context.Context, ids []string) ([]repositorymodels.AuditEntry, error)
+}
+
+// EmailVerificationRepository определяет интерфейс для работы с подтверждением email в БД.
+type EmailVerificationRepository interface {
+	CreateEmailVerification(ctx context.Context, verification *repositorymodels.EmailVerification) error
+	GetEmailVerificationStats(ctx context.Context, userID string, since time.Time) (int, *time.Time, error)
+	SetUserEmailVerified(ctx context.Context, id, email string) (bool, error)
+	CreateUserEmailChange(ctx context.Context, change *repositorymodels.UserEmailChange) error
+	GetUserEmailChange(ctx context.Context, id string) (*repositorymodels.UserEmailChange, error)
+	ConfirmUserEmailChange(ctx context.Context, id string) error
+}
+
+// UserBanRepository определяет интерфейс для работы с блокировками пользователей в БД.
+type UserBanRepository interface {
+	CreateUserBan(ctx context.Context, ban *repositorymodels.UserBan) error
+	GetActiveUserBan(ctx context.Context, userID string) (*repositorymodels.UserBan, error)
+	LiftUserBan(ctx context.Context, ban *repositorymodels.UserBan) error
+	GetUserBans(ctx context.Context, userID string) ([]repositorymodels.UserBan, error)
+	GetExpiredUserBans(ctx context.Context, now time.Time, limit int) ([]repositorymodels.UserBan, error)
+}
+
+// UserAttributeRepository определяет интерфейс для работы с описаниями пользовательских атрибутов в БД.
+type UserAttributeRepository interface {
+	CreateUserAttributeDefinition(ctx context.Context, definition *repositorymodels.UserAttributeDefinition) error
+	GetUserAttributeDefinition(ctx context.Context, key string) (*repositorymodels.UserAttributeDefinition, error)
+	GetUserAttributeDefinitions(ctx context.Context) ([]repositorymodels.UserAttributeDefinition, error)
+	UpdateUserAttributeDefinition(ctx context.Context, definition *repositorymodels.UserAttributeDefinition) error
+	DeleteUserAttributeDefinition(ctx context.Context, key string) error
+	SetMissingUserAttribute(ctx context.Context, key string, value any) (int64, error)
+	RemoveUserAttribute(ctx context.Context, key string) (int64, error)
+}
+
+// TagRepository определяет интерфейс для работы с метками пользователей в БД.
+type TagRepository interface {
+	CreateTag(ctx context.Context, tag *repositorymodels.Tag) error
+	GetTagByID(ctx context.Context, id string) (*repositorymodels.Tag, error)
+	GetTags(ctx context.Context) ([]repositorymodels.Tag, error)
+	GetTagsByIDs(ctx context.Context, ids []string) ([]repositorymodels.Tag, error)
+	UpdateTag(ctx context.Context, tag *repositorymodels.Tag) error
+	DeleteTag(ctx context.Context, id string) error
+	AddUserTags(ctx context.Context, userID string, tagIDs []string, adminID *string) ([]string, error)
+	RemoveUserTags(ctx context.Context, userID string, tagIDs []string) ([]string, error)
+	GetUsersTags(ctx context.Context, userIDs []string) (map[string][]repositorymodels.Tag, error)
+}
+
+// UserNoteRepository определяет интерфейс для работы с заметками о пользователях в БД.
+type UserNoteRepository interface {
+	CreateUserNote(ctx context.Context, note *repositorymodels.UserNote) error
+	GetUserNote(ctx context.Context, userID, id string) (*repositorymodels.UserNote, error)
+	GetUserNotes(ctx context.Context, userID string) ([]repositorymodels.UserNote, error)
+	UpdateUserNote(ctx context.Context, note *repositorymodels.UserNote) error
+	CreateUserNoteRevision(ctx context.Context, revision *repositorymodels.UserNoteRevision) error
+	GetUserNoteRevisions(ctx context.Context, noteID string) ([]repositorymodels.UserNoteRevision, error)
+}
+
+// BloggerRepository определяет интерфейс для работы с блогерами в БД.
+type BloggerRepository interface {
+	CreateBlogger(ctx context.Context, blogger *repositorymodels.Blogger) error
+	GetBloggerByID(ctx context.Context, id string) (*repositorymodels.Blogger, error)
+	GetBloggers(ctx context.Context, req *usecasemodels.GetBloggersRequest) ([]repositorymodels.Blogger, int, error)
+	UpdateBlogger(ctx context.Context, blogger *repositorymodels.Blogger) error
+	DeleteBlogger(ctx context.Context, id string) error
+	LockBloggerImport(ctx context.Context) error
+	GetBloggersByUsernames(ctx context.Context, usernames []string) ([]repositorymodels.Blogger, error)
+	CreateBloggerContribution(ctx context.Context, contribution *repositorymodels.BloggerContribution) error
+	GetBloggerContributions(ctx context.Context, bloggerID string) ([]repositorymodels.BloggerContribution, error)
+}
+
+// CampaignRepository определяет интерфейс для работы с кампаниями и участием блогеров в БД.
+type CampaignRepository interface {
+	CreateCampaign(ctx context.Context, campaign *repositorymodels.Campaign) error
+	GetCampaignByID(ctx context.Context, id string) (*repositorymodels.Campaign, error)
+	GetCampaigns(ctx context.Context, req *usecasemodels.GetCampaignsRequest) ([]repositorymodels.Campaign, int, error)
+	UpdateCampaign(ctx context.Context, campaign *repositorymodels.Campaign) error
+	DeleteCampaign(ctx context.Context, id string) error
+	CreateCampaignBlogger(ctx context.Context, assignment *repositorymodels.CampaignBlogger) error
+	GetCampaignBlogger(ctx context.Context, campaignID, bloggerID string) (*repositorymodels.CampaignBlogger, error)
+	UpdateCampaignBlogger(ctx context.Context, assignment *repositorymodels.CampaignBlogger) error
+	DeleteCampaignBlogger(ctx context.Context, campaignID, bloggerID string) error
+	GetCampaignBloggers(ctx context.Context, campaignID string) ([]repositorymodels.CampaignBlogger, error)
+	GetBloggerCampaigns(ctx context.Context, bloggerID string) ([]repositorymodels.CampaignBlogger, error)
+}
+
+// DashboardRepository определяет интерфейс для работы с агрегатами статистики в БД.
+type DashboardRepository interface {
+	RefreshUserStats(ctx context.Context) error
+	GetUserStats(ctx context.Context) ([]repositorymodels.UserStats, error)
+}
+
+// AnalyticsRepository определяет интерфейс для аналитических выборок по пользователям в БД.
+type AnalyticsRepository interface {
+	GetUserTimeseries(ctx context.Context, q *usecasemodels.UserTimeseriesQuery) ([]repositorymodels.UserTimeseriesPoint, error)
+	GetUserCohortSizes(ctx context.Context, q *usecasemodels.UserCohortQuery) ([]repositorymodels.UserCohortCount, error)
+	GetUserCohortRetention(ctx context.Context, q *usecasemodels.UserCohortQuery) ([]repositorymodels.UserCohortCount, error)
+}
+
+// Mailer определяет интерфейс для отправки писем.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// WebhookSender определяет интерфейс для отправки событий во внешние системы.
+type WebhookSender interface {
+	Post(ctx context.Context, url string, payload any) error
 }
 
 // UserUseCase определяет интерфейс для бизнес-логики пользователей.
@@ -44,4 +199,83 @@ type UserUseCase interface {
 	CreateUser(ctx context.Context, req *usecasemodels.CreateUserRequest) (*usecasemodels.UserResponse, error)
 	UpdateUser(ctx context.Context, id string, req *usecasemodels.UpdateUserRequest) (*usecasemodels.UserResponse, error)
 	DeleteUser(ctx context.Context, id string) error
+	BulkUsers(ctx context.Context, req *usecasemodels.BulkUsersRequest) (*usecasemodels.BulkUsersResponse, error)
+	ImportUsers(ctx context.Context, req *usecasemodels.ImportUsersRequest) (*usecasemodels.UserImportJobResponse, error)
+	GetUserImportJob(ctx context.Context, id string) (*usecasemodels.UserImportJobResponse, error)
+	ExportUsers(ctx context.Context, req *usecasemodels.ExportUsersRequest, w io.Writer) error
+	GetUserHistory(ctx context.Context, id string, page, limit int) (*usecasemodels.GetUserHistoryResponse, error)
+	RevertUser(ctx context.Context, id string, version int, req *usecasemodels.RevertUserRequest) (*usecasemodels.UserResponse, error)
+	SendVerificationEmail(ctx context.Context, id string) (*usecasemodels.SendVerificationEmailResponse, error)
+	VerifyEmail(ctx context.Context, token string) (*usecasemodels.UserResponse, error)
+	ConfirmEmailChange(ctx context.Context, token string) (*usecasemodels.UserResponse, error)
+	BanUser(ctx context.Context, id string, req *usecasemodels.BanUserRequest) (*usecasemodels.UserBanResponse, error)
+	UnbanUser(ctx context.Context, id string, req *usecasemodels.UnbanUserRequest) (*usecasemodels.UserBanResponse, error)
+	GetUserBans(ctx context.Context, id string) ([]usecasemodels.UserBanResponse, error)
+	LiftExpiredBans(ctx context.Context) error
+	GetUserAttributes(ctx context.Context) ([]usecasemodels.UserAttributeResponse, error)
+	CreateUserAttribute(ctx context.Context, req *usecasemodels.UserAttributeRequest) (*usecasemodels.UserAttributeResponse, error)
+	UpdateUserAttribute(ctx context.Context, key string, req *usecasemodels.UserAttributeRequest) (*usecasemodels.UserAttributeResponse, error)
+	DeleteUserAttribute(ctx context.Context, key string) error
+	GetTags(ctx context.Context) ([]usecasemodels.TagResponse, error)
+	CreateTag(ctx context.Context, req *usecasemodels.TagRequest) (*usecasemodels.TagResponse, error)
+	UpdateTag(ctx context.Context, id string, req *usecasemodels.TagRequest) (*usecasemodels.TagResponse, error)
+	DeleteTag(ctx context.Context, id string) error
+	AddUserTags(ctx context.Context, id string, req *usecasemodels.UserTagsRequest) ([]usecasemodels.UserTagResponse, error)
+	RemoveUserTag(ctx context.Context, id, tagID string) ([]usecasemodels.UserTagResponse, error)
+	GetUserNotes(ctx context.Context, userID string) ([]usecasemodels.UserNoteResponse, error)
+	CreateUserNote(ctx context.Context, userID string, req *usecasemodels.CreateUserNoteRequest) (*usecasemodels.UserNoteResponse, error)
+	UpdateUserNote(ctx context.Context, userID, id string, req *usecasemodels.UpdateUserNoteRequest) (*usecasemodels.UserNoteResponse, error)
+	DeleteUserNote(ctx context.Context, userID, id string) error
+	PinUserNote(ctx context.Context, userID, id string, pinned bool) (*usecasemodels.UserNoteResponse, error)
+	GetUserNoteRevisions(ctx context.Context, userID, id string) ([]usecasemodels.UserNoteRevisionResponse, error)
+	FindUserDuplicates(ctx context.Context, req *usecasemodels.FindUserDuplicatesRequest) (*usecasemodels.FindUserDuplicatesResponse, error)
+	MergeUsers(ctx context.Context, req *usecasemodels.MergeUsersRequest) (*usecasemodels.UserResponse, error)
+	ExportUserData(ctx context.Context, id string, w io.Writer) error
+	EraseUser(ctx context.Context, id string, req *usecasemodels.EraseUserRequest) (*usecasemodels.EraseUserResponse, error)
+	ReencryptUsers(ctx context.Context, batchSize int, decrypt bool) (*usecasemodels.ReencryptUsersResponse, error)
+	RevealUserPII(ctx context.Context, id string, req *usecasemodels.RevealUserPIIRequest) (*usecasemodels.RevealUserPIIResponse, error)
+}
+
+// BloggerUseCase определяет интерфейс для бизнес-логики блогеров.
+type BloggerUseCase interface {
+	GetBloggers(ctx context.Context, req *usecasemodels.GetBloggersRequest) (*usecasemodels.GetBloggersResponse, error)
+	GetBlogger(ctx context.Context, id string) (*usecasemodels.BloggerResponse, error)
+	CreateBlogger(ctx context.Context, req *usecasemodels.CreateBloggerRequest) (*usecasemodels.BloggerResponse, error)
+	UpdateBlogger(ctx context.Context, id string, req *usecasemodels.UpdateBloggerRequest) (*usecasemodels.BloggerResponse, error)
+	DeleteBlogger(ctx context.Context, id string) error
+	ImportLegacyBloggers(ctx context.Context, records []usecasemodels.BloggerLegacyRecord) (*usecasemodels.BloggerLegacyImportResponse, error)
+}
+
+// CampaignUseCase определяет интерфейс для бизнес-логики кампаний.
+type CampaignUseCase interface {
+	GetCampaigns(ctx context.Context, req *usecasemodels.GetCampaignsRequest) (*usecasemodels.GetCampaignsResponse, error)
+	GetCampaign(ctx context.Context, id string) (*usecasemodels.CampaignResponse, error)
+	CreateCampaign(ctx context.Context, req *usecasemodels.CreateCampaignRequest) (*usecasemodels.CampaignResponse, error)
+	UpdateCampaign(ctx context.Context, id string, req *usecasemodels.UpdateCampaignRequest) (*usecasemodels.CampaignResponse, error)
+	DeleteCampaign(ctx context.Context, id string) error
+	GetCampaignBloggers(ctx context.Context, id string) (*usecasemodels.CampaignRosterResponse, error)
+	GetBloggerCampaigns(ctx context.Context, bloggerID string) (*usecasemodels.BloggerCampaignsResponse, error)
+	AssignCampaignBlogger(ctx context.Context, campaignID string, req *usecasemodels.AssignCampaignBloggerRequest) (*usecasemodels.CampaignBloggerResponse, error)
+	UpdateCampaignBlogger(ctx context.Context, campaignID, bloggerID string, req *usecasemodels.UpdateCampaignBloggerRequest) (*usecasemodels.CampaignBloggerResponse, error)
+	RemoveCampaignBlogger(ctx context.Context, campaignID, bloggerID string) error
+}
+
+// DashboardUseCase определяет интерфейс для бизнес-логики статистики дашборда.
+type DashboardUseCase interface {
+	GetDashboardStats(ctx context.Context) (*usecasemodels.DashboardStatsResponse, error)
+	RefreshDashboardStats(ctx context.Context) error
+}
+
+// AnalyticsUseCase определяет интерфейс для бизнес-логики аналитики пользователей.
+type AnalyticsUseCase interface {
+	GetUserTimeseries(ctx context.Context, req *usecasemodels.UserTimeseriesRequest) (*usecasemodels.UserTimeseriesResponse, error)
+	ExportUserTimeseries(ctx context.Context, req *usecasemodels.ExportUserTimeseriesRequest, w io.Writer) error
+	GetUserCohorts(ctx context.Context, req *usecasemodels.UserCohortsRequest) (*usecasemodels.UserCohortsResponse, error)
+}
+
+// AuditUseCase определяет интерфейс для бизнес-логики журнала аудита.
+type AuditUseCase interface {
+	RecordAudit(ctx context.Context, entry *usecasemodels.AuditEntry) error
+	GetAuditLog(ctx context.Context, req *usecasemodels.GetAuditLogRequest) (*usecasemodels.GetAuditLogResponse, error)
+	VerifyAuditLog(ctx context.Context) (*usecasemodels.VerifyAuditLogResponse, error)
 }
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"adminkaback/internal/usecase"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditResourceIDKey — ключ gin контекста, через который обработчик может передать
+// ID ресурса, если его нет в пути (например, ID созданного пользователя).
+const AuditResourceIDKey = "audit_resource_id"
+
+// auditRoute описывает действие, записываемое в журнал аудита для маршрута.
+type auditRoute struct {
+	action       string
+	resourceType string
+}
+
+// auditRoutes — маршруты, вызовы которых записываются в журнал аудита.
+// Ключ — метод и шаблон пути gin.
+var auditRoutes = map[string]auditRoute{
+	"POST /api/v1/users":                                {action: "user.create", resourceType: "user"},
+	"PUT /api/v1/users/:id":                             {action: "user.update", resourceType: "user"},
+	"DELETE /api/v1/users/:id":                          {action: "user.delete", resourceType: "user"},
+	"POST /api/v1/users/bulk":                           {action: "user.bulk", resourceType: "user"},
+	"POST /api/v1/users/import":                         {action: "user.import", resourceType: "user"},
+	"GET /api/v1/users/export":                          {action: "user.export", resourceType: "user"},
+	"POST /api/v1/users/:id/history/:version/revert":    {action: "user.revert", resourceType: "user"},
+	"GET /api/v1/users/import/:job_id/report":           {action: "user.import_report", resourceType: "user_import"},
+	"POST /api/v1/users/:id/verification-email":         {action: "user.send_verification_email", resourceType: "user"},
+	"POST /api/v1/users/:id/ban":                        {action: "user.ban", resourceType: "user"},
+	"POST /api/v1/users/:id/unban":                      {action: "user.unban", resourceType: "user"},
+	"POST /api/v1/users/attributes":                     {action: "user_attribute.create", resourceType: "user_attribute"},
+	"PUT /api/v1/users/attributes/:key":                 {action: "user_attribute.update", resourceType: "user_attribute"},
+	"DELETE /api/v1/users/attributes/:key":              {action: "user_attribute.delete", resourceType: "user_attribute"},
+	"POST /api/v1/users/tags":                           {action: "tag.create", resourceType: "tag"},
+	"PUT /api/v1/users/tags/:id":                        {action: "tag.update", resourceType: "tag"},
+	"DELETE /api/v1/users/tags/:id":                     {action: "tag.delete", resourceType: "tag"},
+	"POST /api/v1/users/:id/notes":                      {action: "user_note.create", resourceType: "user_note"},
+	"PUT /api/v1/users/:id/notes/:note_id":              {action: "user_note.update", resourceType: "user_note"},
+	"DELETE /api/v1/users/:id/notes/:note_id":           {action: "user_note.delete", resourceType: "user_note"},
+	"POST /api/v1/users/:id/notes/:note_id/pin":         {action: "user_note.pin", resourceType: "user_note"},
+	"DELETE /api/v1/users/:id/notes/:note_id/pin":       {action: "user_note.unpin", resourceType: "user_note"},
+	"POST /api/v1/bloggers":                             {action: "blogger.create", resourceType: "blogger"},
+	"PUT /api/v1/bloggers/:id":                          {action: "blogger.update", resourceType: "blogger"},
+	"DELETE /api/v1/bloggers/:id":                       {action: "blogger.delete", resourceType: "blogger"},
+	"POST /api/v1/campaigns":                            {action: "campaign.create", resourceType: "campaign"},
+	"PUT /api/v1/campaigns/:id":                         {action: "campaign.update", resourceType: "campaign"},
+	"DELETE /api/v1/campaigns/:id":                      {action: "campaign.delete", resourceType: "campaign"},
+	"POST /api/v1/campaigns/:id/bloggers":               {action: "campaign_blogger.assign", resourceType: "campaign"},
+	"PUT /api/v1/campaigns/:id/bloggers/:blogger_id":    {action: "campaign_blogger.update", resourceType: "campaign"},
+	"DELETE /api/v1/campaigns/:id/bloggers/:blogger_id": {action: "campaign_blogger.remove", resourceType: "campaign"},
+	"GET /api/v1/analytics/users/timeseries/export":     {action: "analytics.users_timeseries_export", resourceType: "user"},
+}
+
+// auditRedactedQueryParams — параметры запроса, которые могут содержать email, телефон или имя
+// пользователя. В журнал аудита записывается только факт их наличия.
+var auditRedactedQueryParams = map[string]bool{
+	"search": true,
+	"email":  true,
+	"phone":  true,
+}
+
+// auditQuery возвращает строку запроса для журнала аудита со скрытыми персональными данными.
+// Имена параметров сравниваются без учета регистра: в строку запроса клиент может передать любые.
+func auditQuery(query url.Values) string {
+	for key, values := range query {
+		if !auditRedactedQueryParams[strings.ToLower(key)] {
+			continue
+		}
+
+		for i := range values {
+			values[i] = "redacted"
+		}
+	}
+
+	return query.Encode()
+}
+
+// AuditMiddleware записывает в журнал аудита вызовы маршрутов из auditRoutes.
+// Исход определяется по коду ответа.
+func AuditMiddleware(useCase *usecase.UseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route, ok := auditRoutes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.Next()
+
+			return
+		}
+
+		c.Next()
+
+		resourceID := c.Param("id")
+		if resourceID == "" {
+			resourceID = c.Param("job_id")
+		}
+		if id := c.GetString(AuditResourceIDKey); id != "" {
+			resourceID = id
+		}
+
+		status := c.Writer.Status()
+		outcome := usecasemodels.AuditOutcomeSuccess
+		if status >= http.StatusBadRequest {
+			outcome = usecasemodels.AuditOutcomeFailure
+		}
+
+		details := map[string]string{
+			"method": c.Request.Method,
+			"path":   c.Request.URL.Path,
+			"status": strconv.Itoa(status),
+		}
+		if c.Request.URL.RawQuery != "" {
+			details["query"] = auditQuery(c.Request.URL.Query())
+		}
+
+		entry := &usecasemodels.AuditEntry{
+			Action:       route.action,
+			ResourceType: route.resourceType,
+			ResourceID:   resourceID,
+			Outcome:      outcome,
+			Details:      details,
+		}
+
+		if err := useCase.RecordAudit(c.Request.Context(), entry); err != nil {
+			c.Error(err)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestAuditQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "without personal data", query: "page=2&status=active", want: "page=2&status=active"},
+		{name: "search", query: "search=ivan%40example.com&page=1", want: "page=1&search=redacted"},
+		{name: "email and phone", query: "email=ivan%40example.com&phone=%2B79123456789", want: "email=redacted&phone=redacted"},
+		{name: "repeated parameter", query: "search=ivan&search=petr", want: "search=redacted&search=redacted"},
+		{name: "empty value", query: "search=", want: "search=redacted"},
+		{name: "name in another case", query: "Email=ivan%40example.com&SEARCH=ivan", want: "Email=redacted&SEARCH=redacted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("url.ParseQuery() error = %v", err)
+			}
+
+			if got := auditQuery(values); got != tt.want {
+				t.Fatalf("auditQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"adminkaback/internal/usecase"
 	"adminkaback/pkg/config"
 	"adminkaback/pkg/jwt"
+	"adminkaback/pkg/reqctx"
 	"github.com/gin-gonic/gin"
 )
 
@@ -62,6 +63,12 @@ func AuthMiddleware(useCase *usecase.UseCase, cfg *config.Config) gin.HandlerFun
 		c.Set("admin_email", claims.Email)
 		c.Set("admin_role", claims.Role)
 
+		info := reqctx.From(c.Request.Context())
+		info.AdminID = claims.AdminID
+		info.AdminEmail = claims.Email
+		info.AdminRole = claims.Role
+		c.Request = c.Request.WithContext(reqctx.With(c.Request.Context(), info))
+
 		c.Next()
 	}
 }
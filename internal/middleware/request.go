@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"adminkaback/pkg/reqctx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader — заголовок с идентификатором запроса.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestMiddleware присваивает запросу идентификатор и сохраняет метаданные запроса в контексте.
+func RequestMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.New().String()
+		}
+
+		c.Set("request_id", requestID)
+		c.Writer.Header().Set(RequestIDHeader, requestID)
+
+		info := &reqctx.Info{
+			RequestID: requestID,
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}
+		c.Request = c.Request.WithContext(reqctx.With(c.Request.Context(), info))
+
+		c.Next()
+	}
+}
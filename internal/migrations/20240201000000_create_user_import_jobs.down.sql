@@ -0,0 +1,2 @@
+-- Drop user_import_jobs table
+DROP TABLE IF EXISTS user_import_jobs;
@@ -0,0 +1,29 @@
+-- Create user_import_jobs table
+CREATE TABLE user_import_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    admin_id UUID REFERENCES admins(id) ON DELETE SET NULL,
+    filename VARCHAR(255) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    on_duplicate VARCHAR(10) NOT NULL DEFAULT 'skip',
+    dry_run BOOLEAN NOT NULL DEFAULT false,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    created_count INTEGER NOT NULL DEFAULT 0,
+    updated_count INTEGER NOT NULL DEFAULT 0,
+    skipped_count INTEGER NOT NULL DEFAULT 0,
+    error_count INTEGER NOT NULL DEFAULT 0,
+    row_errors JSONB NOT NULL DEFAULT '[]',
+    error_message TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_user_import_jobs_admin_id ON user_import_jobs(admin_id);
+CREATE INDEX idx_user_import_jobs_created_at ON user_import_jobs(created_at);
+
+-- Create trigger for user_import_jobs table
+CREATE TRIGGER update_user_import_jobs_updated_at BEFORE UPDATE ON user_import_jobs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+-- Drop user_history table
+DROP TABLE IF EXISTS user_history;
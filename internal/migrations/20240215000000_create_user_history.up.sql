@@ -0,0 +1,17 @@
+-- Create user_history table
+CREATE TABLE user_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    admin_id UUID REFERENCES admins(id) ON DELETE SET NULL,
+    request_id VARCHAR(128),
+    changes JSONB NOT NULL DEFAULT '{}',
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, version)
+);
+
+-- Create indexes
+CREATE INDEX idx_user_history_admin_id ON user_history(admin_id);
+CREATE INDEX idx_user_history_created_at ON user_history(created_at);
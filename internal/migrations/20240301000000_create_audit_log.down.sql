@@ -0,0 +1,8 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+
+-- Drop function
+DROP FUNCTION IF EXISTS prevent_audit_log_modification();
+
+-- Drop audit_log table
+DROP TABLE IF EXISTS audit_log;
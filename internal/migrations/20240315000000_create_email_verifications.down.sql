@@ -0,0 +1,8 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS reset_users_email_verification ON users;
+
+-- Drop function
+DROP FUNCTION IF EXISTS reset_email_verification();
+
+-- Drop email_verifications table
+DROP TABLE IF EXISTS email_verifications;
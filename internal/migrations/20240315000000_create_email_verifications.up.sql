@@ -0,0 +1,25 @@
+-- Create email_verifications table
+CREATE TABLE email_verifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    admin_id UUID REFERENCES admins(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_email_verifications_user_id_created_at ON email_verifications(user_id, created_at);
+
+-- Reset verification when email changes
+CREATE OR REPLACE FUNCTION reset_email_verification()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.email IS DISTINCT FROM OLD.email THEN
+        NEW.is_email_verified = false;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER reset_users_email_verification BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION reset_email_verification();
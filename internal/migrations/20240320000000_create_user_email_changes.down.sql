@@ -0,0 +1,6 @@
+-- Drop user_email_changes table
+DROP TABLE IF EXISTS user_email_changes;
+
+-- Restore global email uniqueness
+DROP INDEX IF EXISTS idx_users_email_live;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
@@ -0,0 +1,17 @@
+-- Email must be unique only among live users
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX idx_users_email_live ON users(email) WHERE deleted_at IS NULL;
+
+-- Create user_email_changes table
+CREATE TABLE user_email_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_email VARCHAR(255) NOT NULL,
+    new_email VARCHAR(255) NOT NULL,
+    admin_id UUID REFERENCES admins(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    confirmed_at TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_user_email_changes_user_id ON user_email_changes(user_id);
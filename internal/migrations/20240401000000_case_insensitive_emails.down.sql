@@ -0,0 +1,8 @@
+-- Restore case-sensitive email indexes (stored emails stay lowercased)
+DROP INDEX IF EXISTS idx_users_email_lower_live;
+CREATE INDEX idx_users_email ON users(email);
+CREATE UNIQUE INDEX idx_users_email_live ON users(email) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_admins_email_lower;
+CREATE INDEX idx_admins_email ON admins(email);
+ALTER TABLE admins ADD CONSTRAINT admins_email_key UNIQUE (email);
@@ -0,0 +1,53 @@
+-- Report emails that differ only by case before changing anything
+DO $$
+DECLARE
+    collisions TEXT;
+BEGIN
+    SELECT string_agg(format('admins: %s (%s)', lower_email, emails), E'\n')
+    INTO collisions
+    FROM (
+        SELECT lower(email) AS lower_email, string_agg(email || ' id=' || id, ', ' ORDER BY created_at) AS emails
+        FROM admins
+        GROUP BY lower(email)
+        HAVING COUNT(*) > 1
+    ) c;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'admins have emails that differ only by case, merge or rename them before migrating:%', E'\n' || collisions;
+    END IF;
+
+    SELECT string_agg(format('users: %s (%s)', lower_email, emails), E'\n')
+    INTO collisions
+    FROM (
+        SELECT lower(email) AS lower_email, string_agg(email || ' id=' || id, ', ' ORDER BY created_at) AS emails
+        FROM users
+        WHERE deleted_at IS NULL
+        GROUP BY lower(email)
+        HAVING COUNT(*) > 1
+    ) c;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'users have emails that differ only by case, merge or rename them before migrating:%', E'\n' || collisions;
+    END IF;
+END $$;
+
+-- Canonicalize stored emails without resetting verification or touching updated_at
+ALTER TABLE users DISABLE TRIGGER reset_users_email_verification;
+ALTER TABLE users DISABLE TRIGGER update_users_updated_at;
+ALTER TABLE admins DISABLE TRIGGER update_admins_updated_at;
+
+UPDATE admins SET email = lower(email) WHERE email <> lower(email);
+UPDATE users SET email = lower(email) WHERE email <> lower(email) AND deleted_at IS NULL;
+
+ALTER TABLE users ENABLE TRIGGER reset_users_email_verification;
+ALTER TABLE users ENABLE TRIGGER update_users_updated_at;
+ALTER TABLE admins ENABLE TRIGGER update_admins_updated_at;
+
+-- Replace case-sensitive uniqueness with lower(email)
+ALTER TABLE admins DROP CONSTRAINT IF EXISTS admins_email_key;
+DROP INDEX IF EXISTS idx_admins_email;
+CREATE UNIQUE INDEX idx_admins_email_lower ON admins(lower(email));
+
+DROP INDEX IF EXISTS idx_users_email_live;
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX idx_users_email_lower_live ON users(lower(email)) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- Drop user_bans table
+DROP TABLE IF EXISTS user_bans;
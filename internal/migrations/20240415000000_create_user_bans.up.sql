@@ -0,0 +1,18 @@
+-- Create user_bans table
+CREATE TABLE user_bans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    note TEXT,
+    admin_id UUID REFERENCES admins(id) ON DELETE SET NULL,
+    banned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    until TIMESTAMP,
+    lifted_at TIMESTAMP,
+    lifted_by UUID REFERENCES admins(id) ON DELETE SET NULL,
+    lift_reason TEXT
+);
+
+-- Create indexes
+CREATE INDEX idx_user_bans_user_id ON user_bans(user_id, banned_at);
+CREATE UNIQUE INDEX idx_user_bans_active ON user_bans(user_id) WHERE lifted_at IS NULL;
+CREATE INDEX idx_user_bans_until ON user_bans(until) WHERE lifted_at IS NULL AND until IS NOT NULL;
@@ -0,0 +1,6 @@
+-- Drop user_attribute_definitions table
+DROP TABLE IF EXISTS user_attribute_definitions;
+
+-- Drop attributes column from users
+DROP INDEX IF EXISTS idx_users_attributes;
+ALTER TABLE users DROP COLUMN IF EXISTS attributes;
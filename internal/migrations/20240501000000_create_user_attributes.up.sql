@@ -0,0 +1,17 @@
+-- Add attributes column to users
+ALTER TABLE users ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+
+-- Create user_attribute_definitions table
+CREATE TABLE user_attribute_definitions (
+    key VARCHAR(64) PRIMARY KEY,
+    type VARCHAR(16) NOT NULL,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    enum JSONB,
+    default_value JSONB,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_users_attributes ON users USING GIN (attributes jsonb_path_ops);
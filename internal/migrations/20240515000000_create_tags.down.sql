@@ -0,0 +1,5 @@
+-- Drop user_tags table
+DROP TABLE IF EXISTS user_tags;
+
+-- Drop tags table
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,5 @@
+-- Drop user_note_revisions table
+DROP TABLE IF EXISTS user_note_revisions;
+
+-- Drop user_notes table
+DROP TABLE IF EXISTS user_notes;
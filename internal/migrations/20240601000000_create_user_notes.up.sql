@@ -0,0 +1,37 @@
+-- Create user_notes table
+CREATE TABLE user_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES user_notes(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES admins(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    visible_to_roles TEXT[] NOT NULL DEFAULT '{}',
+    mentions UUID[] NOT NULL DEFAULT '{}',
+    pinned_at TIMESTAMP,
+    pinned_by UUID REFERENCES admins(id) ON DELETE SET NULL,
+    edited_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    deleted_by UUID REFERENCES admins(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create user_note_revisions table
+CREATE TABLE user_note_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    note_id UUID NOT NULL REFERENCES user_notes(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    mentions UUID[] NOT NULL DEFAULT '{}',
+    edited_by UUID REFERENCES admins(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_user_notes_user_id ON user_notes(user_id, created_at);
+CREATE INDEX idx_user_notes_parent_id ON user_notes(parent_id);
+CREATE INDEX idx_user_notes_mentions ON user_notes USING GIN (mentions);
+CREATE INDEX idx_user_note_revisions_note_id ON user_note_revisions(note_id, created_at);
+
+-- Create trigger for user_notes table
+CREATE TRIGGER update_user_notes_updated_at BEFORE UPDATE ON user_notes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
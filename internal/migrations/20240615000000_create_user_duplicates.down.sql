@@ -0,0 +1,12 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_users_merged_into;
+DROP INDEX IF EXISTS idx_users_name_trgm;
+DROP INDEX IF EXISTS idx_users_phone_digits;
+DROP INDEX IF EXISTS idx_users_email_key;
+
+-- Drop merge references
+ALTER TABLE user_history DROP COLUMN IF EXISTS merged_from;
+ALTER TABLE users DROP COLUMN IF EXISTS merged_into;
+
+-- Drop email key function
+DROP FUNCTION IF EXISTS user_email_key(TEXT);
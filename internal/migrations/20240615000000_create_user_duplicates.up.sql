@@ -0,0 +1,24 @@
+-- Enable trigram extension for name similarity
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Normalized email for duplicate detection: lower case, without +suffix, without dots for Gmail
+CREATE OR REPLACE FUNCTION user_email_key(email TEXT)
+RETURNS TEXT AS $$
+    SELECT CASE
+        WHEN split_part(lower(email), '@', 2) IN ('gmail.com', 'googlemail.com')
+            THEN replace(regexp_replace(split_part(lower(email), '@', 1), '\+.*$', ''), '.', '') || '@gmail.com'
+        ELSE regexp_replace(split_part(lower(email), '@', 1), '\+.*$', '') || '@' || split_part(lower(email), '@', 2)
+    END
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Reference to the surviving user for users removed by merge
+ALTER TABLE users ADD COLUMN merged_into UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Original user of history versions moved by merge
+ALTER TABLE user_history ADD COLUMN merged_from UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Create indexes
+CREATE INDEX idx_users_email_key ON users(user_email_key(email)) WHERE deleted_at IS NULL;
+CREATE INDEX idx_users_phone_digits ON users(regexp_replace(phone, '[^0-9]', '', 'g')) WHERE deleted_at IS NULL AND phone IS NOT NULL;
+CREATE INDEX idx_users_name_trgm ON users USING GIN (lower(name) gin_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX idx_users_merged_into ON users(merged_into) WHERE merged_into IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_user_history_merged_from;
+
+-- Drop erasure timestamp
+ALTER TABLE users DROP COLUMN IF EXISTS erased_at;
@@ -0,0 +1,5 @@
+-- Add erasure timestamp to users
+ALTER TABLE users ADD COLUMN erased_at TIMESTAMP;
+
+-- Create indexes
+CREATE INDEX idx_user_history_merged_from ON user_history(merged_from) WHERE merged_from IS NOT NULL;
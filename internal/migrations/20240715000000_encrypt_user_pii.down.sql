@@ -0,0 +1,28 @@
+-- Encrypted values must be decrypted with pii-rotate -decrypt before rolling back
+
+-- Restore email verification reset
+CREATE OR REPLACE FUNCTION reset_email_verification()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.email IS DISTINCT FROM OLD.email THEN
+        NEW.is_email_verified = false;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+-- Restore indexes
+DROP INDEX IF EXISTS idx_users_phone_key;
+DROP INDEX IF EXISTS idx_users_email_key;
+DROP INDEX IF EXISTS idx_users_email_live;
+CREATE INDEX idx_users_phone_digits ON users(regexp_replace(phone, '[^0-9]', '', 'g')) WHERE deleted_at IS NULL AND phone IS NOT NULL;
+CREATE INDEX idx_users_email_key ON users(user_email_key(email)) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_users_email_lower_live ON users(lower(email)) WHERE deleted_at IS NULL;
+
+-- Drop encrypted columns
+ALTER TABLE users
+    DROP COLUMN IF EXISTS phone_index,
+    DROP COLUMN IF EXISTS phone_encrypted,
+    DROP COLUMN IF EXISTS email_key_index,
+    DROP COLUMN IF EXISTS email_index,
+    DROP COLUMN IF EXISTS email_encrypted;
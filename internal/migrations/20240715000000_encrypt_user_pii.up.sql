@@ -0,0 +1,29 @@
+-- Add encrypted email and phone with blind indexes
+-- Encrypted values keep a masked copy in email and phone
+ALTER TABLE users
+    ADD COLUMN email_encrypted BYTEA,
+    ADD COLUMN email_index VARCHAR(64),
+    ADD COLUMN email_key_index VARCHAR(64),
+    ADD COLUMN phone_encrypted BYTEA,
+    ADD COLUMN phone_index VARCHAR(64);
+
+-- Email uniqueness uses the blind index for encrypted emails
+DROP INDEX IF EXISTS idx_users_email_lower_live;
+CREATE UNIQUE INDEX idx_users_email_live ON users(COALESCE(email_index, lower(email))) WHERE deleted_at IS NULL;
+
+-- Duplicate detection uses blind indexes for encrypted values
+DROP INDEX IF EXISTS idx_users_email_key;
+DROP INDEX IF EXISTS idx_users_phone_digits;
+CREATE INDEX idx_users_email_key ON users(COALESCE(email_key_index, user_email_key(email))) WHERE deleted_at IS NULL;
+CREATE INDEX idx_users_phone_key ON users(COALESCE(phone_index, regexp_replace(phone, '[^0-9]', '', 'g'))) WHERE deleted_at IS NULL AND phone IS NOT NULL;
+
+-- Reset verification when email changes: the masked copy can stay the same, the blind index cannot
+CREATE OR REPLACE FUNCTION reset_email_verification()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.email IS DISTINCT FROM OLD.email OR NEW.email_index IS DISTINCT FROM OLD.email_index THEN
+        NEW.is_email_verified = false;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
@@ -0,0 +1,2 @@
+-- Drop bloggers table
+DROP TABLE IF EXISTS bloggers;
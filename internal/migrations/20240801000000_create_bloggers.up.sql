@@ -0,0 +1,23 @@
+-- Create bloggers table
+CREATE TABLE bloggers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'ON',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    number VARCHAR(64),
+    date DATE NOT NULL DEFAULT CURRENT_DATE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_bloggers_username_live ON bloggers(lower(username)) WHERE deleted_at IS NULL;
+CREATE INDEX idx_bloggers_status ON bloggers(status);
+CREATE INDEX idx_bloggers_date ON bloggers(date);
+CREATE INDEX idx_bloggers_tags ON bloggers USING GIN(tags);
+
+-- Create trigger for bloggers table
+CREATE TRIGGER update_bloggers_updated_at BEFORE UPDATE ON bloggers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+-- Drop blogger_contributions table
+DROP TABLE IF EXISTS blogger_contributions;
@@ -0,0 +1,14 @@
+-- Create blogger_contributions table
+CREATE TABLE blogger_contributions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    blogger_id UUID NOT NULL REFERENCES bloggers(id) ON DELETE CASCADE,
+    admin_id UUID REFERENCES admins(id) ON DELETE SET NULL,
+    legacy_id VARCHAR(255),
+    outcome VARCHAR(16) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_blogger_contributions_blogger_id ON blogger_contributions(blogger_id, created_at);
+CREATE INDEX idx_blogger_contributions_admin_id ON blogger_contributions(admin_id);
@@ -0,0 +1,5 @@
+-- Drop campaign_bloggers table
+DROP TABLE IF EXISTS campaign_bloggers;
+
+-- Drop campaigns table
+DROP TABLE IF EXISTS campaigns;
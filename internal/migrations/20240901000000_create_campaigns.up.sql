@@ -0,0 +1,42 @@
+-- Create campaigns table
+CREATE TABLE campaigns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    budget BIGINT NOT NULL DEFAULT 0 CHECK (budget >= 0),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'draft',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP,
+    CHECK (end_date >= start_date)
+);
+
+-- Create campaign_bloggers table
+CREATE TABLE campaign_bloggers (
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    blogger_id UUID NOT NULL REFERENCES bloggers(id) ON DELETE CASCADE,
+    price BIGINT NOT NULL DEFAULT 0 CHECK (price >= 0),
+    deliverables TEXT,
+    publication_url TEXT,
+    due_date DATE,
+    status VARCHAR(16) NOT NULL DEFAULT 'negotiating',
+    contracted_at TIMESTAMP,
+    published_at TIMESTAMP,
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (campaign_id, blogger_id)
+);
+
+-- Create indexes
+CREATE INDEX idx_campaigns_status ON campaigns(status);
+CREATE INDEX idx_campaigns_dates ON campaigns(start_date, end_date);
+CREATE INDEX idx_campaign_bloggers_blogger_id ON campaign_bloggers(blogger_id);
+
+-- Create triggers for campaigns tables
+CREATE TRIGGER update_campaigns_updated_at BEFORE UPDATE ON campaigns
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_campaign_bloggers_updated_at BEFORE UPDATE ON campaign_bloggers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
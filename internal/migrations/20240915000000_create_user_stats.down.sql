@@ -0,0 +1,2 @@
+-- Drop user_stats materialized view
+DROP MATERIALIZED VIEW IF EXISTS user_stats;
@@ -0,0 +1,2 @@
+-- Drop admin_preferences table
+DROP TABLE IF EXISTS admin_preferences;
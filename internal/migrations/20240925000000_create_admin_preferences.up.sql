@@ -0,0 +1,13 @@
+-- Create admin_preferences table
+CREATE TABLE admin_preferences (
+    admin_id UUID PRIMARY KEY REFERENCES admins(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL DEFAULT 1,
+    schema_version INTEGER NOT NULL DEFAULT 1,
+    preferences JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create trigger for admin_preferences table
+CREATE TRIGGER update_admin_preferences_updated_at BEFORE UPDATE ON admin_preferences
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+-- Encrypted values must be decrypted with pii-rotate -decrypt before rolling back
+
+-- Drop encrypted columns
+ALTER TABLE user_email_changes
+    DROP COLUMN IF EXISTS new_email_encrypted,
+    DROP COLUMN IF EXISTS old_email_encrypted;
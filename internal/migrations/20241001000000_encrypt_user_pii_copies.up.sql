@@ -0,0 +1,5 @@
+-- Add encrypted emails to user_email_changes
+-- Encrypted values keep a masked copy in old_email and new_email
+ALTER TABLE user_email_changes
+    ADD COLUMN old_email_encrypted BYTEA,
+    ADD COLUMN new_email_encrypted BYTEA;
@@ -0,0 +1,8 @@
+-- Restore users triggers without the skip setting
+DROP TRIGGER IF EXISTS reset_users_email_verification ON users;
+CREATE TRIGGER reset_users_email_verification BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION reset_email_verification();
+
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,14 @@
+-- Users triggers can be skipped for the current transaction with
+-- SET LOCAL app.skip_user_triggers = 'on' (used by PII re-encryption)
+-- without ALTER TABLE ... DISABLE TRIGGER, which locks the whole table
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users
+    FOR EACH ROW
+    WHEN (current_setting('app.skip_user_triggers', true) IS DISTINCT FROM 'on')
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS reset_users_email_verification ON users;
+CREATE TRIGGER reset_users_email_verification BEFORE UPDATE ON users
+    FOR EACH ROW
+    WHEN (current_setting('app.skip_user_triggers', true) IS DISTINCT FROM 'on')
+    EXECUTE FUNCTION reset_email_verification();
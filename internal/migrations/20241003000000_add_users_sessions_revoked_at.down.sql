@@ -0,0 +1,2 @@
+-- Drop sessions revocation time
+ALTER TABLE users DROP COLUMN IF EXISTS sessions_revoked_at;
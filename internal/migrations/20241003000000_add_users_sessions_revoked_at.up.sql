@@ -0,0 +1,3 @@
+-- Add sessions revocation time to users
+-- Sessions issued before sessions_revoked_at must be rejected by the user-facing application
+ALTER TABLE users ADD COLUMN sessions_revoked_at TIMESTAMP;
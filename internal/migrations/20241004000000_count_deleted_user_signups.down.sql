@@ -0,0 +1,22 @@
+-- Restore user_stats materialized view without deleted users
+DROP MATERIALIZED VIEW IF EXISTS user_stats;
+
+CREATE MATERIALIZED VIEW user_stats AS
+SELECT
+    status,
+    role,
+    is_email_verified,
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '1 day') AS new_1d,
+    COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '2 days' AND created_at < NOW() - INTERVAL '1 day') AS previous_1d,
+    COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '7 days') AS new_7d,
+    COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '14 days' AND created_at < NOW() - INTERVAL '7 days') AS previous_7d,
+    COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '30 days') AS new_30d,
+    COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '60 days' AND created_at < NOW() - INTERVAL '30 days') AS previous_30d,
+    NOW() AS refreshed_at
+FROM users
+WHERE deleted_at IS NULL
+GROUP BY status, role, is_email_verified;
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_user_stats_group ON user_stats(status, role, is_email_verified);
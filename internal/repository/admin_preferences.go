@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetAdminPreferences получает настройки администратора. Возвращает nil, если они не сохранялись.
+func (r *Repository) GetAdminPreferences(ctx context.Context, adminID string) (*repositorymodels.AdminPreferences, error) {
+	query, args, err := squirrel.
+		Select("admin_id", "version", "schema_version", "preferences", "created_at", "updated_at").
+		From("admin_preferences").
+		Where(squirrel.Eq{"admin_id": adminID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	var preferences repositorymodels.AdminPreferences
+	err = r.conn(ctx).QueryRow(ctx, query, args...).Scan(
+		&preferences.AdminID,
+		&preferences.Version,
+		&preferences.SchemaVersion,
+		&preferences.Preferences,
+		&preferences.CreatedAt,
+		&preferences.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan admin preferences: %w", err)
+	}
+
+	return &preferences, nil
+}
+
+// SaveAdminPreferences сохраняет настройки администратора, если их текущая версия равна expectedVersion
+// (0 — настройки еще не сохранялись), и увеличивает версию. Иначе возвращает ErrAdminPreferencesVersionConflict.
+// Заполняет в preferences новую версию и время изменения.
+func (r *Repository) SaveAdminPreferences(ctx context.Context, preferences *repositorymodels.AdminPreferences, expectedVersion int) error {
+	query, args, err := squirrel.
+		Insert("admin_preferences").
+		Columns("admin_id", "version", "schema_version", "preferences").
+		Values(preferences.AdminID, 1, preferences.SchemaVersion, preferences.Preferences).
+		Suffix(`ON CONFLICT (admin_id) DO UPDATE SET
+			version = admin_preferences.version + 1,
+			schema_version = EXCLUDED.schema_version,
+			preferences = EXCLUDED.preferences
+		WHERE admin_preferences.version = ?
+		RETURNING version, created_at, updated_at`, expectedVersion).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	err = r.conn(ctx).QueryRow(ctx, query, args...).Scan(&preferences.Version, &preferences.CreatedAt, &preferences.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return usecasemodels.ErrAdminPreferencesVersionConflict
+		}
+
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
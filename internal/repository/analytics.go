@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// userMetricSources — таблица, колонка времени события и дополнительное условие для метрик временных рядов.
+// Пользователь в них доступен под псевдонимом u. Удалениями не считаются объединение дубликата
+// и обезличивание: у обезличенного пользователя deleted_at совпадает с erased_at, если он не был удален раньше.
+var userMetricSources = map[string]struct {
+	from   string
+	column string
+	where  string
+}{
+	usecasemodels.UserMetricSignups: {from: "users u", column: "u.created_at"},
+	usecasemodels.UserMetricDeletions: {
+		from:   "users u",
+		column: "u.deleted_at",
+		where:  "u.merged_into IS NULL AND (u.erased_at IS NULL OR u.deleted_at <> u.erased_at)",
+	},
+	usecasemodels.UserMetricBans: {from: "user_bans b JOIN users u ON u.id = b.user_id", column: "b.banned_at"},
+}
+
+// userBreakdownColumns — колонки разбивки временных рядов.
+var userBreakdownColumns = map[string]string{
+	usecasemodels.UserBreakdownRole:   "u.role",
+	usecasemodels.UserBreakdownStatus: "u.status",
+}
+
+// GetUserTimeseries считает события метрики по интервалам. Время события переводится из часового пояса
+// хранения в часовой пояс запроса, интервалы без событий не возвращаются.
+func (r *Repository) GetUserTimeseries(ctx context.Context, q *usecasemodels.UserTimeseriesQuery) ([]repositorymodels.UserTimeseriesPoint, error) {
+	source, ok := userMetricSources[q.Metric]
+	if !ok {
+		return nil, usecasemodels.ErrorInvalidParameterMetric
+	}
+
+	bucket := squirrel.Expr(
+		fmt.Sprintf("date_trunc(?, (%s AT TIME ZONE ?) AT TIME ZONE ?)", source.column),
+		q.Interval, q.StorageTimezone, q.Timezone,
+	)
+
+	query := squirrel.Select().
+		Column(bucket).
+		From(source.from).
+		Where(squirrel.GtOrEq{source.column: q.From}).
+		Where(squirrel.Lt{source.column: q.To}).
+		GroupBy("1").
+		OrderBy("1")
+	if source.where != "" {
+		query = query.Where(source.where)
+	}
+
+	if q.Breakdown != "" {
+		column, ok := userBreakdownColumns[q.Breakdown]
+		if !ok {
+			return nil, usecasemodels.ErrorInvalidParameterBreakdown
+		}
+
+		query = query.Column(column).GroupBy("2").OrderBy("2")
+	} else {
+		query = query.Column("NULL::text")
+	}
+	query = query.Column("COUNT(*)")
+
+	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var points []repositorymodels.UserTimeseriesPoint
+	for rows.Next() {
+		var point repositorymodels.UserTimeseriesPoint
+		if err := rows.Scan(&point.Bucket, &point.Key, &point.Count); err != nil {
+			return nil, fmt.Errorf("scan user timeseries point: %w", err)
+		}
+
+		points = append(points, point)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return points, nil
+}
+
+// GetUserCohortSizes считает пользователей, зарегистрированных в границах каждой когорты.
+// Удаленные пользователи учитываются: они входят в когорту, но не считаются удержанными.
+func (r *Repository) GetUserCohortSizes(ctx context.Context, q *usecasemodels.UserCohortQuery) ([]repositorymodels.UserCohortCount, error) {
+	query := squirrel.Select("c.cohort - 1", "0", "COUNT(*)").
+		From("users").
+		Join(
+			"unnest(?::timestamp[], ?::timestamp[]) WITH ORDINALITY AS c(starts_at, ends_at, cohort) "+
+				"ON users.created_at >= c.starts_at AND users.created_at < c.ends_at",
+			q.Starts, q.Ends,
+		).
+		GroupBy("c.cohort")
+	query = r.applyUserFilters(query, &q.UserFilter)
+
+	return r.queryUserCohortCounts(ctx, query)
+}
+
+// GetUserCohortRetention считает удержанных пользователей когорт на моменты проверки.
+// Статус на момент берется из последней версии истории пользователя до него. Если до момента
+// версий нет (пользователь создан до ведения истории), берется статус до первой версии после него,
+// а при отсутствии истории — текущий.
+func (r *Repository) GetUserCohortRetention(ctx context.Context, q *usecasemodels.UserCohortQuery) ([]repositorymodels.UserCohortCount, error) {
+	cohorts := make([]int, len(q.Checkpoints))
+	periods := make([]int, len(q.Checkpoints))
+	starts := make([]time.Time, len(q.Checkpoints))
+	ends := make([]time.Time, len(q.Checkpoints))
+	at := make([]time.Time, len(q.Checkpoints))
+	for i, checkpoint := range q.Checkpoints {
+		cohorts[i] = checkpoint.Cohort
+		periods[i] = checkpoint.Period
+		starts[i] = q.Starts[checkpoint.Cohort]
+		ends[i] = q.Ends[checkpoint.Cohort]
+		at[i] = checkpoint.At
+	}
+
+	query := squirrel.Select("cp.cohort", "cp.period", "COUNT(*)").
+		From("users").
+		Join(
+			"unnest(?::int[], ?::int[], ?::timestamp[], ?::timestamp[], ?::timestamp[]) AS cp(cohort, period, starts_at, ends_at, checkpoint_at) "+
+				"ON users.created_at >= cp.starts_at AND users.created_at < cp.ends_at",
+			cohorts, periods, starts, ends, at,
+		).
+		LeftJoin(`LATERAL (
+			SELECT h.snapshot->>'status' AS history_status
+			FROM user_history h
+			WHERE h.user_id = users.id AND h.merged_from IS NULL AND h.created_at <= cp.checkpoint_at
+			ORDER BY h.version DESC
+			LIMIT 1
+		) h ON true`).
+		LeftJoin(`LATERAL (
+			SELECT COALESCE(nh.changes->'status'->>'old', nh.snapshot->>'status') AS next_status
+			FROM user_history nh
+			WHERE nh.user_id = users.id AND nh.merged_from IS NULL AND nh.created_at > cp.checkpoint_at
+			ORDER BY nh.version
+			LIMIT 1
+		) nh ON true`).
+		Where(squirrel.Expr("COALESCE(h.history_status, nh.next_status, users.status) = ?", q.RetainedStatus)).
+		Where("(users.deleted_at IS NULL OR users.deleted_at > cp.checkpoint_at)").
+		GroupBy("cp.cohort", "cp.period")
+	query = r.applyUserFilters(query, &q.UserFilter)
+
+	return r.queryUserCohortCounts(ctx, query)
+}
+
+// queryUserCohortCounts выполняет запрос, выбирающий когорту, период и количество.
+func (r *Repository) queryUserCohortCounts(ctx context.Context, query squirrel.SelectBuilder) ([]repositorymodels.UserCohortCount, error) {
+	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []repositorymodels.UserCohortCount
+	for rows.Next() {
+		var count repositorymodels.UserCohortCount
+		if err := rows.Scan(&count.Cohort, &count.Period, &count.Count); err != nil {
+			return nil, fmt.Errorf("scan user cohort count: %w", err)
+		}
+
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+func TestGetUserCohortRetention(t *testing.T) {
+	repo, pool := newTestRepository(t)
+	ctx := context.Background()
+
+	date := func(day int) time.Time {
+		return time.Date(2024, 1, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	deletedAt := date(10)
+
+	type historyRow struct {
+		at      time.Time
+		changes string
+		status  string
+	}
+
+	users := []struct {
+		name      string
+		status    string
+		createdAt time.Time
+		deletedAt *time.Time
+		history   []historyRow
+	}{
+		{
+			// Создан до ведения истории, заблокирован после обоих моментов проверки.
+			name: "banned later", status: "banned", createdAt: date(2),
+			history: []historyRow{{at: date(20), changes: `{"status": {"old": "active", "new": "banned"}}`, status: "banned"}},
+		},
+		{
+			name: "deactivated in the second week", status: "inactive", createdAt: date(3),
+			history: []historyRow{
+				{at: date(3), changes: `{}`, status: "active"},
+				{at: date(10), changes: `{"status": {"old": "active", "new": "inactive"}}`, status: "inactive"},
+			},
+		},
+		{name: "without history", status: "active", createdAt: date(4)},
+		{
+			// Первая версия после моментов проверки не меняет статус.
+			name: "inactive with later profile change", status: "inactive", createdAt: date(5),
+			history: []historyRow{{at: date(20), changes: `{"name": {"old": "Old", "new": "New"}}`, status: "inactive"}},
+		},
+		{name: "deleted in the second week", status: "active", createdAt: date(6), deletedAt: &deletedAt},
+		{name: "outside of cohort", status: "active", createdAt: date(9)},
+	}
+
+	for i, user := range users {
+		var id string
+		err := pool.QueryRow(ctx,
+			"INSERT INTO users (email, name, status, created_at, deleted_at) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+			user.name+"@example.com", user.name, user.status, user.createdAt, user.deletedAt,
+		).Scan(&id)
+		if err != nil {
+			t.Fatalf("insert user %d: %v", i, err)
+		}
+
+		for version, row := range user.history {
+			_, err := pool.Exec(ctx,
+				"INSERT INTO user_history (user_id, version, action, changes, snapshot, created_at) VALUES ($1, $2, 'update', $3::jsonb, $4::jsonb, $5)",
+				id, version+1, row.changes, `{"status": "`+row.status+`"}`, row.at,
+			)
+			if err != nil {
+				t.Fatalf("insert user %d history: %v", i, err)
+			}
+		}
+	}
+
+	got, err := repo.GetUserCohortRetention(ctx, &usecasemodels.UserCohortQuery{
+		Starts: []time.Time{date(1)},
+		Ends:   []time.Time{date(8)},
+		Checkpoints: []usecasemodels.UserCohortCheckpoint{
+			{Cohort: 0, Period: 0, At: date(8)},
+			{Cohort: 0, Period: 1, At: date(15)},
+		},
+		RetainedStatus: "active",
+	})
+	if err != nil {
+		t.Fatalf("GetUserCohortRetention() error = %v", err)
+	}
+
+	// Удержаны в первую неделю: banned later, deactivated in the second week, without history,
+	// deleted in the second week; во вторую — banned later и without history.
+	retained := make(map[int]int, len(got))
+	for _, count := range got {
+		retained[count.Period] = count.Count
+	}
+
+	want := map[int]int{0: 4, 1: 2}
+	if !reflect.DeepEqual(retained, want) {
+		t.Fatalf("GetUserCohortRetention() = %+v, want retained by period %v", got, want)
+	}
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// auditLogLockKey — ключ advisory lock, сериализующего запись в журнал аудита.
+const auditLogLockKey = 7_301_001
+
+// auditColumns — колонки, из которых собирается модель записи в scanAuditEntry.
+var auditColumns = []string{
+	"seq", "id", "actor_id", "actor_email", "action", "resource_type", "resource_id", "outcome",
+	"ip", "user_agent", "request_id", "details", "prev_hash", "hash", "created_at",
+}
+
+// LockAuditLog блокирует запись в журнал аудита до конца текущей транзакции.
+func (r *Repository) LockAuditLog(ctx context.Context) error {
+	if _, err := r.conn(ctx).Exec(ctx, "SELECT pg_advisory_xact_lock($1)", auditLogLockKey); err != nil {
+		return fmt.Errorf("lock audit log: %w", err)
+	}
+
+	return nil
+}
+
+// GetLastAuditHash возвращает хеш последней записи журнала аудита или пустую строку.
+func (r *Repository) GetLastAuditHash(ctx context.Context) (string, error) {
+	query, args, err := squirrel.
+		Select("hash").
+		From("audit_log").
+		OrderBy("seq DESC").
+		Limit(1).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("build select query: %w", err)
+	}
+
+	var hash string
+	if err := r.conn(ctx).QueryRow(ctx, query, args...).Scan(&hash); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("scan audit hash: %w", err)
+	}
+
+	return hash, nil
+}
+
+// CreateAuditEntry добавляет запись в журнал аудита и записывает ее номер в entry.Seq.
+func (r *Repository) CreateAuditEntry(ctx context.Context, entry *repositorymodels.AuditEntry) error {
+	query, args, err := squirrel.
+		Insert("audit_log").
+		Columns("id", "actor_id", "actor_email", "action", "resource_type", "resource_id", "outcome",
+			"ip", "user_agent", "request_id", "details", "prev_hash", "hash", "created_at").
+		Values(entry.ID, entry.ActorID, entry.ActorEmail, entry.Action, entry.ResourceType, entry.ResourceID, entry.Outcome,
+			entry.IP, entry.UserAgent, entry.RequestID, entry.Details, entry.PrevHash, entry.Hash, entry.CreatedAt).
+		Suffix("RETURNING seq").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if err := r.conn(ctx).QueryRow(ctx, query, args...).Scan(&entry.Seq); err != nil {
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetAuditEntries получает записи журнала аудита с фильтрацией и пагинацией, начиная с последних.
+func (r *Repository) GetAuditEntries(ctx context.Context, req *usecasemodels.GetAuditLogRequest) ([]repositorymodels.AuditEntry, int, error) {
+	countQuery := applyAuditFilters(squirrel.Select("COUNT(*)").From("audit_log"), req)
+
+	countSQL, countArgs, err := countQuery.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build count query: %w", err)
+	}
+
+	var total int
+	if err := r.conn(ctx).QueryRow(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("execute count query: %w", err)
+	}
+
+	query := applyAuditFilters(squirrel.Select(auditColumns...).From("audit_log"), req).OrderBy("seq DESC")
+
+	if req.Limit > 0 {
+		query = query.Limit(uint64(req.Limit))
+	}
+	if req.Page > 0 && req.Limit > 0 {
+		query = query.Offset(uint64((req.Page - 1) * req.Limit))
+	}
+
+	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build select query: %w", err)
+	}
+
+	entries, err := r.queryAuditEntries(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// GetAuditEntriesAfter получает до limit записей с номером больше afterSeq в порядке записи.
+func (r *Repository) GetAuditEntriesAfter(ctx context.Context, afterSeq int64, limit int) ([]repositorymodels.AuditEntry, error) {
+	sql, args, err := squirrel.
+		Select(auditColumns...).
+		From("audit_log").
+		Where(squirrel.Gt{"seq": afterSeq}).
+		OrderBy("seq ASC").
+		Limit(uint64(limit)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	return r.queryAuditEntries(ctx, sql, args...)
+}
+
+// GetAuditEntriesByResourceIDs получает записи журнала аудита о ресурсах с указанными ID в порядке записи.
+func (r *Repository) GetAuditEntriesByResourceIDs(ctx context.Context, ids []string) ([]repositorymodels.AuditEntry, error) {
+	sql, args, err := squirrel.
+		Select(auditColumns...).
+		From("audit_log").
+		Where(squirrel.Eq{"resource_id": ids}).
+		OrderBy("seq ASC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	return r.queryAuditEntries(ctx, sql, args...)
+}
+
+// queryAuditEntries выполняет запрос и читает записи журнала аудита.
+func (r *Repository) queryAuditEntries(ctx context.Context, sql string, args ...any) ([]repositorymodels.AuditEntry, error) {
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []repositorymodels.AuditEntry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+
+		entries = append(entries, *entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return entries, nil
+}
+
+// applyAuditFilters добавляет к запросу фильтры журнала аудита.
+func applyAuditFilters(query squirrel.SelectBuilder, req *usecasemodels.GetAuditLogRequest) squirrel.SelectBuilder {
+	if req.ActorID != "" {
+		query = query.Where(squirrel.Eq{"actor_id": req.ActorID})
+	}
+	if len(req.Action) > 0 {
+		query = query.Where(squirrel.Eq{"action": req.Action})
+	}
+	if req.ResourceType != "" {
+		query = query.Where(squirrel.Eq{"resource_type": req.ResourceType})
+	}
+	if req.ResourceID != "" {
+		query = query.Where(squirrel.Eq{"resource_id": req.ResourceID})
+	}
+	if req.Outcome != "" {
+		query = query.Where(squirrel.Eq{"outcome": req.Outcome})
+	}
+	if req.From != nil {
+		query = query.Where(squirrel.GtOrEq{"created_at": *req.From})
+	}
+	if req.To != nil {
+		query = query.Where(squirrel.Lt{"created_at": *req.To})
+	}
+
+	return query
+}
+
+// scanAuditEntry читает строку, выбранную по auditColumns, в модель записи.
+func scanAuditEntry(row pgx.Row) (*repositorymodels.AuditEntry, error) {
+	var entry repositorymodels.AuditEntry
+	err := row.Scan(
+		&entry.Seq,
+		&entry.ID,
+		&entry.ActorID,
+		&entry.ActorEmail,
+		&entry.Action,
+		&entry.ResourceType,
+		&entry.ResourceID,
+		&entry.Outcome,
+		&entry.IP,
+		&entry.UserAgent,
+		&entry.RequestID,
+		&entry.Details,
+		&entry.PrevHash,
+		&entry.Hash,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -24,20 +25,24 @@ func (r *Repository) CreateAdmin(ctx context.Context, admin *repositorymodels.Ad
 		return fmt.Errorf("build insert query: %w", err)
 	}
 
-	_, err = r.pool.Exec(ctx, query, args...)
+	_, err = r.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return usecasemodels.ErrAdminAlreadyExists
+		}
+
 		return fmt.Errorf("execute insert: %w", err)
 	}
 
 	return nil
 }
 
-// GetAdminByEmail получает администратора по email.
+// GetAdminByEmail получает администратора по email без учета регистра.
 func (r *Repository) GetAdminByEmail(ctx context.Context, email string) (*repositorymodels.Admin, error) {
 	query, args, err := squirrel.
 		Select("id", "email", "password_hash", "name", "role", "is_active", "created_at", "updated_at").
 		From("admins").
-		Where(squirrel.Eq{"email": email}).
+		Where("lower(email) = lower(?)", email).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
@@ -45,7 +50,7 @@ func (r *Repository) GetAdminByEmail(ctx context.Context, email string) (*reposi
 	}
 
 	var admin repositorymodels.Admin
-	err = r.pool.QueryRow(ctx, query, args...).Scan(
+	err = r.conn(ctx).QueryRow(ctx, query, args...).Scan(
 		&admin.ID,
 		&admin.Email,
 		&admin.PasswordHash,
@@ -79,7 +84,7 @@ func (r *Repository) GetAdminByID(ctx context.Context, id string) (*repositorymo
 	}
 
 	var admin repositorymodels.Admin
-	err = r.pool.QueryRow(ctx, query, args...).Scan(
+	err = r.conn(ctx).QueryRow(ctx, query, args...).Scan(
 		&admin.ID,
 		&admin.Email,
 		&admin.PasswordHash,
@@ -100,6 +105,51 @@ func (r *Repository) GetAdminByID(ctx context.Context, id string) (*repositorymo
 	return &admin, nil
 }
 
+// GetAdminsByIDs получает администраторов с указанными ID. Несуществующие ID пропускаются.
+func (r *Repository) GetAdminsByIDs(ctx context.Context, ids []string) ([]repositorymodels.Admin, error) {
+	query, args, err := squirrel.
+		Select("id", "email", "password_hash", "name", "role", "is_active", "created_at", "updated_at").
+		From("admins").
+		Where(squirrel.Eq{"id": ids}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var admins []repositorymodels.Admin
+	for rows.Next() {
+		var admin repositorymodels.Admin
+		err := rows.Scan(
+			&admin.ID,
+			&admin.Email,
+			&admin.PasswordHash,
+			&admin.Name,
+			&admin.Role,
+			&admin.IsActive,
+			&admin.CreatedAt,
+			&admin.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan admin: %w", err)
+		}
+
+		admins = append(admins, admin)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return admins, nil
+}
+
 // CreateRefreshToken создает новый refresh токен.
 func (r *Repository) CreateRefreshToken(ctx context.Context, token *repositorymodels.RefreshToken) error {
 	query, args, err := squirrel.
@@ -112,7 +162,7 @@ func (r *Repository) CreateRefreshToken(ctx context.Context, token *repositorymo
 		return fmt.Errorf("build insert query: %w", err)
 	}
 
-	_, err = r.pool.Exec(ctx, query, args...)
+	_, err = r.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("execute insert: %w", err)
 	}
@@ -133,7 +183,7 @@ func (r *Repository) GetRefreshToken(ctx context.Context, token string) (*reposi
 	}
 
 	var refreshToken repositorymodels.RefreshToken
-	err = r.pool.QueryRow(ctx, query, args...).Scan(
+	err = r.conn(ctx).QueryRow(ctx, query, args...).Scan(
 		&refreshToken.ID,
 		&refreshToken.AdminID,
 		&refreshToken.Token,
@@ -162,7 +212,7 @@ func (r *Repository) DeleteRefreshToken(ctx context.Context, token string) error
 		return fmt.Errorf("build delete query: %w", err)
 	}
 
-	_, err = r.pool.Exec(ctx, query, args...)
+	_, err = r.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("execute delete: %w", err)
 	}
@@ -181,7 +231,7 @@ func (r *Repository) DeleteRefreshTokensByAdminID(ctx context.Context, adminID s
 		return fmt.Errorf("build delete query: %w", err)
 	}
 
-	_, err = r.pool.Exec(ctx, query, args...)
+	_, err = r.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("execute delete: %w", err)
 	}
@@ -200,7 +250,7 @@ func (r *Repository) CleanExpiredRefreshTokens(ctx context.Context) error {
 		return fmt.Errorf("build delete query: %w", err)
 	}
 
-	_, err = r.pool.Exec(ctx, query, args...)
+	_, err = r.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("execute delete: %w", err)
 	}
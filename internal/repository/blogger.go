@@ -0,0 +1,396 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// bloggerImportLockKey — ключ advisory lock, сериализующего импорт блогеров.
+const bloggerImportLockKey = 7_301_002
+
+// bloggerColumns — колонки, из которых собирается модель блогера в scanBlogger.
+var bloggerColumns = []string{"id", "name", "username", "status", "tags", "number", "date", "created_at", "updated_at", "deleted_at"}
+
+// bloggerSortColumns — колонки, по которым разрешена сортировка списка блогеров.
+var bloggerSortColumns = map[string]struct{}{
+	"name":       {},
+	"username":   {},
+	"status":     {},
+	"number":     {},
+	"date":       {},
+	"created_at": {},
+	"updated_at": {},
+}
+
+// CreateBlogger создает блогера.
+func (r *Repository) CreateBlogger(ctx context.Context, blogger *repositorymodels.Blogger) error {
+	query, args, err := squirrel.
+		Insert("bloggers").
+		Columns("id", "name", "username", "status", "tags", "number", "date", "created_at", "updated_at").
+		Values(
+			blogger.ID,
+			blogger.Name,
+			blogger.Username,
+			blogger.Status,
+			nonNilStrings(blogger.Tags),
+			blogger.Number,
+			blogger.Date,
+			blogger.CreatedAt,
+			blogger.UpdatedAt,
+		).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		if isUniqueViolation(err) {
+			return usecasemodels.ErrBloggerAlreadyExists
+		}
+
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetBloggerByID получает неудаленного блогера по ID.
+func (r *Repository) GetBloggerByID(ctx context.Context, id string) (*repositorymodels.Blogger, error) {
+	query, args, err := squirrel.
+		Select(bloggerColumns...).
+		From("bloggers").
+		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	blogger, err := scanBlogger(r.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan blogger: %w", err)
+	}
+
+	return blogger, nil
+}
+
+// GetBloggers получает неудаленных блогеров с фильтрацией, сортировкой и пагинацией.
+func (r *Repository) GetBloggers(ctx context.Context, req *usecasemodels.GetBloggersRequest) ([]repositorymodels.Blogger, int, error) {
+	countQuery := squirrel.Select("COUNT(*)").From("bloggers").Where(squirrel.Eq{"deleted_at": nil})
+	countQuery = applyBloggerFilters(countQuery, &req.BloggerFilter)
+
+	countSQL, countArgs, err := countQuery.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build count query: %w", err)
+	}
+
+	var total int
+	if err := r.conn(ctx).QueryRow(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("execute count query: %w", err)
+	}
+
+	query := squirrel.
+		Select(bloggerColumns...).
+		From("bloggers").
+		Where(squirrel.Eq{"deleted_at": nil})
+	query = applyBloggerFilters(query, &req.BloggerFilter)
+	query = applyBloggerSort(query, req.Sort, req.Order)
+
+	if req.Limit > 0 {
+		query = query.Limit(uint64(req.Limit))
+	}
+	if req.Page > 0 && req.Limit > 0 {
+		query = query.Offset(uint64((req.Page - 1) * req.Limit))
+	}
+
+	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var bloggers []repositorymodels.Blogger
+	for rows.Next() {
+		blogger, err := scanBlogger(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan blogger: %w", err)
+		}
+
+		bloggers = append(bloggers, *blogger)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return bloggers, total, nil
+}
+
+// UpdateBlogger сохраняет все изменяемые поля блогера.
+func (r *Repository) UpdateBlogger(ctx context.Context, blogger *repositorymodels.Blogger) error {
+	query, args, err := squirrel.
+		Update("bloggers").
+		Set("name", blogger.Name).
+		Set("username", blogger.Username).
+		Set("status", blogger.Status).
+		Set("tags", nonNilStrings(blogger.Tags)).
+		Set("number", blogger.Number).
+		Set("date", blogger.Date).
+		Where(squirrel.Eq{"id": blogger.ID}).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		Suffix("RETURNING updated_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update query: %w", err)
+	}
+
+	if err := r.conn(ctx).QueryRow(ctx, query, args...).Scan(&blogger.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return usecasemodels.ErrBloggerNotFound
+		}
+		if isUniqueViolation(err) {
+			return usecasemodels.ErrBloggerAlreadyExists
+		}
+
+		return fmt.Errorf("execute update: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBlogger выполняет soft delete блогера.
+func (r *Repository) DeleteBlogger(ctx context.Context, id string) error {
+	query, args, err := squirrel.
+		Update("bloggers").
+		Set("deleted_at", time.Now()).
+		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build delete query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("execute delete: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return usecasemodels.ErrBloggerNotFound
+	}
+
+	return nil
+}
+
+// LockBloggerImport блокирует импорт блогеров до конца текущей транзакции, чтобы одновременные
+// загрузки разных администраторов не создали блогеров с одинаковым username.
+func (r *Repository) LockBloggerImport(ctx context.Context) error {
+	if _, err := r.conn(ctx).Exec(ctx, "SELECT pg_advisory_xact_lock($1)", bloggerImportLockKey); err != nil {
+		return fmt.Errorf("lock blogger import: %w", err)
+	}
+
+	return nil
+}
+
+// GetBloggersByUsernames получает неудаленных блогеров по username без учета регистра.
+func (r *Repository) GetBloggersByUsernames(ctx context.Context, usernames []string) ([]repositorymodels.Blogger, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := squirrel.
+		Select(bloggerColumns...).
+		From("bloggers").
+		Where("lower(username) = ANY(?::text[])", usernames).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var bloggers []repositorymodels.Blogger
+	for rows.Next() {
+		blogger, err := scanBlogger(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan blogger: %w", err)
+		}
+
+		bloggers = append(bloggers, *blogger)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return bloggers, nil
+}
+
+// CreateBloggerContribution сохраняет запись блогера, загруженную администратором.
+func (r *Repository) CreateBloggerContribution(ctx context.Context, contribution *repositorymodels.BloggerContribution) error {
+	query, args, err := squirrel.
+		Insert("blogger_contributions").
+		Columns("id", "blogger_id", "admin_id", "legacy_id", "outcome", "payload", "created_at").
+		Values(
+			contribution.ID,
+			contribution.BloggerID,
+			contribution.AdminID,
+			contribution.LegacyID,
+			contribution.Outcome,
+			contribution.Payload,
+			contribution.CreatedAt,
+		).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetBloggerContributions получает загруженные администраторами записи блогера в порядке загрузки.
+func (r *Repository) GetBloggerContributions(ctx context.Context, bloggerID string) ([]repositorymodels.BloggerContribution, error) {
+	query, args, err := squirrel.
+		Select("id", "blogger_id", "admin_id", "legacy_id", "outcome", "payload", "created_at").
+		From("blogger_contributions").
+		Where(squirrel.Eq{"blogger_id": bloggerID}).
+		OrderBy("created_at ASC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var contributions []repositorymodels.BloggerContribution
+	for rows.Next() {
+		var contribution repositorymodels.BloggerContribution
+		err := rows.Scan(
+			&contribution.ID,
+			&contribution.BloggerID,
+			&contribution.AdminID,
+			&contribution.LegacyID,
+			&contribution.Outcome,
+			&contribution.Payload,
+			&contribution.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan blogger contribution: %w", err)
+		}
+
+		contributions = append(contributions, contribution)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return contributions, nil
+}
+
+// applyBloggerSort добавляет к запросу сортировку. Неизвестная колонка заменяется на created_at.
+func applyBloggerSort(query squirrel.SelectBuilder, sort, order string) squirrel.SelectBuilder {
+	direction := "ASC"
+	if order == "desc" {
+		direction = "DESC"
+	}
+
+	if _, ok := bloggerSortColumns[sort]; !ok {
+		return query.OrderBy("created_at DESC")
+	}
+
+	// id делает порядок стабильным при совпадении значений.
+	return query.OrderBy(fmt.Sprintf("%s %s", sort, direction), "id ASC")
+}
+
+// applyBloggerFilters добавляет к запросу фильтры списка блогеров.
+func applyBloggerFilters(query squirrel.SelectBuilder, filter *usecasemodels.BloggerFilter) squirrel.SelectBuilder {
+	if len(filter.Status) > 0 {
+		query = query.Where(squirrel.Eq{"status": filter.Status})
+	}
+	if len(filter.Tags) > 0 {
+		if filter.TagsMode == usecasemodels.TagsModeAll {
+			query = query.Where("tags @> ?::text[]", filter.Tags)
+		} else {
+			query = query.Where("tags && ?::text[]", filter.Tags)
+		}
+	}
+	if filter.DateFrom != "" {
+		query = query.Where("date >= ?::date", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		query = query.Where("date <= ?::date", filter.DateTo)
+	}
+	if filter.Search != "" {
+		query = query.Where(squirrel.Or{
+			squirrel.ILike{"name": "%" + filter.Search + "%"},
+			squirrel.ILike{"username": "%" + filter.Search + "%"},
+			squirrel.ILike{"number": "%" + filter.Search + "%"},
+		})
+	}
+
+	return query
+}
+
+// scanBlogger читает строку, выбранную по bloggerColumns, в модель блогера.
+func scanBlogger(row pgx.Row) (*repositorymodels.Blogger, error) {
+	var blogger repositorymodels.Blogger
+	if err := row.Scan(bloggerFields(&blogger)...); err != nil {
+		return nil, err
+	}
+
+	return &blogger, nil
+}
+
+// bloggerFields возвращает поля модели блогера в порядке bloggerColumns.
+func bloggerFields(blogger *repositorymodels.Blogger) []any {
+	return []any{
+		&blogger.ID,
+		&blogger.Name,
+		&blogger.Username,
+		&blogger.Status,
+		&blogger.Tags,
+		&blogger.Number,
+		&blogger.Date,
+		&blogger.CreatedAt,
+		&blogger.UpdatedAt,
+		&blogger.DeletedAt,
+	}
+}
@@ -0,0 +1,476 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// campaignColumns — колонки, из которых собирается модель кампании в scanCampaign.
+var campaignColumns = []string{"id", "name", "budget", "start_date", "end_date", "status", "created_at", "updated_at", "deleted_at"}
+
+// campaignBloggerColumns — колонки, из которых собирается модель участия в scanCampaignBlogger.
+var campaignBloggerColumns = []string{
+	"cb.campaign_id", "cb.blogger_id", "cb.price", "cb.deliverables", "cb.publication_url", "cb.due_date",
+	"cb.status", "cb.contracted_at", "cb.published_at", "cb.paid_at", "cb.created_at", "cb.updated_at",
+}
+
+// campaignSortColumns — колонки, по которым разрешена сортировка списка кампаний.
+var campaignSortColumns = map[string]struct{}{
+	"name":       {},
+	"budget":     {},
+	"start_date": {},
+	"end_date":   {},
+	"status":     {},
+	"created_at": {},
+	"updated_at": {},
+}
+
+// CreateCampaign создает кампанию.
+func (r *Repository) CreateCampaign(ctx context.Context, campaign *repositorymodels.Campaign) error {
+	query, args, err := squirrel.
+		Insert("campaigns").
+		Columns("id", "name", "budget", "start_date", "end_date", "status", "created_at", "updated_at").
+		Values(
+			campaign.ID,
+			campaign.Name,
+			campaign.Budget,
+			campaign.StartDate,
+			campaign.EndDate,
+			campaign.Status,
+			campaign.CreatedAt,
+			campaign.UpdatedAt,
+		).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetCampaignByID получает неудаленную кампанию по ID.
+func (r *Repository) GetCampaignByID(ctx context.Context, id string) (*repositorymodels.Campaign, error) {
+	query, args, err := squirrel.
+		Select(campaignColumns...).
+		From("campaigns").
+		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	campaign, err := scanCampaign(r.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// GetCampaigns получает неудаленные кампании с фильтрацией, сортировкой и пагинацией.
+func (r *Repository) GetCampaigns(ctx context.Context, req *usecasemodels.GetCampaignsRequest) ([]repositorymodels.Campaign, int, error) {
+	countQuery := squirrel.Select("COUNT(*)").From("campaigns").Where(squirrel.Eq{"deleted_at": nil})
+	countQuery = applyCampaignFilters(countQuery, &req.CampaignFilter)
+
+	countSQL, countArgs, err := countQuery.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build count query: %w", err)
+	}
+
+	var total int
+	if err := r.conn(ctx).QueryRow(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("execute count query: %w", err)
+	}
+
+	query := squirrel.
+		Select(campaignColumns...).
+		From("campaigns").
+		Where(squirrel.Eq{"deleted_at": nil})
+	query = applyCampaignFilters(query, &req.CampaignFilter)
+	query = applyCampaignSort(query, req.Sort, req.Order)
+
+	if req.Limit > 0 {
+		query = query.Limit(uint64(req.Limit))
+	}
+	if req.Page > 0 && req.Limit > 0 {
+		query = query.Offset(uint64((req.Page - 1) * req.Limit))
+	}
+
+	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var campaigns []repositorymodels.Campaign
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan campaign: %w", err)
+		}
+
+		campaigns = append(campaigns, *campaign)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return campaigns, total, nil
+}
+
+// UpdateCampaign сохраняет все изменяемые поля кампании.
+func (r *Repository) UpdateCampaign(ctx context.Context, campaign *repositorymodels.Campaign) error {
+	query, args, err := squirrel.
+		Update("campaigns").
+		Set("name", campaign.Name).
+		Set("budget", campaign.Budget).
+		Set("start_date", campaign.StartDate).
+		Set("end_date", campaign.EndDate).
+		Set("status", campaign.Status).
+		Where(squirrel.Eq{"id": campaign.ID}).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		Suffix("RETURNING updated_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update query: %w", err)
+	}
+
+	if err := r.conn(ctx).QueryRow(ctx, query, args...).Scan(&campaign.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return usecasemodels.ErrCampaignNotFound
+		}
+
+		return fmt.Errorf("execute update: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCampaign выполняет soft delete кампании. Участие блогеров сохраняется.
+func (r *Repository) DeleteCampaign(ctx context.Context, id string) error {
+	query, args, err := squirrel.
+		Update("campaigns").
+		Set("deleted_at", time.Now()).
+		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build delete query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("execute delete: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return usecasemodels.ErrCampaignNotFound
+	}
+
+	return nil
+}
+
+// CreateCampaignBlogger добавляет блогера в кампанию.
+func (r *Repository) CreateCampaignBlogger(ctx context.Context, assignment *repositorymodels.CampaignBlogger) error {
+	query, args, err := squirrel.
+		Insert("campaign_bloggers").
+		Columns("campaign_id", "blogger_id", "price", "deliverables", "due_date", "status", "created_at", "updated_at").
+		Values(
+			assignment.CampaignID,
+			assignment.BloggerID,
+			assignment.Price,
+			assignment.Deliverables,
+			assignment.DueDate,
+			assignment.Status,
+			assignment.CreatedAt,
+			assignment.UpdatedAt,
+		).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		if isUniqueViolation(err) {
+			return usecasemodels.ErrCampaignBloggerAlreadyExists
+		}
+
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetCampaignBlogger получает участие блогера в кампании и блокирует его до конца транзакции.
+func (r *Repository) GetCampaignBlogger(ctx context.Context, campaignID, bloggerID string) (*repositorymodels.CampaignBlogger, error) {
+	query, args, err := squirrel.
+		Select(campaignBloggerColumns...).
+		From("campaign_bloggers cb").
+		Where(squirrel.Eq{"cb.campaign_id": campaignID}).
+		Where(squirrel.Eq{"cb.blogger_id": bloggerID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	var assignment repositorymodels.CampaignBlogger
+	if err := r.conn(ctx).QueryRow(ctx, query, args...).Scan(campaignBloggerFields(&assignment)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan campaign blogger: %w", err)
+	}
+
+	return &assignment, nil
+}
+
+// UpdateCampaignBlogger сохраняет условия, ссылку на публикацию и статус участия блогера.
+func (r *Repository) UpdateCampaignBlogger(ctx context.Context, assignment *repositorymodels.CampaignBlogger) error {
+	query, args, err := squirrel.
+		Update("campaign_bloggers").
+		Set("price", assignment.Price).
+		Set("deliverables", assignment.Deliverables).
+		Set("publication_url", assignment.PublicationURL).
+		Set("due_date", assignment.DueDate).
+		Set("status", assignment.Status).
+		Set("contracted_at", assignment.ContractedAt).
+		Set("published_at", assignment.PublishedAt).
+		Set("paid_at", assignment.PaidAt).
+		Where(squirrel.Eq{"campaign_id": assignment.CampaignID}).
+		Where(squirrel.Eq{"blogger_id": assignment.BloggerID}).
+		Suffix("RETURNING updated_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update query: %w", err)
+	}
+
+	if err := r.conn(ctx).QueryRow(ctx, query, args...).Scan(&assignment.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return usecasemodels.ErrCampaignBloggerNotFound
+		}
+
+		return fmt.Errorf("execute update: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCampaignBlogger убирает блогера из кампании.
+func (r *Repository) DeleteCampaignBlogger(ctx context.Context, campaignID, bloggerID string) error {
+	query, args, err := squirrel.
+		Delete("campaign_bloggers").
+		Where(squirrel.Eq{"campaign_id": campaignID}).
+		Where(squirrel.Eq{"blogger_id": bloggerID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build delete query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("execute delete: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return usecasemodels.ErrCampaignBloggerNotFound
+	}
+
+	return nil
+}
+
+// GetCampaignBloggers получает состав кампании вместе с данными блогеров, включая удаленных,
+// в порядке добавления.
+func (r *Repository) GetCampaignBloggers(ctx context.Context, campaignID string) ([]repositorymodels.CampaignBlogger, error) {
+	query, args, err := squirrel.
+		Select(campaignBloggerColumns...).
+		Columns(prefixColumns("b", bloggerColumns)...).
+		From("campaign_bloggers cb").
+		Join("bloggers b ON b.id = cb.blogger_id").
+		Where(squirrel.Eq{"cb.campaign_id": campaignID}).
+		OrderBy("cb.created_at ASC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var assignments []repositorymodels.CampaignBlogger
+	for rows.Next() {
+		assignment := repositorymodels.CampaignBlogger{Blogger: &repositorymodels.Blogger{}}
+		fields := append(campaignBloggerFields(&assignment), bloggerFields(assignment.Blogger)...)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, fmt.Errorf("scan campaign blogger: %w", err)
+		}
+
+		assignments = append(assignments, assignment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// GetBloggerCampaigns получает участие блогера в неудаленных кампаниях вместе с данными кампаний,
+// начиная с последних по дате начала.
+func (r *Repository) GetBloggerCampaigns(ctx context.Context, bloggerID string) ([]repositorymodels.CampaignBlogger, error) {
+	query, args, err := squirrel.
+		Select(campaignBloggerColumns...).
+		Columns(prefixColumns("c", campaignColumns)...).
+		From("campaign_bloggers cb").
+		Join("campaigns c ON c.id = cb.campaign_id").
+		Where(squirrel.Eq{"cb.blogger_id": bloggerID}).
+		Where(squirrel.Eq{"c.deleted_at": nil}).
+		OrderBy("c.start_date DESC", "c.id ASC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var assignments []repositorymodels.CampaignBlogger
+	for rows.Next() {
+		assignment := repositorymodels.CampaignBlogger{Campaign: &repositorymodels.Campaign{}}
+		fields := append(campaignBloggerFields(&assignment), campaignFields(assignment.Campaign)...)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, fmt.Errorf("scan campaign blogger: %w", err)
+		}
+
+		assignments = append(assignments, assignment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// applyCampaignSort добавляет к запросу сортировку. Неизвестная колонка заменяется на created_at.
+func applyCampaignSort(query squirrel.SelectBuilder, sort, order string) squirrel.SelectBuilder {
+	direction := "ASC"
+	if order == "desc" {
+		direction = "DESC"
+	}
+
+	if _, ok := campaignSortColumns[sort]; !ok {
+		return query.OrderBy("created_at DESC")
+	}
+
+	// id делает порядок стабильным при совпадении значений.
+	return query.OrderBy(fmt.Sprintf("%s %s", sort, direction), "id ASC")
+}
+
+// applyCampaignFilters добавляет к запросу фильтры списка кампаний.
+func applyCampaignFilters(query squirrel.SelectBuilder, filter *usecasemodels.CampaignFilter) squirrel.SelectBuilder {
+	if len(filter.Status) > 0 {
+		query = query.Where(squirrel.Eq{"status": filter.Status})
+	}
+	if filter.Active != "" {
+		query = query.Where("start_date <= ?::date AND end_date >= ?::date", filter.Active, filter.Active)
+	}
+	if filter.Search != "" {
+		query = query.Where(squirrel.ILike{"name": "%" + filter.Search + "%"})
+	}
+
+	return query
+}
+
+// prefixColumns добавляет к колонкам псевдоним таблицы.
+func prefixColumns(alias string, columns []string) []string {
+	prefixed := make([]string, len(columns))
+	for i, column := range columns {
+		prefixed[i] = alias + "." + column
+	}
+
+	return prefixed
+}
+
+// scanCampaign читает строку, выбранную по campaignColumns, в модель кампании.
+func scanCampaign(row pgx.Row) (*repositorymodels.Campaign, error) {
+	var campaign repositorymodels.Campaign
+	if err := row.Scan(campaignFields(&campaign)...); err != nil {
+		return nil, err
+	}
+
+	return &campaign, nil
+}
+
+// campaignFields возвращает поля модели кампании в порядке campaignColumns.
+func campaignFields(campaign *repositorymodels.Campaign) []any {
+	return []any{
+		&campaign.ID,
+		&campaign.Name,
+		&campaign.Budget,
+		&campaign.StartDate,
+		&campaign.EndDate,
+		&campaign.Status,
+		&campaign.CreatedAt,
+		&campaign.UpdatedAt,
+		&campaign.DeletedAt,
+	}
+}
+
+// campaignBloggerFields возвращает поля модели участия в порядке campaignBloggerColumns.
+func campaignBloggerFields(assignment *repositorymodels.CampaignBlogger) []any {
+	return []any{
+		&assignment.CampaignID,
+		&assignment.BloggerID,
+		&assignment.Price,
+		&assignment.Deliverables,
+		&assignment.PublicationURL,
+		&assignment.DueDate,
+		&assignment.Status,
+		&assignment.ContractedAt,
+		&assignment.PublishedAt,
+		&assignment.PaidAt,
+		&assignment.CreatedAt,
+		&assignment.UpdatedAt,
+	}
+}
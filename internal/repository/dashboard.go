@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	repositorymodels "adminkaback/internal/repository/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// RefreshUserStats пересчитывает агрегат user_stats. Чтение агрегата во время пересчета не блокируется.
+func (r *Repository) RefreshUserStats(ctx context.Context) error {
+	if _, err := r.conn(ctx).Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY user_stats"); err != nil {
+		return fmt.Errorf("refresh user stats: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserStats получает строки агрегата user_stats.
+func (r *Repository) GetUserStats(ctx context.Context) ([]repositorymodels.UserStats, error) {
+	query := squirrel.Select(
+		"status", "role", "is_email_verified", "total",
+		"new_1d", "previous_1d", "new_7d", "previous_7d", "new_30d", "previous_30d", "refreshed_at",
+	).
+		From("user_stats").
+		OrderBy("status", "role", "is_email_verified")
+
+	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []repositorymodels.UserStats
+	for rows.Next() {
+		var s repositorymodels.UserStats
+		if err := rows.Scan(
+			&s.Status, &s.Role, &s.IsEmailVerified, &s.Total,
+			&s.New1d, &s.Previous1d, &s.New7d, &s.Previous7d, &s.New30d, &s.Previous30d, &s.RefreshedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan user stats: %w", err)
+		}
+
+		stats = append(stats, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// CreateEmailVerification сохраняет отправку письма подтверждения email.
+// Адрес нужен только для истории отправок, поэтому с мастер-ключом сохраняется его маска.
+func (r *Repository) CreateEmailVerification(ctx context.Context, verification *repositorymodels.EmailVerification) error {
+	email := maskPIICopy(r.cipher, usecasemodels.UserPIIFieldEmail, verification.Email)
+
+	query, args, err := squirrel.
+		Insert("email_verifications").
+		Columns("id", "user_id", "email", "admin_id", "created_at").
+		Values(verification.ID, verification.UserID, email, verification.AdminID, verification.CreatedAt).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetEmailVerificationStats возвращает количество писем подтверждения, отправленных
+// пользователю начиная с since, и время последней отправки (nil, если писем не было).
+func (r *Repository) GetEmailVerificationStats(ctx context.Context, userID string, since time.Time) (int, *time.Time, error) {
+	query, args, err := squirrel.
+		Select().
+		Column(squirrel.Expr("COUNT(*) FILTER (WHERE created_at >= ?)", since)).
+		Column("MAX(created_at)").
+		From("email_verifications").
+		Where(squirrel.Eq{"user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	var count int
+	var lastSentAt *time.Time
+	if err := r.conn(ctx).QueryRow(ctx, query, args...).Scan(&count, &lastSentAt); err != nil {
+		return 0, nil, fmt.Errorf("scan email verification stats: %w", err)
+	}
+
+	return count, lastSentAt, nil
+}
+
+// SetUserEmailVerified помечает email пользователя подтвержденным, если он не изменился.
+// Возвращает false, если пользователь не найден или его email отличается от email.
+func (r *Repository) SetUserEmailVerified(ctx context.Context, id, email string) (bool, error) {
+	query, args, err := squirrel.
+		Update("users").
+		Set("is_email_verified", true).
+		Set("updated_at", time.Now()).
+		Where(squirrel.Eq{"id": id}).
+		Where(r.emailMatch(email)).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build update query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("execute update: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
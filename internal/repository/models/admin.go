@@ -22,3 +22,35 @@ type RefreshToken struct {
 	ExpiresAt time.Time
 	CreatedAt time.Time
 }
+
+// AdminPreferences представляет настройки интерфейса администратора в БД.
+// Version увеличивается при каждом сохранении, SchemaVersion — версия формата Preferences.
+type AdminPreferences struct {
+	AdminID       string
+	Version       int
+	SchemaVersion int
+	Preferences   AdminPreferencesDocument
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// AdminPreferencesDocument представляет настройки администратора.
+// Хранится в JSONB колонке preferences.
+type AdminPreferencesDocument struct {
+	Locale   string                           `json:"locale"`
+	Theme    string                           `json:"theme"`
+	Timezone string                           `json:"timezone"`
+	PageSize int                              `json:"page_size"`
+	Tables   map[string]AdminTablePreferences `json:"tables"`
+}
+
+// AdminTablePreferences представляет видимость и порядок колонок таблицы.
+type AdminTablePreferences struct {
+	Columns []AdminTableColumn `json:"columns"`
+}
+
+// AdminTableColumn представляет колонку таблицы в порядке отображения.
+type AdminTableColumn struct {
+	Key     string `json:"key"`
+	Visible bool   `json:"visible"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// UserTimeseriesPoint представляет количество событий в интервале временного ряда.
+// Bucket — начало интервала по часам часового пояса запроса, Key — значение разбивки.
+type UserTimeseriesPoint struct {
+	Bucket time.Time
+	Key    *string
+	Count  int
+}
+
+// UserCohortCount представляет количество пользователей когорты Cohort в периоде Period.
+type UserCohortCount struct {
+	Cohort int
+	Period int
+	Count  int
+}
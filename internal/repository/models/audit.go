@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// AuditEntry представляет запись журнала аудита в БД.
+type AuditEntry struct {
+	Seq          int64
+	ID           string
+	ActorID      *string
+	ActorEmail   *string
+	Action       string
+	ResourceType string
+	ResourceID   *string
+	Outcome      string
+	IP           string
+	UserAgent    string
+	RequestID    string
+	Details      map[string]string
+	PrevHash     string
+	Hash         string
+	CreatedAt    time.Time
+}
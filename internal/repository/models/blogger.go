@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Blogger представляет блогера в БД.
+type Blogger struct {
+	ID        string
+	Name      string
+	Username  string
+	Status    string
+	Tags      []string
+	Number    *string
+	Date      time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// BloggerContribution представляет запись блогера, загруженную администратором
+// из списка в localStorage браузера. Payload хранит исходную запись без изменений.
+type BloggerContribution struct {
+	ID        string
+	BloggerID string
+	AdminID   *string
+	LegacyID  *string
+	Outcome   string
+	Payload   map[string]any
+	CreatedAt time.Time
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Campaign представляет рекламную кампанию в БД. Бюджет хранится в копейках.
+type Campaign struct {
+	ID        string
+	Name      string
+	Budget    int64
+	StartDate time.Time
+	EndDate   time.Time
+	Status    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// CampaignBlogger представляет участие блогера в кампании. Цена хранится в копейках.
+// Campaign и Blogger заполняются при чтении списков участия.
+type CampaignBlogger struct {
+	CampaignID     string
+	BloggerID      string
+	Price          int64
+	Deliverables   *string
+	PublicationURL *string
+	DueDate        *time.Time
+	Status         string
+	ContractedAt   *time.Time
+	PublishedAt    *time.Time
+	PaidAt         *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Campaign       *Campaign
+	Blogger        *Blogger
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// UserStats представляет строку агрегата user_stats: счетчики пользователей
+// с одинаковыми статусом, ролью и подтверждением email.
+type UserStats struct {
+	Status          string
+	Role            string
+	IsEmailVerified bool
+	Total           int
+	New1d           int
+	Previous1d      int
+	New7d           int
+	Previous7d      int
+	New30d          int
+	Previous30d     int
+	RefreshedAt     time.Time
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// EmailVerification представляет отправленное письмо подтверждения email в БД.
+type EmailVerification struct {
+	ID        string
+	UserID    string
+	Email     string
+	AdminID   *string
+	CreatedAt time.Time
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Tag представляет метку пользователей в БД.
+// UsersCount заполняется только при чтении каталога меток.
+type Tag struct {
+	ID          string
+	Name        string
+	Color       string
+	Description *string
+	UsersCount  int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -3,6 +3,8 @@ package models
 import "time"
 
 // User представляет модель пользователя в БД.
+// Attributes — значения пользовательских атрибутов из JSONB колонки attributes.
+// ErasedAt задан у пользователей, персональные данные которых обезличены.
 type User struct {
 	ID              string
 	Email           string
@@ -11,7 +13,9 @@ type User struct {
 	Role            string
 	Status          string
 	IsEmailVerified bool
+	Attributes      map[string]any
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 	DeletedAt       *time.Time
+	ErasedAt        *time.Time
 }
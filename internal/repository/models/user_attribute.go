@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// UserAttributeDefinition представляет описание пользовательского атрибута в БД.
+// Enum и Default хранятся в JSONB колонках enum и default_value.
+type UserAttributeDefinition struct {
+	Key         string
+	Type        string
+	Required    bool
+	Enum        []any
+	Default     any
+	Description *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
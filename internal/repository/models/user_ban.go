@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// UserBan представляет блокировку пользователя в БД.
+// Блокировка активна, пока LiftedAt равен nil. LiftedBy равен nil, если блокировка снята автоматически.
+type UserBan struct {
+	ID         string
+	UserID     string
+	Reason     string
+	Note       *string
+	AdminID    *string
+	BannedAt   time.Time
+	Until      *time.Time
+	LiftedAt   *time.Time
+	LiftedBy   *string
+	LiftReason *string
+}
@@ -0,0 +1,12 @@
+package models
+
+// UserDuplicate представляет пару пользователей, похожих на дубликаты.
+// UserID всегда меньше DuplicateID, поэтому каждая пара встречается один раз.
+type UserDuplicate struct {
+	UserID         string
+	DuplicateID    string
+	EmailMatch     bool
+	PhoneMatch     bool
+	NameSimilarity float64
+	Score          float64
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// UserEmailChange представляет запрос на смену email пользователя, ожидающий подтверждения.
+type UserEmailChange struct {
+	ID          string
+	UserID      string
+	OldEmail    string
+	NewEmail    string
+	AdminID     *string
+	CreatedAt   time.Time
+	ConfirmedAt *time.Time
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// UserHistory представляет версию записи пользователя в БД.
+// MergedFrom задан у версий, перенесенных от пользователя, объединенного с этим.
+type UserHistory struct {
+	ID         string
+	UserID     string
+	Version    int
+	Action     string
+	AdminID    *string
+	RequestID  *string
+	Changes    map[string]UserFieldChange
+	Snapshot   UserSnapshot
+	MergedFrom *string
+	CreatedAt  time.Time
+}
+
+// UserFieldChange представляет изменение одного поля пользователя.
+// Хранится в JSONB колонке changes.
+type UserFieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// UserSnapshot представляет состояние пользователя после изменения.
+// Хранится в JSONB колонке snapshot.
+type UserSnapshot struct {
+	Email           string         `json:"email"`
+	Name            string         `json:"name"`
+	Phone           *string        `json:"phone"`
+	Role            string         `json:"role"`
+	Status          string         `json:"status"`
+	IsEmailVerified bool           `json:"is_email_verified"`
+	Attributes      map[string]any `json:"attributes,omitempty"`
+	DeletedAt       *time.Time     `json:"deleted_at"`
+}
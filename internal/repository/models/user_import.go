@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// UserImportJob представляет модель задачи импорта пользователей в БД.
+type UserImportJob struct {
+	ID            string
+	AdminID       *string
+	Filename      string
+	Format        string
+	Status        string
+	OnDuplicate   string
+	DryRun        bool
+	TotalRows     int
+	ProcessedRows int
+	CreatedCount  int
+	UpdatedCount  int
+	SkippedCount  int
+	ErrorCount    int
+	RowErrors     []UserImportRowError
+	ErrorMessage  *string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	FinishedAt    *time.Time
+}
+
+// UserImportRowError представляет ошибку в строке импортируемого файла.
+// Хранится в JSONB колонке row_errors.
+type UserImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// UserNote представляет заметку администратора о пользователе в БД.
+// ParentID задан у ответов; ответы всегда ссылаются на корневую заметку ветки.
+// Заметка видна всем администраторам, если VisibleToRoles пуст.
+type UserNote struct {
+	ID             string
+	UserID         string
+	ParentID       *string
+	AuthorID       *string
+	Body           string
+	VisibleToRoles []string
+	Mentions       []string
+	PinnedAt       *time.Time
+	PinnedBy       *string
+	EditedAt       *time.Time
+	DeletedAt      *time.Time
+	DeletedBy      *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// UserNoteRevision представляет предыдущую версию текста заметки в БД.
+type UserNoteRevision struct {
+	ID        string
+	NoteID    string
+	Body      string
+	Mentions  []string
+	EditedBy  *string
+	CreatedAt time.Time
+}
@@ -0,0 +1,13 @@
+package models
+
+// UserReencryption представляет результат перешифрования пакета пользователей.
+// LastID — ID последнего просмотренного пользователя, пустой, если пользователей больше нет.
+// HistoryVersions и EmailChanges — количество перешифрованных копий в истории изменений
+// и запросах на смену email.
+type UserReencryption struct {
+	LastID          string
+	Checked         int
+	Updated         int
+	HistoryVersions int
+	EmailChanges    int
+}
@@ -0,0 +1,11 @@
+package models
+
+// UserErasure представляет количество записей, обезличенных при удалении персональных данных пользователя.
+type UserErasure struct {
+	Users           int64
+	HistoryVersions int64
+	Notes           int64
+	NoteRevisions   int64
+	Bans            int64
+	ImportJobs      int64
+}
@@ -2,21 +2,38 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"adminkaback/pkg/fieldcrypt"
+
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Repository содержит подключение к БД и реализует работу с данными.
+// cipher шифрует персональные данные пользователей; без мастер-ключа они хранятся открыто.
 type Repository struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	cipher *fieldcrypt.Cipher
+}
+
+// querier описывает общий набор методов пула и транзакции.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
+// txKey — ключ контекста, под которым хранится активная транзакция.
+type txKey struct{}
+
 // NewRepository создает новый экземпляр Repository.
-func NewRepository(pool *pgxpool.Pool) *Repository {
+func NewRepository(pool *pgxpool.Pool, cipher *fieldcrypt.Cipher) *Repository {
 	return &Repository{
-		pool: pool,
+		pool:   pool,
+		cipher: cipher,
 	}
 }
 
@@ -30,7 +47,52 @@ func (r *Repository) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	return tx, nil
 }
 
+// WithTx выполняет fn в транзакции. Все методы репозитория, вызванные с
+// переданным в fn контекстом, работают внутри этой транзакции.
+// Если fn возвращает ошибку, транзакция откатывается.
+func (r *Repository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		// Уже внутри транзакции — переиспользуем ее.
+		return fn(ctx)
+	}
+
+	tx, err := r.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			return fmt.Errorf("rollback transaction: %w (original error: %w)", rbErr, err)
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// conn возвращает транзакцию из контекста, если она есть, иначе пул.
+func (r *Repository) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+
+	return r.pool
+}
+
 // Close закрывает соединение с БД.
 func (r *Repository) Close() {
 	r.pool.Close()
 }
+
+// isUniqueViolation проверяет, что ошибка вызвана нарушением уникального ограничения.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
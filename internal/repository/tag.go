@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// tagColumns — колонки, из которых собирается модель метки в scanTag.
+var tagColumns = []string{"id", "name", "color", "description", "created_at", "updated_at"}
+
+// CreateTag создает метку.
+func (r *Repository) CreateTag(ctx context.Context, tag *repositorymodels.Tag) error {
+	query, args, err := squirrel.
+		Insert("tags").
+		Columns(tagColumns...).
+		Values(tag.ID, tag.Name, tag.Color, tag.Description, tag.CreatedAt, tag.UpdatedAt).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		if isUniqueViolation(err) {
+			return usecasemodels.ErrTagAlreadyExists
+		}
+
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetTagByID получает метку по ID.
+func (r *Repository) GetTagByID(ctx context.Context, id string) (*repositorymodels.Tag, error) {
+	query, args, err := squirrel.
+		Select(tagColumns...).
+		From("tags").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	tag, err := scanTag(r.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// GetTags получает каталог меток с количеством неудаленных пользователей у каждой метки.
+func (r *Repository) GetTags(ctx context.Context) ([]repositorymodels.Tag, error) {
+	query, args, err := squirrel.
+		Select(tagColumns...).
+		Column(`(SELECT COUNT(*) FROM user_tags ut JOIN users u ON u.id = ut.user_id
+			WHERE ut.tag_id = tags.id AND u.deleted_at IS NULL)`).
+		From("tags").
+		OrderBy("lower(name) ASC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []repositorymodels.Tag
+	for rows.Next() {
+		var tag repositorymodels.Tag
+		err := rows.Scan(
+			&tag.ID,
+			&tag.Name,
+			&tag.Color,
+			&tag.Description,
+			&tag.CreatedAt,
+			&tag.UpdatedAt,
+			&tag.UsersCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan tag: %w", err)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetTagsByIDs получает метки с указанными ID. Несуществующие ID пропускаются.
+func (r *Repository) GetTagsByIDs(ctx context.Context, ids []string) ([]repositorymodels.Tag, error) {
+	query, args, err := squirrel.
+		Select(tagColumns...).
+		From("tags").
+		Where(squirrel.Eq{"id": ids}).
+		OrderBy("lower(name) ASC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []repositorymodels.Tag
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan tag: %w", err)
+		}
+
+		tags = append(tags, *tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return tags, nil
+}
+
+// UpdateTag обновляет название, цвет и описание метки.
+func (r *Repository) UpdateTag(ctx context.Context, tag *repositorymodels.Tag) error {
+	query, args, err := squirrel.
+		Update("tags").
+		Set("name", tag.Name).
+		Set("color", tag.Color).
+		Set("description", tag.Description).
+		Set("updated_at", tag.UpdatedAt).
+		Where(squirrel.Eq{"id": tag.ID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return usecasemodels.ErrTagAlreadyExists
+		}
+
+		return fmt.Errorf("execute update: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return usecasemodels.ErrTagNotFound
+	}
+
+	return nil
+}
+
+// DeleteTag удаляет метку. Назначения метки пользователям удаляются каскадно.
+func (r *Repository) DeleteTag(ctx context.Context, id string) error {
+	query, args, err := squirrel.
+		Delete("tags").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build delete query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("execute delete: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return usecasemodels.ErrTagNotFound
+	}
+
+	return nil
+}
+
+// AddUserTags назначает пользователю метки и возвращает ID меток, которых у него еще не было.
+func (r *Repository) AddUserTags(ctx context.Context, userID string, tagIDs []string, adminID *string) ([]string, error) {
+	now := time.Now()
+	insert := squirrel.
+		Insert("user_tags").
+		Columns("user_id", "tag_id", "admin_id", "created_at")
+	for _, tagID := range tagIDs {
+		insert = insert.Values(userID, tagID, adminID, now)
+	}
+
+	query, args, err := insert.
+		Suffix("ON CONFLICT DO NOTHING RETURNING tag_id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build insert query: %w", err)
+	}
+
+	return r.queryTagIDs(ctx, query, args...)
+}
+
+// RemoveUserTags снимает с пользователя метки и возвращает ID меток, которые у него были.
+func (r *Repository) RemoveUserTags(ctx context.Context, userID string, tagIDs []string) ([]string, error) {
+	query, args, err := squirrel.
+		Delete("user_tags").
+		Where(squirrel.Eq{"user_id": userID}).
+		Where(squirrel.Eq{"tag_id": tagIDs}).
+		Suffix("RETURNING tag_id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build delete query: %w", err)
+	}
+
+	return r.queryTagIDs(ctx, query, args...)
+}
+
+// GetUsersTags получает метки пользователей, сгруппированные по ID пользователя.
+func (r *Repository) GetUsersTags(ctx context.Context, userIDs []string) (map[string][]repositorymodels.Tag, error) {
+	columns := make([]string, 0, len(tagColumns)+1)
+	columns = append(columns, "ut.user_id")
+	for _, column := range tagColumns {
+		columns = append(columns, "t."+column)
+	}
+
+	query, args, err := squirrel.
+		Select(columns...).
+		From("user_tags ut").
+		Join("tags t ON t.id = ut.tag_id").
+		Where(squirrel.Eq{"ut.user_id": userIDs}).
+		OrderBy("lower(t.name) ASC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make(map[string][]repositorymodels.Tag, len(userIDs))
+	for rows.Next() {
+		var userID string
+		var tag repositorymodels.Tag
+		err := rows.Scan(
+			&userID,
+			&tag.ID,
+			&tag.Name,
+			&tag.Color,
+			&tag.Description,
+			&tag.CreatedAt,
+			&tag.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan user tag: %w", err)
+		}
+
+		tags[userID] = append(tags[userID], tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return tags, nil
+}
+
+// queryTagIDs выполняет запрос с RETURNING tag_id и читает ID меток.
+func (r *Repository) queryTagIDs(ctx context.Context, sql string, args ...any) ([]string, error) {
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan tag id: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return ids, nil
+}
+
+// scanTag читает строку, выбранную по tagColumns, в модель метки.
+func scanTag(row pgx.Row) (*repositorymodels.Tag, error) {
+	var tag repositorymodels.Tag
+	err := row.Scan(
+		&tag.ID,
+		&tag.Name,
+		&tag.Color,
+		&tag.Description,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
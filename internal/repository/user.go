@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	repositorymodels "adminkaback/internal/repository/models"
@@ -13,20 +14,54 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// userColumns — колонки, из которых собирается модель пользователя в scanUser.
+// Зашифрованные email и телефон расшифровываются при чтении, без ключа остаются маски из email и phone.
+var userColumns = []string{
+	"id", "email", "name", "phone", "role", "status", "is_email_verified", "attributes",
+	"created_at", "updated_at", "deleted_at", "erased_at", "email_encrypted", "phone_encrypted",
+}
+
 // CreateUser создает нового пользователя в БД.
 func (r *Repository) CreateUser(ctx context.Context, user *repositorymodels.User) error {
+	attributes := user.Attributes
+	if attributes == nil {
+		attributes = map[string]any{}
+	}
+
+	values := map[string]any{
+		"id":                user.ID,
+		"name":              user.Name,
+		"role":              user.Role,
+		"status":            user.Status,
+		"is_email_verified": user.IsEmailVerified,
+		"attributes":        attributes,
+		"created_at":        user.CreatedAt,
+		"updated_at":        user.UpdatedAt,
+	}
+
+	pii, err := userPIIValues(r.cipher, user.ID, &user.Email, user.Phone)
+	if err != nil {
+		return err
+	}
+	for column, value := range pii {
+		values[column] = value
+	}
+
 	query, args, err := squirrel.
 		Insert("users").
-		Columns("id", "email", "name", "phone", "role", "status", "is_email_verified", "created_at", "updated_at").
-		Values(user.ID, user.Email, user.Name, user.Phone, user.Role, user.Status, user.IsEmailVerified, user.CreatedAt, user.UpdatedAt).
+		SetMap(values).
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("build insert query: %w", err)
 	}
 
-	_, err = r.pool.Exec(ctx, query, args...)
+	_, err = r.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return usecasemodels.ErrUserAlreadyExists
+		}
+
 		return fmt.Errorf("execute insert: %w", err)
 	}
 
@@ -36,7 +71,7 @@ func (r *Repository) CreateUser(ctx context.Context, user *repositorymodels.User
 // GetUserByID получает пользователя по ID.
 func (r *Repository) GetUserByID(ctx context.Context, id string) (*repositorymodels.User, error) {
 	query, args, err := squirrel.
-		Select("id", "email", "name", "phone", "role", "status", "is_email_verified", "created_at", "updated_at", "deleted_at").
+		Select(userColumns...).
 		From("users").
 		Where(squirrel.Eq{"id": id}).
 		Where(squirrel.Eq{"deleted_at": nil}).
@@ -46,20 +81,7 @@ func (r *Repository) GetUserByID(ctx context.Context, id string) (*repositorymod
 		return nil, fmt.Errorf("build select query: %w", err)
 	}
 
-	var user repositorymodels.User
-	var deletedAt *time.Time
-	err = r.pool.QueryRow(ctx, query, args...).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Name,
-		&user.Phone,
-		&user.Role,
-		&user.Status,
-		&user.IsEmailVerified,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-		&deletedAt,
-	)
+	user, err := r.scanUser(r.conn(ctx).QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -68,38 +90,24 @@ func (r *Repository) GetUserByID(ctx context.Context, id string) (*repositorymod
 		return nil, fmt.Errorf("scan user: %w", err)
 	}
 
-	user.DeletedAt = deletedAt
-
-	return &user, nil
+	return user, nil
 }
 
-// GetUserByEmail получает пользователя по email.
-func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*repositorymodels.User, error) {
+// GetUserByIDForUpdate получает пользователя по ID и блокирует его строку до конца транзакции.
+func (r *Repository) GetUserByIDForUpdate(ctx context.Context, id string) (*repositorymodels.User, error) {
 	query, args, err := squirrel.
-		Select("id", "email", "name", "phone", "role", "status", "is_email_verified", "created_at", "updated_at", "deleted_at").
+		Select(userColumns...).
 		From("users").
-		Where(squirrel.Eq{"email": email}).
+		Where(squirrel.Eq{"id": id}).
 		Where(squirrel.Eq{"deleted_at": nil}).
+		Suffix("FOR UPDATE").
 		PlaceholderFormat(squirrel.Dollar).
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("build select query: %w", err)
 	}
 
-	var user repositorymodels.User
-	var deletedAt *time.Time
-	err = r.pool.QueryRow(ctx, query, args...).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Name,
-		&user.Phone,
-		&user.Role,
-		&user.Status,
-		&user.IsEmailVerified,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-		&deletedAt,
-	)
+	user, err := r.scanUser(r.conn(ctx).QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -108,71 +116,53 @@ func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*reposit
 		return nil, fmt.Errorf("scan user: %w", err)
 	}
 
-	user.DeletedAt = deletedAt
-
-	return &user, nil
+	return user, nil
 }
 
-// GetUsers получает список пользователей с фильтрацией и пагинацией.
-func (r *Repository) GetUsers(ctx context.Context, req *usecasemodels.GetUsersRequest) ([]repositorymodels.User, int, error) {
-	// Подсчет общего количества
-	countQuery := squirrel.Select("COUNT(*)").From("users").Where(squirrel.Eq{"deleted_at": nil})
-
-	// Применяем фильтры для подсчета
-	if len(req.Status) > 0 {
-		countQuery = countQuery.Where(squirrel.Eq{"status": req.Status})
-	}
-	if len(req.Role) > 0 {
-		countQuery = countQuery.Where(squirrel.Eq{"role": req.Role})
-	}
-	if req.Search != "" {
-		countQuery = countQuery.Where(squirrel.Or{
-			squirrel.ILike{"email": "%" + req.Search + "%"},
-			squirrel.ILike{"name": "%" + req.Search + "%"},
-		})
+// GetUserByEmail получает пользователя по email без учета регистра.
+func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*repositorymodels.User, error) {
+	query, args, err := squirrel.
+		Select(userColumns...).
+		From("users").
+		Where(r.emailMatch(email)).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
 	}
 
-	countSQL, countArgs, err := countQuery.PlaceholderFormat(squirrel.Dollar).ToSql()
+	user, err := r.scanUser(r.conn(ctx).QueryRow(ctx, query, args...))
 	if err != nil {
-		return nil, 0, fmt.Errorf("build count query: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan user: %w", err)
 	}
 
-	var total int
-	err = r.pool.QueryRow(ctx, countSQL, countArgs...).Scan(&total)
+	return user, nil
+}
+
+// GetUsers получает список пользователей с фильтрацией и пагинацией.
+func (r *Repository) GetUsers(ctx context.Context, req *usecasemodels.GetUsersRequest) ([]repositorymodels.User, int, error) {
+	// Подсчет общего количества
+	total, err := r.CountUsers(ctx, &req.UserFilter)
 	if err != nil {
-		return nil, 0, fmt.Errorf("execute count query: %w", err)
+		return nil, 0, err
 	}
 
 	// Запрос данных
 	query := squirrel.
-		Select("id", "email", "name", "phone", "role", "status", "is_email_verified", "created_at", "updated_at", "deleted_at").
+		Select(userColumns...).
 		From("users").
 		Where(squirrel.Eq{"deleted_at": nil})
 
 	// Применяем фильтры
-	if len(req.Status) > 0 {
-		query = query.Where(squirrel.Eq{"status": req.Status})
-	}
-	if len(req.Role) > 0 {
-		query = query.Where(squirrel.Eq{"role": req.Role})
-	}
-	if req.Search != "" {
-		query = query.Where(squirrel.Or{
-			squirrel.ILike{"email": "%" + req.Search + "%"},
-			squirrel.ILike{"name": "%" + req.Search + "%"},
-		})
-	}
+	query = r.applyUserFilters(query, &req.UserFilter)
 
 	// Сортировка
-	if req.Sort != "" {
-		order := "ASC"
-		if req.Order == "desc" {
-			order = "DESC"
-		}
-		query = query.OrderBy(fmt.Sprintf("%s %s", req.Sort, order))
-	} else {
-		query = query.OrderBy("created_at DESC")
-	}
+	query = applyUserSort(query, req.Sort, req.Order)
 
 	// Пагинация
 	if req.Limit > 0 {
@@ -188,7 +178,7 @@ func (r *Repository) GetUsers(ctx context.Context, req *usecasemodels.GetUsersRe
 		return nil, 0, fmt.Errorf("build select query: %w", err)
 	}
 
-	rows, err := r.pool.Query(ctx, sql, args...)
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("execute select query: %w", err)
 	}
@@ -196,26 +186,12 @@ func (r *Repository) GetUsers(ctx context.Context, req *usecasemodels.GetUsersRe
 
 	var users []repositorymodels.User
 	for rows.Next() {
-		var user repositorymodels.User
-		var deletedAt *time.Time
-		err := rows.Scan(
-			&user.ID,
-			&user.Email,
-			&user.Name,
-			&user.Phone,
-			&user.Role,
-			&user.Status,
-			&user.IsEmailVerified,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-			&deletedAt,
-		)
+		user, err := r.scanUser(rows)
 		if err != nil {
 			return nil, 0, fmt.Errorf("scan user: %w", err)
 		}
 
-		user.DeletedAt = deletedAt
-		users = append(users, user)
+		users = append(users, *user)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -225,22 +201,169 @@ func (r *Repository) GetUsers(ctx context.Context, req *usecasemodels.GetUsersRe
 	return users, total, nil
 }
 
+// CountUsers возвращает количество неудаленных пользователей, подходящих под фильтры.
+func (r *Repository) CountUsers(ctx context.Context, filter *usecasemodels.UserFilter) (int, error) {
+	countQuery := squirrel.Select("COUNT(*)").From("users").Where(squirrel.Eq{"deleted_at": nil})
+
+	// Применяем фильтры для подсчета
+	countQuery = r.applyUserFilters(countQuery, filter)
+
+	countSQL, countArgs, err := countQuery.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build count query: %w", err)
+	}
+
+	var total int
+	err = r.conn(ctx).QueryRow(ctx, countSQL, countArgs...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("execute count query: %w", err)
+	}
+
+	return total, nil
+}
+
+// StreamUsers последовательно передает в fn всех пользователей, подходящих под фильтры
+// и сортировку req (пагинация игнорируется). Строки читаются из серверного курсора
+// порциями по batchSize, поэтому потребление памяти не зависит от размера выборки.
+func (r *Repository) StreamUsers(
+	ctx context.Context,
+	req *usecasemodels.GetUsersRequest,
+	batchSize int,
+	fn func(user *repositorymodels.User) error,
+) error {
+	query := squirrel.
+		Select(userColumns...).
+		From("users").
+		Where(squirrel.Eq{"deleted_at": nil})
+
+	query = r.applyUserFilters(query, &req.UserFilter)
+	query = applyUserSort(query, req.Sort, req.Order)
+
+	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("build select query: %w", err)
+	}
+
+	return r.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := r.conn(ctx).Exec(ctx, "DECLARE users_stream NO SCROLL CURSOR FOR "+sql, args...); err != nil {
+			return fmt.Errorf("declare cursor: %w", err)
+		}
+
+		fetchSQL := fmt.Sprintf("FETCH FORWARD %d FROM users_stream", batchSize)
+		for {
+			fetched, err := r.fetchUsers(ctx, fetchSQL, fn)
+			if err != nil {
+				return err
+			}
+
+			if fetched < batchSize {
+				return nil
+			}
+		}
+	})
+}
+
+// fetchUsers выполняет FETCH из курсора и возвращает количество прочитанных строк.
+func (r *Repository) fetchUsers(ctx context.Context, fetchSQL string, fn func(user *repositorymodels.User) error) (int, error) {
+	rows, err := r.conn(ctx).Query(ctx, fetchSQL)
+	if err != nil {
+		return 0, fmt.Errorf("fetch from cursor: %w", err)
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		user, err := r.scanUser(rows)
+		if err != nil {
+			return 0, fmt.Errorf("scan user: %w", err)
+		}
+
+		if err := fn(user); err != nil {
+			return 0, err
+		}
+
+		fetched++
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return fetched, nil
+}
+
+// GetUserIDsByFilter возвращает ID пользователей, подходящих под фильтр.
+// Возвращается не более limit идентификаторов.
+func (r *Repository) GetUserIDsByFilter(ctx context.Context, filter *usecasemodels.UserFilter, limit int) ([]string, error) {
+	query := squirrel.
+		Select("id").
+		From("users").
+		Where(squirrel.Eq{"deleted_at": nil})
+
+	query = r.applyUserFilters(query, filter)
+	query = query.OrderBy("created_at ASC")
+
+	if limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+
+	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan user id: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return ids, nil
+}
+
 // UpdateUser обновляет данные пользователя.
 func (r *Repository) UpdateUser(ctx context.Context, id string, user *repositorymodels.User) error {
 	query := squirrel.Update("users").Where(squirrel.Eq{"id": id}).Where(squirrel.Eq{"deleted_at": nil})
 
+	var email *string
+	if user.Email != "" {
+		// Подтверждение email сбрасывается триггером при смене адреса.
+		email = &user.Email
+	}
+
+	pii, err := userPIIValues(r.cipher, id, email, user.Phone)
+	if err != nil {
+		return err
+	}
+	query = query.SetMap(pii)
+
 	if user.Name != "" {
 		query = query.Set("name", user.Name)
 	}
-	if user.Phone != nil {
-		query = query.Set("phone", user.Phone)
-	}
 	if user.Role != "" {
 		query = query.Set("role", user.Role)
 	}
 	if user.Status != "" {
 		query = query.Set("status", user.Status)
 	}
+	if user.Attributes != nil {
+		// Атрибуты заменяются целиком: слияние с текущими значениями выполняется в usecase.
+		query = query.Set("attributes", user.Attributes)
+	}
 	query = query.Set("updated_at", time.Now())
 
 	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
@@ -248,8 +371,12 @@ func (r *Repository) UpdateUser(ctx context.Context, id string, user *repository
 		return fmt.Errorf("build update query: %w", err)
 	}
 
-	result, err := r.pool.Exec(ctx, sql, args...)
+	result, err := r.conn(ctx).Exec(ctx, sql, args...)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return usecasemodels.ErrUserAlreadyExists
+		}
+
 		return fmt.Errorf("execute update: %w", err)
 	}
 
@@ -260,6 +387,26 @@ func (r *Repository) UpdateUser(ctx context.Context, id string, user *repository
 	return nil
 }
 
+// RevokeUserSessions отзывает сессии пользователя: сессии, выданные до sessions_revoked_at,
+// приложение пользователей считает недействительными.
+func (r *Repository) RevokeUserSessions(ctx context.Context, id string) error {
+	query, args, err := squirrel.
+		Update("users").
+		Set("sessions_revoked_at", time.Now()).
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("execute update: %w", err)
+	}
+
+	return nil
+}
+
 // DeleteUser выполняет soft delete пользователя.
 func (r *Repository) DeleteUser(ctx context.Context, id string) error {
 	query, args, err := squirrel.
@@ -273,7 +420,7 @@ func (r *Repository) DeleteUser(ctx context.Context, id string) error {
 		return fmt.Errorf("build delete query: %w", err)
 	}
 
-	result, err := r.pool.Exec(ctx, query, args...)
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("execute delete: %w", err)
 	}
@@ -284,3 +431,112 @@ func (r *Repository) DeleteUser(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// userSortColumns — колонки, по которым разрешена сортировка списка пользователей.
+var userSortColumns = map[string]struct{}{
+	"email":      {},
+	"name":       {},
+	"role":       {},
+	"status":     {},
+	"created_at": {},
+	"updated_at": {},
+}
+
+// applyUserSort добавляет к запросу сортировку. Неизвестная колонка заменяется на created_at.
+// Сортировка по атрибуту задается как attributes.<key>; пользователи без атрибута идут последними.
+func applyUserSort(query squirrel.SelectBuilder, sort, order string) squirrel.SelectBuilder {
+	direction := "ASC"
+	if order == "desc" {
+		direction = "DESC"
+	}
+
+	if key, ok := strings.CutPrefix(sort, usecasemodels.UserAttributeSortPrefix); ok && key != "" {
+		return query.OrderByClause(fmt.Sprintf("attributes -> ?::text %s NULLS LAST", direction), key)
+	}
+
+	if _, ok := userSortColumns[sort]; !ok {
+		return query.OrderBy("created_at DESC")
+	}
+
+	return query.OrderBy(fmt.Sprintf("%s %s", sort, direction))
+}
+
+// applyUserFilters добавляет к запросу общие фильтры списка пользователей.
+func (r *Repository) applyUserFilters(query squirrel.SelectBuilder, filter *usecasemodels.UserFilter) squirrel.SelectBuilder {
+	if len(filter.Status) > 0 {
+		query = query.Where(squirrel.Eq{"status": filter.Status})
+	}
+	if len(filter.Role) > 0 {
+		query = query.Where(squirrel.Eq{"role": filter.Role})
+	}
+	if filter.IsEmailVerified != nil {
+		query = query.Where(squirrel.Eq{"is_email_verified": *filter.IsEmailVerified})
+	}
+	if filter.AttributesJSON != "" {
+		query = query.Where("attributes @> ?::jsonb", filter.AttributesJSON)
+	}
+	if len(filter.Tags) > 0 {
+		if filter.TagsMode == usecasemodels.TagsModeAll {
+			query = query.Where(
+				"(SELECT COUNT(*) FROM user_tags ut WHERE ut.user_id = users.id AND ut.tag_id = ANY(?::uuid[])) = ?",
+				filter.Tags, len(filter.Tags),
+			)
+		} else {
+			query = query.Where(
+				"EXISTS (SELECT 1 FROM user_tags ut WHERE ut.user_id = users.id AND ut.tag_id = ANY(?::uuid[]))",
+				filter.Tags,
+			)
+		}
+	}
+	if filter.Search != "" {
+		search := squirrel.Or{
+			squirrel.ILike{"email": "%" + filter.Search + "%"},
+			squirrel.ILike{"name": "%" + filter.Search + "%"},
+		}
+		// Зашифрованные значения находятся только по полному совпадению через слепой индекс.
+		if strings.Contains(filter.Search, "@") && r.cipher.CanIndex() {
+			search = append(search, squirrel.Eq{"email_index": r.cipher.BlindIndex(userEmailIndexDomain, normalizeIndexedEmail(filter.Search))})
+		}
+		if filter.PhoneDigits != "" {
+			search = append(search, squirrel.Expr("regexp_replace(phone, '[^0-9]', '', 'g') LIKE ?", "%"+filter.PhoneDigits+"%"))
+			if r.cipher.CanIndex() {
+				search = append(search, squirrel.Eq{"phone_index": r.cipher.BlindIndex(userPhoneIndexDomain, "+"+filter.PhoneDigits)})
+			}
+		}
+
+		query = query.Where(search)
+	}
+
+	return query
+}
+
+// scanUser читает строку, выбранную по userColumns, в модель пользователя.
+func (r *Repository) scanUser(row pgx.Row) (*repositorymodels.User, error) {
+	var user repositorymodels.User
+	var emailEncrypted, phoneEncrypted []byte
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Name,
+		&user.Phone,
+		&user.Role,
+		&user.Status,
+		&user.IsEmailVerified,
+		&user.Attributes,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.DeletedAt,
+		&user.ErasedAt,
+		&emailEncrypted,
+		&phoneEncrypted,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.decryptUserPII(&user, emailEncrypted, phoneEncrypted); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
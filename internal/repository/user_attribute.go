@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// userAttributeColumns — колонки, из которых собирается описание атрибута в scanUserAttributeDefinition.
+var userAttributeColumns = []string{"key", "type", "required", "enum", "default_value", "description", "created_at", "updated_at"}
+
+// CreateUserAttributeDefinition создает описание пользовательского атрибута.
+func (r *Repository) CreateUserAttributeDefinition(ctx context.Context, definition *repositorymodels.UserAttributeDefinition) error {
+	defaultValue, err := jsonValue(definition.Default)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := squirrel.
+		Insert("user_attribute_definitions").
+		Columns(userAttributeColumns...).
+		Values(
+			definition.Key,
+			definition.Type,
+			definition.Required,
+			definition.Enum,
+			defaultValue,
+			definition.Description,
+			definition.CreatedAt,
+			definition.UpdatedAt,
+		).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		if isUniqueViolation(err) {
+			return usecasemodels.ErrUserAttributeAlreadyExists
+		}
+
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserAttributeDefinition получает описание атрибута по ключу.
+func (r *Repository) GetUserAttributeDefinition(ctx context.Context, key string) (*repositorymodels.UserAttributeDefinition, error) {
+	query, args, err := squirrel.
+		Select(userAttributeColumns...).
+		From("user_attribute_definitions").
+		Where(squirrel.Eq{"key": key}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	definition, err := scanUserAttributeDefinition(r.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan user attribute definition: %w", err)
+	}
+
+	return definition, nil
+}
+
+// GetUserAttributeDefinitions получает описания всех атрибутов, упорядоченные по ключу.
+func (r *Repository) GetUserAttributeDefinitions(ctx context.Context) ([]repositorymodels.UserAttributeDefinition, error) {
+	query, args, err := squirrel.
+		Select(userAttributeColumns...).
+		From("user_attribute_definitions").
+		OrderBy("key ASC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var definitions []repositorymodels.UserAttributeDefinition
+	for rows.Next() {
+		definition, err := scanUserAttributeDefinition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user attribute definition: %w", err)
+		}
+
+		definitions = append(definitions, *definition)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return definitions, nil
+}
+
+// UpdateUserAttributeDefinition обновляет описание атрибута. Ключ и тип не меняются.
+func (r *Repository) UpdateUserAttributeDefinition(ctx context.Context, definition *repositorymodels.UserAttributeDefinition) error {
+	defaultValue, err := jsonValue(definition.Default)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := squirrel.
+		Update("user_attribute_definitions").
+		Set("required", definition.Required).
+		Set("enum", definition.Enum).
+		Set("default_value", defaultValue).
+		Set("description", definition.Description).
+		Set("updated_at", definition.UpdatedAt).
+		Where(squirrel.Eq{"key": definition.Key}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("execute update: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return usecasemodels.ErrUserAttributeNotFound
+	}
+
+	return nil
+}
+
+// DeleteUserAttributeDefinition удаляет описание атрибута.
+func (r *Repository) DeleteUserAttributeDefinition(ctx context.Context, key string) error {
+	query, args, err := squirrel.
+		Delete("user_attribute_definitions").
+		Where(squirrel.Eq{"key": key}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build delete query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("execute delete: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return usecasemodels.ErrUserAttributeNotFound
+	}
+
+	return nil
+}
+
+// SetMissingUserAttribute записывает значение атрибута пользователям, у которых его нет.
+// Дата изменения пользователей не обновляется.
+func (r *Repository) SetMissingUserAttribute(ctx context.Context, key string, value any) (int64, error) {
+	encoded, err := jsonValue(value)
+	if err != nil {
+		return 0, err
+	}
+
+	query, args, err := squirrel.
+		Update("users").
+		Set("attributes", squirrel.Expr("attributes || jsonb_build_object(?::text, ?::jsonb)", key, encoded)).
+		Where("attributes -> ?::text IS NULL", key).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build update query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("execute update: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// RemoveUserAttribute удаляет значение атрибута у всех пользователей.
+// Дата изменения пользователей не обновляется.
+func (r *Repository) RemoveUserAttribute(ctx context.Context, key string) (int64, error) {
+	query, args, err := squirrel.
+		Update("users").
+		Set("attributes", squirrel.Expr("attributes - ?::text", key)).
+		Where("attributes -> ?::text IS NOT NULL", key).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build update query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("execute update: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// jsonValue кодирует значение JSONB колонки. pgx передает строки в JSONB как готовый JSON,
+// поэтому скалярные значения кодируются заранее. nil сохраняется как NULL.
+func jsonValue(v any) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal json value: %w", err)
+	}
+
+	s := string(encoded)
+
+	return &s, nil
+}
+
+// scanUserAttributeDefinition читает строку, выбранную по userAttributeColumns, в описание атрибута.
+func scanUserAttributeDefinition(row pgx.Row) (*repositorymodels.UserAttributeDefinition, error) {
+	var definition repositorymodels.UserAttributeDefinition
+	err := row.Scan(
+		&definition.Key,
+		&definition.Type,
+		&definition.Required,
+		&definition.Enum,
+		&definition.Default,
+		&definition.Description,
+		&definition.CreatedAt,
+		&definition.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &definition, nil
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// userBanColumns — колонки, из которых собирается модель блокировки в scanUserBan.
+var userBanColumns = []string{"id", "user_id", "reason", "note", "admin_id", "banned_at", "until", "lifted_at", "lifted_by", "lift_reason"}
+
+// CreateUserBan создает блокировку пользователя.
+func (r *Repository) CreateUserBan(ctx context.Context, ban *repositorymodels.UserBan) error {
+	query, args, err := squirrel.
+		Insert("user_bans").
+		Columns("id", "user_id", "reason", "note", "admin_id", "banned_at", "until").
+		Values(ban.ID, ban.UserID, ban.Reason, ban.Note, ban.AdminID, ban.BannedAt, ban.Until).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		if isUniqueViolation(err) {
+			return usecasemodels.ErrUserAlreadyBanned
+		}
+
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveUserBan получает активную блокировку пользователя и блокирует ее до конца транзакции.
+func (r *Repository) GetActiveUserBan(ctx context.Context, userID string) (*repositorymodels.UserBan, error) {
+	query, args, err := squirrel.
+		Select(userBanColumns...).
+		From("user_bans").
+		Where(squirrel.Eq{"user_id": userID}).
+		Where(squirrel.Eq{"lifted_at": nil}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	ban, err := scanUserBan(r.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan user ban: %w", err)
+	}
+
+	return ban, nil
+}
+
+// LiftUserBan снимает блокировку. Данные о снятии берутся из ban.
+func (r *Repository) LiftUserBan(ctx context.Context, ban *repositorymodels.UserBan) error {
+	query, args, err := squirrel.
+		Update("user_bans").
+		Set("lifted_at", ban.LiftedAt).
+		Set("lifted_by", ban.LiftedBy).
+		Set("lift_reason", ban.LiftReason).
+		Where(squirrel.Eq{"id": ban.ID}).
+		Where(squirrel.Eq{"lifted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("execute update: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserBans получает все блокировки пользователя, начиная с последней.
+func (r *Repository) GetUserBans(ctx context.Context, userID string) ([]repositorymodels.UserBan, error) {
+	query, args, err := squirrel.
+		Select(userBanColumns...).
+		From("user_bans").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("banned_at DESC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	return r.queryUserBans(ctx, query, args...)
+}
+
+// GetExpiredUserBans получает до limit активных блокировок, срок которых истек к now.
+// Строки блокируются, а уже заблокированные другим экземпляром сервиса пропускаются.
+func (r *Repository) GetExpiredUserBans(ctx context.Context, now time.Time, limit int) ([]repositorymodels.UserBan, error) {
+	query, args, err := squirrel.
+		Select(userBanColumns...).
+		From("user_bans").
+		Where(squirrel.Eq{"lifted_at": nil}).
+		Where(squirrel.LtOrEq{"until": now}).
+		OrderBy("until ASC").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	return r.queryUserBans(ctx, query, args...)
+}
+
+// queryUserBans выполняет запрос и читает блокировки.
+func (r *Repository) queryUserBans(ctx context.Context, sql string, args ...any) ([]repositorymodels.UserBan, error) {
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var bans []repositorymodels.UserBan
+	for rows.Next() {
+		ban, err := scanUserBan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user ban: %w", err)
+		}
+
+		bans = append(bans, *ban)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return bans, nil
+}
+
+// scanUserBan читает строку, выбранную по userBanColumns, в модель блокировки.
+func scanUserBan(row pgx.Row) (*repositorymodels.UserBan, error) {
+	var ban repositorymodels.UserBan
+	err := row.Scan(
+		&ban.ID,
+		&ban.UserID,
+		&ban.Reason,
+		&ban.Note,
+		&ban.AdminID,
+		&ban.BannedAt,
+		&ban.Until,
+		&ban.LiftedAt,
+		&ban.LiftedBy,
+		&ban.LiftReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ban, nil
+}
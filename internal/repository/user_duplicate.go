@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// userDuplicateScore — оценка пары от 0 до 1: вероятность того, что хотя бы один признак указывает на дубликат.
+// Совпадение email весит 0.9, телефона — 0.8, сходство имен — до 0.7.
+const userDuplicateScore = `(1 - (1 - CASE WHEN email_match THEN 0.9 ELSE 0 END)
+	* (1 - CASE WHEN phone_match THEN 0.8 ELSE 0 END)
+	* (1 - 0.7 * name_similarity))::float8`
+
+// userEmailKeyExpr возвращает выражение нормализованного email пользователя с псевдонимом alias:
+// слепой индекс для зашифрованного адреса, иначе user_email_key. Совпадает с индексом idx_users_email_key.
+func userEmailKeyExpr(alias string) string {
+	return "COALESCE(" + alias + ".email_key_index, user_email_key(" + alias + ".email))"
+}
+
+// userPhoneKeyExpr возвращает выражение телефона пользователя с псевдонимом alias для сравнения:
+// слепой индекс для зашифрованного номера, иначе цифры номера. Совпадает с индексом idx_users_phone_key.
+func userPhoneKeyExpr(alias string) string {
+	return "COALESCE(" + alias + ".phone_index, regexp_replace(" + alias + ".phone, '[^0-9]', '', 'g'))"
+}
+
+// FindUserDuplicates находит пары неудаленных пользователей с совпадающим нормализованным email,
+// цифрами телефона или похожими именами и возвращает их по убыванию оценки.
+func (r *Repository) FindUserDuplicates(ctx context.Context, req *usecasemodels.FindUserDuplicatesRequest) ([]repositorymodels.UserDuplicate, int, error) {
+	pairs := squirrel.
+		Select(
+			"a.id AS user_id",
+			"b.id AS duplicate_id",
+			userEmailKeyExpr("a")+" = "+userEmailKeyExpr("b")+" AS email_match",
+			"COALESCE("+userPhoneKeyExpr("a")+" = "+userPhoneKeyExpr("b")+", false) AS phone_match",
+			"similarity(lower(a.name), lower(b.name))::float8 AS name_similarity",
+		).
+		From("users a").
+		Join("users b ON a.id < b.id AND b.deleted_at IS NULL AND (" +
+			userEmailKeyExpr("a") + " = " + userEmailKeyExpr("b") +
+			" OR " + userPhoneKeyExpr("a") + " = " + userPhoneKeyExpr("b") +
+			" OR lower(a.name) % lower(b.name))").
+		Where(squirrel.Eq{"a.deleted_at": nil})
+
+	if req.UserID != "" {
+		pairs = pairs.Where(squirrel.Or{squirrel.Eq{"a.id": req.UserID}, squirrel.Eq{"b.id": req.UserID}})
+	}
+
+	scored := squirrel.
+		Select("*", userDuplicateScore+" AS score").
+		FromSelect(pairs, "pairs")
+
+	countSQL, countArgs, err := squirrel.
+		Select("COUNT(*)").
+		FromSelect(scored, "scored").
+		Where("score >= ?", req.MinScore).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build count query: %w", err)
+	}
+
+	var total int
+	if err := r.conn(ctx).QueryRow(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("execute count query: %w", err)
+	}
+
+	query := squirrel.
+		Select("user_id", "duplicate_id", "email_match", "phone_match", "name_similarity", "score").
+		FromSelect(scored, "scored").
+		Where("score >= ?", req.MinScore).
+		OrderBy("score DESC", "user_id", "duplicate_id")
+
+	if req.Limit > 0 {
+		query = query.Limit(uint64(req.Limit))
+	}
+	if req.Page > 0 && req.Limit > 0 {
+		query = query.Offset(uint64((req.Page - 1) * req.Limit))
+	}
+
+	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var duplicates []repositorymodels.UserDuplicate
+	for rows.Next() {
+		var duplicate repositorymodels.UserDuplicate
+		err := rows.Scan(
+			&duplicate.UserID,
+			&duplicate.DuplicateID,
+			&duplicate.EmailMatch,
+			&duplicate.PhoneMatch,
+			&duplicate.NameSimilarity,
+			&duplicate.Score,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan user duplicate: %w", err)
+		}
+
+		duplicates = append(duplicates, duplicate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return duplicates, total, nil
+}
+
+// GetUsersByIDs получает неудаленных пользователей с указанными ID. Несуществующие ID пропускаются.
+func (r *Repository) GetUsersByIDs(ctx context.Context, ids []string) ([]repositorymodels.User, error) {
+	query, args, err := squirrel.
+		Select(userColumns...).
+		From("users").
+		Where(squirrel.Eq{"id": ids}).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var users []repositorymodels.User
+	for rows.Next() {
+		user, err := r.scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+
+		users = append(users, *user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return users, nil
+}
+
+// MarkUserMerged выполняет soft delete пользователя loserID со ссылкой на сохраненного пользователя survivorID.
+func (r *Repository) MarkUserMerged(ctx context.Context, loserID, survivorID string) error {
+	query, args, err := squirrel.
+		Update("users").
+		Set("deleted_at", time.Now()).
+		Set("merged_into", survivorID).
+		Where(squirrel.Eq{"id": loserID}).
+		Where(squirrel.Eq{"deleted_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("execute update: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return usecasemodels.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// MoveUserRelations переносит метки, заметки и историю пользователя fromID к пользователю toID.
+// Перенесенные версии истории нумеруются после последней версии toID и помечаются merged_from.
+func (r *Repository) MoveUserRelations(ctx context.Context, fromID, toID string) error {
+	tagsSQL, tagsArgs, err := squirrel.
+		Insert("user_tags").
+		Columns("user_id", "tag_id", "admin_id", "created_at").
+		Select(squirrel.
+			Select().
+			Column("?::uuid", toID).
+			Columns("tag_id", "admin_id", "created_at").
+			From("user_tags").
+			Where(squirrel.Eq{"user_id": fromID})).
+		Suffix("ON CONFLICT DO NOTHING").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build tags insert query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, tagsSQL, tagsArgs...); err != nil {
+		return fmt.Errorf("copy user tags: %w", err)
+	}
+
+	deleteTagsSQL, deleteTagsArgs, err := squirrel.
+		Delete("user_tags").
+		Where(squirrel.Eq{"user_id": fromID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build tags delete query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, deleteTagsSQL, deleteTagsArgs...); err != nil {
+		return fmt.Errorf("delete user tags: %w", err)
+	}
+
+	notesSQL, notesArgs, err := squirrel.
+		Update("user_notes").
+		Set("user_id", toID).
+		Where(squirrel.Eq{"user_id": fromID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build notes update query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, notesSQL, notesArgs...); err != nil {
+		return fmt.Errorf("move user notes: %w", err)
+	}
+
+	historySQL, historyArgs, err := squirrel.
+		Update("user_history").
+		Set("user_id", toID).
+		Set("version", squirrel.Expr("version + (SELECT COALESCE(MAX(version), 0) FROM user_history WHERE user_id = ?)", toID)).
+		Set("merged_from", squirrel.Expr("COALESCE(merged_from, ?)", fromID)).
+		Where(squirrel.Eq{"user_id": fromID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build history update query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, historySQL, historyArgs...); err != nil {
+		return fmt.Errorf("move user history: %w", err)
+	}
+
+	return nil
+}
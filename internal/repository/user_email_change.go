@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/fieldcrypt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateUserEmailChange создает запрос на смену email пользователя.
+// С мастер-ключом адреса шифруются, а в old_email и new_email записываются маски.
+func (r *Repository) CreateUserEmailChange(ctx context.Context, change *repositorymodels.UserEmailChange) error {
+	values, err := userEmailChangePIIValues(r.cipher, change.ID, change.OldEmail, change.NewEmail)
+	if err != nil {
+		return err
+	}
+
+	values["id"] = change.ID
+	values["user_id"] = change.UserID
+	values["admin_id"] = change.AdminID
+	values["created_at"] = change.CreatedAt
+
+	query, args, err := squirrel.
+		Insert("user_email_changes").
+		SetMap(values).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserEmailChange получает запрос на смену email по ID и блокирует его до конца транзакции.
+func (r *Repository) GetUserEmailChange(ctx context.Context, id string) (*repositorymodels.UserEmailChange, error) {
+	query, args, err := squirrel.
+		Select("id", "user_id", "old_email", "new_email", "old_email_encrypted", "new_email_encrypted", "admin_id", "created_at", "confirmed_at").
+		From("user_email_changes").
+		Where(squirrel.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	var change repositorymodels.UserEmailChange
+	var oldEmailEncrypted, newEmailEncrypted []byte
+	err = r.conn(ctx).QueryRow(ctx, query, args...).Scan(
+		&change.ID,
+		&change.UserID,
+		&change.OldEmail,
+		&change.NewEmail,
+		&oldEmailEncrypted,
+		&newEmailEncrypted,
+		&change.AdminID,
+		&change.CreatedAt,
+		&change.ConfirmedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan user email change: %w", err)
+	}
+
+	change.OldEmail, err = decryptPIICopy(r.cipher, oldEmailEncrypted, change.OldEmail, userEmailChangeAAD(change.ID, "old_email"), false)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt old email of email change %s: %w", change.ID, err)
+	}
+
+	change.NewEmail, err = decryptPIICopy(r.cipher, newEmailEncrypted, change.NewEmail, userEmailChangeAAD(change.ID, "new_email"), false)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt new email of email change %s: %w", change.ID, err)
+	}
+
+	return &change, nil
+}
+
+// ConfirmUserEmailChange отмечает запрос на смену email подтвержденным.
+func (r *Repository) ConfirmUserEmailChange(ctx context.Context, id string) error {
+	query, args, err := squirrel.
+		Update("user_email_changes").
+		Set("confirmed_at", time.Now()).
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("execute update: %w", err)
+	}
+
+	return nil
+}
+
+// userEmailChangePIIValues возвращает значения колонок адресов запроса на смену email id.
+func userEmailChangePIIValues(cipher *fieldcrypt.Cipher, id, oldEmail, newEmail string) (map[string]any, error) {
+	oldMasked, oldEncrypted, err := encryptPIICopy(cipher, usecasemodels.UserPIIFieldEmail, oldEmail, userEmailChangeAAD(id, "old_email"))
+	if err != nil {
+		return nil, err
+	}
+
+	newMasked, newEncrypted, err := encryptPIICopy(cipher, usecasemodels.UserPIIFieldEmail, newEmail, userEmailChangeAAD(id, "new_email"))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"old_email":           oldMasked,
+		"old_email_encrypted": oldEncrypted,
+		"new_email":           newMasked,
+		"new_email_encrypted": newEncrypted,
+	}, nil
+}
+
+// userEmailChangeAAD возвращает контекст шифрования колонки column запроса на смену email.
+func userEmailChangeAAD(id, column string) string {
+	return "user_email_changes." + column + ":" + id
+}
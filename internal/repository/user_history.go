@@ -0,0 +1,354 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/fieldcrypt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// userHistoryColumns — колонки, из которых собирается модель версии в scanUserHistory.
+var userHistoryColumns = []string{"id", "user_id", "version", "action", "admin_id", "request_id", "changes", "snapshot", "merged_from", "created_at"}
+
+// CreateUserHistory сохраняет новую версию пользователя. Номер версии назначается
+// последовательно для каждого пользователя и записывается в history.Version.
+// С мастер-ключом email и телефон в снимке и изменениях шифруются.
+func (r *Repository) CreateUserHistory(ctx context.Context, history *repositorymodels.UserHistory) error {
+	snapshot, changes, err := encryptUserHistoryPII(r.cipher, history.ID, history.Snapshot, history.Changes)
+	if err != nil {
+		return err
+	}
+
+	// Блокируем строку пользователя, чтобы параллельные изменения не получили одинаковую версию.
+	lockSQL, lockArgs, err := squirrel.
+		Select("1").
+		From("users").
+		Where(squirrel.Eq{"id": history.UserID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build lock query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, lockSQL, lockArgs...); err != nil {
+		return fmt.Errorf("lock user: %w", err)
+	}
+
+	query, args, err := squirrel.
+		Insert("user_history").
+		Columns("id", "user_id", "version", "action", "admin_id", "request_id", "changes", "snapshot", "created_at").
+		Values(
+			history.ID,
+			history.UserID,
+			squirrel.Expr("(SELECT COALESCE(MAX(version), 0) + 1 FROM user_history WHERE user_id = ?)", history.UserID),
+			history.Action,
+			history.AdminID,
+			history.RequestID,
+			changes,
+			snapshot,
+			history.CreatedAt,
+		).
+		Suffix("RETURNING version").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if err := r.conn(ctx).QueryRow(ctx, query, args...).Scan(&history.Version); err != nil {
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserHistory получает версии пользователя, начиная с последней.
+func (r *Repository) GetUserHistory(ctx context.Context, userID string, page, limit int) ([]repositorymodels.UserHistory, int, error) {
+	countSQL, countArgs, err := squirrel.
+		Select("COUNT(*)").
+		From("user_history").
+		Where(squirrel.Eq{"user_id": userID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build count query: %w", err)
+	}
+
+	var total int
+	if err := r.conn(ctx).QueryRow(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("execute count query: %w", err)
+	}
+
+	query := squirrel.
+		Select(userHistoryColumns...).
+		From("user_history").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("version DESC")
+
+	if limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+	if page > 0 && limit > 0 {
+		query = query.Offset(uint64((page - 1) * limit))
+	}
+
+	sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []repositorymodels.UserHistory
+	for rows.Next() {
+		history, err := r.scanUserHistory(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan user history: %w", err)
+		}
+
+		versions = append(versions, *history)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return versions, total, nil
+}
+
+// GetUserHistoryVersion получает конкретную версию пользователя.
+func (r *Repository) GetUserHistoryVersion(ctx context.Context, userID string, version int) (*repositorymodels.UserHistory, error) {
+	query, args, err := squirrel.
+		Select(userHistoryColumns...).
+		From("user_history").
+		Where(squirrel.Eq{"user_id": userID, "version": version}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	history, err := r.scanUserHistory(r.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan user history: %w", err)
+	}
+
+	return history, nil
+}
+
+// scanUserHistory читает строку, выбранную по userHistoryColumns, в модель версии
+// и расшифровывает email и телефон.
+func (r *Repository) scanUserHistory(row pgx.Row) (*repositorymodels.UserHistory, error) {
+	var history repositorymodels.UserHistory
+	var snapshot storedUserSnapshot
+	var changes map[string]storedUserFieldChange
+	err := row.Scan(
+		&history.ID,
+		&history.UserID,
+		&history.Version,
+		&history.Action,
+		&history.AdminID,
+		&history.RequestID,
+		&changes,
+		&snapshot,
+		&history.MergedFrom,
+		&history.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	history.Snapshot, history.Changes, err = decryptUserHistoryPII(r.cipher, history.ID, snapshot, changes, false)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt user history %s: %w", history.ID, err)
+	}
+
+	return &history, nil
+}
+
+// storedUserSnapshot — снимок версии в том виде, в каком он хранится в колонке snapshot.
+// С мастер-ключом email и телефон заменены масками, а их значения зашифрованы.
+type storedUserSnapshot struct {
+	repositorymodels.UserSnapshot
+	EmailEncrypted []byte `json:"email_encrypted,omitempty"`
+	PhoneEncrypted []byte `json:"phone_encrypted,omitempty"`
+}
+
+// storedUserFieldChange — изменение поля в том виде, в каком оно хранится в колонке changes.
+type storedUserFieldChange struct {
+	repositorymodels.UserFieldChange
+	OldEncrypted []byte `json:"old_encrypted,omitempty"`
+	NewEncrypted []byte `json:"new_encrypted,omitempty"`
+}
+
+// isUserPIIField проверяет, что поле версии содержит email или телефон.
+func isUserPIIField(field string) bool {
+	return field == usecasemodels.UserPIIFieldEmail || field == usecasemodels.UserPIIFieldPhone
+}
+
+// encryptUserHistoryPII возвращает снимок и изменения версии id в том виде, в каком они записываются в БД.
+func encryptUserHistoryPII(
+	cipher *fieldcrypt.Cipher,
+	id string,
+	snapshot repositorymodels.UserSnapshot,
+	changes map[string]repositorymodels.UserFieldChange,
+) (storedUserSnapshot, map[string]storedUserFieldChange, error) {
+	stored := storedUserSnapshot{UserSnapshot: snapshot}
+
+	var err error
+	stored.Email, stored.EmailEncrypted, err = encryptPIICopy(cipher, usecasemodels.UserPIIFieldEmail, snapshot.Email, userHistorySnapshotAAD(id, usecasemodels.UserPIIFieldEmail))
+	if err != nil {
+		return storedUserSnapshot{}, nil, err
+	}
+
+	if snapshot.Phone != nil {
+		var phone string
+		phone, stored.PhoneEncrypted, err = encryptPIICopy(cipher, usecasemodels.UserPIIFieldPhone, *snapshot.Phone, userHistorySnapshotAAD(id, usecasemodels.UserPIIFieldPhone))
+		if err != nil {
+			return storedUserSnapshot{}, nil, err
+		}
+		stored.Phone = &phone
+	}
+
+	storedChanges := make(map[string]storedUserFieldChange, len(changes))
+	for field, change := range changes {
+		storedChange := storedUserFieldChange{UserFieldChange: change}
+
+		if isUserPIIField(field) {
+			if value, ok := changeString(change.Old); ok {
+				storedChange.Old, storedChange.OldEncrypted, err = encryptPIICopy(cipher, field, value, userHistoryChangeAAD(id, field, "old"))
+				if err != nil {
+					return storedUserSnapshot{}, nil, err
+				}
+			}
+			if value, ok := changeString(change.New); ok {
+				storedChange.New, storedChange.NewEncrypted, err = encryptPIICopy(cipher, field, value, userHistoryChangeAAD(id, field, "new"))
+				if err != nil {
+					return storedUserSnapshot{}, nil, err
+				}
+			}
+		}
+
+		storedChanges[field] = storedChange
+	}
+
+	return stored, storedChanges, nil
+}
+
+// decryptUserHistoryPII расшифровывает email и телефон в снимке и изменениях версии id.
+// Без strict значения, мастер-ключ которых не задан, остаются масками.
+func decryptUserHistoryPII(
+	cipher *fieldcrypt.Cipher,
+	id string,
+	stored storedUserSnapshot,
+	storedChanges map[string]storedUserFieldChange,
+	strict bool,
+) (repositorymodels.UserSnapshot, map[string]repositorymodels.UserFieldChange, error) {
+	snapshot := stored.UserSnapshot
+
+	var err error
+	snapshot.Email, err = decryptPIICopy(cipher, stored.EmailEncrypted, snapshot.Email, userHistorySnapshotAAD(id, usecasemodels.UserPIIFieldEmail), strict)
+	if err != nil {
+		return repositorymodels.UserSnapshot{}, nil, fmt.Errorf("decrypt email: %w", err)
+	}
+
+	if stored.PhoneEncrypted != nil {
+		phone, err := decryptPIICopy(cipher, stored.PhoneEncrypted, stringValue(snapshot.Phone), userHistorySnapshotAAD(id, usecasemodels.UserPIIFieldPhone), strict)
+		if err != nil {
+			return repositorymodels.UserSnapshot{}, nil, fmt.Errorf("decrypt phone: %w", err)
+		}
+		snapshot.Phone = &phone
+	}
+
+	changes := make(map[string]repositorymodels.UserFieldChange, len(storedChanges))
+	for field, storedChange := range storedChanges {
+		change := storedChange.UserFieldChange
+
+		if storedChange.OldEncrypted != nil {
+			value, _ := changeString(change.Old)
+			if change.Old, err = decryptPIICopy(cipher, storedChange.OldEncrypted, value, userHistoryChangeAAD(id, field, "old"), strict); err != nil {
+				return repositorymodels.UserSnapshot{}, nil, fmt.Errorf("decrypt old %s: %w", field, err)
+			}
+		}
+		if storedChange.NewEncrypted != nil {
+			value, _ := changeString(change.New)
+			if change.New, err = decryptPIICopy(cipher, storedChange.NewEncrypted, value, userHistoryChangeAAD(id, field, "new"), strict); err != nil {
+				return repositorymodels.UserSnapshot{}, nil, fmt.Errorf("decrypt new %s: %w", field, err)
+			}
+		}
+
+		changes[field] = change
+	}
+
+	return snapshot, changes, nil
+}
+
+// userHistoryPIIUpToDate проверяет, что email и телефон версии записаны так, как их записал бы target.
+func userHistoryPIIUpToDate(target *fieldcrypt.Cipher, snapshot storedUserSnapshot, changes map[string]storedUserFieldChange) bool {
+	if !piiCopyUpToDate(target, snapshot.EmailEncrypted, snapshot.Email) ||
+		!piiCopyUpToDate(target, snapshot.PhoneEncrypted, stringValue(snapshot.Phone)) {
+		return false
+	}
+
+	for field, change := range changes {
+		if !isUserPIIField(field) {
+			continue
+		}
+
+		oldValue, _ := changeString(change.Old)
+		newValue, _ := changeString(change.New)
+		if !piiCopyUpToDate(target, change.OldEncrypted, oldValue) || !piiCopyUpToDate(target, change.NewEncrypted, newValue) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// changeString возвращает строковое значение изменения: string или непустой *string.
+func changeString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case *string:
+		if v != nil {
+			return *v, true
+		}
+	}
+
+	return "", false
+}
+
+// stringValue возвращает значение указателя или пустую строку.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+// userHistorySnapshotAAD возвращает контекст шифрования поля снимка версии.
+func userHistorySnapshotAAD(id, field string) string {
+	return "user_history.snapshot." + field + ":" + id
+}
+
+// userHistoryChangeAAD возвращает контекст шифрования значения side ("old" или "new") изменения поля версии.
+func userHistoryChangeAAD(id, field, side string) string {
+	return "user_history.changes." + field + "." + side + ":" + id
+}
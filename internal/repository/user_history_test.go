@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	"adminkaback/pkg/fieldcrypt"
+)
+
+func newTestCipher(t *testing.T, masterKeys ...string) *fieldcrypt.Cipher {
+	t.Helper()
+
+	c, err := fieldcrypt.New(masterKeys, "")
+	if err != nil {
+		t.Fatalf("fieldcrypt.New() error = %v", err)
+	}
+
+	return c
+}
+
+func newTestKey(t *testing.T) string {
+	t.Helper()
+
+	key, err := fieldcrypt.GenerateKey()
+	if err != nil {
+		t.Fatalf("fieldcrypt.GenerateKey() error = %v", err)
+	}
+
+	return key
+}
+
+func TestUserHistoryPIIRoundTrip(t *testing.T) {
+	phone := "+79123456789"
+	oldEmail := "old@example.com"
+	snapshot := repositorymodels.UserSnapshot{
+		Email:  "user@example.com",
+		Name:   "User",
+		Phone:  &phone,
+		Role:   "user",
+		Status: "active",
+	}
+	changes := map[string]repositorymodels.UserFieldChange{
+		"email": {Old: oldEmail, New: "user@example.com"},
+		"phone": {Old: nil, New: &phone},
+		"name":  {Old: "Old", New: "User"},
+	}
+	wantChanges := map[string]repositorymodels.UserFieldChange{
+		"email": {Old: oldEmail, New: "user@example.com"},
+		"phone": {Old: nil, New: phone},
+		"name":  {Old: "Old", New: "User"},
+	}
+
+	key := newTestKey(t)
+
+	tests := []struct {
+		name       string
+		cipher     *fieldcrypt.Cipher
+		wantMasked bool
+	}{
+		{name: "without master key", cipher: newTestCipher(t)},
+		{name: "with master key", cipher: newTestCipher(t, key), wantMasked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, storedChanges, err := encryptUserHistoryPII(tt.cipher, "history-1", snapshot, changes)
+			if err != nil {
+				t.Fatalf("encryptUserHistoryPII() error = %v", err)
+			}
+
+			if masked := stored.Email != snapshot.Email; masked != tt.wantMasked {
+				t.Fatalf("stored email = %q, masked %v, want %v", stored.Email, masked, tt.wantMasked)
+			}
+			if masked := storedChanges["email"].Old != oldEmail; masked != tt.wantMasked {
+				t.Fatalf("stored old email = %v, masked %v, want %v", storedChanges["email"].Old, masked, tt.wantMasked)
+			}
+			if storedChanges["name"].OldEncrypted != nil {
+				t.Fatalf("name change is encrypted")
+			}
+
+			if !userHistoryPIIUpToDate(tt.cipher, stored, storedChanges) {
+				t.Fatalf("userHistoryPIIUpToDate() = false right after encryption")
+			}
+
+			gotSnapshot, gotChanges, err := decryptUserHistoryPII(tt.cipher, "history-1", stored, storedChanges, true)
+			if err != nil {
+				t.Fatalf("decryptUserHistoryPII() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(gotSnapshot, snapshot) {
+				t.Fatalf("decrypted snapshot = %+v, want %+v", gotSnapshot, snapshot)
+			}
+			if !reflect.DeepEqual(gotChanges, wantChanges) {
+				t.Fatalf("decrypted changes = %+v, want %+v", gotChanges, wantChanges)
+			}
+		})
+	}
+}
+
+func TestDecryptUserHistoryPIIWithoutKey(t *testing.T) {
+	snapshot := repositorymodels.UserSnapshot{Email: "user@example.com"}
+	encrypter := newTestCipher(t, newTestKey(t))
+	other := newTestCipher(t, newTestKey(t))
+
+	stored, storedChanges, err := encryptUserHistoryPII(encrypter, "history-1", snapshot, nil)
+	if err != nil {
+		t.Fatalf("encryptUserHistoryPII() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		cipher    *fieldcrypt.Cipher
+		id        string
+		strict    bool
+		wantEmail string
+		wantErr   error
+	}{
+		{name: "missing key returns mask", cipher: other, id: "history-1", wantEmail: stored.Email},
+		{name: "missing key in strict mode", cipher: other, id: "history-1", strict: true, wantErr: fieldcrypt.ErrKeyNotFound},
+		{name: "other history row", cipher: encrypter, id: "history-2", wantErr: fieldcrypt.ErrInvalidCiphertext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := decryptUserHistoryPII(tt.cipher, tt.id, stored, storedChanges, tt.strict)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("decryptUserHistoryPII() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && got.Email != tt.wantEmail {
+				t.Fatalf("decrypted email = %q, want %q", got.Email, tt.wantEmail)
+			}
+		})
+	}
+}
+
+func TestUserHistoryPIIUpToDate(t *testing.T) {
+	oldKey, newKey := newTestKey(t), newTestKey(t)
+	oldCipher := newTestCipher(t, oldKey)
+	rotated := newTestCipher(t, newKey, oldKey)
+	snapshot := repositorymodels.UserSnapshot{Email: "user@example.com"}
+
+	plain, plainChanges, err := encryptUserHistoryPII(newTestCipher(t), "history-1", snapshot, nil)
+	if err != nil {
+		t.Fatalf("encryptUserHistoryPII() error = %v", err)
+	}
+
+	encrypted, encryptedChanges, err := encryptUserHistoryPII(oldCipher, "history-1", snapshot, nil)
+	if err != nil {
+		t.Fatalf("encryptUserHistoryPII() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		target   *fieldcrypt.Cipher
+		snapshot storedUserSnapshot
+		changes  map[string]storedUserFieldChange
+		want     bool
+	}{
+		{name: "plaintext without key", target: newTestCipher(t), snapshot: plain, changes: plainChanges, want: true},
+		{name: "plaintext after enabling encryption", target: oldCipher, snapshot: plain, changes: plainChanges},
+		{name: "encrypted with current key", target: oldCipher, snapshot: encrypted, changes: encryptedChanges, want: true},
+		{name: "encrypted with previous key", target: rotated, snapshot: encrypted, changes: encryptedChanges},
+		{name: "encrypted before decryption", target: newTestCipher(t), snapshot: encrypted, changes: encryptedChanges},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userHistoryPIIUpToDate(tt.target, tt.snapshot, tt.changes); got != tt.want {
+				t.Fatalf("userHistoryPIIUpToDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskImportRowErrors(t *testing.T) {
+	rowErrors := []repositorymodels.UserImportRowError{
+		{Row: 2, Field: "email", Value: "user@example.com", Message: "duplicate"},
+		{Row: 3, Field: "phone", Value: "+79123456789", Message: "invalid"},
+		{Row: 4, Field: "role", Value: "owner", Message: "invalid"},
+		{Row: 5, Message: "empty row"},
+	}
+	want := []repositorymodels.UserImportRowError{
+		{Row: 2, Field: "email", Value: "u***@example.com", Message: "duplicate"},
+		{Row: 3, Field: "phone", Value: "+7********89", Message: "invalid"},
+		{Row: 4, Field: "role", Value: "owner", Message: "invalid"},
+		{Row: 5, Message: "empty row"},
+	}
+
+	got := maskImportRowErrors(rowErrors)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("maskImportRowErrors() = %+v, want %+v", got, want)
+	}
+
+	if rowErrors[0].Value != "user@example.com" {
+		t.Fatalf("maskImportRowErrors() changed its argument")
+	}
+}
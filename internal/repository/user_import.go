@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateUserImportJob создает задачу импорта пользователей.
+func (r *Repository) CreateUserImportJob(ctx context.Context, job *repositorymodels.UserImportJob) error {
+	query, args, err := squirrel.
+		Insert("user_import_jobs").
+		Columns("id", "admin_id", "filename", "format", "status", "on_duplicate", "dry_run", "total_rows", "row_errors", "created_at", "updated_at").
+		Values(job.ID, job.AdminID, job.Filename, job.Format, job.Status, job.OnDuplicate, job.DryRun, job.TotalRows, maskImportRowErrors(job.RowErrors), job.CreatedAt, job.UpdatedAt).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	_, err = r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateUserImportJob сохраняет прогресс и результат задачи импорта.
+// Значения email и телефона в отчете об ошибках сохраняются маскированными.
+func (r *Repository) UpdateUserImportJob(ctx context.Context, job *repositorymodels.UserImportJob) error {
+	query, args, err := squirrel.
+		Update("user_import_jobs").
+		Set("status", job.Status).
+		Set("total_rows", job.TotalRows).
+		Set("processed_rows", job.ProcessedRows).
+		Set("created_count", job.CreatedCount).
+		Set("updated_count", job.UpdatedCount).
+		Set("skipped_count", job.SkippedCount).
+		Set("error_count", job.ErrorCount).
+		Set("row_errors", maskImportRowErrors(job.RowErrors)).
+		Set("error_message", job.ErrorMessage).
+		Set("finished_at", job.FinishedAt).
+		Where(squirrel.Eq{"id": job.ID}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update query: %w", err)
+	}
+
+	_, err = r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("execute update: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserImportJob получает задачу импорта по ID.
+func (r *Repository) GetUserImportJob(ctx context.Context, id string) (*repositorymodels.UserImportJob, error) {
+	query, args, err := squirrel.
+		Select("id", "admin_id", "filename", "format", "status", "on_duplicate", "dry_run", "total_rows", "processed_rows",
+			"created_count", "updated_count", "skipped_count", "error_count", "row_errors", "error_message",
+			"created_at", "updated_at", "finished_at").
+		From("user_import_jobs").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	var job repositorymodels.UserImportJob
+	err = r.conn(ctx).QueryRow(ctx, query, args...).Scan(
+		&job.ID,
+		&job.AdminID,
+		&job.Filename,
+		&job.Format,
+		&job.Status,
+		&job.OnDuplicate,
+		&job.DryRun,
+		&job.TotalRows,
+		&job.ProcessedRows,
+		&job.CreatedCount,
+		&job.UpdatedCount,
+		&job.SkippedCount,
+		&job.ErrorCount,
+		&job.RowErrors,
+		&job.ErrorMessage,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan user import job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// FailUnfinishedUserImportJobs переводит незавершенные задачи импорта в статус failed
+// и возвращает количество таких задач.
+func (r *Repository) FailUnfinishedUserImportJobs(ctx context.Context, message string) (int64, error) {
+	query, args, err := squirrel.
+		Update("user_import_jobs").
+		Set("status", usecasemodels.ImportStatusFailed).
+		Set("error_message", message).
+		Set("finished_at", time.Now()).
+		Where(squirrel.Eq{"status": []string{usecasemodels.ImportStatusPending, usecasemodels.ImportStatusRunning}}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build update query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("execute update: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// maskImportRowErrors возвращает копию отчета об ошибках импорта, в которой значения email
+// и телефона маскированы. Номера строки достаточно, чтобы найти ячейку в файле.
+func maskImportRowErrors(rowErrors []repositorymodels.UserImportRowError) []repositorymodels.UserImportRowError {
+	masked := make([]repositorymodels.UserImportRowError, len(rowErrors))
+	for i, rowError := range rowErrors {
+		if isUserPIIField(rowError.Field) {
+			rowError.Value = maskPIIValue(rowError.Field, rowError.Value)
+		}
+		masked[i] = rowError
+	}
+
+	return masked
+}
+
+// MaskUserImportJobs маскирует открытые значения email и телефона в отчетах об ошибках импорта,
+// сохраненных до включения маскирования. Возвращает количество измененных задач.
+func (r *Repository) MaskUserImportJobs(ctx context.Context) (int, error) {
+	query, args, err := squirrel.
+		Select("id", "row_errors").
+		From("user_import_jobs").
+		Where("jsonb_array_length(row_errors) > 0").
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build select query: %w", err)
+	}
+
+	updated := 0
+	err = r.WithTx(ctx, func(ctx context.Context) error {
+		rows, err := r.conn(ctx).Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("execute select query: %w", err)
+		}
+
+		masked := make(map[string][]repositorymodels.UserImportRowError)
+		for rows.Next() {
+			var id string
+			var rowErrors []repositorymodels.UserImportRowError
+			if err := rows.Scan(&id, &rowErrors); err != nil {
+				rows.Close()
+
+				return fmt.Errorf("scan user import job: %w", err)
+			}
+
+			if maskedErrors := maskImportRowErrors(rowErrors); !slices.Equal(maskedErrors, rowErrors) {
+				masked[id] = maskedErrors
+			}
+		}
+		rows.Close()
+
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("rows error: %w", err)
+		}
+
+		for id, rowErrors := range masked {
+			updateSQL, updateArgs, err := squirrel.
+				Update("user_import_jobs").
+				Set("row_errors", rowErrors).
+				Where(squirrel.Eq{"id": id}).
+				PlaceholderFormat(squirrel.Dollar).
+				ToSql()
+			if err != nil {
+				return fmt.Errorf("build update query: %w", err)
+			}
+
+			if _, err := r.conn(ctx).Exec(ctx, updateSQL, updateArgs...); err != nil {
+				return fmt.Errorf("update user import job %s: %w", id, err)
+			}
+			updated++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
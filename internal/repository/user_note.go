@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	repositorymodels "adminkaback/internal/repository/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// userNoteColumns — колонки, из которых собирается модель заметки в scanUserNote.
+var userNoteColumns = []string{
+	"id", "user_id", "parent_id", "author_id", "body", "visible_to_roles", "mentions",
+	"pinned_at", "pinned_by", "edited_at", "deleted_at", "deleted_by", "created_at", "updated_at",
+}
+
+// CreateUserNote создает заметку о пользователе.
+func (r *Repository) CreateUserNote(ctx context.Context, note *repositorymodels.UserNote) error {
+	query, args, err := squirrel.
+		Insert("user_notes").
+		Columns("id", "user_id", "parent_id", "author_id", "body", "visible_to_roles", "mentions", "created_at", "updated_at").
+		Values(
+			note.ID,
+			note.UserID,
+			note.ParentID,
+			note.AuthorID,
+			note.Body,
+			nonNilStrings(note.VisibleToRoles),
+			nonNilStrings(note.Mentions),
+			note.CreatedAt,
+			note.UpdatedAt,
+		).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserNote получает заметку пользователя по ID, включая удаленные, и блокирует ее до конца транзакции.
+func (r *Repository) GetUserNote(ctx context.Context, userID, id string) (*repositorymodels.UserNote, error) {
+	query, args, err := squirrel.
+		Select(userNoteColumns...).
+		From("user_notes").
+		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.Eq{"user_id": userID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	note, err := scanUserNote(r.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan user note: %w", err)
+	}
+
+	return note, nil
+}
+
+// GetUserNotes получает все заметки пользователя, включая удаленные, в порядке создания.
+func (r *Repository) GetUserNotes(ctx context.Context, userID string) ([]repositorymodels.UserNote, error) {
+	query, args, err := squirrel.
+		Select(userNoteColumns...).
+		From("user_notes").
+		Where(squirrel.Eq{"user_id": userID}).
+		OrderBy("created_at ASC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []repositorymodels.UserNote
+	for rows.Next() {
+		note, err := scanUserNote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user note: %w", err)
+		}
+
+		notes = append(notes, *note)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return notes, nil
+}
+
+// UpdateUserNote сохраняет текст, упоминания, видимость, закрепление и удаление заметки.
+func (r *Repository) UpdateUserNote(ctx context.Context, note *repositorymodels.UserNote) error {
+	query, args, err := squirrel.
+		Update("user_notes").
+		Set("body", note.Body).
+		Set("visible_to_roles", nonNilStrings(note.VisibleToRoles)).
+		Set("mentions", nonNilStrings(note.Mentions)).
+		Set("pinned_at", note.PinnedAt).
+		Set("pinned_by", note.PinnedBy).
+		Set("edited_at", note.EditedAt).
+		Set("deleted_at", note.DeletedAt).
+		Set("deleted_by", note.DeletedBy).
+		Where(squirrel.Eq{"id": note.ID}).
+		Suffix("RETURNING updated_at").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build update query: %w", err)
+	}
+
+	if err := r.conn(ctx).QueryRow(ctx, query, args...).Scan(&note.UpdatedAt); err != nil {
+		return fmt.Errorf("execute update: %w", err)
+	}
+
+	return nil
+}
+
+// CreateUserNoteRevision сохраняет предыдущую версию текста заметки.
+func (r *Repository) CreateUserNoteRevision(ctx context.Context, revision *repositorymodels.UserNoteRevision) error {
+	query, args, err := squirrel.
+		Insert("user_note_revisions").
+		Columns("id", "note_id", "body", "mentions", "edited_by", "created_at").
+		Values(revision.ID, revision.NoteID, revision.Body, nonNilStrings(revision.Mentions), revision.EditedBy, revision.CreatedAt).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build insert query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("execute insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserNoteRevisions получает предыдущие версии заметки, начиная с последней.
+func (r *Repository) GetUserNoteRevisions(ctx context.Context, noteID string) ([]repositorymodels.UserNoteRevision, error) {
+	query, args, err := squirrel.
+		Select("id", "note_id", "body", "mentions", "edited_by", "created_at").
+		From("user_note_revisions").
+		Where(squirrel.Eq{"note_id": noteID}).
+		OrderBy("created_at DESC").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []repositorymodels.UserNoteRevision
+	for rows.Next() {
+		var revision repositorymodels.UserNoteRevision
+		err := rows.Scan(
+			&revision.ID,
+			&revision.NoteID,
+			&revision.Body,
+			&revision.Mentions,
+			&revision.EditedBy,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan user note revision: %w", err)
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// scanUserNote читает строку, выбранную по userNoteColumns, в модель заметки.
+func scanUserNote(row pgx.Row) (*repositorymodels.UserNote, error) {
+	var note repositorymodels.UserNote
+	err := row.Scan(
+		&note.ID,
+		&note.UserID,
+		&note.ParentID,
+		&note.AuthorID,
+		&note.Body,
+		&note.VisibleToRoles,
+		&note.Mentions,
+		&note.PinnedAt,
+		&note.PinnedBy,
+		&note.EditedAt,
+		&note.DeletedAt,
+		&note.DeletedBy,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &note, nil
+}
+
+// nonNilStrings заменяет nil на пустой список, чтобы в NOT NULL колонку-массив не попал NULL.
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
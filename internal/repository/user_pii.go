@@ -0,0 +1,617 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/fieldcrypt"
+	"adminkaback/pkg/pii"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// Контексты шифрования и слепых индексов персональных данных пользователей.
+const (
+	userEmailIndexDomain    = "users.email"
+	userEmailKeyIndexDomain = "users.email_key"
+	userPhoneIndexDomain    = "users.phone"
+)
+
+// gmailDomains — домены, в адресах которых Gmail игнорирует точки.
+var gmailDomains = map[string]bool{"gmail.com": true, "googlemail.com": true}
+
+// plusSuffix — суффикс локальной части адреса после "+".
+var plusSuffix = regexp.MustCompile(`\+.*$`)
+
+// userPIIValues возвращает значения колонок email и телефона для записи. nil оставляет поле
+// без изменений, пустой телефон очищает его. С мастер-ключом в email и phone записываются маски,
+// а значения шифруются и индексируются; без ключа значения записываются открыто.
+func userPIIValues(cipher *fieldcrypt.Cipher, id string, email, phone *string) (map[string]any, error) {
+	values := make(map[string]any, 8)
+
+	if email != nil {
+		values["email"] = *email
+		values["email_encrypted"] = nil
+		values["email_index"] = nil
+		values["email_key_index"] = nil
+
+		if cipher.CanEncrypt() {
+			encrypted, err := cipher.Encrypt(*email, userEmailIndexDomain+":"+id)
+			if err != nil {
+				return nil, fmt.Errorf("encrypt email: %w", err)
+			}
+
+			values["email"] = pii.MaskEmail(*email)
+			values["email_encrypted"] = encrypted
+			values["email_index"] = cipher.BlindIndex(userEmailIndexDomain, normalizeIndexedEmail(*email))
+			values["email_key_index"] = cipher.BlindIndex(userEmailKeyIndexDomain, userEmailKey(*email))
+		}
+	}
+
+	if phone != nil {
+		values["phone"] = nil
+		values["phone_encrypted"] = nil
+		values["phone_index"] = nil
+
+		if *phone != "" {
+			values["phone"] = *phone
+
+			if cipher.CanEncrypt() {
+				encrypted, err := cipher.Encrypt(*phone, userPhoneIndexDomain+":"+id)
+				if err != nil {
+					return nil, fmt.Errorf("encrypt phone: %w", err)
+				}
+
+				values["phone"] = pii.MaskPhone(*phone)
+				values["phone_encrypted"] = encrypted
+				values["phone_index"] = cipher.BlindIndex(userPhoneIndexDomain, *phone)
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// encryptPIICopy шифрует копию email или телефона пользователя, хранящуюся вне таблицы users,
+// и возвращает маску для открытой колонки. Без мастер-ключа значение возвращается открыто.
+func encryptPIICopy(cipher *fieldcrypt.Cipher, field, value, aad string) (string, []byte, error) {
+	if !cipher.CanEncrypt() || value == "" {
+		return value, nil, nil
+	}
+
+	encrypted, err := cipher.Encrypt(value, aad)
+	if err != nil {
+		return "", nil, fmt.Errorf("encrypt %s: %w", field, err)
+	}
+
+	return maskPIICopy(cipher, field, value), encrypted, nil
+}
+
+// decryptPIICopy расшифровывает копию, зашифрованную encryptPIICopy. Незашифрованное значение
+// возвращается как есть; если мастер-ключ значения не задан, возвращается маска,
+// а со strict — ошибка.
+func decryptPIICopy(cipher *fieldcrypt.Cipher, encrypted []byte, value, aad string, strict bool) (string, error) {
+	if encrypted == nil {
+		return value, nil
+	}
+
+	decrypted, err := cipher.Decrypt(encrypted, aad)
+	if err != nil {
+		if errors.Is(err, fieldcrypt.ErrKeyNotFound) && !strict {
+			return value, nil
+		}
+
+		return "", err
+	}
+
+	return decrypted, nil
+}
+
+// maskPIICopy возвращает маску email или телефона, если задан мастер-ключ, и значение как есть без него.
+// Используется для копий, которые не нужно расшифровывать.
+func maskPIICopy(cipher *fieldcrypt.Cipher, field, value string) string {
+	if !cipher.CanEncrypt() {
+		return value
+	}
+
+	return maskPIIValue(field, value)
+}
+
+// maskPIIValue возвращает маску email или телефона.
+func maskPIIValue(field, value string) string {
+	if value == "" {
+		return value
+	}
+
+	if field == usecasemodels.UserPIIFieldPhone {
+		return pii.MaskPhone(value)
+	}
+
+	return pii.MaskEmail(value)
+}
+
+// piiCopyUpToDate проверяет, что копия записана так, как ее записал бы target:
+// зашифрована его текущим мастер-ключом или, без ключа, хранится открыто.
+func piiCopyUpToDate(target *fieldcrypt.Cipher, encrypted []byte, value string) bool {
+	if !target.CanEncrypt() {
+		return encrypted == nil
+	}
+
+	if encrypted == nil {
+		return value == ""
+	}
+
+	keyID, err := fieldcrypt.KeyID(encrypted)
+
+	return err == nil && keyID == target.CurrentKeyID()
+}
+
+// decryptUserPII расшифровывает email и телефон пользователя. Если мастер-ключ значения
+// не задан, в модели остаются маски, сохраненные при шифровании.
+func (r *Repository) decryptUserPII(user *repositorymodels.User, emailEncrypted, phoneEncrypted []byte) error {
+	if emailEncrypted != nil {
+		email, err := r.cipher.Decrypt(emailEncrypted, userEmailIndexDomain+":"+user.ID)
+		switch {
+		case err == nil:
+			user.Email = email
+		case !errors.Is(err, fieldcrypt.ErrKeyNotFound):
+			return fmt.Errorf("decrypt email of user %s: %w", user.ID, err)
+		}
+	}
+
+	if phoneEncrypted != nil {
+		phone, err := r.cipher.Decrypt(phoneEncrypted, userPhoneIndexDomain+":"+user.ID)
+		switch {
+		case err == nil:
+			user.Phone = &phone
+		case !errors.Is(err, fieldcrypt.ErrKeyNotFound):
+			return fmt.Errorf("decrypt phone of user %s: %w", user.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// emailMatch возвращает условие поиска пользователя по email без учета регистра.
+// Зашифрованные адреса сравниваются по слепому индексу: он и открытый адрес покрываются
+// одним индексом по COALESCE(email_index, lower(email)).
+func (r *Repository) emailMatch(email string) squirrel.Sqlizer {
+	if !r.cipher.CanIndex() {
+		return squirrel.Expr("lower(email) = lower(?)", email)
+	}
+
+	return squirrel.Expr(
+		"COALESCE(email_index, lower(email)) IN (?, lower(?))",
+		r.cipher.BlindIndex(userEmailIndexDomain, normalizeIndexedEmail(email)), email,
+	)
+}
+
+// normalizeIndexedEmail приводит email к виду, по которому строится слепой индекс.
+func normalizeIndexedEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// userEmailKey повторяет SQL функцию user_email_key: email в нижнем регистре без +суффикса,
+// для Gmail — без точек.
+func userEmailKey(email string) string {
+	local, domain, _ := strings.Cut(strings.ToLower(email), "@")
+	local = plusSuffix.ReplaceAllString(local, "")
+
+	if gmailDomains[domain] {
+		return strings.ReplaceAll(local, ".", "") + "@gmail.com"
+	}
+
+	return local + "@" + domain
+}
+
+// ReencryptUsers перешифровывает email и телефон до limit пользователей с ID больше afterID
+// текущим мастер-ключом и пересчитывает слепые индексы; открытые значения шифруются.
+// Так же перешифровываются их копии в истории изменений и запросах на смену email, а адреса
+// в истории писем подтверждения маскируются. С decrypt значения, наоборот, записываются открыто
+// (маски в истории писем остаются). Обезличенные пользователи пропускаются.
+// updated_at и подтверждение email не меняются.
+func (r *Repository) ReencryptUsers(ctx context.Context, afterID string, limit int, decrypt bool) (*repositorymodels.UserReencryption, error) {
+	if !decrypt && !r.cipher.CanEncrypt() {
+		return nil, fieldcrypt.ErrEncryptionDisabled
+	}
+
+	cipher := r.cipher
+	if decrypt {
+		// Без шифра userPIIValues записывает значения открыто.
+		cipher = nil
+	}
+
+	query := squirrel.
+		Select("id", "email", "phone", "email_encrypted", "email_index", "phone_encrypted", "phone_index").
+		From("users").
+		Where(squirrel.Eq{"erased_at": nil}).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE")
+
+	if afterID != "" {
+		query = query.Where(squirrel.Gt{"id": afterID})
+	}
+
+	var result repositorymodels.UserReencryption
+
+	err := r.WithTx(ctx, func(ctx context.Context) error {
+		sql, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+		if err != nil {
+			return fmt.Errorf("build select query: %w", err)
+		}
+
+		rows, err := r.conn(ctx).Query(ctx, sql, args...)
+		if err != nil {
+			return fmt.Errorf("execute select query: %w", err)
+		}
+
+		var users []userPIIRow
+		for rows.Next() {
+			var row userPIIRow
+			if err := rows.Scan(&row.id, &row.email, &row.phone, &row.emailEncrypted, &row.emailIndex, &row.phoneEncrypted, &row.phoneIndex); err != nil {
+				rows.Close()
+
+				return fmt.Errorf("scan user: %w", err)
+			}
+
+			users = append(users, row)
+		}
+		rows.Close()
+
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("rows error: %w", err)
+		}
+
+		result.Checked = len(users)
+		if len(users) == 0 {
+			return nil
+		}
+		result.LastID = users[len(users)-1].id
+
+		ids := make([]string, 0, len(users))
+		var changed []map[string]any
+		for i := range users {
+			ids = append(ids, users[i].id)
+
+			values, err := users[i].reencrypt(r.cipher, cipher)
+			if err != nil {
+				return err
+			}
+
+			if values != nil {
+				values["id"] = users[i].id
+				changed = append(changed, values)
+			}
+		}
+
+		if result.HistoryVersions, err = r.reencryptUserHistory(ctx, ids, cipher); err != nil {
+			return err
+		}
+		if result.EmailChanges, err = r.reencryptUserEmailChanges(ctx, ids, cipher); err != nil {
+			return err
+		}
+		if err := r.maskEmailVerifications(ctx, ids, cipher); err != nil {
+			return err
+		}
+
+		if len(changed) == 0 {
+			return nil
+		}
+
+		// Перешифрование не является изменением пользователя: триггеры users пропускаются
+		// до конца транзакции без блокировки всей таблицы.
+		if _, err := r.conn(ctx).Exec(ctx, "SET LOCAL app.skip_user_triggers = 'on'"); err != nil {
+			return fmt.Errorf("skip user triggers: %w", err)
+		}
+
+		for _, values := range changed {
+			id := values["id"]
+			delete(values, "id")
+
+			updateSQL, updateArgs, err := squirrel.
+				Update("users").
+				SetMap(values).
+				Where(squirrel.Eq{"id": id}).
+				PlaceholderFormat(squirrel.Dollar).
+				ToSql()
+			if err != nil {
+				return fmt.Errorf("build update query: %w", err)
+			}
+
+			if _, err := r.conn(ctx).Exec(ctx, updateSQL, updateArgs...); err != nil {
+				return fmt.Errorf("update user %s: %w", id, err)
+			}
+			result.Updated++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// userPIIRow — email и телефон пользователя в том виде, в каком они хранятся.
+type userPIIRow struct {
+	id             string
+	email          string
+	phone          *string
+	emailEncrypted []byte
+	emailIndex     *string
+	phoneEncrypted []byte
+	phoneIndex     *string
+}
+
+// reencrypt расшифровывает значения ключами current и возвращает их колонки, записанные шифром
+// target, или nil, если значения уже записаны им. Значение, мастер-ключ которого не задан,
+// возвращает ошибку: иначе вместо него была бы записана маска.
+func (row *userPIIRow) reencrypt(current, target *fieldcrypt.Cipher) (map[string]any, error) {
+	email := row.email
+	if row.emailEncrypted != nil {
+		decrypted, err := current.Decrypt(row.emailEncrypted, userEmailIndexDomain+":"+row.id)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt email of user %s: %w", row.id, err)
+		}
+		email = decrypted
+	}
+
+	phone := ""
+	if row.phone != nil {
+		phone = *row.phone
+	}
+	if row.phoneEncrypted != nil {
+		decrypted, err := current.Decrypt(row.phoneEncrypted, userPhoneIndexDomain+":"+row.id)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt phone of user %s: %w", row.id, err)
+		}
+		phone = decrypted
+	}
+
+	upToDate := func(encrypted []byte, index *string, domain, value string) bool {
+		if !target.CanEncrypt() {
+			return encrypted == nil
+		}
+
+		keyID, err := fieldcrypt.KeyID(encrypted)
+
+		return err == nil && keyID == target.CurrentKeyID() &&
+			index != nil && *index == target.BlindIndex(domain, value)
+	}
+
+	if upToDate(row.emailEncrypted, row.emailIndex, userEmailIndexDomain, normalizeIndexedEmail(email)) &&
+		(phone == "" || upToDate(row.phoneEncrypted, row.phoneIndex, userPhoneIndexDomain, phone)) {
+		return nil, nil
+	}
+
+	return userPIIValues(target, row.id, &email, &phone)
+}
+
+// reencryptUserHistory перешифровывает email и телефон в версиях пользователей ids шифром target.
+// Возвращает количество измененных версий.
+func (r *Repository) reencryptUserHistory(ctx context.Context, ids []string, target *fieldcrypt.Cipher) (int, error) {
+	query, args, err := squirrel.
+		Select("id", "changes", "snapshot").
+		From("user_history").
+		Where(squirrel.Eq{"user_id": ids}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build history select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("execute history select query: %w", err)
+	}
+
+	type historyRow struct {
+		id       string
+		changes  map[string]storedUserFieldChange
+		snapshot storedUserSnapshot
+	}
+
+	var versions []historyRow
+	for rows.Next() {
+		var row historyRow
+		if err := rows.Scan(&row.id, &row.changes, &row.snapshot); err != nil {
+			rows.Close()
+
+			return 0, fmt.Errorf("scan user history: %w", err)
+		}
+
+		versions = append(versions, row)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	updated := 0
+	for _, row := range versions {
+		if userHistoryPIIUpToDate(target, row.snapshot, row.changes) {
+			continue
+		}
+
+		snapshot, changes, err := decryptUserHistoryPII(r.cipher, row.id, row.snapshot, row.changes, true)
+		if err != nil {
+			return 0, fmt.Errorf("decrypt user history %s: %w", row.id, err)
+		}
+
+		storedSnapshot, storedChanges, err := encryptUserHistoryPII(target, row.id, snapshot, changes)
+		if err != nil {
+			return 0, err
+		}
+
+		updateSQL, updateArgs, err := squirrel.
+			Update("user_history").
+			Set("changes", storedChanges).
+			Set("snapshot", storedSnapshot).
+			Where(squirrel.Eq{"id": row.id}).
+			PlaceholderFormat(squirrel.Dollar).
+			ToSql()
+		if err != nil {
+			return 0, fmt.Errorf("build history update query: %w", err)
+		}
+
+		if _, err := r.conn(ctx).Exec(ctx, updateSQL, updateArgs...); err != nil {
+			return 0, fmt.Errorf("update user history %s: %w", row.id, err)
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// reencryptUserEmailChanges перешифровывает адреса в запросах на смену email пользователей ids
+// шифром target. Возвращает количество измененных запросов.
+func (r *Repository) reencryptUserEmailChanges(ctx context.Context, ids []string, target *fieldcrypt.Cipher) (int, error) {
+	query, args, err := squirrel.
+		Select("id", "old_email", "new_email", "old_email_encrypted", "new_email_encrypted").
+		From("user_email_changes").
+		Where(squirrel.Eq{"user_id": ids}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build email changes select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("execute email changes select query: %w", err)
+	}
+
+	type emailChangeRow struct {
+		id                string
+		oldEmail          string
+		newEmail          string
+		oldEmailEncrypted []byte
+		newEmailEncrypted []byte
+	}
+
+	var changes []emailChangeRow
+	for rows.Next() {
+		var row emailChangeRow
+		if err := rows.Scan(&row.id, &row.oldEmail, &row.newEmail, &row.oldEmailEncrypted, &row.newEmailEncrypted); err != nil {
+			rows.Close()
+
+			return 0, fmt.Errorf("scan user email change: %w", err)
+		}
+
+		changes = append(changes, row)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	updated := 0
+	for _, row := range changes {
+		if piiCopyUpToDate(target, row.oldEmailEncrypted, row.oldEmail) && piiCopyUpToDate(target, row.newEmailEncrypted, row.newEmail) {
+			continue
+		}
+
+		oldEmail, err := decryptPIICopy(r.cipher, row.oldEmailEncrypted, row.oldEmail, userEmailChangeAAD(row.id, "old_email"), true)
+		if err != nil {
+			return 0, fmt.Errorf("decrypt old email of email change %s: %w", row.id, err)
+		}
+
+		newEmail, err := decryptPIICopy(r.cipher, row.newEmailEncrypted, row.newEmail, userEmailChangeAAD(row.id, "new_email"), true)
+		if err != nil {
+			return 0, fmt.Errorf("decrypt new email of email change %s: %w", row.id, err)
+		}
+
+		values, err := userEmailChangePIIValues(target, row.id, oldEmail, newEmail)
+		if err != nil {
+			return 0, err
+		}
+
+		updateSQL, updateArgs, err := squirrel.
+			Update("user_email_changes").
+			SetMap(values).
+			Where(squirrel.Eq{"id": row.id}).
+			PlaceholderFormat(squirrel.Dollar).
+			ToSql()
+		if err != nil {
+			return 0, fmt.Errorf("build email change update query: %w", err)
+		}
+
+		if _, err := r.conn(ctx).Exec(ctx, updateSQL, updateArgs...); err != nil {
+			return 0, fmt.Errorf("update user email change %s: %w", row.id, err)
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// maskEmailVerifications маскирует открытые адреса в истории писем подтверждения пользователей ids,
+// если target шифрует значения.
+func (r *Repository) maskEmailVerifications(ctx context.Context, ids []string, target *fieldcrypt.Cipher) error {
+	if !target.CanEncrypt() {
+		return nil
+	}
+
+	query, args, err := squirrel.
+		Select("id", "email").
+		From("email_verifications").
+		Where(squirrel.Eq{"user_id": ids}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build email verifications select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("execute email verifications select query: %w", err)
+	}
+
+	masked := make(map[string]string)
+	for rows.Next() {
+		var id, email string
+		if err := rows.Scan(&id, &email); err != nil {
+			rows.Close()
+
+			return fmt.Errorf("scan email verification: %w", err)
+		}
+
+		if mask := maskPIICopy(target, usecasemodels.UserPIIFieldEmail, email); mask != email {
+			masked[id] = mask
+		}
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("rows error: %w", err)
+	}
+
+	for id, email := range masked {
+		updateSQL, updateArgs, err := squirrel.
+			Update("email_verifications").
+			Set("email", email).
+			Where(squirrel.Eq{"id": id}).
+			PlaceholderFormat(squirrel.Dollar).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("build email verification update query: %w", err)
+		}
+
+		if _, err := r.conn(ctx).Exec(ctx, updateSQL, updateArgs...); err != nil {
+			return fmt.Errorf("update email verification %s: %w", id, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// erasedUserName — имя, которое получает пользователь после обезличивания.
+const erasedUserName = "Erased user"
+
+// erasedEmail возвращает SQL выражение обезличенного email пользователя с ID из колонки column.
+// Адрес уникален и не может принадлежать реальному получателю (домен .invalid).
+func erasedEmail(column string) string {
+	return "'erased+' || " + column + " || '@erased.invalid'"
+}
+
+// GetUserByIDWithDeleted получает пользователя по ID, в том числе удаленного.
+func (r *Repository) GetUserByIDWithDeleted(ctx context.Context, id string) (*repositorymodels.User, error) {
+	query, args, err := squirrel.
+		Select(userColumns...).
+		From("users").
+		Where(squirrel.Eq{"id": id}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	user, err := r.scanUser(r.conn(ctx).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("scan user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetMergedUsers получает пользователей, объединенных с пользователем id, в том числе через
+// промежуточные объединения, в порядке объединения.
+func (r *Repository) GetMergedUsers(ctx context.Context, id string) ([]repositorymodels.User, error) {
+	columns := make([]string, 0, len(userColumns))
+	for _, column := range userColumns {
+		columns = append(columns, "u."+column)
+	}
+
+	query, args, err := squirrel.
+		Select(columns...).
+		Prefix(`WITH RECURSIVE merged AS (
+			SELECT id FROM users WHERE merged_into = ?
+			UNION
+			SELECT u.id FROM users u JOIN merged m ON u.merged_into = m.id
+		)`, id).
+		From("users u").
+		Join("merged m ON m.id = u.id").
+		OrderBy("u.deleted_at ASC", "u.id").
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	var users []repositorymodels.User
+	for rows.Next() {
+		user, err := r.scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+
+		users = append(users, *user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return users, nil
+}
+
+// EraseUsers необратимо обезличивает персональные данные пользователей с указанными ID.
+// Записи не удаляются: пользователи помечаются удаленными и обезличенными, в истории стираются
+// значения email, имени, телефона и атрибутов, в заметках и блокировках — свободный текст,
+// в отчетах импорта — значения ячеек с email и телефоном пользователей.
+// Связи, статусы, роли, метки и даты сохраняются для статистики.
+func (r *Repository) EraseUsers(ctx context.Context, ids []string, erasedAt time.Time) (*repositorymodels.UserErasure, error) {
+	var erasure repositorymodels.UserErasure
+
+	// Адреса и телефоны читаются до обезличивания, чтобы найти их в отчетах импорта.
+	importJobs, err := r.eraseUserImportRowErrors(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	erasure.ImportJobs = importJobs
+
+	usersSQL, usersArgs, err := squirrel.
+		Update("users").
+		Set("email", squirrel.Expr(erasedEmail("id::text"))).
+		Set("name", erasedUserName).
+		Set("phone", nil).
+		Set("email_encrypted", nil).
+		Set("email_index", nil).
+		Set("email_key_index", nil).
+		Set("phone_encrypted", nil).
+		Set("phone_index", nil).
+		Set("attributes", squirrel.Expr("'{}'::jsonb")).
+		Set("is_email_verified", false).
+		Set("deleted_at", squirrel.Expr("COALESCE(deleted_at, ?)", erasedAt)).
+		Set("erased_at", erasedAt).
+		Where(squirrel.Eq{"id": ids}).
+		Where(squirrel.Eq{"erased_at": nil}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build users update query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, usersSQL, usersArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("erase users: %w", err)
+	}
+	erasure.Users = result.RowsAffected()
+
+	// Перенесенные при объединении версии обезличиваются по ID исходного пользователя.
+	historySQL, historyArgs, err := squirrel.
+		Update("user_history").
+		Set("changes", squirrel.Expr(`(
+			SELECT COALESCE(jsonb_object_agg(key, CASE
+				WHEN key IN ('email', 'name', 'phone') OR key LIKE ? THEN '{"old": null, "new": null}'::jsonb
+				ELSE value
+			END), '{}'::jsonb)
+			FROM jsonb_each(changes))`, usecasemodels.UserAttributeSortPrefix+"%")).
+		Set("snapshot", squirrel.Expr("(snapshot - 'email_encrypted' - 'phone_encrypted') || jsonb_build_object('email', "+erasedEmail("COALESCE(merged_from, user_id)::text")+
+			", 'name', ?::text, 'phone', NULL, 'attributes', '{}'::jsonb)", erasedUserName)).
+		Where(squirrel.Or{
+			squirrel.Expr("merged_from = ANY(?::uuid[])", ids),
+			squirrel.And{squirrel.Eq{"user_id": ids}, squirrel.Eq{"merged_from": nil}},
+		}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build history update query: %w", err)
+	}
+
+	result, err = r.conn(ctx).Exec(ctx, historySQL, historyArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("erase user history: %w", err)
+	}
+	erasure.HistoryVersions = result.RowsAffected()
+
+	revisionsSQL, revisionsArgs, err := squirrel.
+		Update("user_note_revisions").
+		Set("body", "").
+		Where("note_id IN (SELECT id FROM user_notes WHERE user_id = ANY(?::uuid[]))", ids).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build note revisions update query: %w", err)
+	}
+
+	result, err = r.conn(ctx).Exec(ctx, revisionsSQL, revisionsArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("erase user note revisions: %w", err)
+	}
+	erasure.NoteRevisions = result.RowsAffected()
+
+	notesSQL, notesArgs, err := squirrel.
+		Update("user_notes").
+		Set("body", "").
+		Set("deleted_at", squirrel.Expr("COALESCE(deleted_at, ?)", erasedAt)).
+		Where(squirrel.Eq{"user_id": ids}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build notes update query: %w", err)
+	}
+
+	result, err = r.conn(ctx).Exec(ctx, notesSQL, notesArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("erase user notes: %w", err)
+	}
+	erasure.Notes = result.RowsAffected()
+
+	// Активная блокировка снимается, чтобы ее не обработало автоматическое снятие истекших блокировок.
+	bansSQL, bansArgs, err := squirrel.
+		Update("user_bans").
+		Set("reason", "").
+		Set("note", nil).
+		Set("lift_reason", squirrel.Expr("CASE WHEN lift_reason IS NULL AND lifted_at IS NOT NULL THEN NULL ELSE '' END")).
+		Set("lifted_at", squirrel.Expr("COALESCE(lifted_at, ?)", erasedAt)).
+		Where(squirrel.Eq{"user_id": ids}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build bans update query: %w", err)
+	}
+
+	result, err = r.conn(ctx).Exec(ctx, bansSQL, bansArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("erase user bans: %w", err)
+	}
+	erasure.Bans = result.RowsAffected()
+
+	verificationsSQL, verificationsArgs, err := squirrel.
+		Update("email_verifications").
+		Set("email", squirrel.Expr(erasedEmail("user_id::text"))).
+		Where(squirrel.Eq{"user_id": ids}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build email verifications update query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, verificationsSQL, verificationsArgs...); err != nil {
+		return nil, fmt.Errorf("erase email verifications: %w", err)
+	}
+
+	changesSQL, changesArgs, err := squirrel.
+		Update("user_email_changes").
+		Set("old_email", squirrel.Expr(erasedEmail("user_id::text"))).
+		Set("new_email", squirrel.Expr(erasedEmail("user_id::text"))).
+		Set("old_email_encrypted", nil).
+		Set("new_email_encrypted", nil).
+		Where(squirrel.Eq{"user_id": ids}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build email changes update query: %w", err)
+	}
+
+	if _, err := r.conn(ctx).Exec(ctx, changesSQL, changesArgs...); err != nil {
+		return nil, fmt.Errorf("erase email changes: %w", err)
+	}
+
+	return &erasure, nil
+}
+
+// eraseUserImportRowErrors удаляет из отчетов импорта значения ячеек, совпадающие с email
+// или телефоном пользователей с указанными ID. Отчеты, записанные до маскирования, хранят значения открыто.
+func (r *Repository) eraseUserImportRowErrors(ctx context.Context, ids []string) (int64, error) {
+	query, args, err := squirrel.
+		Select(userColumns...).
+		From("users").
+		Where(squirrel.Eq{"id": ids}).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build select query: %w", err)
+	}
+
+	rows, err := r.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("execute select query: %w", err)
+	}
+	defer rows.Close()
+
+	emails := []string{}
+	phones := []string{}
+	for rows.Next() {
+		user, err := r.scanUser(rows)
+		if err != nil {
+			return 0, fmt.Errorf("scan user: %w", err)
+		}
+
+		emails = append(emails, strings.ToLower(user.Email))
+		if digits := phoneDigits(stringValue(user.Phone)); digits != "" {
+			phones = append(phones, digits)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	if len(emails) == 0 {
+		return 0, nil
+	}
+
+	// Значение сравнивается без учета регистра и пробелов, телефон — по цифрам.
+	match := `(lower(btrim(e->>'value')) = ANY(?::text[])
+		OR (e->>'field' = 'phone' AND regexp_replace(e->>'value', '\D', '', 'g') = ANY(?::text[])))`
+
+	updateSQL, updateArgs, err := squirrel.
+		Update("user_import_jobs").
+		Set("row_errors", squirrel.Expr(`(
+			SELECT jsonb_agg(CASE WHEN `+match+` THEN e - 'value' ELSE e END ORDER BY ord)
+			FROM jsonb_array_elements(row_errors) WITH ORDINALITY AS t(e, ord))`, emails, phones)).
+		Where(`EXISTS (SELECT 1 FROM jsonb_array_elements(row_errors) AS t(e) WHERE `+match+`)`, emails, phones).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build import jobs update query: %w", err)
+	}
+
+	result, err := r.conn(ctx).Exec(ctx, updateSQL, updateArgs...)
+	if err != nil {
+		return 0, fmt.Errorf("erase user import row errors: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// phoneDigits возвращает цифры телефона.
+func phoneDigits(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+
+		return -1
+	}, phone)
+}
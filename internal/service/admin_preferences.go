@@ -0,0 +1,52 @@
+package service
+
+import (
+	"net/http"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getAdminPreferences возвращает настройки интерфейса текущего администратора.
+func (s *Service) getAdminPreferences(c *gin.Context) {
+	preferences, err := s.useCase.GetAdminPreferences(c.Request.Context(), adminIDFromContext(c))
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    preferences,
+	})
+}
+
+// updateAdminPreferences сохраняет настройки интерфейса текущего администратора.
+func (s *Service) updateAdminPreferences(c *gin.Context) {
+	var req usecasemodels.UpdateAdminPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	preferences, err := s.useCase.UpdateAdminPreferences(c.Request.Context(), adminIDFromContext(c), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    preferences,
+	})
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/tabular"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userTimeseriesRequest собирает параметры временного ряда пользователей из query.
+func userTimeseriesRequest(c *gin.Context) usecasemodels.UserTimeseriesRequest {
+	return usecasemodels.UserTimeseriesRequest{
+		Metric:    c.Query("metric"),
+		Interval:  c.Query("interval"),
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+		Timezone:  c.Query("tz"),
+		Breakdown: c.Query("breakdown"),
+	}
+}
+
+// getUserTimeseries обрабатывает получение временного ряда событий пользователей.
+func (s *Service) getUserTimeseries(c *gin.Context) {
+	req := userTimeseriesRequest(c)
+
+	resp, err := s.useCase.GetUserTimeseries(c.Request.Context(), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
+
+// exportUserTimeseries обрабатывает выгрузку временного ряда событий пользователей.
+func (s *Service) exportUserTimeseries(c *gin.Context) {
+	req := &usecasemodels.ExportUserTimeseriesRequest{
+		UserTimeseriesRequest: userTimeseriesRequest(c),
+		Format:                c.DefaultQuery("format", tabular.FormatCSV),
+		Lang:                  c.Query("lang"),
+	}
+
+	w := &exportResponseWriter{
+		c:           c,
+		contentType: tabular.ContentType(req.Format),
+		filename:    fmt.Sprintf("users-timeseries-%s.%s", time.Now().Format("20060102-150405"), req.Format),
+	}
+
+	if err := s.useCase.ExportUserTimeseries(c.Request.Context(), req, w); err != nil {
+		if !w.started {
+			s.handleError(c, err)
+
+			return
+		}
+
+		log.Printf("Error in users timeseries export: %v", err)
+		c.Error(err)
+	}
+}
+
+// getUserCohorts обрабатывает получение матрицы удержания пользователей.
+// Пользователи отбираются теми же фильтрами, что и в списке пользователей.
+func (s *Service) getUserCohorts(c *gin.Context) {
+	req := &usecasemodels.UserCohortsRequest{
+		UserFilter: usecasemodels.UserFilter{
+			Search:     c.Query("search"),
+			Status:     c.QueryArray("status"),
+			Role:       c.QueryArray("role"),
+			Attributes: queryUserAttributes(c),
+			Tags:       c.QueryArray("tags"),
+			TagsMode:   c.Query("tags_mode"),
+		},
+		Cohort:         c.Query("cohort"),
+		From:           c.Query("from"),
+		To:             c.Query("to"),
+		Timezone:       c.Query("tz"),
+		RetainedStatus: c.Query("retained_status"),
+	}
+
+	if verified, err := strconv.ParseBool(c.Query("is_email_verified")); err == nil {
+		req.IsEmailVerified = &verified
+	}
+
+	if periods := c.Query("periods"); periods != "" {
+		value, err := strconv.Atoi(periods)
+		if err != nil {
+			s.handleError(c, usecasemodels.ErrorInvalidParameterPeriods)
+
+			return
+		}
+		req.Periods = value
+	}
+
+	resp, err := s.useCase.GetUserCohorts(c.Request.Context(), req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getAuditLog обрабатывает получение журнала аудита с фильтрами.
+func (s *Service) getAuditLog(c *gin.Context) {
+	req := &usecasemodels.GetAuditLogRequest{
+		Page:         1,
+		Limit:        50,
+		ActorID:      c.Query("actor_id"),
+		Action:       c.QueryArray("action"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+		Outcome:      c.Query("outcome"),
+	}
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		req.Page = page
+	}
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		req.Limit = limit
+	}
+
+	var err error
+	if req.From, err = parseTimeQuery(c, "from"); err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	if req.To, err = parseTimeQuery(c, "to"); err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	resp, err := s.useCase.GetAuditLog(c.Request.Context(), req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
+
+// parseTimeQuery разбирает параметр запроса в формате RFC3339 или YYYY-MM-DD.
+// Отсутствующий параметр возвращается как nil.
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return &t, nil
+	}
+
+	return nil, usecasemodels.ErrorInvalidParameterDate
+}
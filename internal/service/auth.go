@@ -2,10 +2,12 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 
 	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/tabular"
 
 	"github.com/gin-gonic/gin"
 )
@@ -168,6 +170,11 @@ func (s *Service) getCurrentAdmin(c *gin.Context) {
 	})
 }
 
+// adminIDFromContext возвращает ID текущего администратора, установленный AuthMiddleware.
+func adminIDFromContext(c *gin.Context) string {
+	return c.GetString("admin_id")
+}
+
 // healthCheck обрабатывает health check запрос.
 func (s *Service) healthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
@@ -258,7 +265,37 @@ func (s *Service) handleError(c *gin.Context, err error) {
 		return
 	}
 
-	if errors.Is(err, usecasemodels.ErrUserAlreadyExists) {
+	if errors.Is(err, usecasemodels.ErrImportJobNotFound) ||
+		errors.Is(err, usecasemodels.ErrUserVersionNotFound) ||
+		errors.Is(err, usecasemodels.ErrUserAttributeNotFound) ||
+		errors.Is(err, usecasemodels.ErrTagNotFound) ||
+		errors.Is(err, usecasemodels.ErrBloggerNotFound) ||
+		errors.Is(err, usecasemodels.ErrCampaignNotFound) ||
+		errors.Is(err, usecasemodels.ErrCampaignBloggerNotFound) ||
+		errors.Is(err, usecasemodels.ErrUserNoteNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "NOT_FOUND",
+				"message": err.Error(),
+			},
+		})
+
+		return
+	}
+
+	if errors.Is(err, usecasemodels.ErrUserAlreadyExists) ||
+		errors.Is(err, usecasemodels.ErrEmailAlreadyVerified) ||
+		errors.Is(err, usecasemodels.ErrUserAlreadyBanned) ||
+		errors.Is(err, usecasemodels.ErrUserNotBanned) ||
+		errors.Is(err, usecasemodels.ErrUserAttributeAlreadyExists) ||
+		errors.Is(err, usecasemodels.ErrTagAlreadyExists) ||
+		errors.Is(err, usecasemodels.ErrBloggerAlreadyExists) ||
+		errors.Is(err, usecasemodels.ErrCampaignBloggerAlreadyExists) ||
+		errors.Is(err, usecasemodels.ErrCampaignBloggerPaid) ||
+		errors.Is(err, usecasemodels.ErrCampaignBloggerPriceLocked) ||
+		errors.Is(err, usecasemodels.ErrCampaignBloggerPublicationURLRequired) ||
+		errors.Is(err, usecasemodels.ErrAdminPreferencesVersionConflict) {
 		c.JSON(http.StatusConflict, gin.H{
 			"success": false,
 			"error": gin.H{
@@ -271,7 +308,49 @@ func (s *Service) handleError(c *gin.Context, err error) {
 	}
 
 	if errors.Is(err, usecasemodels.ErrorInvalidParameterRole) ||
-		errors.Is(err, usecasemodels.ErrorInvalidParameterStatus) {
+		errors.Is(err, usecasemodels.ErrorInvalidParameterPhone) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterReason) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterUntil) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterStatus) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterOperation) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterTarget) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterFormat) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterMapping) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterOnDuplicate) ||
+		errors.Is(err, usecasemodels.ErrImportFileEmpty) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterColumns) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterField) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterDate) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterKey) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterType) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterEnum) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterDefault) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterAttributes) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterColor) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterTags) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterTagsMode) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterBody) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterMentions) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterParent) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterVisibility) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterScore) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterMerge) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterUsername) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterBudget) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterPrice) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterURL) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterMetric) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterInterval) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterTimezone) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterBreakdown) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterPeriods) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterNumber) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterRecords) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterLocale) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterTheme) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterPageSize) ||
+		errors.Is(err, usecasemodels.ErrorInvalidParameterTables) ||
+		errors.Is(err, usecasemodels.ErrUserNoteReplyPin) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
 			"error": gin.H{
@@ -283,6 +362,117 @@ func (s *Service) handleError(c *gin.Context, err error) {
 		return
 	}
 
+	if errors.Is(err, usecasemodels.ErrInvalidVerificationToken) ||
+		errors.Is(err, usecasemodels.ErrExpiredVerificationToken) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "INVALID_VERIFICATION_TOKEN",
+				"message": err.Error(),
+			},
+		})
+
+		return
+	}
+
+	if errors.Is(err, usecasemodels.ErrVerificationRateLimited) {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "TOO_MANY_REQUESTS",
+				"message": err.Error(),
+			},
+		})
+
+		return
+	}
+
+	if errors.Is(err, usecasemodels.ErrExportTooManyRows) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "EXPORT_TOO_MANY_ROWS",
+				"message": fmt.Sprintf("XLSX export may contain at most %d users, use csv or ndjson", tabular.MaxRows(tabular.FormatXLSX)),
+			},
+		})
+
+		return
+	}
+
+	if errors.Is(err, usecasemodels.ErrUserErased) {
+		c.JSON(http.StatusGone, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "USER_ERASED",
+				"message": err.Error(),
+			},
+		})
+
+		return
+	}
+
+	if errors.Is(err, usecasemodels.ErrUserNoteForbidden) ||
+		errors.Is(err, usecasemodels.ErrPrivacyForbidden) ||
+		errors.Is(err, usecasemodels.ErrPIIRevealForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "FORBIDDEN",
+				"message": err.Error(),
+			},
+		})
+
+		return
+	}
+
+	var transitionErr *usecasemodels.StatusTransitionError
+	if errors.As(err, &transitionErr) {
+		status, code := http.StatusUnprocessableEntity, "INVALID_STATUS_TRANSITION"
+		switch {
+		case errors.Is(err, usecasemodels.ErrStatusTransitionForbidden):
+			status, code = http.StatusForbidden, "STATUS_TRANSITION_FORBIDDEN"
+		case errors.Is(err, usecasemodels.ErrStatusTransitionGuard):
+			code = "STATUS_TRANSITION_GUARD_FAILED"
+		case errors.Is(err, usecasemodels.ErrStatusRequiresBan):
+			code = "STATUS_REQUIRES_BAN"
+		}
+
+		c.JSON(status, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    code,
+				"message": transitionErr.Error(),
+				"allowed": transitionErr.Allowed,
+			},
+		})
+
+		return
+	}
+
+	if errors.Is(err, usecasemodels.ErrStatusRequiresBan) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "STATUS_REQUIRES_BAN",
+				"message": "Status banned can only be set via POST /api/v1/users/:id/ban",
+			},
+		})
+
+		return
+	}
+
+	if errors.Is(err, usecasemodels.ErrBulkLimitExceeded) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "BULK_LIMIT_EXCEEDED",
+				"message": fmt.Sprintf("Bulk operation may affect at most %d users", s.cfg.Users.BulkMaxItems),
+			},
+		})
+
+		return
+	}
+
 	c.JSON(http.StatusInternalServerError, gin.H{
 		"success": false,
 		"error": gin.H{
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	"adminkaback/internal/middleware"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getBloggers обрабатывает получение списка блогеров.
+func (s *Service) getBloggers(c *gin.Context) {
+	req := &usecasemodels.GetBloggersRequest{
+		Page:  1,
+		Limit: 10,
+		Sort:  "created_at",
+		Order: "desc",
+	}
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		req.Page = page
+	}
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		req.Limit = limit
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		req.Sort = sort
+	}
+
+	if order := c.Query("order"); order != "" {
+		req.Order = order
+	}
+
+	req.Search = c.Query("search")
+	req.Status = c.QueryArray("status")
+	req.Tags = c.QueryArray("tags")
+	req.TagsMode = c.Query("tags_mode")
+	req.DateFrom = c.Query("date_from")
+	req.DateTo = c.Query("date_to")
+
+	resp, err := s.useCase.GetBloggers(c.Request.Context(), req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
+
+// getBlogger обрабатывает получение блогера по ID.
+func (s *Service) getBlogger(c *gin.Context) {
+	blogger, err := s.useCase.GetBlogger(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    blogger,
+	})
+}
+
+// createBlogger обрабатывает создание блогера.
+func (s *Service) createBlogger(c *gin.Context) {
+	var req usecasemodels.CreateBloggerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	blogger, err := s.useCase.CreateBlogger(c.Request.Context(), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.Set(middleware.AuditResourceIDKey, blogger.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    blogger,
+	})
+}
+
+// updateBlogger обрабатывает изменение блогера.
+func (s *Service) updateBlogger(c *gin.Context) {
+	var req usecasemodels.UpdateBloggerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	blogger, err := s.useCase.UpdateBlogger(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    blogger,
+	})
+}
+
+// deleteBlogger обрабатывает удаление блогера.
+func (s *Service) deleteBlogger(c *gin.Context) {
+	if err := s.useCase.DeleteBlogger(c.Request.Context(), c.Param("id")); err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Blogger deleted successfully",
+	})
+}
+
+// importLegacyBloggers обрабатывает загрузку списка блогеров из localStorage браузера.
+func (s *Service) importLegacyBloggers(c *gin.Context) {
+	var records []usecasemodels.BloggerLegacyRecord
+	if err := c.ShouldBindJSON(&records); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	resp, err := s.useCase.ImportLegacyBloggers(c.Request.Context(), records)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
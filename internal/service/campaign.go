@@ -0,0 +1,240 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	"adminkaback/internal/middleware"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getCampaigns обрабатывает получение списка кампаний.
+func (s *Service) getCampaigns(c *gin.Context) {
+	req := &usecasemodels.GetCampaignsRequest{
+		Page:  1,
+		Limit: 10,
+		Sort:  "start_date",
+		Order: "desc",
+	}
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		req.Page = page
+	}
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		req.Limit = limit
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		req.Sort = sort
+	}
+
+	if order := c.Query("order"); order != "" {
+		req.Order = order
+	}
+
+	req.Search = c.Query("search")
+	req.Status = c.QueryArray("status")
+	req.Active = c.Query("active")
+
+	resp, err := s.useCase.GetCampaigns(c.Request.Context(), req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
+
+// getCampaign обрабатывает получение кампании по ID.
+func (s *Service) getCampaign(c *gin.Context) {
+	campaign, err := s.useCase.GetCampaign(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// createCampaign обрабатывает создание кампании.
+func (s *Service) createCampaign(c *gin.Context) {
+	var req usecasemodels.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	campaign, err := s.useCase.CreateCampaign(c.Request.Context(), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.Set(middleware.AuditResourceIDKey, campaign.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// updateCampaign обрабатывает изменение кампании.
+func (s *Service) updateCampaign(c *gin.Context) {
+	var req usecasemodels.UpdateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	campaign, err := s.useCase.UpdateCampaign(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// deleteCampaign обрабатывает удаление кампании.
+func (s *Service) deleteCampaign(c *gin.Context) {
+	if err := s.useCase.DeleteCampaign(c.Request.Context(), c.Param("id")); err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Campaign deleted successfully",
+	})
+}
+
+// getCampaignBloggers обрабатывает получение состава кампании.
+func (s *Service) getCampaignBloggers(c *gin.Context) {
+	resp, err := s.useCase.GetCampaignBloggers(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
+
+// assignCampaignBlogger обрабатывает добавление блогера в кампанию.
+func (s *Service) assignCampaignBlogger(c *gin.Context) {
+	var req usecasemodels.AssignCampaignBloggerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	assignment, err := s.useCase.AssignCampaignBlogger(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    assignment,
+	})
+}
+
+// updateCampaignBlogger обрабатывает изменение участия блогера в кампании.
+func (s *Service) updateCampaignBlogger(c *gin.Context) {
+	var req usecasemodels.UpdateCampaignBloggerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	assignment, err := s.useCase.UpdateCampaignBlogger(c.Request.Context(), c.Param("id"), c.Param("blogger_id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    assignment,
+	})
+}
+
+// removeCampaignBlogger обрабатывает удаление блогера из кампании.
+func (s *Service) removeCampaignBlogger(c *gin.Context) {
+	if err := s.useCase.RemoveCampaignBlogger(c.Request.Context(), c.Param("id"), c.Param("blogger_id")); err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Blogger removed from campaign successfully",
+	})
+}
+
+// getBloggerCampaigns обрабатывает получение истории кампаний блогера.
+func (s *Service) getBloggerCampaigns(c *gin.Context) {
+	resp, err := s.useCase.GetBloggerCampaigns(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getDashboardStats обрабатывает получение статистики пользователей для дашборда.
+func (s *Service) getDashboardStats(c *gin.Context) {
+	stats, err := s.useCase.GetDashboardStats(c.Request.Context())
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stats,
+	})
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sendVerificationEmail обрабатывает отправку письма подтверждения email пользователю.
+func (s *Service) sendVerificationEmail(c *gin.Context) {
+	resp, err := s.useCase.SendVerificationEmail(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
+
+// verifyEmail обрабатывает переход по ссылке подтверждения email (публичный endpoint).
+func (s *Service) verifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "token is required",
+			},
+		})
+
+		return
+	}
+
+	user, err := s.useCase.VerifyEmail(c.Request.Context(), token)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"id":                user.ID,
+			"email":             user.Email,
+			"is_email_verified": user.IsEmailVerified,
+		},
+	})
+}
+
+// confirmEmailChange обрабатывает переход по ссылке подтверждения смены email (публичный endpoint).
+func (s *Service) confirmEmailChange(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "token is required",
+			},
+		})
+
+		return
+	}
+
+	user, err := s.useCase.ConfirmEmailChange(c.Request.Context(), token)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"id":                user.ID,
+			"email":             user.Email,
+			"is_email_verified": user.IsEmailVerified,
+		},
+	})
+}
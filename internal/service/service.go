@@ -36,6 +36,7 @@ func NewService(useCase *usecase.UseCase, cfg *config.Config) *Service {
 func (s *Service) setupRoutes() {
 	// CORS middleware
 	s.router.Use(middleware.CORSMiddleware(s.cfg))
+	s.router.Use(middleware.RequestMiddleware())
 
 	// Health check
 	s.router.GET("/_hc", s.healthCheck)
@@ -52,20 +53,100 @@ func (s *Service) setupRoutes() {
 			auth.POST("/logout", s.logout)
 		}
 
+		// Подтверждение email по ссылкам из писем (публичные)
+		v1.GET("/email/verify", s.verifyEmail)
+		v1.GET("/email/confirm-change", s.confirmEmailChange)
+
 		// Protected endpoints
 		protected := v1.Group("")
 		protected.Use(middleware.AuthMiddleware(s.useCase, s.cfg))
+		protected.Use(middleware.AuditMiddleware(s.useCase))
 		{
 			protected.GET("/auth/me", s.getCurrentAdmin)
+			protected.GET("/auth/me/preferences", s.getAdminPreferences)
+			protected.PUT("/auth/me/preferences", s.updateAdminPreferences)
+
+			// Audit log endpoints
+			protected.GET("/audit", s.getAuditLog)
+
+			// Dashboard endpoints
+			protected.GET("/dashboard/stats", s.getDashboardStats)
+
+			// Analytics endpoints
+			analytics := protected.Group("/analytics")
+			{
+				analytics.GET("/users/timeseries", s.getUserTimeseries)
+				analytics.GET("/users/timeseries/export", s.exportUserTimeseries)
+				analytics.GET("/users/cohorts", s.getUserCohorts)
+			}
 
 			// Users endpoints
 			users := protected.Group("/users")
 			{
 				users.GET("", s.getUsers)
+				users.GET("/export", s.exportUsers)
+				users.GET("/attributes", s.getUserAttributes)
+				users.POST("/attributes", s.createUserAttribute)
+				users.PUT("/attributes/:key", s.updateUserAttribute)
+				users.DELETE("/attributes/:key", s.deleteUserAttribute)
+				users.GET("/tags", s.getTags)
+				users.POST("/tags", s.createTag)
+				users.PUT("/tags/:id", s.updateTag)
+				users.DELETE("/tags/:id", s.deleteTag)
+				users.GET("/duplicates", s.getUserDuplicates)
+				users.POST("/merge", s.mergeUsers)
 				users.GET("/:id", s.getUser)
 				users.POST("", s.createUser)
+				users.POST("/bulk", s.bulkUsers)
+				users.POST("/import", s.importUsers)
+				users.GET("/import/:job_id", s.getUserImportJob)
+				users.GET("/import/:job_id/report", s.getUserImportReport)
 				users.PUT("/:id", s.updateUser)
 				users.DELETE("/:id", s.deleteUser)
+				users.GET("/:id/history", s.getUserHistory)
+				users.POST("/:id/history/:version/revert", s.revertUser)
+				users.POST("/:id/verification-email", s.sendVerificationEmail)
+				users.POST("/:id/ban", s.banUser)
+				users.POST("/:id/unban", s.unbanUser)
+				users.GET("/:id/bans", s.getUserBans)
+				users.POST("/:id/tags", s.addUserTags)
+				users.DELETE("/:id/tags/:tag_id", s.removeUserTag)
+				users.GET("/:id/notes", s.getUserNotes)
+				users.POST("/:id/notes", s.createUserNote)
+				users.PUT("/:id/notes/:note_id", s.updateUserNote)
+				users.DELETE("/:id/notes/:note_id", s.deleteUserNote)
+				users.POST("/:id/notes/:note_id/pin", s.pinUserNote)
+				users.DELETE("/:id/notes/:note_id/pin", s.unpinUserNote)
+				users.GET("/:id/notes/:note_id/history", s.getUserNoteRevisions)
+				users.POST("/:id/privacy/export", s.exportUserData)
+				users.POST("/:id/privacy/erase", s.eraseUser)
+				users.POST("/:id/pii/reveal", s.revealUserPII)
+			}
+
+			// Bloggers endpoints
+			bloggers := protected.Group("/bloggers")
+			{
+				bloggers.GET("", s.getBloggers)
+				bloggers.GET("/:id", s.getBlogger)
+				bloggers.POST("", s.createBlogger)
+				bloggers.POST("/import/legacy", s.importLegacyBloggers)
+				bloggers.PUT("/:id", s.updateBlogger)
+				bloggers.DELETE("/:id", s.deleteBlogger)
+				bloggers.GET("/:id/campaigns", s.getBloggerCampaigns)
+			}
+
+			// Campaigns endpoints
+			campaigns := protected.Group("/campaigns")
+			{
+				campaigns.GET("", s.getCampaigns)
+				campaigns.GET("/:id", s.getCampaign)
+				campaigns.POST("", s.createCampaign)
+				campaigns.PUT("/:id", s.updateCampaign)
+				campaigns.DELETE("/:id", s.deleteCampaign)
+				campaigns.GET("/:id/bloggers", s.getCampaignBloggers)
+				campaigns.POST("/:id/bloggers", s.assignCampaignBlogger)
+				campaigns.PUT("/:id/bloggers/:blogger_id", s.updateCampaignBlogger)
+				campaigns.DELETE("/:id/bloggers/:blogger_id", s.removeCampaignBlogger)
 			}
 		}
 	}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"net/http"
+
+	"adminkaback/internal/middleware"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getTags обрабатывает получение каталога меток.
+func (s *Service) getTags(c *gin.Context) {
+	tags, err := s.useCase.GetTags(c.Request.Context())
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tags,
+	})
+}
+
+// createTag обрабатывает создание метки.
+func (s *Service) createTag(c *gin.Context) {
+	var req usecasemodels.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	tag, err := s.useCase.CreateTag(c.Request.Context(), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.Set(middleware.AuditResourceIDKey, tag.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    tag,
+	})
+}
+
+// updateTag обрабатывает изменение метки.
+func (s *Service) updateTag(c *gin.Context) {
+	var req usecasemodels.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	tag, err := s.useCase.UpdateTag(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tag,
+	})
+}
+
+// deleteTag обрабатывает удаление метки.
+func (s *Service) deleteTag(c *gin.Context) {
+	if err := s.useCase.DeleteTag(c.Request.Context(), c.Param("id")); err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tag deleted successfully",
+	})
+}
+
+// addUserTags обрабатывает назначение меток пользователю.
+func (s *Service) addUserTags(c *gin.Context) {
+	var req usecasemodels.UserTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	tags, err := s.useCase.AddUserTags(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tags,
+	})
+}
+
+// removeUserTag обрабатывает снятие метки с пользователя.
+func (s *Service) removeUserTag(c *gin.Context) {
+	tags, err := s.useCase.RemoveUserTag(c.Request.Context(), c.Param("id"), c.Param("tag_id"))
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tags,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"adminkaback/internal/middleware"
 	usecasemodels "adminkaback/internal/usecase/models"
 
 	"github.com/gin-gonic/gin"
@@ -12,11 +13,10 @@ import (
 // getUsers обрабатывает получение списка пользователей.
 func (s *Service) getUsers(c *gin.Context) {
 	req := &usecasemodels.GetUsersRequest{
-		Page:   1,
-		Limit:  10,
-		Search: "",
-		Sort:   "created_at",
-		Order:  "desc",
+		Page:  1,
+		Limit: 10,
+		Sort:  "created_at",
+		Order: "desc",
 	}
 
 	if pageStr := c.Query("page"); pageStr != "" {
@@ -45,6 +45,13 @@ func (s *Service) getUsers(c *gin.Context) {
 
 	req.Status = c.QueryArray("status")
 	req.Role = c.QueryArray("role")
+	req.Attributes = queryUserAttributes(c)
+	req.Tags = c.QueryArray("tags")
+	req.TagsMode = c.Query("tags_mode")
+
+	if verified, err := strconv.ParseBool(c.Query("is_email_verified")); err == nil {
+		req.IsEmailVerified = &verified
+	}
 
 	resp, err := s.useCase.GetUsers(c.Request.Context(), req)
 	if err != nil {
@@ -109,6 +116,8 @@ func (s *Service) createUser(c *gin.Context) {
 		return
 	}
 
+	c.Set(middleware.AuditResourceIDKey, user.ID)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
 		"data":    user,
@@ -182,3 +191,31 @@ func (s *Service) deleteUser(c *gin.Context) {
 		"message": "User deleted successfully",
 	})
 }
+
+// bulkUsers обрабатывает массовую операцию над пользователями.
+func (s *Service) bulkUsers(c *gin.Context) {
+	var req usecasemodels.BulkUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	resp, err := s.useCase.BulkUsers(c.Request.Context(), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"net/http"
+
+	"adminkaback/internal/middleware"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getUserAttributes обрабатывает получение описаний пользовательских атрибутов.
+func (s *Service) getUserAttributes(c *gin.Context) {
+	attributes, err := s.useCase.GetUserAttributes(c.Request.Context())
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    attributes,
+	})
+}
+
+// createUserAttribute обрабатывает создание описания атрибута.
+func (s *Service) createUserAttribute(c *gin.Context) {
+	var req usecasemodels.UserAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	attribute, err := s.useCase.CreateUserAttribute(c.Request.Context(), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.Set(middleware.AuditResourceIDKey, attribute.Key)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    attribute,
+	})
+}
+
+// updateUserAttribute обрабатывает изменение описания атрибута.
+func (s *Service) updateUserAttribute(c *gin.Context) {
+	c.Set(middleware.AuditResourceIDKey, c.Param("key"))
+
+	var req usecasemodels.UserAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	attribute, err := s.useCase.UpdateUserAttribute(c.Request.Context(), c.Param("key"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    attribute,
+	})
+}
+
+// deleteUserAttribute обрабатывает удаление описания атрибута.
+func (s *Service) deleteUserAttribute(c *gin.Context) {
+	c.Set(middleware.AuditResourceIDKey, c.Param("key"))
+
+	if err := s.useCase.DeleteUserAttribute(c.Request.Context(), c.Param("key")); err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "User attribute deleted successfully",
+	})
+}
+
+// queryUserAttributes читает фильтр по атрибутам из query параметров вида attributes[key]=value.
+func queryUserAttributes(c *gin.Context) map[string]any {
+	values := c.QueryMap("attributes")
+	if len(values) == 0 {
+		return nil
+	}
+
+	attributes := make(map[string]any, len(values))
+	for key, value := range values {
+		attributes[key] = value
+	}
+
+	return attributes
+}
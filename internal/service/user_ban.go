@@ -0,0 +1,80 @@
+package service
+
+import (
+	"net/http"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// banUser обрабатывает блокировку пользователя.
+func (s *Service) banUser(c *gin.Context) {
+	var req usecasemodels.BanUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	ban, err := s.useCase.BanUser(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    ban,
+	})
+}
+
+// unbanUser обрабатывает снятие блокировки пользователя.
+func (s *Service) unbanUser(c *gin.Context) {
+	var req usecasemodels.UnbanUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	ban, err := s.useCase.UnbanUser(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    ban,
+	})
+}
+
+// getUserBans обрабатывает получение истории блокировок пользователя.
+func (s *Service) getUserBans(c *gin.Context) {
+	bans, err := s.useCase.GetUserBans(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    bans,
+	})
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getUserDuplicates обрабатывает поиск вероятных дубликатов пользователей.
+func (s *Service) getUserDuplicates(c *gin.Context) {
+	minScore, err := strconv.ParseFloat(c.DefaultQuery("min_score", "0.4"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "min_score must be a number between 0 and 1",
+			},
+		})
+
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	resp, err := s.useCase.FindUserDuplicates(c.Request.Context(), &usecasemodels.FindUserDuplicatesRequest{
+		UserID:   c.Query("user_id"),
+		MinScore: minScore,
+		Page:     page,
+		Limit:    limit,
+	})
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
+
+// mergeUsers обрабатывает объединение дубликата с сохраняемым пользователем.
+func (s *Service) mergeUsers(c *gin.Context) {
+	var req usecasemodels.MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	user, err := s.useCase.MergeUsers(c.Request.Context(), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    user,
+	})
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/tabular"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportResponseWriter откладывает отправку заголовков ответа до первой записи данных,
+// чтобы ошибки валидации экспорта можно было вернуть обычным JSON ответом.
+type exportResponseWriter struct {
+	c           *gin.Context
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", w.contentType)
+		w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.filename))
+		w.c.Status(http.StatusOK)
+	}
+
+	return w.c.Writer.Write(p)
+}
+
+// exportUsers обрабатывает потоковую выгрузку пользователей.
+func (s *Service) exportUsers(c *gin.Context) {
+	req := &usecasemodels.ExportUsersRequest{
+		UserFilter: usecasemodels.UserFilter{
+			Search:     c.Query("search"),
+			Status:     c.QueryArray("status"),
+			Role:       c.QueryArray("role"),
+			Attributes: queryUserAttributes(c),
+			Tags:       c.QueryArray("tags"),
+			TagsMode:   c.Query("tags_mode"),
+		},
+		Format: c.DefaultQuery("format", tabular.FormatCSV),
+		Lang:   c.Query("lang"),
+		Sort:   c.DefaultQuery("sort", "created_at"),
+		Order:  c.DefaultQuery("order", "desc"),
+	}
+
+	if verified, err := strconv.ParseBool(c.Query("is_email_verified")); err == nil {
+		req.IsEmailVerified = &verified
+	}
+
+	if req.Lang == "" {
+		req.Lang = acceptLanguage(c.GetHeader("Accept-Language"))
+	}
+
+	if columns := c.Query("columns"); columns != "" {
+		for _, column := range strings.Split(columns, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				req.Columns = append(req.Columns, column)
+			}
+		}
+	}
+
+	w := &exportResponseWriter{
+		c:           c,
+		contentType: tabular.ContentType(req.Format),
+		filename:    fmt.Sprintf("users-%s.%s", time.Now().Format("20060102-150405"), req.Format),
+	}
+
+	if err := s.useCase.ExportUsers(c.Request.Context(), req, w); err != nil {
+		if !w.started {
+			s.handleError(c, err)
+
+			return
+		}
+
+		// Заголовки уже отправлены, остается только прервать поток.
+		log.Printf("Error in users export: %v", err)
+		c.Error(err)
+	}
+}
+
+// acceptLanguage возвращает основной язык первого тега Accept-Language: "ru-RU,en;q=0.9" -> "ru".
+func acceptLanguage(header string) string {
+	tag, _, _ := strings.Cut(header, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	tag, _, _ = strings.Cut(tag, "-")
+
+	return strings.ToLower(strings.TrimSpace(tag))
+}
@@ -0,0 +1,27 @@
+package service
+
+import "testing"
+
+func TestAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: ""},
+		{header: "ru", want: "ru"},
+		{header: "ru-RU", want: "ru"},
+		{header: "ru,en;q=0.9", want: "ru"},
+		{header: "ru-RU,ru;q=0.9,en-US;q=0.8", want: "ru"},
+		{header: "en-US;q=0.8", want: "en"},
+		{header: " RU-ru , en", want: "ru"},
+		{header: "*", want: "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := acceptLanguage(tt.header); got != tt.want {
+				t.Fatalf("acceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
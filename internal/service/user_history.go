@@ -0,0 +1,72 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getUserHistory обрабатывает получение истории изменений пользователя.
+func (s *Service) getUserHistory(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	resp, err := s.useCase.GetUserHistory(c.Request.Context(), c.Param("id"), page, limit)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
+
+// revertUser обрабатывает откат полей пользователя к версии из истории.
+func (s *Service) revertUser(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "version must be a positive integer",
+			},
+		})
+
+		return
+	}
+
+	var req usecasemodels.RevertUserRequest
+	// Тело запроса опционально: без него откатываются все поля.
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "VALIDATION_ERROR",
+					"message": "Invalid request body",
+				},
+			})
+
+			return
+		}
+	}
+
+	user, err := s.useCase.RevertUser(c.Request.Context(), c.Param("id"), version, &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    user,
+	})
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"adminkaback/internal/middleware"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/tabular"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importUsers обрабатывает загрузку файла для импорта пользователей.
+// Файл передается в multipart поле file; format, on_duplicate, dry_run и mapping (JSON объект) — опциональные поля формы.
+func (s *Service) importUsers(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "file is required",
+			},
+		})
+
+		return
+	}
+
+	if fileHeader.Size > s.cfg.Users.ImportMaxFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "FILE_TOO_LARGE",
+				"message": fmt.Sprintf("File size must not exceed %d bytes", s.cfg.Users.ImportMaxFileSize),
+			},
+		})
+
+		return
+	}
+
+	req := usecasemodels.ImportUsersRequest{
+		AdminID:     adminIDFromContext(c),
+		Filename:    fileHeader.Filename,
+		Format:      c.PostForm("format"),
+		OnDuplicate: c.PostForm("on_duplicate"),
+	}
+
+	if dryRun := c.PostForm("dry_run"); dryRun != "" {
+		req.DryRun, err = strconv.ParseBool(dryRun)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "VALIDATION_ERROR",
+					"message": "dry_run must be a boolean",
+				},
+			})
+
+			return
+		}
+	}
+
+	if mapping := c.PostForm("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &req.Mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "VALIDATION_ERROR",
+					"message": "mapping must be a JSON object",
+				},
+			})
+
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		s.handleError(c, fmt.Errorf("open uploaded file: %w", err))
+
+		return
+	}
+	defer file.Close()
+
+	req.Data, err = io.ReadAll(file)
+	if err != nil {
+		s.handleError(c, fmt.Errorf("read uploaded file: %w", err))
+
+		return
+	}
+
+	job, err := s.useCase.ImportUsers(c.Request.Context(), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.Set(middleware.AuditResourceIDKey, job.ID)
+
+	status := http.StatusOK
+	if job.Status == usecasemodels.ImportStatusPending {
+		status = http.StatusAccepted
+	}
+
+	c.JSON(status, gin.H{
+		"success": true,
+		"data":    job,
+	})
+}
+
+// getUserImportJob возвращает состояние задачи импорта.
+func (s *Service) getUserImportJob(c *gin.Context) {
+	job, err := s.useCase.GetUserImportJob(c.Request.Context(), c.Param("job_id"))
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    job,
+	})
+}
+
+// getUserImportReport отдает отчет об ошибках импорта в формате CSV.
+func (s *Service) getUserImportReport(c *gin.Context) {
+	job, err := s.useCase.GetUserImportJob(c.Request.Context(), c.Param("job_id"))
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.Header("Content-Type", tabular.ContentType(tabular.FormatCSV))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"import-%s-report.csv\"", job.ID))
+	c.Status(http.StatusOK)
+
+	// Значения и сообщения взяты из загруженного файла, поэтому пишутся через tabular,
+	// который экранирует формулы.
+	writer, err := tabular.NewWriter(c.Writer, tabular.FormatCSV)
+	if err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	if err := writer.WriteHeader(nil, []string{"row", "field", "value", "message"}); err != nil {
+		c.Error(err)
+
+		return
+	}
+
+	for _, rowErr := range job.Errors {
+		if err := writer.WriteRow([]any{rowErr.Row, rowErr.Field, rowErr.Value, rowErr.Message}); err != nil {
+			c.Error(err)
+
+			return
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		c.Error(err)
+	}
+}
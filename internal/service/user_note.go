@@ -0,0 +1,143 @@
+package service
+
+import (
+	"net/http"
+
+	"adminkaback/internal/middleware"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getUserNotes обрабатывает получение заметок о пользователе.
+func (s *Service) getUserNotes(c *gin.Context) {
+	notes, err := s.useCase.GetUserNotes(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    notes,
+	})
+}
+
+// createUserNote обрабатывает создание заметки или ответа на заметку.
+func (s *Service) createUserNote(c *gin.Context) {
+	var req usecasemodels.CreateUserNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	note, err := s.useCase.CreateUserNote(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.Set(middleware.AuditResourceIDKey, note.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    note,
+	})
+}
+
+// updateUserNote обрабатывает изменение заметки.
+func (s *Service) updateUserNote(c *gin.Context) {
+	c.Set(middleware.AuditResourceIDKey, c.Param("note_id"))
+
+	var req usecasemodels.UpdateUserNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	note, err := s.useCase.UpdateUserNote(c.Request.Context(), c.Param("id"), c.Param("note_id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    note,
+	})
+}
+
+// deleteUserNote обрабатывает удаление заметки.
+func (s *Service) deleteUserNote(c *gin.Context) {
+	c.Set(middleware.AuditResourceIDKey, c.Param("note_id"))
+
+	if err := s.useCase.DeleteUserNote(c.Request.Context(), c.Param("id"), c.Param("note_id")); err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "User note deleted successfully",
+	})
+}
+
+// pinUserNote обрабатывает закрепление заметки.
+func (s *Service) pinUserNote(c *gin.Context) {
+	s.setUserNotePinned(c, true)
+}
+
+// unpinUserNote обрабатывает открепление заметки.
+func (s *Service) unpinUserNote(c *gin.Context) {
+	s.setUserNotePinned(c, false)
+}
+
+// setUserNotePinned закрепляет или открепляет заметку из пути запроса.
+func (s *Service) setUserNotePinned(c *gin.Context, pinned bool) {
+	c.Set(middleware.AuditResourceIDKey, c.Param("note_id"))
+
+	note, err := s.useCase.PinUserNote(c.Request.Context(), c.Param("id"), c.Param("note_id"), pinned)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    note,
+	})
+}
+
+// getUserNoteRevisions обрабатывает получение истории правок заметки.
+func (s *Service) getUserNoteRevisions(c *gin.Context) {
+	revisions, err := s.useCase.GetUserNoteRevisions(c.Request.Context(), c.Param("id"), c.Param("note_id"))
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    revisions,
+	})
+}
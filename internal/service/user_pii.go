@@ -0,0 +1,37 @@
+package service
+
+import (
+	"net/http"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// revealUserPII обрабатывает раскрытие полного значения маскированного поля пользователя.
+func (s *Service) revealUserPII(c *gin.Context) {
+	var req usecasemodels.RevealUserPIIRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	resp, err := s.useCase.RevealUserPII(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
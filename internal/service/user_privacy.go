@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportUserData обрабатывает выгрузку всех данных пользователя в ZIP архиве.
+func (s *Service) exportUserData(c *gin.Context) {
+	id := c.Param("id")
+
+	w := &exportResponseWriter{
+		c:           c,
+		contentType: "application/zip",
+		filename:    fmt.Sprintf("user-%s-%s.zip", id, time.Now().Format("20060102-150405")),
+	}
+
+	if err := s.useCase.ExportUserData(c.Request.Context(), id, w); err != nil {
+		if !w.started {
+			s.handleError(c, err)
+
+			return
+		}
+
+		// Заголовки уже отправлены, остается только прервать поток.
+		log.Printf("Error in user data export: %v", err)
+		c.Error(err)
+	}
+}
+
+// eraseUser обрабатывает необратимое обезличивание данных пользователя.
+func (s *Service) eraseUser(c *gin.Context) {
+	var req usecasemodels.EraseUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error": gin.H{
+				"code":    "VALIDATION_ERROR",
+				"message": "Invalid request body",
+			},
+		})
+
+		return
+	}
+
+	resp, err := s.useCase.EraseUser(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		s.handleError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resp,
+	})
+}
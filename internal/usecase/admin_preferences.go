@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+const (
+	// adminPreferencesDefaultPageSize — размер страницы таблиц по умолчанию.
+	adminPreferencesDefaultPageSize = 10
+	// adminPreferencesMaxPageSize — максимальный размер страницы, как в списках API.
+	adminPreferencesMaxPageSize = 100
+	// adminPreferencesMaxTables — максимальное число таблиц с настройками колонок.
+	adminPreferencesMaxTables = 50
+	// adminPreferencesMaxColumns — максимальное число колонок в настройках таблицы.
+	adminPreferencesMaxColumns = 100
+)
+
+// adminPreferencesKeyPattern — допустимые ключи таблиц и колонок.
+var adminPreferencesKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,64}$`)
+
+// GetAdminPreferences получает настройки интерфейса администратора.
+// Если они не сохранялись, возвращаются настройки по умолчанию с версией 0.
+func (uc *UseCase) GetAdminPreferences(ctx context.Context, adminID string) (*usecasemodels.AdminPreferencesResponse, error) {
+	preferences, err := uc.authRepo.GetAdminPreferences(ctx, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("get admin preferences: %w", err)
+	}
+
+	if preferences == nil {
+		preferences = &repositorymodels.AdminPreferences{
+			AdminID:       adminID,
+			SchemaVersion: usecasemodels.AdminPreferencesSchemaVersion,
+		}
+	}
+
+	return adminPreferencesToResponse(preferences), nil
+}
+
+// UpdateAdminPreferences проверяет и сохраняет настройки интерфейса администратора целиком.
+func (uc *UseCase) UpdateAdminPreferences(ctx context.Context, adminID string, req *usecasemodels.UpdateAdminPreferencesRequest) (*usecasemodels.AdminPreferencesResponse, error) {
+	document := repositorymodels.AdminPreferencesDocument{
+		Locale:   req.Locale,
+		Theme:    req.Theme,
+		Timezone: req.Timezone,
+		PageSize: req.PageSize,
+		Tables:   make(map[string]repositorymodels.AdminTablePreferences, len(req.Tables)),
+	}
+	for key, table := range req.Tables {
+		columns := make([]repositorymodels.AdminTableColumn, 0, len(table.Columns))
+		for _, column := range table.Columns {
+			columns = append(columns, repositorymodels.AdminTableColumn{Key: column.Key, Visible: column.Visible})
+		}
+		document.Tables[key] = repositorymodels.AdminTablePreferences{Columns: columns}
+	}
+
+	if err := validateAdminPreferences(&document); err != nil {
+		return nil, err
+	}
+
+	current, err := uc.authRepo.GetAdminPreferences(ctx, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("get admin preferences: %w", err)
+	}
+
+	currentVersion := 0
+	if current != nil {
+		currentVersion = current.Version
+	}
+	if req.Version != nil && *req.Version != currentVersion {
+		return nil, usecasemodels.ErrAdminPreferencesVersionConflict
+	}
+
+	preferences := &repositorymodels.AdminPreferences{
+		AdminID:       adminID,
+		SchemaVersion: usecasemodels.AdminPreferencesSchemaVersion,
+		Preferences:   document,
+	}
+	if err := uc.authRepo.SaveAdminPreferences(ctx, preferences, currentVersion); err != nil {
+		return nil, err
+	}
+
+	return adminPreferencesToResponse(preferences), nil
+}
+
+// validateAdminPreferences заполняет незаданные поля значениями по умолчанию и проверяет настройки.
+// В таблице каждая колонка указывается не больше одного раза.
+func validateAdminPreferences(document *repositorymodels.AdminPreferencesDocument) error {
+	applyAdminPreferencesDefaults(document)
+
+	if !slices.Contains(usecasemodels.AdminLocales, document.Locale) {
+		return usecasemodels.ErrorInvalidParameterLocale
+	}
+
+	if document.Theme != usecasemodels.ThemeLight && document.Theme != usecasemodels.ThemeDark {
+		return usecasemodels.ErrorInvalidParameterTheme
+	}
+
+	loc, err := time.LoadLocation(document.Timezone)
+	if err != nil || document.Timezone == "Local" {
+		return fmt.Errorf("%w: %s", usecasemodels.ErrorInvalidParameterTimezone, document.Timezone)
+	}
+	document.Timezone = loc.String()
+
+	if document.PageSize < 1 || document.PageSize > adminPreferencesMaxPageSize {
+		return usecasemodels.ErrorInvalidParameterPageSize
+	}
+
+	if len(document.Tables) > adminPreferencesMaxTables {
+		return fmt.Errorf("%w: more than %d tables", usecasemodels.ErrorInvalidParameterTables, adminPreferencesMaxTables)
+	}
+	for key, table := range document.Tables {
+		if !adminPreferencesKeyPattern.MatchString(key) {
+			return fmt.Errorf("%w: %s", usecasemodels.ErrorInvalidParameterTables, key)
+		}
+		if len(table.Columns) > adminPreferencesMaxColumns {
+			return fmt.Errorf("%w: %s: more than %d columns", usecasemodels.ErrorInvalidParameterTables, key, adminPreferencesMaxColumns)
+		}
+
+		seen := make(map[string]struct{}, len(table.Columns))
+		for _, column := range table.Columns {
+			if !adminPreferencesKeyPattern.MatchString(column.Key) {
+				return fmt.Errorf("%w: %s.%s", usecasemodels.ErrorInvalidParameterTables, key, column.Key)
+			}
+			if _, ok := seen[column.Key]; ok {
+				return fmt.Errorf("%w: %s.%s: duplicate column", usecasemodels.ErrorInvalidParameterTables, key, column.Key)
+			}
+			seen[column.Key] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// applyAdminPreferencesDefaults заполняет незаданные поля настроек значениями по умолчанию.
+func applyAdminPreferencesDefaults(document *repositorymodels.AdminPreferencesDocument) {
+	if document.Locale == "" {
+		document.Locale = usecasemodels.AdminLocales[0]
+	}
+	if document.Theme == "" {
+		document.Theme = usecasemodels.ThemeLight
+	}
+	if document.Timezone == "" {
+		document.Timezone = "UTC"
+	}
+	if document.PageSize == 0 {
+		document.PageSize = adminPreferencesDefaultPageSize
+	}
+	if document.Tables == nil {
+		document.Tables = make(map[string]repositorymodels.AdminTablePreferences)
+	}
+}
+
+// adminPreferencesToResponse преобразует настройки администратора в ответ.
+func adminPreferencesToResponse(preferences *repositorymodels.AdminPreferences) *usecasemodels.AdminPreferencesResponse {
+	document := preferences.Preferences
+	applyAdminPreferencesDefaults(&document)
+
+	response := &usecasemodels.AdminPreferencesResponse{
+		Version:       preferences.Version,
+		SchemaVersion: preferences.SchemaVersion,
+		Locale:        document.Locale,
+		Theme:         document.Theme,
+		Timezone:      document.Timezone,
+		PageSize:      document.PageSize,
+		Tables:        make(map[string]usecasemodels.AdminTablePreferences, len(document.Tables)),
+	}
+	for key, table := range document.Tables {
+		columns := make([]usecasemodels.AdminTableColumn, 0, len(table.Columns))
+		for _, column := range table.Columns {
+			columns = append(columns, usecasemodels.AdminTableColumn{Key: column.Key, Visible: column.Visible})
+		}
+		response.Tables[key] = usecasemodels.AdminTablePreferences{Columns: columns}
+	}
+	if preferences.Version > 0 {
+		response.UpdatedAt = formatOptionalTime(&preferences.UpdatedAt)
+	}
+
+	return response
+}
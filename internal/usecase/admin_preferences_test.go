@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+func TestValidateAdminPreferences(t *testing.T) {
+	tables := func(count int) map[string]repositorymodels.AdminTablePreferences {
+		result := make(map[string]repositorymodels.AdminTablePreferences, count)
+		for i := 0; i < count; i++ {
+			result[fmt.Sprintf("table_%d", i)] = repositorymodels.AdminTablePreferences{}
+		}
+
+		return result
+	}
+
+	columns := func(count int) []repositorymodels.AdminTableColumn {
+		result := make([]repositorymodels.AdminTableColumn, count)
+		for i := range result {
+			result[i] = repositorymodels.AdminTableColumn{Key: fmt.Sprintf("column_%d", i), Visible: true}
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		document repositorymodels.AdminPreferencesDocument
+		want     *repositorymodels.AdminPreferencesDocument
+		wantErr  error
+	}{
+		{
+			name: "defaults",
+			want: &repositorymodels.AdminPreferencesDocument{
+				Locale:   "ru",
+				Theme:    "light",
+				Timezone: "UTC",
+				PageSize: 10,
+				Tables:   map[string]repositorymodels.AdminTablePreferences{},
+			},
+		},
+		{
+			name: "valid",
+			document: repositorymodels.AdminPreferencesDocument{
+				Locale:   "en",
+				Theme:    "dark",
+				Timezone: "Europe/Moscow",
+				PageSize: 100,
+				Tables: map[string]repositorymodels.AdminTablePreferences{
+					"users": {Columns: []repositorymodels.AdminTableColumn{{Key: "email", Visible: true}, {Key: "attributes.city"}}},
+				},
+			},
+			want: &repositorymodels.AdminPreferencesDocument{
+				Locale:   "en",
+				Theme:    "dark",
+				Timezone: "Europe/Moscow",
+				PageSize: 100,
+				Tables: map[string]repositorymodels.AdminTablePreferences{
+					"users": {Columns: []repositorymodels.AdminTableColumn{{Key: "email", Visible: true}, {Key: "attributes.city"}}},
+				},
+			},
+		},
+		{name: "unknown locale", document: repositorymodels.AdminPreferencesDocument{Locale: "de"}, wantErr: usecasemodels.ErrorInvalidParameterLocale},
+		{name: "unknown theme", document: repositorymodels.AdminPreferencesDocument{Theme: "blue"}, wantErr: usecasemodels.ErrorInvalidParameterTheme},
+		{name: "unknown timezone", document: repositorymodels.AdminPreferencesDocument{Timezone: "Mars/Olympus"}, wantErr: usecasemodels.ErrorInvalidParameterTimezone},
+		{name: "server local timezone", document: repositorymodels.AdminPreferencesDocument{Timezone: "Local"}, wantErr: usecasemodels.ErrorInvalidParameterTimezone},
+		{name: "negative page size", document: repositorymodels.AdminPreferencesDocument{PageSize: -1}, wantErr: usecasemodels.ErrorInvalidParameterPageSize},
+		{name: "page size too large", document: repositorymodels.AdminPreferencesDocument{PageSize: 101}, wantErr: usecasemodels.ErrorInvalidParameterPageSize},
+		{name: "too many tables", document: repositorymodels.AdminPreferencesDocument{Tables: tables(51)}, wantErr: usecasemodels.ErrorInvalidParameterTables},
+		{
+			name: "invalid table key",
+			document: repositorymodels.AdminPreferencesDocument{
+				Tables: map[string]repositorymodels.AdminTablePreferences{"users list": {}},
+			},
+			wantErr: usecasemodels.ErrorInvalidParameterTables,
+		},
+		{
+			name: "too many columns",
+			document: repositorymodels.AdminPreferencesDocument{
+				Tables: map[string]repositorymodels.AdminTablePreferences{"users": {Columns: columns(101)}},
+			},
+			wantErr: usecasemodels.ErrorInvalidParameterTables,
+		},
+		{
+			name: "empty column key",
+			document: repositorymodels.AdminPreferencesDocument{
+				Tables: map[string]repositorymodels.AdminTablePreferences{"users": {Columns: []repositorymodels.AdminTableColumn{{Key: ""}}}},
+			},
+			wantErr: usecasemodels.ErrorInvalidParameterTables,
+		},
+		{
+			name: "duplicate column",
+			document: repositorymodels.AdminPreferencesDocument{
+				Tables: map[string]repositorymodels.AdminTablePreferences{
+					"users": {Columns: []repositorymodels.AdminTableColumn{{Key: "email"}, {Key: "email", Visible: true}}},
+				},
+			},
+			wantErr: usecasemodels.ErrorInvalidParameterTables,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document := tt.document
+
+			err := validateAdminPreferences(&document)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateAdminPreferences() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.want != nil && !reflect.DeepEqual(document, *tt.want) {
+				t.Fatalf("validateAdminPreferences() document = %+v, want %+v", document, *tt.want)
+			}
+		})
+	}
+}
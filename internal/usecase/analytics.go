@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/tabular"
+)
+
+// timeseriesMaxBuckets — максимальное число интервалов во временном ряду.
+const timeseriesMaxBuckets = 1000
+
+// GetUserTimeseries строит временной ряд событий пользователей по интервалам в часовом поясе запроса.
+// Интервалы без событий заполняются нулями.
+func (uc *UseCase) GetUserTimeseries(ctx context.Context, req *usecasemodels.UserTimeseriesRequest) (*usecasemodels.UserTimeseriesResponse, error) {
+	if req.Metric == "" {
+		req.Metric = usecasemodels.UserMetricSignups
+	}
+	if req.Metric != usecasemodels.UserMetricSignups &&
+		req.Metric != usecasemodels.UserMetricBans &&
+		req.Metric != usecasemodels.UserMetricDeletions {
+		return nil, usecasemodels.ErrorInvalidParameterMetric
+	}
+
+	if req.Breakdown != "" && req.Breakdown != usecasemodels.UserBreakdownRole && req.Breakdown != usecasemodels.UserBreakdownStatus {
+		return nil, usecasemodels.ErrorInvalidParameterBreakdown
+	}
+	// После разблокировки статус пользователя меняется, поэтому блокировки по текущему статусу не разбиваются.
+	if req.Metric == usecasemodels.UserMetricBans && req.Breakdown == usecasemodels.UserBreakdownStatus {
+		return nil, usecasemodels.ErrorInvalidParameterBreakdown
+	}
+
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(req.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", usecasemodels.ErrorInvalidParameterTimezone, req.Timezone)
+	}
+
+	storageLoc, err := time.LoadLocation(uc.cfg.Analytics.StorageTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("load storage timezone: %w", err)
+	}
+
+	if req.Interval == "" {
+		req.Interval = usecasemodels.TimeseriesIntervalDay
+	}
+	next, truncate, err := timeseriesInterval(req.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := timeseriesPeriod(req.From, req.To, loc, func(to time.Time) time.Time { return to.AddDate(0, 0, -29) })
+	if err != nil {
+		return nil, err
+	}
+	req.From = from.Format(usecasemodels.DateLayout)
+	req.To = to.Format(usecasemodels.DateLayout)
+
+	buckets, err := timeseriesBuckets(from, to, next, truncate, timeseriesMaxBuckets)
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := uc.analyticsRepo.GetUserTimeseries(ctx, &usecasemodels.UserTimeseriesQuery{
+		Metric:          req.Metric,
+		Interval:        req.Interval,
+		Breakdown:       req.Breakdown,
+		Timezone:        loc.String(),
+		StorageTimezone: storageLoc.String(),
+		From:            buckets[0].In(storageLoc),
+		To:              buckets[len(buckets)-1].In(storageLoc),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get user timeseries: %w", err)
+	}
+
+	// Начала интервалов из БД — часы в поясе запроса без зоны, поэтому сопоставляются по дате.
+	counts := make(map[string]map[string]int)
+	keys := make(map[string]struct{})
+	for _, point := range points {
+		date := point.Bucket.Format(usecasemodels.DateLayout)
+		if counts[date] == nil {
+			counts[date] = make(map[string]int)
+		}
+
+		key := stringValue(point.Key)
+		counts[date][key] += point.Count
+		keys[key] = struct{}{}
+	}
+
+	response := &usecasemodels.UserTimeseriesResponse{
+		Metric:    req.Metric,
+		Interval:  req.Interval,
+		Timezone:  loc.String(),
+		From:      req.From,
+		To:        req.To,
+		Breakdown: req.Breakdown,
+		Buckets:   make([]usecasemodels.UserTimeseriesBucket, 0, len(buckets)-1),
+	}
+	if req.Breakdown != "" {
+		response.Keys = slices.Sorted(maps.Keys(keys))
+	}
+
+	for _, start := range buckets[:len(buckets)-1] {
+		bucket := usecasemodels.UserTimeseriesBucket{Start: start.Format(time.RFC3339)}
+		bucketCounts := counts[start.Format(usecasemodels.DateLayout)]
+
+		if req.Breakdown != "" {
+			bucket.Breakdown = make(map[string]int, len(response.Keys))
+			for _, key := range response.Keys {
+				bucket.Breakdown[key] = bucketCounts[key]
+			}
+		}
+		for _, count := range bucketCounts {
+			bucket.Count += count
+		}
+
+		response.Total += bucket.Count
+		response.Buckets = append(response.Buckets, bucket)
+	}
+
+	return response, nil
+}
+
+// ExportUserTimeseries выгружает временной ряд пользователей в w: начало интервала, количество
+// и по колонке на каждое значение разбивки. Ничего не пишет в w, если запрос невалиден.
+func (uc *UseCase) ExportUserTimeseries(ctx context.Context, req *usecasemodels.ExportUserTimeseriesRequest, w io.Writer) error {
+	if req.Format != tabular.FormatCSV && req.Format != tabular.FormatNDJSON && req.Format != tabular.FormatXLSX {
+		return usecasemodels.ErrorInvalidParameterFormat
+	}
+
+	timeseries, err := uc.GetUserTimeseries(ctx, &req.UserTimeseriesRequest)
+	if err != nil {
+		return err
+	}
+
+	titles := map[string][]string{
+		"en": {"Period", "Count"},
+		"ru": {"Период", "Количество"},
+	}
+	lang := req.Lang
+	if lang != "ru" {
+		lang = "en"
+	}
+
+	keys := append([]string{"start", "count"}, timeseries.Keys...)
+	header := append(slices.Clone(titles[lang]), timeseries.Keys...)
+
+	writer, err := tabular.NewWriter(w, req.Format)
+	if err != nil {
+		return fmt.Errorf("create writer: %w", err)
+	}
+
+	if err := writer.WriteHeader(keys, header); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	values := make([]any, len(keys))
+	for _, bucket := range timeseries.Buckets {
+		values[0] = bucket.Start
+		values[1] = bucket.Count
+		for i, key := range timeseries.Keys {
+			values[i+2] = bucket.Breakdown[key]
+		}
+
+		if err := writer.WriteRow(values); err != nil {
+			return fmt.Errorf("write row: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("close writer: %w", err)
+	}
+
+	return nil
+}
+
+// timeseriesInterval возвращает функции перехода к началу следующего интервала и к началу интервала,
+// содержащего дату.
+func timeseriesInterval(interval string) (next, truncate func(t time.Time) time.Time, err error) {
+	switch interval {
+	case usecasemodels.TimeseriesIntervalDay:
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+		truncate = func(t time.Time) time.Time { return t }
+	case usecasemodels.TimeseriesIntervalWeek:
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+		truncate = func(t time.Time) time.Time { return t.AddDate(0, 0, -(int(t.Weekday())+6)%7) }
+	case usecasemodels.TimeseriesIntervalMonth:
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+		truncate = func(t time.Time) time.Time { return t.AddDate(0, 0, 1-t.Day()) }
+	default:
+		return nil, nil, usecasemodels.ErrorInvalidParameterInterval
+	}
+
+	return next, truncate, nil
+}
+
+// timeseriesPeriod разбирает даты периода (YYYY-MM-DD) как полночь в поясе loc.
+// Без to берется сегодняшний день, без from — defaultFrom(to).
+func timeseriesPeriod(from, to string, loc *time.Location, defaultFrom func(to time.Time) time.Time) (time.Time, time.Time, error) {
+	now := time.Now().In(loc)
+	toDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if to != "" {
+		date, err := parseDate(to)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		toDate = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	}
+
+	fromDate := defaultFrom(toDate)
+	if from != "" {
+		date, err := parseDate(from)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		fromDate = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	}
+
+	if toDate.Before(fromDate) {
+		return time.Time{}, time.Time{}, usecasemodels.ErrorInvalidParameterDate
+	}
+
+	return fromDate, toDate, nil
+}
+
+// timeseriesBuckets возвращает начала интервалов, целиком покрывающих период [from, to],
+// и конец последнего интервала: первый начинается не позже from, последний заканчивается после to.
+func timeseriesBuckets(from, to time.Time, next, truncate func(t time.Time) time.Time, maxBuckets int) ([]time.Time, error) {
+	buckets := []time.Time{truncate(from)}
+	for !buckets[len(buckets)-1].After(to) {
+		if len(buckets) > maxBuckets {
+			return nil, fmt.Errorf("%w: more than %d buckets", usecasemodels.ErrorInvalidParameterInterval, maxBuckets)
+		}
+
+		buckets = append(buckets, next(buckets[len(buckets)-1]))
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+const (
+	// cohortMaxCount — максимальное число когорт в запросе.
+	cohortMaxCount = 100
+	// cohortMaxPeriods — максимальное число периодов удержания.
+	cohortMaxPeriods = 36
+	// cohortDefaultPeriods — число периодов удержания по умолчанию.
+	cohortDefaultPeriods = 12
+	// cohortCacheMaxEntries — максимальное число закешированных матриц удержания.
+	cohortCacheMaxEntries = 256
+)
+
+// GetUserCohorts строит матрицу удержания: пользователи делятся на когорты по дате регистрации,
+// и для каждой когорты считается, сколько из них в конце каждого следующего периода имели
+// статус RetainedStatus и не были удалены. Статус на конец периода берется из истории изменений.
+// Результат кешируется для одинаковых параметров на ANALYTICS_COHORT_CACHE_TTL.
+func (uc *UseCase) GetUserCohorts(ctx context.Context, req *usecasemodels.UserCohortsRequest) (*usecasemodels.UserCohortsResponse, error) {
+	if req.Cohort == "" {
+		req.Cohort = usecasemodels.TimeseriesIntervalMonth
+	}
+	next, truncate, err := timeseriesInterval(req.Cohort)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Periods == 0 {
+		req.Periods = cohortDefaultPeriods
+	}
+	if req.Periods < 0 || req.Periods > cohortMaxPeriods {
+		return nil, usecasemodels.ErrorInvalidParameterPeriods
+	}
+
+	if req.RetainedStatus == "" {
+		req.RetainedStatus = "active"
+	}
+	if !uc.isKnownStatus(req.RetainedStatus) {
+		return nil, usecasemodels.ErrorInvalidParameterStatus
+	}
+
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(req.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", usecasemodels.ErrorInvalidParameterTimezone, req.Timezone)
+	}
+	req.Timezone = loc.String()
+
+	storageLoc, err := time.LoadLocation(uc.cfg.Analytics.StorageTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("load storage timezone: %w", err)
+	}
+
+	// По умолчанию берутся 12 когорт, последняя из которых — текущая.
+	from, to, err := timeseriesPeriod(req.From, req.To, loc, func(to time.Time) time.Time {
+		from := truncate(to)
+		for range cohortDefaultPeriods - 1 {
+			from = truncate(from.AddDate(0, 0, -1))
+		}
+
+		return from
+	})
+	if err != nil {
+		return nil, err
+	}
+	req.From = from.Format(usecasemodels.DateLayout)
+	req.To = to.Format(usecasemodels.DateLayout)
+
+	buckets, err := timeseriesBuckets(from, to, next, truncate, cohortMaxCount)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.preparePhoneSearch(&req.UserFilter)
+
+	if err := prepareTagFilter(&req.UserFilter); err != nil {
+		return nil, err
+	}
+
+	if err := uc.prepareUserAttributeQuery(ctx, &req.UserFilter, nil); err != nil {
+		return nil, err
+	}
+
+	key, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal cohort cache key: %w", err)
+	}
+	if cached, ok := uc.cohortCache.Get(string(key)); ok {
+		return cached, nil
+	}
+
+	now := time.Now()
+	query := &usecasemodels.UserCohortQuery{
+		UserFilter:     req.UserFilter,
+		Starts:         make([]time.Time, 0, len(buckets)-1),
+		Ends:           make([]time.Time, 0, len(buckets)-1),
+		RetainedStatus: req.RetainedStatus,
+	}
+	for i := range len(buckets) - 1 {
+		query.Starts = append(query.Starts, buckets[i].In(storageLoc))
+		query.Ends = append(query.Ends, buckets[i+1].In(storageLoc))
+
+		// Статус проверяется только на конец уже закончившихся периодов.
+		end := buckets[i+1]
+		for period := 0; period <= req.Periods && !end.After(now); period++ {
+			query.Checkpoints = append(query.Checkpoints, usecasemodels.UserCohortCheckpoint{
+				Cohort: i,
+				Period: period,
+				At:     end.In(storageLoc),
+			})
+			end = next(end)
+		}
+	}
+
+	sizes, err := uc.analyticsRepo.GetUserCohortSizes(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("get user cohort sizes: %w", err)
+	}
+
+	var retained []repositorymodels.UserCohortCount
+	if len(query.Checkpoints) > 0 {
+		if retained, err = uc.analyticsRepo.GetUserCohortRetention(ctx, query); err != nil {
+			return nil, fmt.Errorf("get user cohort retention: %w", err)
+		}
+	}
+
+	response := &usecasemodels.UserCohortsResponse{
+		Cohort:         req.Cohort,
+		Periods:        req.Periods,
+		Timezone:       req.Timezone,
+		From:           req.From,
+		To:             req.To,
+		RetainedStatus: req.RetainedStatus,
+		Cohorts:        make([]usecasemodels.UserCohortResult, len(buckets)-1),
+		ComputedAt:     now.Format(time.RFC3339),
+	}
+	for i := range response.Cohorts {
+		response.Cohorts[i] = usecasemodels.UserCohortResult{
+			Start:    buckets[i].Format(time.RFC3339),
+			Retained: make([]*int, req.Periods+1),
+			Rates:    make([]*float64, req.Periods+1),
+		}
+	}
+	for _, size := range sizes {
+		response.Cohorts[size.Cohort].Size = size.Count
+	}
+	for _, checkpoint := range query.Checkpoints {
+		zero := 0
+		response.Cohorts[checkpoint.Cohort].Retained[checkpoint.Period] = &zero
+	}
+	for _, count := range retained {
+		*response.Cohorts[count.Cohort].Retained[count.Period] = count.Count
+	}
+	for i := range response.Cohorts {
+		cohort := &response.Cohorts[i]
+		if cohort.Size == 0 {
+			continue
+		}
+
+		for period, count := range cohort.Retained {
+			if count == nil {
+				continue
+			}
+
+			rate := math.Round(float64(*count)/float64(cohort.Size)*10000) / 10000
+			cohort.Rates[period] = &rate
+		}
+	}
+
+	uc.cohortCache.Set(string(key), response)
+
+	return response, nil
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/config"
+)
+
+func TestTimeseriesInterval(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, moscow)
+	}
+
+	tests := []struct {
+		name         string
+		interval     string
+		date         time.Time
+		wantNext     time.Time
+		wantTruncate time.Time
+		wantErr      error
+	}{
+		{name: "day", interval: "day", date: date(2024, 2, 28), wantNext: date(2024, 2, 29), wantTruncate: date(2024, 2, 28)},
+		{name: "week from wednesday", interval: "week", date: date(2024, 10, 2), wantNext: date(2024, 10, 9), wantTruncate: date(2024, 9, 30)},
+		{name: "week from monday", interval: "week", date: date(2024, 9, 30), wantNext: date(2024, 10, 7), wantTruncate: date(2024, 9, 30)},
+		{name: "week from sunday", interval: "week", date: date(2024, 10, 6), wantNext: date(2024, 10, 13), wantTruncate: date(2024, 9, 30)},
+		{name: "month", interval: "month", date: date(2024, 12, 1), wantNext: date(2025, 1, 1), wantTruncate: date(2024, 12, 1)},
+		{name: "month from the middle", interval: "month", date: date(2024, 3, 15), wantNext: date(2024, 4, 15), wantTruncate: date(2024, 3, 1)},
+		{name: "unknown interval", interval: "year", wantErr: usecasemodels.ErrorInvalidParameterInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, truncate, err := timeseriesInterval(tt.interval)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("timeseriesInterval(%q) error = %v, want %v", tt.interval, err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got := next(tt.date); !got.Equal(tt.wantNext) {
+				t.Fatalf("next(%v) = %v, want %v", tt.date, got, tt.wantNext)
+			}
+			if got := truncate(tt.date); !got.Equal(tt.wantTruncate) {
+				t.Fatalf("truncate(%v) = %v, want %v", tt.date, got, tt.wantTruncate)
+			}
+		})
+	}
+}
+
+func TestTimeseriesBuckets(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		interval   string
+		from       time.Time
+		to         time.Time
+		maxBuckets int
+		want       []time.Time
+		wantErr    error
+	}{
+		{
+			name:     "single day",
+			interval: "day",
+			from:     date(2024, 10, 1), to: date(2024, 10, 1), maxBuckets: 10,
+			want: []time.Time{date(2024, 10, 1), date(2024, 10, 2)},
+		},
+		{
+			name:     "days",
+			interval: "day",
+			from:     date(2024, 2, 28), to: date(2024, 3, 1), maxBuckets: 10,
+			want: []time.Time{date(2024, 2, 28), date(2024, 2, 29), date(2024, 3, 1), date(2024, 3, 2)},
+		},
+		{
+			name:     "weeks start on monday before from",
+			interval: "week",
+			from:     date(2024, 10, 2), to: date(2024, 10, 8), maxBuckets: 10,
+			want: []time.Time{date(2024, 9, 30), date(2024, 10, 7), date(2024, 10, 14)},
+		},
+		{
+			name:     "months",
+			interval: "month",
+			from:     date(2024, 11, 15), to: date(2025, 1, 1), maxBuckets: 10,
+			want: []time.Time{date(2024, 11, 1), date(2024, 12, 1), date(2025, 1, 1), date(2025, 2, 1)},
+		},
+		{
+			name:     "exactly max buckets",
+			interval: "day",
+			from:     date(2024, 10, 1), to: date(2024, 10, 3), maxBuckets: 3,
+			want: []time.Time{date(2024, 10, 1), date(2024, 10, 2), date(2024, 10, 3), date(2024, 10, 4)},
+		},
+		{
+			name:     "too many buckets",
+			interval: "day",
+			from:     date(2024, 10, 1), to: date(2024, 10, 4), maxBuckets: 3,
+			wantErr: usecasemodels.ErrorInvalidParameterInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, truncate, err := timeseriesInterval(tt.interval)
+			if err != nil {
+				t.Fatalf("timeseriesInterval(%q) error = %v", tt.interval, err)
+			}
+
+			got, err := timeseriesBuckets(tt.from, tt.to, next, truncate, tt.maxBuckets)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("timeseriesBuckets() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("timeseriesBuckets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetUserTimeseriesValidation(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Analytics.StorageTimezone = "UTC"
+	uc := &UseCase{cfg: cfg}
+
+	tests := []struct {
+		name    string
+		req     usecasemodels.UserTimeseriesRequest
+		wantErr error
+	}{
+		{name: "unknown metric", req: usecasemodels.UserTimeseriesRequest{Metric: "logins"}, wantErr: usecasemodels.ErrorInvalidParameterMetric},
+		{name: "unknown breakdown", req: usecasemodels.UserTimeseriesRequest{Breakdown: "tag"}, wantErr: usecasemodels.ErrorInvalidParameterBreakdown},
+		{
+			name:    "bans by status",
+			req:     usecasemodels.UserTimeseriesRequest{Metric: "bans", Breakdown: "status"},
+			wantErr: usecasemodels.ErrorInvalidParameterBreakdown,
+		},
+		{name: "unknown timezone", req: usecasemodels.UserTimeseriesRequest{Timezone: "Mars/Olympus"}, wantErr: usecasemodels.ErrorInvalidParameterTimezone},
+		{name: "unknown interval", req: usecasemodels.UserTimeseriesRequest{Interval: "year"}, wantErr: usecasemodels.ErrorInvalidParameterInterval},
+		{
+			name:    "to before from",
+			req:     usecasemodels.UserTimeseriesRequest{From: "2024-10-02", To: "2024-10-01"},
+			wantErr: usecasemodels.ErrorInvalidParameterDate,
+		},
+		{
+			name:    "too many buckets",
+			req:     usecasemodels.UserTimeseriesRequest{From: "2020-01-01", To: "2024-10-01"},
+			wantErr: usecasemodels.ErrorInvalidParameterInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			if _, err := uc.GetUserTimeseries(context.Background(), &req); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetUserTimeseries() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
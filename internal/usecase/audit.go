@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/reqctx"
+
+	"github.com/google/uuid"
+)
+
+// auditGenesisHash — prev_hash первой записи журнала аудита.
+var auditGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// auditVerifyBatchSize — количество записей, читаемых за раз при проверке цепочки.
+const auditVerifyBatchSize = 1000
+
+// auditHashPayload — поля записи, которые входят в хеш. Порядок полей фиксирован.
+type auditHashPayload struct {
+	ID           string            `json:"id"`
+	ActorID      *string           `json:"actor_id"`
+	ActorEmail   *string           `json:"actor_email"`
+	Action       string            `json:"action"`
+	ResourceType string            `json:"resource_type"`
+	ResourceID   *string           `json:"resource_id"`
+	Outcome      string            `json:"outcome"`
+	IP           string            `json:"ip"`
+	UserAgent    string            `json:"user_agent"`
+	RequestID    string            `json:"request_id"`
+	Details      map[string]string `json:"details"`
+	CreatedAt    string            `json:"created_at"`
+}
+
+// RecordAudit добавляет запись в журнал аудита. Каждая запись содержит хеш
+// предыдущей, поэтому изменение или удаление любой записи обнаруживается VerifyAuditLog.
+// Вызывается вне транзакций бизнес-операций, чтобы запись не откатывалась вместе с ними
+// и блокировка журнала не удерживалась дольше необходимого.
+func (uc *UseCase) RecordAudit(ctx context.Context, entry *usecasemodels.AuditEntry) error {
+	info := reqctx.From(ctx)
+
+	actorID := entry.ActorID
+	if actorID == "" {
+		actorID = info.AdminID
+	}
+
+	actorEmail := entry.ActorEmail
+	if actorEmail == "" {
+		actorEmail = info.AdminEmail
+	}
+
+	details := entry.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+
+	record := &repositorymodels.AuditEntry{
+		ID:           uuid.New().String(),
+		ActorID:      optionalString(actorID),
+		ActorEmail:   optionalString(actorEmail),
+		Action:       entry.Action,
+		ResourceType: entry.ResourceType,
+		ResourceID:   optionalString(entry.ResourceID),
+		Outcome:      entry.Outcome,
+		IP:           info.IP,
+		UserAgent:    info.UserAgent,
+		RequestID:    info.RequestID,
+		Details:      details,
+		// TIMESTAMP хранит микросекунды без зоны: хешируем ровно то, что будет прочитано из БД.
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	// Запись в журнал доводится до конца, даже если клиент уже отключился.
+	ctx = context.WithoutCancel(ctx)
+
+	return uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := uc.auditRepo.LockAuditLog(ctx); err != nil {
+			return err
+		}
+
+		prevHash, err := uc.auditRepo.GetLastAuditHash(ctx)
+		if err != nil {
+			return fmt.Errorf("get last audit hash: %w", err)
+		}
+
+		if prevHash == "" {
+			prevHash = auditGenesisHash
+		}
+
+		record.PrevHash = prevHash
+		record.Hash, err = auditEntryHash(record)
+		if err != nil {
+			return err
+		}
+
+		if err := uc.auditRepo.CreateAuditEntry(ctx, record); err != nil {
+			return fmt.Errorf("create audit entry: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// audit записывает действие в журнал аудита. Ошибка записи только логируется:
+// недоступность журнала не должна ломать основную операцию.
+func (uc *UseCase) audit(ctx context.Context, entry *usecasemodels.AuditEntry) {
+	if err := uc.RecordAudit(ctx, entry); err != nil {
+		log.Printf("Failed to record audit entry %s: %v", entry.Action, err)
+	}
+}
+
+// auditResult записывает действие в журнал аудита с исходом, определенным по err.
+func (uc *UseCase) auditResult(ctx context.Context, entry *usecasemodels.AuditEntry, err error) {
+	entry.Outcome = usecasemodels.AuditOutcomeSuccess
+	if err != nil {
+		entry.Outcome = usecasemodels.AuditOutcomeFailure
+		if entry.Details == nil {
+			entry.Details = map[string]string{}
+		}
+		entry.Details["error"] = err.Error()
+	}
+
+	uc.audit(ctx, entry)
+}
+
+// GetAuditLog получает записи журнала аудита с фильтрацией и пагинацией.
+func (uc *UseCase) GetAuditLog(ctx context.Context, req *usecasemodels.GetAuditLogRequest) (*usecasemodels.GetAuditLogResponse, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 50
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, usecasemodels.ErrorInvalidParameterDate
+	}
+
+	entries, total, err := uc.auditRepo.GetAuditEntries(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("get audit entries: %w", err)
+	}
+
+	data := make([]usecasemodels.AuditEntryResponse, 0, len(entries))
+	for i := range entries {
+		data = append(data, auditEntryToResponse(&entries[i]))
+	}
+
+	return &usecasemodels.GetAuditLogResponse{
+		Data:       data,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.Limit))),
+	}, nil
+}
+
+// VerifyAuditLog проверяет цепочку хешей журнала аудита от первой записи до последней.
+// Проверка останавливается на первой записи, не совпадающей с цепочкой.
+func (uc *UseCase) VerifyAuditLog(ctx context.Context) (*usecasemodels.VerifyAuditLogResponse, error) {
+	result := &usecasemodels.VerifyAuditLogResponse{Valid: true}
+
+	prevHash := auditGenesisHash
+	var lastSeq int64
+	for {
+		entries, err := uc.auditRepo.GetAuditEntriesAfter(ctx, lastSeq, auditVerifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("get audit entries: %w", err)
+		}
+
+		for i := range entries {
+			entry := &entries[i]
+
+			hash, err := auditEntryHash(entry)
+			if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case entry.PrevHash != prevHash:
+				result.Reason = "prev_hash does not match the previous entry"
+			case entry.Hash != hash:
+				result.Reason = "hash does not match entry contents"
+			}
+
+			if result.Reason != "" {
+				seq := entry.Seq
+				result.Valid = false
+				result.BrokenSeq = &seq
+
+				return result, nil
+			}
+
+			result.Checked++
+			prevHash = entry.Hash
+			lastSeq = entry.Seq
+		}
+
+		if len(entries) < auditVerifyBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// auditEntryHash вычисляет хеш записи: sha256(prev_hash + содержимое записи).
+func auditEntryHash(entry *repositorymodels.AuditEntry) (string, error) {
+	payload, err := json.Marshal(auditHashPayload{
+		ID:           entry.ID,
+		ActorID:      entry.ActorID,
+		ActorEmail:   entry.ActorEmail,
+		Action:       entry.Action,
+		ResourceType: entry.ResourceType,
+		ResourceID:   entry.ResourceID,
+		Outcome:      entry.Outcome,
+		IP:           entry.IP,
+		UserAgent:    entry.UserAgent,
+		RequestID:    entry.RequestID,
+		Details:      entry.Details,
+		CreatedAt:    entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal audit payload: %w", err)
+	}
+
+	sum := sha256.Sum256(append([]byte(entry.PrevHash), payload...))
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// auditEntryToResponse преобразует запись журнала аудита в ответ.
+func auditEntryToResponse(entry *repositorymodels.AuditEntry) usecasemodels.AuditEntryResponse {
+	return usecasemodels.AuditEntryResponse{
+		Seq:          entry.Seq,
+		ID:           entry.ID,
+		ActorID:      entry.ActorID,
+		ActorEmail:   entry.ActorEmail,
+		Action:       entry.Action,
+		ResourceType: entry.ResourceType,
+		ResourceID:   entry.ResourceID,
+		Outcome:      entry.Outcome,
+		IP:           entry.IP,
+		UserAgent:    entry.UserAgent,
+		RequestID:    entry.RequestID,
+		Details:      entry.Details,
+		Hash:         entry.Hash,
+		CreatedAt:    entry.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"adminkaback/internal"
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/reqctx"
+)
+
+// testTxManager выполняет функцию без транзакции.
+type testTxManager struct{}
+
+func (testTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// testAuditRepo хранит журнал аудита в памяти.
+type testAuditRepo struct {
+	internal.AuditRepository
+	entries []repositorymodels.AuditEntry
+}
+
+func (r *testAuditRepo) LockAuditLog(context.Context) error {
+	return nil
+}
+
+func (r *testAuditRepo) GetLastAuditHash(context.Context) (string, error) {
+	if len(r.entries) == 0 {
+		return "", nil
+	}
+
+	return r.entries[len(r.entries)-1].Hash, nil
+}
+
+func (r *testAuditRepo) CreateAuditEntry(_ context.Context, entry *repositorymodels.AuditEntry) error {
+	entry.Seq = int64(len(r.entries) + 1)
+	r.entries = append(r.entries, *entry)
+
+	return nil
+}
+
+func (r *testAuditRepo) GetAuditEntriesAfter(_ context.Context, afterSeq int64, limit int) ([]repositorymodels.AuditEntry, error) {
+	var entries []repositorymodels.AuditEntry
+	for _, entry := range r.entries {
+		if entry.Seq > afterSeq && len(entries) < limit {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// newAuditTestUseCase создает UseCase с журналом аудита из count записей.
+func newAuditTestUseCase(t *testing.T, count int) (*UseCase, *testAuditRepo) {
+	t.Helper()
+
+	repo := &testAuditRepo{}
+	uc := &UseCase{txManager: testTxManager{}, auditRepo: repo}
+
+	ctx := reqctx.With(context.Background(), &reqctx.Info{AdminID: "admin-1", AdminEmail: "admin@example.com", IP: "127.0.0.1"})
+	for i := 0; i < count; i++ {
+		err := uc.RecordAudit(ctx, &usecasemodels.AuditEntry{
+			Action:       "user.update",
+			ResourceType: "user",
+			ResourceID:   fmt.Sprintf("user-%d", i),
+			Outcome:      usecasemodels.AuditOutcomeSuccess,
+			Details:      map[string]string{"status": "200"},
+		})
+		if err != nil {
+			t.Fatalf("RecordAudit() error = %v", err)
+		}
+	}
+
+	return uc, repo
+}
+
+func TestRecordAuditChain(t *testing.T) {
+	_, repo := newAuditTestUseCase(t, 3)
+
+	prevHash := auditGenesisHash
+	for _, entry := range repo.entries {
+		if entry.PrevHash != prevHash {
+			t.Fatalf("entry %d prev_hash = %s, want %s", entry.Seq, entry.PrevHash, prevHash)
+		}
+
+		hash, err := auditEntryHash(&entry)
+		if err != nil {
+			t.Fatalf("auditEntryHash() error = %v", err)
+		}
+		if entry.Hash != hash {
+			t.Fatalf("entry %d hash = %s, want %s", entry.Seq, entry.Hash, hash)
+		}
+		if entry.ActorID == nil || *entry.ActorID != "admin-1" || entry.IP != "127.0.0.1" {
+			t.Fatalf("entry %d actor = %v, ip = %q, want request metadata", entry.Seq, entry.ActorID, entry.IP)
+		}
+
+		prevHash = entry.Hash
+	}
+}
+
+func TestAuditEntryHash(t *testing.T) {
+	actor := "admin-1"
+	otherActor := "admin-2"
+	base := repositorymodels.AuditEntry{
+		ID:           "entry-1",
+		ActorID:      &actor,
+		Action:       "user.update",
+		ResourceType: "user",
+		Outcome:      usecasemodels.AuditOutcomeSuccess,
+		Details:      map[string]string{"status": "200", "path": "/api/v1/users/1"},
+		PrevHash:     auditGenesisHash,
+		CreatedAt:    time.Date(2024, 3, 1, 12, 0, 0, 123456000, time.UTC),
+	}
+
+	baseHash, err := auditEntryHash(&base)
+	if err != nil {
+		t.Fatalf("auditEntryHash() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		change   func(e *repositorymodels.AuditEntry)
+		wantSame bool
+	}{
+		{name: "same contents", change: func(*repositorymodels.AuditEntry) {}, wantSame: true},
+		{
+			name: "same time in another zone",
+			change: func(e *repositorymodels.AuditEntry) {
+				e.CreatedAt = e.CreatedAt.In(time.FixedZone("MSK", 3*60*60))
+			},
+			wantSame: true,
+		},
+		{name: "prev hash", change: func(e *repositorymodels.AuditEntry) { e.PrevHash = "1" + e.PrevHash[1:] }},
+		{name: "actor", change: func(e *repositorymodels.AuditEntry) { e.ActorID = &otherActor }},
+		{name: "no actor", change: func(e *repositorymodels.AuditEntry) { e.ActorID = nil }},
+		{name: "outcome", change: func(e *repositorymodels.AuditEntry) { e.Outcome = usecasemodels.AuditOutcomeFailure }},
+		{name: "details", change: func(e *repositorymodels.AuditEntry) { e.Details = map[string]string{"status": "403"} }},
+		{name: "created at", change: func(e *repositorymodels.AuditEntry) { e.CreatedAt = e.CreatedAt.Add(time.Microsecond) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := base
+			entry.Details = map[string]string{}
+			for key, value := range base.Details {
+				entry.Details[key] = value
+			}
+			tt.change(&entry)
+
+			hash, err := auditEntryHash(&entry)
+			if err != nil {
+				t.Fatalf("auditEntryHash() error = %v", err)
+			}
+
+			if same := hash == baseHash; same != tt.wantSame {
+				t.Fatalf("auditEntryHash() same = %v, want %v", same, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	// rehash пересчитывает хеш измененной записи, чтобы подделка не обнаруживалась по ее содержимому.
+	rehash := func(t *testing.T, e *repositorymodels.AuditEntry) {
+		hash, err := auditEntryHash(e)
+		if err != nil {
+			t.Fatalf("auditEntryHash() error = %v", err)
+		}
+		e.Hash = hash
+	}
+
+	tests := []struct {
+		name          string
+		count         int
+		tamper        func(t *testing.T, entries []repositorymodels.AuditEntry) []repositorymodels.AuditEntry
+		wantBrokenSeq int64
+		wantReason    string
+		wantChecked   int64
+	}{
+		{name: "empty", count: 0, wantChecked: 0},
+		{name: "intact", count: 5, wantChecked: 5},
+		{name: "intact across batches", count: 2*auditVerifyBatchSize + 1, wantChecked: 2*auditVerifyBatchSize + 1},
+		{
+			name:  "changed details",
+			count: 5,
+			tamper: func(_ *testing.T, entries []repositorymodels.AuditEntry) []repositorymodels.AuditEntry {
+				entries[2].Details = map[string]string{"status": "403"}
+				return entries
+			},
+			wantBrokenSeq: 3, wantReason: "hash does not match entry contents", wantChecked: 2,
+		},
+		{
+			name:  "changed first entry",
+			count: 5,
+			tamper: func(_ *testing.T, entries []repositorymodels.AuditEntry) []repositorymodels.AuditEntry {
+				entries[0].Action = "user.delete"
+				return entries
+			},
+			wantBrokenSeq: 1, wantReason: "hash does not match entry contents", wantChecked: 0,
+		},
+		{
+			name:  "changed and rehashed entry",
+			count: 5,
+			tamper: func(t *testing.T, entries []repositorymodels.AuditEntry) []repositorymodels.AuditEntry {
+				entries[2].Outcome = usecasemodels.AuditOutcomeFailure
+				rehash(t, &entries[2])
+				return entries
+			},
+			wantBrokenSeq: 4, wantReason: "prev_hash does not match the previous entry", wantChecked: 3,
+		},
+		{
+			name:  "deleted entry",
+			count: 5,
+			tamper: func(_ *testing.T, entries []repositorymodels.AuditEntry) []repositorymodels.AuditEntry {
+				return append(entries[:2], entries[3:]...)
+			},
+			wantBrokenSeq: 4, wantReason: "prev_hash does not match the previous entry", wantChecked: 2,
+		},
+		{
+			name:  "appended entry forking the chain",
+			count: 5,
+			tamper: func(t *testing.T, entries []repositorymodels.AuditEntry) []repositorymodels.AuditEntry {
+				forged := entries[4]
+				forged.ID = "forged"
+				forged.Seq = 6
+				forged.PrevHash = entries[3].Hash
+				rehash(t, &forged)
+
+				return append(entries, forged)
+			},
+			wantBrokenSeq: 6, wantReason: "prev_hash does not match the previous entry", wantChecked: 5,
+		},
+		{
+			name:  "changed entry in a later batch",
+			count: auditVerifyBatchSize + 10,
+			tamper: func(_ *testing.T, entries []repositorymodels.AuditEntry) []repositorymodels.AuditEntry {
+				entries[auditVerifyBatchSize+4].IP = "10.0.0.1"
+				return entries
+			},
+			wantBrokenSeq: auditVerifyBatchSize + 5, wantReason: "hash does not match entry contents", wantChecked: auditVerifyBatchSize + 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo := newAuditTestUseCase(t, tt.count)
+			if tt.tamper != nil {
+				repo.entries = tt.tamper(t, repo.entries)
+			}
+
+			result, err := uc.VerifyAuditLog(context.Background())
+			if err != nil {
+				t.Fatalf("VerifyAuditLog() error = %v", err)
+			}
+
+			if result.Checked != tt.wantChecked {
+				t.Fatalf("VerifyAuditLog() checked = %d, want %d", result.Checked, tt.wantChecked)
+			}
+
+			if tt.wantBrokenSeq == 0 {
+				if !result.Valid || result.BrokenSeq != nil {
+					t.Fatalf("VerifyAuditLog() = %+v, want valid", result)
+				}
+
+				return
+			}
+
+			if result.Valid || result.BrokenSeq == nil || *result.BrokenSeq != tt.wantBrokenSeq {
+				t.Fatalf("VerifyAuditLog() = %+v, want broken at seq %d", result, tt.wantBrokenSeq)
+			}
+			if result.Reason != tt.wantReason {
+				t.Fatalf("VerifyAuditLog() reason = %q, want %q", result.Reason, tt.wantReason)
+			}
+		})
+	}
+}
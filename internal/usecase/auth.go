@@ -13,7 +13,16 @@ import (
 )
 
 // Register регистрирует нового администратора.
-func (uc *UseCase) Register(ctx context.Context, req *usecasemodels.RegisterRequest) (*usecasemodels.AuthResponse, error) {
+func (uc *UseCase) Register(ctx context.Context, req *usecasemodels.RegisterRequest) (resp *usecasemodels.AuthResponse, err error) {
+	defer func() {
+		entry := &usecasemodels.AuditEntry{Action: "admin.register", ResourceType: "admin", ActorEmail: req.Email}
+		if resp != nil {
+			entry.ActorID = resp.Admin.ID
+			entry.ResourceID = resp.Admin.ID
+		}
+		uc.auditResult(ctx, entry, err)
+	}()
+
 	if err := uc.validateRegisterRequest(req); err != nil {
 		return nil, err
 	}
@@ -83,7 +92,17 @@ func (uc *UseCase) Register(ctx context.Context, req *usecasemodels.RegisterRequ
 }
 
 // Login выполняет вход администратора.
-func (uc *UseCase) Login(ctx context.Context, req *usecasemodels.LoginRequest) (*usecasemodels.AuthResponse, error) {
+// Успешные и неудачные попытки входа записываются в журнал аудита.
+func (uc *UseCase) Login(ctx context.Context, req *usecasemodels.LoginRequest) (resp *usecasemodels.AuthResponse, err error) {
+	defer func() {
+		entry := &usecasemodels.AuditEntry{Action: "auth.login", ResourceType: "admin", ActorEmail: req.Email}
+		if resp != nil {
+			entry.ActorID = resp.Admin.ID
+			entry.ResourceID = resp.Admin.ID
+		}
+		uc.auditResult(ctx, entry, err)
+	}()
+
 	if err := uc.validateLoginRequest(req); err != nil {
 		return nil, err
 	}
@@ -140,7 +159,17 @@ func (uc *UseCase) Login(ctx context.Context, req *usecasemodels.LoginRequest) (
 }
 
 // RefreshToken обновляет access токен по refresh токену.
-func (uc *UseCase) RefreshToken(ctx context.Context, refreshToken string) (*usecasemodels.RefreshTokenResponse, error) {
+func (uc *UseCase) RefreshToken(ctx context.Context, refreshToken string) (resp *usecasemodels.RefreshTokenResponse, err error) {
+	var adminID string
+	defer func() {
+		uc.auditResult(ctx, &usecasemodels.AuditEntry{
+			Action:       "auth.refresh",
+			ResourceType: "admin",
+			ActorID:      adminID,
+			ResourceID:   adminID,
+		}, err)
+	}()
+
 	tokenModel, err := uc.authRepo.GetRefreshToken(ctx, refreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("get refresh token: %w", err)
@@ -150,6 +179,8 @@ func (uc *UseCase) RefreshToken(ctx context.Context, refreshToken string) (*usec
 		return nil, usecasemodels.ErrInvalidToken
 	}
 
+	adminID = tokenModel.AdminID
+
 	if time.Now().After(tokenModel.ExpiresAt) {
 		return nil, usecasemodels.ErrExpiredToken
 	}
@@ -174,7 +205,26 @@ func (uc *UseCase) RefreshToken(ctx context.Context, refreshToken string) (*usec
 }
 
 // Logout выполняет выход администратора.
-func (uc *UseCase) Logout(ctx context.Context, refreshToken string) error {
+func (uc *UseCase) Logout(ctx context.Context, refreshToken string) (err error) {
+	var adminID string
+	defer func() {
+		uc.auditResult(ctx, &usecasemodels.AuditEntry{
+			Action:       "auth.logout",
+			ResourceType: "admin",
+			ActorID:      adminID,
+			ResourceID:   adminID,
+		}, err)
+	}()
+
+	tokenModel, err := uc.authRepo.GetRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return fmt.Errorf("get refresh token: %w", err)
+	}
+
+	if tokenModel != nil {
+		adminID = tokenModel.AdminID
+	}
+
 	if err := uc.authRepo.DeleteRefreshToken(ctx, refreshToken); err != nil {
 		return fmt.Errorf("delete refresh token: %w", err)
 	}
@@ -182,7 +232,7 @@ func (uc *UseCase) Logout(ctx context.Context, refreshToken string) error {
 	return nil
 }
 
-// GetCurrentAdmin получает данные текущего администратора.
+// GetCurrentAdmin получает данные текущего администратора вместе с его настройками интерфейса.
 func (uc *UseCase) GetCurrentAdmin(ctx context.Context, adminID string) (*usecasemodels.AdminResponse, error) {
 	admin, err := uc.authRepo.GetAdminByID(ctx, adminID)
 	if err != nil {
@@ -193,18 +243,26 @@ func (uc *UseCase) GetCurrentAdmin(ctx context.Context, adminID string) (*usecas
 		return nil, usecasemodels.ErrAdminNotFound
 	}
 
+	preferences, err := uc.GetAdminPreferences(ctx, admin.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &usecasemodels.AdminResponse{
-		ID:    admin.ID,
-		Email: admin.Email,
-		Name:  admin.Name,
-		Role:  admin.Role,
+		ID:          admin.ID,
+		Email:       admin.Email,
+		Name:        admin.Name,
+		Role:        admin.Role,
+		Preferences: preferences,
 	}, nil
 }
 
 func (uc *UseCase) validateRegisterRequest(req *usecasemodels.RegisterRequest) error {
-	if req.Email == "" {
-		return usecasemodels.ErrorInvalidParameterEmail
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return err
 	}
+	req.Email = email
 
 	if req.Password == "" || len(req.Password) < 8 {
 		return usecasemodels.ErrorInvalidParameterPassword
@@ -222,6 +280,12 @@ func (uc *UseCase) validateLoginRequest(req *usecasemodels.LoginRequest) error {
 		return usecasemodels.ErrorInvalidParameterEmail
 	}
 
+	// Поиск администратора не зависит от регистра, нормализация нужна для IDN-доменов.
+	// Email, не прошедший нормализацию, ищется как есть: вход просто не найдет администратора.
+	if email, err := normalizeEmail(req.Email); err == nil {
+		req.Email = email
+	}
+
 	if req.Password == "" {
 		return usecasemodels.ErrorInvalidParameterPassword
 	}
@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// bloggerFieldMaxLength — максимальная длина имени и username блогера.
+	bloggerFieldMaxLength = 255
+	// bloggerNumberMaxLength — максимальная длина номера блогера.
+	bloggerNumberMaxLength = 64
+)
+
+// GetBloggers получает список блогеров с фильтрацией и пагинацией.
+func (uc *UseCase) GetBloggers(ctx context.Context, req *usecasemodels.GetBloggersRequest) (*usecasemodels.GetBloggersResponse, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	if err := prepareBloggerFilter(&req.BloggerFilter); err != nil {
+		return nil, err
+	}
+
+	bloggers, total, err := uc.bloggerRepo.GetBloggers(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("get bloggers: %w", err)
+	}
+
+	data := make([]usecasemodels.BloggerResponse, 0, len(bloggers))
+	for i := range bloggers {
+		data = append(data, bloggerToResponse(&bloggers[i]))
+	}
+
+	return &usecasemodels.GetBloggersResponse{
+		Data:       data,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.Limit))),
+	}, nil
+}
+
+// GetBlogger получает блогера по ID вместе с загрузками его записей из localStorage.
+func (uc *UseCase) GetBlogger(ctx context.Context, id string) (*usecasemodels.BloggerResponse, error) {
+	blogger, err := uc.getBlogger(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	contributions, err := uc.bloggerRepo.GetBloggerContributions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get blogger contributions: %w", err)
+	}
+
+	response := bloggerToResponse(blogger)
+	for _, contribution := range contributions {
+		response.Contributors = append(response.Contributors, usecasemodels.BloggerContributionResponse{
+			AdminID:   contribution.AdminID,
+			LegacyID:  contribution.LegacyID,
+			Outcome:   contribution.Outcome,
+			CreatedAt: contribution.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &response, nil
+}
+
+// CreateBlogger создает блогера. Username уникален без учета регистра среди неудаленных блогеров.
+func (uc *UseCase) CreateBlogger(ctx context.Context, req *usecasemodels.CreateBloggerRequest) (*usecasemodels.BloggerResponse, error) {
+	now := time.Now()
+	blogger := &repositorymodels.Blogger{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		Username:  req.Username,
+		Status:    req.Status,
+		Tags:      req.Tags,
+		Number:    req.Number,
+		Date:      now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if blogger.Status == "" {
+		blogger.Status = usecasemodels.BloggerStatusOn
+	}
+
+	if req.Date != "" {
+		date, err := parseDate(req.Date)
+		if err != nil {
+			return nil, err
+		}
+		blogger.Date = date
+	}
+
+	if err := validateBlogger(blogger); err != nil {
+		return nil, err
+	}
+
+	if err := uc.bloggerRepo.CreateBlogger(ctx, blogger); err != nil {
+		return nil, err
+	}
+
+	response := bloggerToResponse(blogger)
+	return &response, nil
+}
+
+// UpdateBlogger изменяет заданные поля блогера.
+func (uc *UseCase) UpdateBlogger(ctx context.Context, id string, req *usecasemodels.UpdateBloggerRequest) (*usecasemodels.BloggerResponse, error) {
+	blogger, err := uc.getBlogger(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		blogger.Name = *req.Name
+	}
+	if req.Username != nil {
+		blogger.Username = *req.Username
+	}
+	if req.Status != nil {
+		blogger.Status = *req.Status
+	}
+	if req.Tags != nil {
+		blogger.Tags = req.Tags
+	}
+	if req.Number != nil {
+		blogger.Number = req.Number
+	}
+	if req.Date != nil {
+		date, err := parseDate(*req.Date)
+		if err != nil {
+			return nil, err
+		}
+		blogger.Date = date
+	}
+
+	if err := validateBlogger(blogger); err != nil {
+		return nil, err
+	}
+
+	if err := uc.bloggerRepo.UpdateBlogger(ctx, blogger); err != nil {
+		return nil, err
+	}
+
+	response := bloggerToResponse(blogger)
+	return &response, nil
+}
+
+// DeleteBlogger удаляет блогера (soft delete).
+func (uc *UseCase) DeleteBlogger(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return usecasemodels.ErrBloggerNotFound
+	}
+
+	return uc.bloggerRepo.DeleteBlogger(ctx, id)
+}
+
+// getBlogger получает неудаленного блогера или возвращает ErrBloggerNotFound.
+func (uc *UseCase) getBlogger(ctx context.Context, id string) (*repositorymodels.Blogger, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, usecasemodels.ErrBloggerNotFound
+	}
+
+	blogger, err := uc.bloggerRepo.GetBloggerByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get blogger by id: %w", err)
+	}
+
+	if blogger == nil {
+		return nil, usecasemodels.ErrBloggerNotFound
+	}
+
+	return blogger, nil
+}
+
+// validateBlogger нормализует и проверяет поля блогера: username сохраняется без "@",
+// метки — без пробелов по краям, пустых значений и повторов.
+func validateBlogger(blogger *repositorymodels.Blogger) error {
+	blogger.Name = strings.TrimSpace(blogger.Name)
+	if blogger.Name == "" || len([]rune(blogger.Name)) > bloggerFieldMaxLength {
+		return usecasemodels.ErrorInvalidParameterName
+	}
+
+	blogger.Username = normalizeBloggerUsername(blogger.Username)
+	if blogger.Username == "" || len([]rune(blogger.Username)) > bloggerFieldMaxLength {
+		return usecasemodels.ErrorInvalidParameterUsername
+	}
+
+	if blogger.Status != usecasemodels.BloggerStatusOn && blogger.Status != usecasemodels.BloggerStatusOff {
+		return usecasemodels.ErrorInvalidParameterStatus
+	}
+
+	tags := make([]string, 0, len(blogger.Tags))
+	for _, tag := range blogger.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || containsString(tags, tag) {
+			continue
+		}
+		if len([]rune(tag)) > tagNameMaxLength {
+			return fmt.Errorf("%w: %s", usecasemodels.ErrorInvalidParameterTags, tag)
+		}
+
+		tags = append(tags, tag)
+	}
+	blogger.Tags = tags
+
+	if blogger.Number != nil {
+		number := strings.TrimSpace(*blogger.Number)
+		if len([]rune(number)) > bloggerNumberMaxLength {
+			return usecasemodels.ErrorInvalidParameterNumber
+		}
+
+		blogger.Number = optionalString(number)
+	}
+
+	return nil
+}
+
+// normalizeBloggerUsername убирает пробелы по краям и ведущий "@".
+func normalizeBloggerUsername(username string) string {
+	return strings.TrimPrefix(strings.TrimSpace(username), "@")
+}
+
+// parseDate разбирает дату в формате YYYY-MM-DD.
+func parseDate(value string) (time.Time, error) {
+	date, err := time.Parse(usecasemodels.DateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", usecasemodels.ErrorInvalidParameterDate, value)
+	}
+
+	return date, nil
+}
+
+// prepareBloggerFilter проверяет режим фильтра по меткам и интервал дат.
+func prepareBloggerFilter(filter *usecasemodels.BloggerFilter) error {
+	if filter.TagsMode == "" {
+		filter.TagsMode = usecasemodels.TagsModeAny
+	}
+	if filter.TagsMode != usecasemodels.TagsModeAny && filter.TagsMode != usecasemodels.TagsModeAll {
+		return usecasemodels.ErrorInvalidParameterTagsMode
+	}
+
+	var from, to time.Time
+	var err error
+	if filter.DateFrom != "" {
+		if from, err = parseDate(filter.DateFrom); err != nil {
+			return err
+		}
+	}
+	if filter.DateTo != "" {
+		if to, err = parseDate(filter.DateTo); err != nil {
+			return err
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return usecasemodels.ErrorInvalidParameterDate
+	}
+
+	return nil
+}
+
+// bloggerToResponse преобразует модель блогера в ответ.
+func bloggerToResponse(blogger *repositorymodels.Blogger) usecasemodels.BloggerResponse {
+	return usecasemodels.BloggerResponse{
+		ID:        blogger.ID,
+		Name:      blogger.Name,
+		Username:  blogger.Username,
+		Status:    blogger.Status,
+		Tags:      nonNilStrings(blogger.Tags),
+		Number:    blogger.Number,
+		Date:      blogger.Date.Format(usecasemodels.DateLayout),
+		CreatedAt: blogger.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: blogger.UpdatedAt.Format(time.RFC3339),
+	}
+}
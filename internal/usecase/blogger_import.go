@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/reqctx"
+
+	"github.com/google/uuid"
+)
+
+// bloggerLegacyImportMaxRecords — максимальное количество записей в одной загрузке из localStorage.
+const bloggerLegacyImportMaxRecords = 5000
+
+// ImportLegacyBloggers загружает список блогеров из localStorage фронтенда. Блогеры сопоставляются
+// по username без учета регистра: новый username создает блогера, совпадающая запись считается
+// дубликатом, а расходящаяся — конфликтом, при котором существующие значения не меняются.
+// Каждая загруженная запись сохраняется вместе с ID администратора, который ее загрузил.
+// Записи с ошибками пропускаются и перечисляются в ответе.
+func (uc *UseCase) ImportLegacyBloggers(ctx context.Context, records []usecasemodels.BloggerLegacyRecord) (resp *usecasemodels.BloggerLegacyImportResponse, err error) {
+	defer func() {
+		entry := &usecasemodels.AuditEntry{
+			Action:       "blogger.import_legacy",
+			ResourceType: "blogger",
+			Details:      map[string]string{"total": strconv.Itoa(len(records))},
+		}
+		if err == nil {
+			entry.Details["created"] = strconv.Itoa(resp.Created)
+			entry.Details["duplicates"] = strconv.Itoa(resp.Duplicates)
+			entry.Details["conflicts"] = strconv.Itoa(len(resp.Conflicts))
+			entry.Details["errors"] = strconv.Itoa(len(resp.Errors))
+		}
+
+		uc.auditResult(ctx, entry, err)
+	}()
+
+	if len(records) == 0 || len(records) > bloggerLegacyImportMaxRecords {
+		return nil, usecasemodels.ErrorInvalidParameterRecords
+	}
+
+	resp = &usecasemodels.BloggerLegacyImportResponse{
+		Total:     len(records),
+		Conflicts: []usecasemodels.BloggerLegacyConflict{},
+		Errors:    []usecasemodels.BloggerLegacyError{},
+	}
+
+	now := time.Now()
+	bloggers := make([]*repositorymodels.Blogger, len(records))
+	usernames := make([]string, 0, len(records))
+	for i := range records {
+		blogger, err := legacyRecordToBlogger(&records[i], now)
+		if err != nil {
+			resp.Errors = append(resp.Errors, usecasemodels.BloggerLegacyError{
+				Index:    i,
+				LegacyID: records[i].ID,
+				Message:  err.Error(),
+			})
+
+			continue
+		}
+
+		bloggers[i] = blogger
+		usernames = append(usernames, strings.ToLower(blogger.Username))
+	}
+
+	adminID := optionalString(reqctx.From(ctx).AdminID)
+	err = uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := uc.bloggerRepo.LockBloggerImport(ctx); err != nil {
+			return err
+		}
+
+		existing, err := uc.bloggerRepo.GetBloggersByUsernames(ctx, usernames)
+		if err != nil {
+			return fmt.Errorf("get bloggers by usernames: %w", err)
+		}
+
+		known := make(map[string]*repositorymodels.Blogger, len(existing))
+		for i := range existing {
+			known[strings.ToLower(existing[i].Username)] = &existing[i]
+		}
+
+		for i, blogger := range bloggers {
+			if blogger == nil {
+				continue
+			}
+
+			key := strings.ToLower(blogger.Username)
+			target, ok := known[key]
+			outcome := usecasemodels.BloggerContributionCreated
+			if !ok {
+				if err := uc.bloggerRepo.CreateBlogger(ctx, blogger); err != nil {
+					return fmt.Errorf("create blogger %q: %w", blogger.Username, err)
+				}
+
+				known[key] = blogger
+				target = blogger
+				resp.Created++
+			} else if fields := diffBloggers(target, blogger); len(fields) > 0 {
+				outcome = usecasemodels.BloggerContributionConflict
+				resp.Conflicts = append(resp.Conflicts, usecasemodels.BloggerLegacyConflict{
+					Index:     i,
+					LegacyID:  records[i].ID,
+					Username:  target.Username,
+					BloggerID: target.ID,
+					Fields:    fields,
+				})
+			} else {
+				outcome = usecasemodels.BloggerContributionDuplicate
+				resp.Duplicates++
+			}
+
+			contribution := &repositorymodels.BloggerContribution{
+				ID:        uuid.New().String(),
+				BloggerID: target.ID,
+				AdminID:   adminID,
+				LegacyID:  optionalString(records[i].ID),
+				Outcome:   outcome,
+				Payload:   legacyRecordPayload(&records[i]),
+				CreatedAt: now,
+			}
+			if err := uc.bloggerRepo.CreateBloggerContribution(ctx, contribution); err != nil {
+				return fmt.Errorf("create blogger contribution: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// legacyRecordToBlogger преобразует запись из localStorage в блогера и проверяет ее.
+// Дата записи задана в формате ru-RU (DD.MM.YYYY), пустой статус считается ON.
+func legacyRecordToBlogger(record *usecasemodels.BloggerLegacyRecord, now time.Time) (*repositorymodels.Blogger, error) {
+	date, err := time.Parse(usecasemodels.BloggerLegacyDateLayout, strings.TrimSpace(record.Date))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", usecasemodels.ErrorInvalidParameterDate, record.Date)
+	}
+
+	blogger := &repositorymodels.Blogger{
+		ID:        uuid.New().String(),
+		Name:      record.Name,
+		Username:  record.Username,
+		Status:    strings.ToUpper(strings.TrimSpace(record.Status)),
+		Tags:      record.Tags,
+		Number:    optionalString(record.Number),
+		Date:      date,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if blogger.Status == "" {
+		blogger.Status = usecasemodels.BloggerStatusOn
+	}
+
+	if err := validateBlogger(blogger); err != nil {
+		return nil, err
+	}
+
+	return blogger, nil
+}
+
+// diffBloggers возвращает поля, в которых загруженная запись расходится с существующим блогером.
+// Метки сравниваются без учета порядка.
+func diffBloggers(existing, loaded *repositorymodels.Blogger) []string {
+	var fields []string
+	if existing.Name != loaded.Name {
+		fields = append(fields, "name")
+	}
+	if existing.Status != loaded.Status {
+		fields = append(fields, "status")
+	}
+
+	existingTags := slices.Sorted(slices.Values(existing.Tags))
+	loadedTags := slices.Sorted(slices.Values(loaded.Tags))
+	if !slices.Equal(existingTags, loadedTags) {
+		fields = append(fields, "tags")
+	}
+
+	if stringValue(existing.Number) != stringValue(loaded.Number) {
+		fields = append(fields, "number")
+	}
+	if !existing.Date.Equal(loaded.Date) {
+		fields = append(fields, "date")
+	}
+
+	return fields
+}
+
+// legacyRecordPayload возвращает исходную запись из localStorage для сохранения в БД.
+func legacyRecordPayload(record *usecasemodels.BloggerLegacyRecord) map[string]any {
+	return map[string]any{
+		"id":       record.ID,
+		"name":     record.Name,
+		"username": record.Username,
+		"status":   record.Status,
+		"tags":     nonNilStrings(record.Tags),
+		"number":   record.Number,
+		"date":     record.Date,
+	}
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+func TestLegacyRecordToBlogger(t *testing.T) {
+	now := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	number := "+7 900 000-00-00"
+
+	tests := []struct {
+		name    string
+		record  usecasemodels.BloggerLegacyRecord
+		want    *repositorymodels.Blogger
+		wantErr error
+	}{
+		{
+			name: "full record",
+			record: usecasemodels.BloggerLegacyRecord{
+				ID: "1", Name: " Blogger ", Username: " @blogger ", Status: "off",
+				Tags: []string{"beauty", " beauty ", "", "travel"}, Number: " " + number + " ", Date: "15.08.2024",
+			},
+			want: &repositorymodels.Blogger{
+				Name: "Blogger", Username: "blogger", Status: usecasemodels.BloggerStatusOff,
+				Tags: []string{"beauty", "travel"}, Number: &number, Date: time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "empty status and number",
+			record: usecasemodels.BloggerLegacyRecord{Name: "Blogger", Username: "blogger", Date: "01.01.2024"},
+			want: &repositorymodels.Blogger{
+				Name: "Blogger", Username: "blogger", Status: usecasemodels.BloggerStatusOn,
+				Tags: []string{}, Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "iso date",
+			record:  usecasemodels.BloggerLegacyRecord{Name: "Blogger", Username: "blogger", Date: "2024-01-01"},
+			wantErr: usecasemodels.ErrorInvalidParameterDate,
+		},
+		{
+			name:    "invalid day",
+			record:  usecasemodels.BloggerLegacyRecord{Name: "Blogger", Username: "blogger", Date: "31.02.2024"},
+			wantErr: usecasemodels.ErrorInvalidParameterDate,
+		},
+		{
+			name:    "unknown status",
+			record:  usecasemodels.BloggerLegacyRecord{Name: "Blogger", Username: "blogger", Status: "paused", Date: "01.01.2024"},
+			wantErr: usecasemodels.ErrorInvalidParameterStatus,
+		},
+		{
+			name:    "empty name",
+			record:  usecasemodels.BloggerLegacyRecord{Name: " ", Username: "blogger", Date: "01.01.2024"},
+			wantErr: usecasemodels.ErrorInvalidParameterName,
+		},
+		{
+			name:    "username of only @",
+			record:  usecasemodels.BloggerLegacyRecord{Name: "Blogger", Username: "@", Date: "01.01.2024"},
+			wantErr: usecasemodels.ErrorInvalidParameterUsername,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := legacyRecordToBlogger(&tt.record, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("legacyRecordToBlogger() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.ID == "" || !got.CreatedAt.Equal(now) || !got.UpdatedAt.Equal(now) {
+				t.Fatalf("legacyRecordToBlogger() = %+v, want ID and timestamps", got)
+			}
+
+			got.ID, got.CreatedAt, got.UpdatedAt = "", time.Time{}, time.Time{}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("legacyRecordToBlogger() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffBloggers(t *testing.T) {
+	number := "1"
+	otherNumber := "2"
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	existing := &repositorymodels.Blogger{
+		Name: "Blogger", Status: usecasemodels.BloggerStatusOn, Tags: []string{"a", "b"}, Number: &number, Date: date,
+	}
+
+	tests := []struct {
+		name   string
+		loaded repositorymodels.Blogger
+		want   []string
+	}{
+		{
+			name:   "same with tags in other order",
+			loaded: repositorymodels.Blogger{Name: "Blogger", Status: usecasemodels.BloggerStatusOn, Tags: []string{"b", "a"}, Number: &number, Date: date},
+		},
+		{
+			name: "all fields differ",
+			loaded: repositorymodels.Blogger{
+				Name: "Other", Status: usecasemodels.BloggerStatusOff, Tags: []string{"a"}, Number: &otherNumber, Date: date.AddDate(0, 0, 1),
+			},
+			want: []string{"name", "status", "tags", "number", "date"},
+		},
+		{
+			name:   "number removed",
+			loaded: repositorymodels.Blogger{Name: "Blogger", Status: usecasemodels.BloggerStatusOn, Tags: []string{"a", "b"}, Date: date},
+			want:   []string{"number"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffBloggers(existing, &tt.loaded); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("diffBloggers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
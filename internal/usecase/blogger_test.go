@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"adminkaback/internal"
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+const testBloggerID = "3c8f5e2a-1d4b-4c6e-9f7a-2b5d8e1c4a7f"
+
+// testBloggerRepo хранит одного блогера и запоминает сохраненные изменения.
+type testBloggerRepo struct {
+	internal.BloggerRepository
+	blogger *repositorymodels.Blogger
+	saved   *repositorymodels.Blogger
+}
+
+func (r *testBloggerRepo) CreateBlogger(_ context.Context, blogger *repositorymodels.Blogger) error {
+	r.saved = blogger
+	return nil
+}
+
+func (r *testBloggerRepo) GetBloggerByID(_ context.Context, id string) (*repositorymodels.Blogger, error) {
+	if r.blogger == nil || r.blogger.ID != id {
+		return nil, nil
+	}
+
+	blogger := *r.blogger
+	return &blogger, nil
+}
+
+func (r *testBloggerRepo) UpdateBlogger(_ context.Context, blogger *repositorymodels.Blogger) error {
+	r.saved = blogger
+	return nil
+}
+
+func (r *testBloggerRepo) DeleteBlogger(_ context.Context, id string) error {
+	return nil
+}
+
+func TestValidateBlogger(t *testing.T) {
+	number := " +7 900 000-00-00 "
+	trimmedNumber := "+7 900 000-00-00"
+	blankNumber := "  "
+	longNumber := strings.Repeat("1", bloggerNumberMaxLength+1)
+
+	tests := []struct {
+		name    string
+		blogger repositorymodels.Blogger
+		want    repositorymodels.Blogger
+		wantErr error
+	}{
+		{
+			name:    "normalized",
+			blogger: repositorymodels.Blogger{Name: " Blogger ", Username: " @blogger ", Status: usecasemodels.BloggerStatusOn, Tags: []string{" beauty ", "", "beauty", "travel"}, Number: &number},
+			want:    repositorymodels.Blogger{Name: "Blogger", Username: "blogger", Status: usecasemodels.BloggerStatusOn, Tags: []string{"beauty", "travel"}, Number: &trimmedNumber},
+		},
+		{
+			name:    "blank number removed",
+			blogger: repositorymodels.Blogger{Name: "Blogger", Username: "blogger", Status: usecasemodels.BloggerStatusOff, Number: &blankNumber},
+			want:    repositorymodels.Blogger{Name: "Blogger", Username: "blogger", Status: usecasemodels.BloggerStatusOff, Tags: []string{}},
+		},
+		{name: "empty name", blogger: repositorymodels.Blogger{Name: " ", Username: "blogger", Status: usecasemodels.BloggerStatusOn}, wantErr: usecasemodels.ErrorInvalidParameterName},
+		{name: "long name", blogger: repositorymodels.Blogger{Name: strings.Repeat("я", bloggerFieldMaxLength+1), Username: "blogger", Status: usecasemodels.BloggerStatusOn}, wantErr: usecasemodels.ErrorInvalidParameterName},
+		{name: "only at in username", blogger: repositorymodels.Blogger{Name: "Blogger", Username: " @ ", Status: usecasemodels.BloggerStatusOn}, wantErr: usecasemodels.ErrorInvalidParameterUsername},
+		{name: "long username", blogger: repositorymodels.Blogger{Name: "Blogger", Username: strings.Repeat("b", bloggerFieldMaxLength+1), Status: usecasemodels.BloggerStatusOn}, wantErr: usecasemodels.ErrorInvalidParameterUsername},
+		{name: "unknown status", blogger: repositorymodels.Blogger{Name: "Blogger", Username: "blogger", Status: "paused"}, wantErr: usecasemodels.ErrorInvalidParameterStatus},
+		{name: "long tag", blogger: repositorymodels.Blogger{Name: "Blogger", Username: "blogger", Status: usecasemodels.BloggerStatusOn, Tags: []string{strings.Repeat("t", tagNameMaxLength+1)}}, wantErr: usecasemodels.ErrorInvalidParameterTags},
+		{name: "long number", blogger: repositorymodels.Blogger{Name: "Blogger", Username: "blogger", Status: usecasemodels.BloggerStatusOn, Number: &longNumber}, wantErr: usecasemodels.ErrorInvalidParameterNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blogger := tt.blogger
+
+			err := validateBlogger(&blogger)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateBlogger() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && !reflect.DeepEqual(blogger, tt.want) {
+				t.Fatalf("validateBlogger() = %+v, want %+v", blogger, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateBlogger(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        usecasemodels.CreateBloggerRequest
+		wantStatus string
+		wantDate   string
+		wantErr    error
+	}{
+		{name: "default status", req: usecasemodels.CreateBloggerRequest{Name: "Blogger", Username: "@blogger", Date: "2024-08-15"}, wantStatus: usecasemodels.BloggerStatusOn, wantDate: "2024-08-15"},
+		{name: "explicit status", req: usecasemodels.CreateBloggerRequest{Name: "Blogger", Username: "blogger", Status: usecasemodels.BloggerStatusOff, Date: "2024-08-15"}, wantStatus: usecasemodels.BloggerStatusOff, wantDate: "2024-08-15"},
+		{name: "date defaults to today", req: usecasemodels.CreateBloggerRequest{Name: "Blogger", Username: "blogger"}, wantStatus: usecasemodels.BloggerStatusOn, wantDate: time.Now().Format(usecasemodels.DateLayout)},
+		{name: "invalid date", req: usecasemodels.CreateBloggerRequest{Name: "Blogger", Username: "blogger", Date: "15.08.2024"}, wantErr: usecasemodels.ErrorInvalidParameterDate},
+		{name: "invalid status", req: usecasemodels.CreateBloggerRequest{Name: "Blogger", Username: "blogger", Status: "paused"}, wantErr: usecasemodels.ErrorInvalidParameterStatus},
+		{name: "missing username", req: usecasemodels.CreateBloggerRequest{Name: "Blogger"}, wantErr: usecasemodels.ErrorInvalidParameterUsername},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &testBloggerRepo{}
+			uc := &UseCase{bloggerRepo: repo}
+
+			resp, err := uc.CreateBlogger(context.Background(), &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateBlogger() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if repo.saved != nil {
+					t.Fatalf("CreateBlogger() saved invalid blogger %+v", repo.saved)
+				}
+
+				return
+			}
+
+			if resp.Username != "blogger" || resp.Status != tt.wantStatus || resp.Date != tt.wantDate {
+				t.Fatalf("CreateBlogger() = %+v, want username blogger, status %q, date %q", resp, tt.wantStatus, tt.wantDate)
+			}
+
+			if repo.saved == nil || repo.saved.ID != resp.ID {
+				t.Fatalf("CreateBlogger() saved %+v, want blogger %s", repo.saved, resp.ID)
+			}
+		})
+	}
+}
+
+func TestUpdateBlogger(t *testing.T) {
+	name := " Renamed "
+	username := "@renamed"
+	status := "paused"
+	date := "2024-13-01"
+	emptyName := ""
+
+	tests := []struct {
+		name    string
+		id      string
+		req     usecasemodels.UpdateBloggerRequest
+		want    func(b *repositorymodels.Blogger) bool
+		wantErr error
+	}{
+		{
+			name: "partial update",
+			id:   testBloggerID,
+			req:  usecasemodels.UpdateBloggerRequest{Name: &name, Username: &username},
+			want: func(b *repositorymodels.Blogger) bool {
+				return b.Name == "Renamed" && b.Username == "renamed" && b.Status == usecasemodels.BloggerStatusOn &&
+					reflect.DeepEqual(b.Tags, []string{"beauty"})
+			},
+		},
+		{
+			name: "tags replaced",
+			id:   testBloggerID,
+			req:  usecasemodels.UpdateBloggerRequest{Tags: []string{"travel", " travel "}},
+			want: func(b *repositorymodels.Blogger) bool {
+				return b.Name == "Blogger" && reflect.DeepEqual(b.Tags, []string{"travel"})
+			},
+		},
+		{name: "invalid id", id: "blogger", wantErr: usecasemodels.ErrBloggerNotFound},
+		{name: "unknown blogger", id: "7d2e9b4c-5a1f-4e8d-b3c6-0f9a2e5d8b1c", wantErr: usecasemodels.ErrBloggerNotFound},
+		{name: "empty name", id: testBloggerID, req: usecasemodels.UpdateBloggerRequest{Name: &emptyName}, wantErr: usecasemodels.ErrorInvalidParameterName},
+		{name: "invalid status", id: testBloggerID, req: usecasemodels.UpdateBloggerRequest{Status: &status}, wantErr: usecasemodels.ErrorInvalidParameterStatus},
+		{name: "invalid date", id: testBloggerID, req: usecasemodels.UpdateBloggerRequest{Date: &date}, wantErr: usecasemodels.ErrorInvalidParameterDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &testBloggerRepo{blogger: &repositorymodels.Blogger{
+				ID: testBloggerID, Name: "Blogger", Username: "blogger", Status: usecasemodels.BloggerStatusOn, Tags: []string{"beauty"},
+			}}
+			uc := &UseCase{bloggerRepo: repo}
+
+			_, err := uc.UpdateBlogger(context.Background(), tt.id, &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateBlogger() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if repo.saved != nil {
+					t.Fatalf("UpdateBlogger() saved invalid blogger %+v", repo.saved)
+				}
+
+				return
+			}
+
+			if repo.saved == nil || !tt.want(repo.saved) {
+				t.Fatalf("UpdateBlogger() saved %+v", repo.saved)
+			}
+		})
+	}
+}
+
+func TestDeleteBloggerInvalidID(t *testing.T) {
+	uc := &UseCase{bloggerRepo: &testBloggerRepo{}}
+
+	if err := uc.DeleteBlogger(context.Background(), "blogger"); !errors.Is(err, usecasemodels.ErrBloggerNotFound) {
+		t.Fatalf("DeleteBlogger() error = %v, want %v", err, usecasemodels.ErrBloggerNotFound)
+	}
+
+	if err := uc.DeleteBlogger(context.Background(), testBloggerID); err != nil {
+		t.Fatalf("DeleteBlogger() error = %v", err)
+	}
+}
+
+func TestPrepareBloggerFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   usecasemodels.BloggerFilter
+		wantMode string
+		wantErr  error
+	}{
+		{name: "default mode", filter: usecasemodels.BloggerFilter{}, wantMode: usecasemodels.TagsModeAny},
+		{name: "all", filter: usecasemodels.BloggerFilter{TagsMode: usecasemodels.TagsModeAll}, wantMode: usecasemodels.TagsModeAll},
+		{name: "unknown mode", filter: usecasemodels.BloggerFilter{TagsMode: "none"}, wantErr: usecasemodels.ErrorInvalidParameterTagsMode},
+		{name: "date range", filter: usecasemodels.BloggerFilter{DateFrom: "2024-01-01", DateTo: "2024-01-31"}, wantMode: usecasemodels.TagsModeAny},
+		{name: "same day", filter: usecasemodels.BloggerFilter{DateFrom: "2024-01-01", DateTo: "2024-01-01"}, wantMode: usecasemodels.TagsModeAny},
+		{name: "only to", filter: usecasemodels.BloggerFilter{DateTo: "2024-01-31"}, wantMode: usecasemodels.TagsModeAny},
+		{name: "reversed range", filter: usecasemodels.BloggerFilter{DateFrom: "2024-02-01", DateTo: "2024-01-31"}, wantErr: usecasemodels.ErrorInvalidParameterDate},
+		{name: "invalid from", filter: usecasemodels.BloggerFilter{DateFrom: "01.01.2024"}, wantErr: usecasemodels.ErrorInvalidParameterDate},
+		{name: "invalid to", filter: usecasemodels.BloggerFilter{DateTo: "2024-02-30"}, wantErr: usecasemodels.ErrorInvalidParameterDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+
+			err := prepareBloggerFilter(&filter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("prepareBloggerFilter() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && filter.TagsMode != tt.wantMode {
+				t.Fatalf("prepareBloggerFilter() mode = %q, want %q", filter.TagsMode, tt.wantMode)
+			}
+		})
+	}
+}
@@ -0,0 +1,519 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/google/uuid"
+)
+
+// campaignNameMaxLength — максимальная длина названия кампании.
+const campaignNameMaxLength = 255
+
+// GetCampaigns получает список кампаний с фильтрацией и пагинацией.
+func (uc *UseCase) GetCampaigns(ctx context.Context, req *usecasemodels.GetCampaignsRequest) (*usecasemodels.GetCampaignsResponse, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	if req.Active != "" {
+		if _, err := parseDate(req.Active); err != nil {
+			return nil, err
+		}
+	}
+
+	campaigns, total, err := uc.campaignRepo.GetCampaigns(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("get campaigns: %w", err)
+	}
+
+	data := make([]usecasemodels.CampaignResponse, 0, len(campaigns))
+	for i := range campaigns {
+		data = append(data, campaignToResponse(&campaigns[i]))
+	}
+
+	return &usecasemodels.GetCampaignsResponse{
+		Data:       data,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.Limit))),
+	}, nil
+}
+
+// GetCampaign получает кампанию по ID.
+func (uc *UseCase) GetCampaign(ctx context.Context, id string) (*usecasemodels.CampaignResponse, error) {
+	campaign, err := uc.getCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := campaignToResponse(campaign)
+	return &response, nil
+}
+
+// CreateCampaign создает кампанию.
+func (uc *UseCase) CreateCampaign(ctx context.Context, req *usecasemodels.CreateCampaignRequest) (*usecasemodels.CampaignResponse, error) {
+	startDate, err := parseDate(req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := parseDate(req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	campaign := &repositorymodels.Campaign{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		Budget:    req.Budget,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Status:    req.Status,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if campaign.Status == "" {
+		campaign.Status = usecasemodels.CampaignStatusDraft
+	}
+
+	if err := validateCampaign(campaign); err != nil {
+		return nil, err
+	}
+
+	if err := uc.campaignRepo.CreateCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	response := campaignToResponse(campaign)
+	return &response, nil
+}
+
+// UpdateCampaign изменяет заданные поля кампании.
+func (uc *UseCase) UpdateCampaign(ctx context.Context, id string, req *usecasemodels.UpdateCampaignRequest) (*usecasemodels.CampaignResponse, error) {
+	campaign, err := uc.getCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		campaign.Name = *req.Name
+	}
+	if req.Budget != nil {
+		campaign.Budget = *req.Budget
+	}
+	if req.StartDate != nil {
+		if campaign.StartDate, err = parseDate(*req.StartDate); err != nil {
+			return nil, err
+		}
+	}
+	if req.EndDate != nil {
+		if campaign.EndDate, err = parseDate(*req.EndDate); err != nil {
+			return nil, err
+		}
+	}
+	if req.Status != nil {
+		campaign.Status = *req.Status
+	}
+
+	if err := validateCampaign(campaign); err != nil {
+		return nil, err
+	}
+
+	if err := uc.campaignRepo.UpdateCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	response := campaignToResponse(campaign)
+	return &response, nil
+}
+
+// DeleteCampaign удаляет кампанию (soft delete). Она пропадает из истории кампаний блогеров.
+func (uc *UseCase) DeleteCampaign(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return usecasemodels.ErrCampaignNotFound
+	}
+
+	return uc.campaignRepo.DeleteCampaign(ctx, id)
+}
+
+// GetCampaignBloggers получает состав кампании с итогами по цене и статусам участия.
+func (uc *UseCase) GetCampaignBloggers(ctx context.Context, id string) (*usecasemodels.CampaignRosterResponse, error) {
+	campaign, err := uc.getCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := uc.campaignRepo.GetCampaignBloggers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get campaign bloggers: %w", err)
+	}
+
+	data := make([]usecasemodels.CampaignBloggerResponse, 0, len(assignments))
+	for i := range assignments {
+		data = append(data, campaignBloggerToResponse(&assignments[i]))
+	}
+
+	totals := campaignBloggerTotals(assignments)
+
+	return &usecasemodels.CampaignRosterResponse{
+		Campaign:  campaignToResponse(campaign),
+		Data:      data,
+		Totals:    totals,
+		Remaining: campaign.Budget - totals.Agreed,
+	}, nil
+}
+
+// GetBloggerCampaigns получает историю участия блогера в кампаниях с итогами.
+func (uc *UseCase) GetBloggerCampaigns(ctx context.Context, bloggerID string) (*usecasemodels.BloggerCampaignsResponse, error) {
+	if _, err := uc.getBlogger(ctx, bloggerID); err != nil {
+		return nil, err
+	}
+
+	assignments, err := uc.campaignRepo.GetBloggerCampaigns(ctx, bloggerID)
+	if err != nil {
+		return nil, fmt.Errorf("get blogger campaigns: %w", err)
+	}
+
+	data := make([]usecasemodels.CampaignBloggerResponse, 0, len(assignments))
+	for i := range assignments {
+		data = append(data, campaignBloggerToResponse(&assignments[i]))
+	}
+
+	return &usecasemodels.BloggerCampaignsResponse{
+		Data:   data,
+		Totals: campaignBloggerTotals(assignments),
+	}, nil
+}
+
+// AssignCampaignBlogger добавляет блогера в кампанию со статусом negotiating.
+func (uc *UseCase) AssignCampaignBlogger(ctx context.Context, campaignID string, req *usecasemodels.AssignCampaignBloggerRequest) (*usecasemodels.CampaignBloggerResponse, error) {
+	if _, err := uc.getCampaign(ctx, campaignID); err != nil {
+		return nil, err
+	}
+
+	blogger, err := uc.getBlogger(ctx, req.BloggerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	assignment := &repositorymodels.CampaignBlogger{
+		CampaignID:   campaignID,
+		BloggerID:    blogger.ID,
+		Price:        req.Price,
+		Deliverables: req.Deliverables,
+		Status:       usecasemodels.CampaignBloggerStatusNegotiating,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Blogger:      blogger,
+	}
+
+	if req.DueDate != nil && *req.DueDate != "" {
+		dueDate, err := parseDate(*req.DueDate)
+		if err != nil {
+			return nil, err
+		}
+		assignment.DueDate = &dueDate
+	}
+
+	if err := validateCampaignBlogger(assignment); err != nil {
+		return nil, err
+	}
+
+	if err := uc.campaignRepo.CreateCampaignBlogger(ctx, assignment); err != nil {
+		return nil, err
+	}
+
+	response := campaignBloggerToResponse(assignment)
+	return &response, nil
+}
+
+// UpdateCampaignBlogger изменяет условия участия блогера в кампании и двигает его по статусам.
+func (uc *UseCase) UpdateCampaignBlogger(ctx context.Context, campaignID, bloggerID string, req *usecasemodels.UpdateCampaignBloggerRequest) (*usecasemodels.CampaignBloggerResponse, error) {
+	if _, err := uuid.Parse(campaignID); err != nil {
+		return nil, usecasemodels.ErrCampaignBloggerNotFound
+	}
+	if _, err := uuid.Parse(bloggerID); err != nil {
+		return nil, usecasemodels.ErrCampaignBloggerNotFound
+	}
+
+	var assignment *repositorymodels.CampaignBlogger
+	err := uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		assignment, err = uc.campaignRepo.GetCampaignBlogger(ctx, campaignID, bloggerID)
+		if err != nil {
+			return fmt.Errorf("get campaign blogger: %w", err)
+		}
+
+		if assignment == nil {
+			return usecasemodels.ErrCampaignBloggerNotFound
+		}
+
+		// Цена входит в согласованную и оплаченную суммы, поэтому после договора не меняется.
+		if req.Price != nil && *req.Price != assignment.Price {
+			if campaignBloggerReached(assignment, usecasemodels.CampaignBloggerStatusContracted) {
+				return usecasemodels.ErrCampaignBloggerPriceLocked
+			}
+			assignment.Price = *req.Price
+		}
+		if req.Deliverables != nil {
+			assignment.Deliverables = optionalString(strings.TrimSpace(*req.Deliverables))
+		}
+		if req.PublicationURL != nil {
+			assignment.PublicationURL = optionalString(strings.TrimSpace(*req.PublicationURL))
+			if assignment.PublicationURL == nil && campaignBloggerReached(assignment, usecasemodels.CampaignBloggerStatusPublished) {
+				return usecasemodels.ErrCampaignBloggerPublicationURLRequired
+			}
+		}
+		if req.DueDate != nil {
+			assignment.DueDate = nil
+			if *req.DueDate != "" {
+				dueDate, err := parseDate(*req.DueDate)
+				if err != nil {
+					return err
+				}
+				assignment.DueDate = &dueDate
+			}
+		}
+
+		if err := validateCampaignBlogger(assignment); err != nil {
+			return err
+		}
+
+		if req.Status != nil && *req.Status != assignment.Status {
+			if err := changeCampaignBloggerStatus(assignment, *req.Status, time.Now()); err != nil {
+				return err
+			}
+		}
+
+		return uc.campaignRepo.UpdateCampaignBlogger(ctx, assignment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := campaignBloggerToResponse(assignment)
+	return &response, nil
+}
+
+// RemoveCampaignBlogger убирает блогера из кампании. Оплаченное участие убрать нельзя.
+func (uc *UseCase) RemoveCampaignBlogger(ctx context.Context, campaignID, bloggerID string) error {
+	if _, err := uuid.Parse(campaignID); err != nil {
+		return usecasemodels.ErrCampaignBloggerNotFound
+	}
+	if _, err := uuid.Parse(bloggerID); err != nil {
+		return usecasemodels.ErrCampaignBloggerNotFound
+	}
+
+	return uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		assignment, err := uc.campaignRepo.GetCampaignBlogger(ctx, campaignID, bloggerID)
+		if err != nil {
+			return fmt.Errorf("get campaign blogger: %w", err)
+		}
+
+		if assignment == nil {
+			return usecasemodels.ErrCampaignBloggerNotFound
+		}
+
+		if assignment.Status == usecasemodels.CampaignBloggerStatusPaid {
+			return usecasemodels.ErrCampaignBloggerPaid
+		}
+
+		return uc.campaignRepo.DeleteCampaignBlogger(ctx, campaignID, bloggerID)
+	})
+}
+
+// getCampaign получает неудаленную кампанию или возвращает ErrCampaignNotFound.
+func (uc *UseCase) getCampaign(ctx context.Context, id string) (*repositorymodels.Campaign, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, usecasemodels.ErrCampaignNotFound
+	}
+
+	campaign, err := uc.campaignRepo.GetCampaignByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get campaign by id: %w", err)
+	}
+
+	if campaign == nil {
+		return nil, usecasemodels.ErrCampaignNotFound
+	}
+
+	return campaign, nil
+}
+
+// validateCampaign нормализует и проверяет поля кампании.
+func validateCampaign(campaign *repositorymodels.Campaign) error {
+	campaign.Name = strings.TrimSpace(campaign.Name)
+	if campaign.Name == "" || len([]rune(campaign.Name)) > campaignNameMaxLength {
+		return usecasemodels.ErrorInvalidParameterName
+	}
+
+	if campaign.Budget < 0 {
+		return usecasemodels.ErrorInvalidParameterBudget
+	}
+
+	if campaign.EndDate.Before(campaign.StartDate) {
+		return usecasemodels.ErrorInvalidParameterDate
+	}
+
+	if !slices.Contains(usecasemodels.CampaignStatuses, campaign.Status) {
+		return usecasemodels.ErrorInvalidParameterStatus
+	}
+
+	return nil
+}
+
+// validateCampaignBlogger проверяет цену и ссылку на публикацию участия блогера.
+func validateCampaignBlogger(assignment *repositorymodels.CampaignBlogger) error {
+	if assignment.Price < 0 {
+		return usecasemodels.ErrorInvalidParameterPrice
+	}
+
+	if assignment.PublicationURL != nil {
+		u, err := url.Parse(*assignment.PublicationURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return usecasemodels.ErrorInvalidParameterURL
+		}
+	}
+
+	return nil
+}
+
+// changeCampaignBloggerStatus переводит участие блогера в следующий статус и отмечает время перехода.
+// Пропускать статусы и возвращаться назад нельзя; для published нужна ссылка на публикацию.
+func changeCampaignBloggerStatus(assignment *repositorymodels.CampaignBlogger, status string, now time.Time) error {
+	if !slices.Contains(usecasemodels.CampaignBloggerStatuses, status) {
+		return usecasemodels.ErrorInvalidParameterStatus
+	}
+
+	transitionErr := &usecasemodels.StatusTransitionError{
+		From:    assignment.Status,
+		To:      status,
+		Allowed: []string{},
+		Err:     usecasemodels.ErrInvalidStatusTransition,
+	}
+
+	current := slices.Index(usecasemodels.CampaignBloggerStatuses, assignment.Status)
+	if current >= 0 && current+1 < len(usecasemodels.CampaignBloggerStatuses) {
+		transitionErr.Allowed = []string{usecasemodels.CampaignBloggerStatuses[current+1]}
+	}
+
+	if !slices.Contains(transitionErr.Allowed, status) {
+		return transitionErr
+	}
+
+	switch status {
+	case usecasemodels.CampaignBloggerStatusContracted:
+		assignment.ContractedAt = &now
+	case usecasemodels.CampaignBloggerStatusPublished:
+		if assignment.PublicationURL == nil {
+			transitionErr.Err = usecasemodels.ErrStatusTransitionGuard
+			transitionErr.Guard = usecasemodels.CampaignBloggerGuardPublicationURL
+
+			return transitionErr
+		}
+		assignment.PublishedAt = &now
+	case usecasemodels.CampaignBloggerStatusPaid:
+		assignment.PaidAt = &now
+	}
+
+	assignment.Status = status
+
+	return nil
+}
+
+// campaignBloggerReached проверяет, что участие дошло до статуса status или прошло его.
+func campaignBloggerReached(assignment *repositorymodels.CampaignBlogger, status string) bool {
+	return slices.Index(usecasemodels.CampaignBloggerStatuses, assignment.Status) >=
+		slices.Index(usecasemodels.CampaignBloggerStatuses, status)
+}
+
+// campaignBloggerTotals считает количество участий по статусам и суммы согласованных
+// и оплаченных размещений. Участие на переговорах в сумму согласованных не входит.
+func campaignBloggerTotals(assignments []repositorymodels.CampaignBlogger) usecasemodels.CampaignBloggerTotals {
+	totals := usecasemodels.CampaignBloggerTotals{
+		Count:    len(assignments),
+		ByStatus: make(map[string]int, len(usecasemodels.CampaignBloggerStatuses)),
+	}
+	for _, status := range usecasemodels.CampaignBloggerStatuses {
+		totals.ByStatus[status] = 0
+	}
+
+	for _, assignment := range assignments {
+		totals.ByStatus[assignment.Status]++
+
+		if assignment.Status == usecasemodels.CampaignBloggerStatusNegotiating {
+			continue
+		}
+
+		totals.Agreed += assignment.Price
+		if assignment.Status == usecasemodels.CampaignBloggerStatusPaid {
+			totals.Paid += assignment.Price
+		}
+	}
+	totals.Unpaid = totals.Agreed - totals.Paid
+
+	return totals
+}
+
+// campaignToResponse преобразует модель кампании в ответ.
+func campaignToResponse(campaign *repositorymodels.Campaign) usecasemodels.CampaignResponse {
+	return usecasemodels.CampaignResponse{
+		ID:        campaign.ID,
+		Name:      campaign.Name,
+		Budget:    campaign.Budget,
+		StartDate: campaign.StartDate.Format(usecasemodels.DateLayout),
+		EndDate:   campaign.EndDate.Format(usecasemodels.DateLayout),
+		Status:    campaign.Status,
+		CreatedAt: campaign.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: campaign.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// campaignBloggerToResponse преобразует участие блогера в кампании в ответ.
+func campaignBloggerToResponse(assignment *repositorymodels.CampaignBlogger) usecasemodels.CampaignBloggerResponse {
+	response := usecasemodels.CampaignBloggerResponse{
+		CampaignID:     assignment.CampaignID,
+		BloggerID:      assignment.BloggerID,
+		Price:          assignment.Price,
+		Deliverables:   assignment.Deliverables,
+		PublicationURL: assignment.PublicationURL,
+		Status:         assignment.Status,
+		ContractedAt:   formatOptionalTime(assignment.ContractedAt),
+		PublishedAt:    formatOptionalTime(assignment.PublishedAt),
+		PaidAt:         formatOptionalTime(assignment.PaidAt),
+		CreatedAt:      assignment.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      assignment.UpdatedAt.Format(time.RFC3339),
+	}
+	if assignment.DueDate != nil {
+		dueDate := assignment.DueDate.Format(usecasemodels.DateLayout)
+		response.DueDate = &dueDate
+	}
+	if assignment.Campaign != nil {
+		campaign := campaignToResponse(assignment.Campaign)
+		response.Campaign = &campaign
+	}
+	if assignment.Blogger != nil {
+		blogger := bloggerToResponse(assignment.Blogger)
+		response.Blogger = &blogger
+	}
+
+	return response
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+func TestChangeCampaignBloggerStatus(t *testing.T) {
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	url := "https://example.com/post/1"
+
+	tests := []struct {
+		name        string
+		from        string
+		url         *string
+		to          string
+		wantErr     error
+		wantAllowed []string
+		wantGuard   string
+	}{
+		{name: "negotiating to contracted", from: "negotiating", to: "contracted"},
+		{name: "contracted to published", from: "contracted", url: &url, to: "published"},
+		{name: "published to paid", from: "published", url: &url, to: "paid"},
+		{name: "unknown status", from: "negotiating", to: "cancelled", wantErr: usecasemodels.ErrorInvalidParameterStatus},
+		{
+			name: "skip status", from: "negotiating", to: "published", url: &url,
+			wantErr: usecasemodels.ErrInvalidStatusTransition, wantAllowed: []string{"contracted"},
+		},
+		{
+			name: "back to previous status", from: "published", to: "contracted", url: &url,
+			wantErr: usecasemodels.ErrInvalidStatusTransition, wantAllowed: []string{"paid"},
+		},
+		{
+			name: "same status", from: "contracted", to: "contracted",
+			wantErr: usecasemodels.ErrInvalidStatusTransition, wantAllowed: []string{"published"},
+		},
+		{
+			name: "after paid", from: "paid", to: "paid", url: &url,
+			wantErr: usecasemodels.ErrInvalidStatusTransition, wantAllowed: []string{},
+		},
+		{
+			name: "published without url", from: "contracted", to: "published",
+			wantErr: usecasemodels.ErrStatusTransitionGuard, wantAllowed: []string{"published"},
+			wantGuard: usecasemodels.CampaignBloggerGuardPublicationURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignment := &repositorymodels.CampaignBlogger{Status: tt.from, PublicationURL: tt.url}
+
+			err := changeCampaignBloggerStatus(assignment, tt.to, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("changeCampaignBloggerStatus() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if assignment.Status != tt.from {
+					t.Fatalf("status = %q after error, want %q", assignment.Status, tt.from)
+				}
+
+				var transitionErr *usecasemodels.StatusTransitionError
+				if !errors.As(err, &transitionErr) {
+					return
+				}
+				if !reflect.DeepEqual(transitionErr.Allowed, tt.wantAllowed) {
+					t.Fatalf("allowed = %v, want %v", transitionErr.Allowed, tt.wantAllowed)
+				}
+				if transitionErr.Guard != tt.wantGuard {
+					t.Fatalf("guard = %q, want %q", transitionErr.Guard, tt.wantGuard)
+				}
+
+				return
+			}
+
+			if assignment.Status != tt.to {
+				t.Fatalf("status = %q, want %q", assignment.Status, tt.to)
+			}
+
+			stamps := map[string]*time.Time{
+				"contracted": assignment.ContractedAt,
+				"published":  assignment.PublishedAt,
+				"paid":       assignment.PaidAt,
+			}
+			for status, stamp := range stamps {
+				if set := stamp != nil; set != (status == tt.to) {
+					t.Fatalf("%s_at set = %v after transition to %q", status, set, tt.to)
+				}
+			}
+			if !stamps[tt.to].Equal(now) {
+				t.Fatalf("%s_at = %v, want %v", tt.to, stamps[tt.to], now)
+			}
+		})
+	}
+}
+
+func TestCampaignBloggerReached(t *testing.T) {
+	tests := []struct {
+		current string
+		status  string
+		want    bool
+	}{
+		{current: "negotiating", status: "contracted", want: false},
+		{current: "contracted", status: "contracted", want: true},
+		{current: "published", status: "contracted", want: true},
+		{current: "contracted", status: "published", want: false},
+		{current: "paid", status: "published", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+"/"+tt.status, func(t *testing.T) {
+			assignment := &repositorymodels.CampaignBlogger{Status: tt.current}
+			if got := campaignBloggerReached(assignment, tt.status); got != tt.want {
+				t.Fatalf("campaignBloggerReached(%q, %q) = %v, want %v", tt.current, tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCampaignBloggerTotals(t *testing.T) {
+	tests := []struct {
+		name        string
+		assignments []repositorymodels.CampaignBlogger
+		want        usecasemodels.CampaignBloggerTotals
+	}{
+		{
+			name: "empty",
+			want: usecasemodels.CampaignBloggerTotals{
+				ByStatus: map[string]int{"negotiating": 0, "contracted": 0, "published": 0, "paid": 0},
+			},
+		},
+		{
+			name: "negotiating is not agreed",
+			assignments: []repositorymodels.CampaignBlogger{
+				{Status: "negotiating", Price: 1000},
+				{Status: "contracted", Price: 2000},
+				{Status: "published", Price: 3000},
+				{Status: "paid", Price: 4000},
+				{Status: "paid", Price: 500},
+			},
+			want: usecasemodels.CampaignBloggerTotals{
+				Count:    5,
+				ByStatus: map[string]int{"negotiating": 1, "contracted": 1, "published": 1, "paid": 2},
+				Agreed:   9500,
+				Paid:     4500,
+				Unpaid:   5000,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := campaignBloggerTotals(tt.assignments)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("campaignBloggerTotals() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
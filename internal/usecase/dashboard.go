@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+// GetDashboardStats получает статистику пользователей из агрегата user_stats без обращения к таблице users.
+// Статусы из конфигурации возвращаются и с нулевым количеством.
+func (uc *UseCase) GetDashboardStats(ctx context.Context) (*usecasemodels.DashboardStatsResponse, error) {
+	stats, err := uc.dashboardRepo.GetUserStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get user stats: %w", err)
+	}
+
+	response := &usecasemodels.DashboardStatsResponse{
+		ByStatus: make(map[string]int),
+		ByRole:   make(map[string]int),
+		ByVerification: map[string]int{
+			usecasemodels.DashboardVerified:   0,
+			usecasemodels.DashboardUnverified: 0,
+		},
+	}
+	for _, status := range uc.cfg.Users.StatusMachine.Statuses {
+		response.ByStatus[status] = 0
+	}
+
+	newUsers := []usecasemodels.DashboardNewUsersStat{{Days: 1}, {Days: 7}, {Days: 30}}
+	var refreshedAt time.Time
+	for _, s := range stats {
+		// Группа может состоять только из удаленных пользователей, которые учитываются лишь в регистрациях.
+		if s.Total > 0 {
+			response.Total += s.Total
+			response.ByStatus[s.Status] += s.Total
+			response.ByRole[s.Role] += s.Total
+			if s.IsEmailVerified {
+				response.ByVerification[usecasemodels.DashboardVerified] += s.Total
+			} else {
+				response.ByVerification[usecasemodels.DashboardUnverified] += s.Total
+			}
+		}
+
+		newUsers[0].Count += s.New1d
+		newUsers[0].Previous += s.Previous1d
+		newUsers[1].Count += s.New7d
+		newUsers[1].Previous += s.Previous7d
+		newUsers[2].Count += s.New30d
+		newUsers[2].Previous += s.Previous30d
+
+		if s.RefreshedAt.After(refreshedAt) {
+			refreshedAt = s.RefreshedAt
+		}
+	}
+
+	for i := range newUsers {
+		newUsers[i].Delta = newUsers[i].Count - newUsers[i].Previous
+	}
+	response.NewUsers = newUsers
+
+	if !refreshedAt.IsZero() {
+		response.RefreshedAt = formatOptionalTime(&refreshedAt)
+	}
+
+	return response, nil
+}
+
+// RefreshDashboardStats пересчитывает агрегат статистики пользователей. Вызывается по расписанию.
+func (uc *UseCase) RefreshDashboardStats(ctx context.Context) error {
+	return uc.dashboardRepo.RefreshUserStats(ctx)
+}
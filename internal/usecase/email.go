@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"strings"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"golang.org/x/net/idna"
+)
+
+// normalizeEmail приводит email к каноническому виду: без пробелов по краям,
+// в нижнем регистре, с доменом в ASCII (punycode для IDN).
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	at := strings.LastIndex(email, "@")
+	if at <= 0 || at == len(email)-1 {
+		return "", usecasemodels.ErrorInvalidParameterEmail
+	}
+
+	local, domain := email[:at], email[at+1:]
+	if strings.ContainsAny(local, " \t\r\n") {
+		return "", usecasemodels.ErrorInvalidParameterEmail
+	}
+
+	asciiDomain, err := idna.Lookup.ToASCII(domain)
+	if err != nil || !strings.Contains(asciiDomain, ".") {
+		return "", usecasemodels.ErrorInvalidParameterEmail
+	}
+
+	return local + "@" + asciiDomain, nil
+}
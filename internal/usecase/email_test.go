@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		want    string
+		wantErr error
+	}{
+		{name: "lower case", email: "ivan@example.com", want: "ivan@example.com"},
+		{name: "mixed case", email: "Ivan.Petrov@Example.COM", want: "ivan.petrov@example.com"},
+		{name: "surrounding spaces", email: "  IVAN@example.com\n", want: "ivan@example.com"},
+		{name: "idn domain", email: "Ivan@Пример.РФ", want: "ivan@xn--e1afmkfd.xn--p1ai"},
+		{name: "at in local part", email: "\"a@b\"@example.com", want: "\"a@b\"@example.com"},
+		{name: "empty", email: "", wantErr: usecasemodels.ErrorInvalidParameterEmail},
+		{name: "no at", email: "ivan.example.com", wantErr: usecasemodels.ErrorInvalidParameterEmail},
+		{name: "empty local part", email: "@example.com", wantErr: usecasemodels.ErrorInvalidParameterEmail},
+		{name: "empty domain", email: "ivan@", wantErr: usecasemodels.ErrorInvalidParameterEmail},
+		{name: "space in local part", email: "ivan petrov@example.com", wantErr: usecasemodels.ErrorInvalidParameterEmail},
+		{name: "domain without dot", email: "ivan@localhost", wantErr: usecasemodels.ErrorInvalidParameterEmail},
+		{name: "invalid domain", email: "ivan@exa mple.com", wantErr: usecasemodels.ErrorInvalidParameterEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeEmail(tt.email)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalizeEmail(%q) error = %v, want %v", tt.email, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("normalizeEmail(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRegisterRequestEmail(t *testing.T) {
+	uc := &UseCase{}
+
+	tests := []struct {
+		name      string
+		email     string
+		wantEmail string
+		wantErr   error
+	}{
+		{name: "normalized", email: " Admin@Example.COM ", wantEmail: "admin@example.com"},
+		{name: "empty", email: "", wantErr: usecasemodels.ErrorInvalidParameterEmail},
+		{name: "invalid", email: "admin", wantErr: usecasemodels.ErrorInvalidParameterEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &usecasemodels.RegisterRequest{Email: tt.email, Password: "password123", Name: "Admin"}
+
+			err := uc.validateRegisterRequest(req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateRegisterRequest() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && req.Email != tt.wantEmail {
+				t.Fatalf("validateRegisterRequest() email = %q, want %q", req.Email, tt.wantEmail)
+			}
+		})
+	}
+}
+
+func TestValidateLoginRequestEmail(t *testing.T) {
+	uc := &UseCase{}
+
+	tests := []struct {
+		name      string
+		email     string
+		wantEmail string
+		wantErr   error
+	}{
+		{name: "normalized", email: "Admin@Example.COM", wantEmail: "admin@example.com"},
+		{name: "idn domain", email: "admin@пример.рф", wantEmail: "admin@xn--e1afmkfd.xn--p1ai"},
+		{name: "not normalizable kept as is", email: "Admin", wantEmail: "Admin"},
+		{name: "empty", email: "", wantErr: usecasemodels.ErrorInvalidParameterEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &usecasemodels.LoginRequest{Email: tt.email, Password: "password123"}
+
+			err := uc.validateLoginRequest(req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateLoginRequest() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && req.Email != tt.wantEmail {
+				t.Fatalf("validateLoginRequest() email = %q, want %q", req.Email, tt.wantEmail)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/pii"
+	"adminkaback/pkg/reqctx"
+	"adminkaback/pkg/signedtoken"
+
+	"github.com/google/uuid"
+)
+
+// emailVerificationPurpose — назначение токена ссылки подтверждения email.
+const emailVerificationPurpose = "email_verification"
+
+// SendVerificationEmail отправляет пользователю письмо со ссылкой подтверждения email.
+// Повторная отправка ограничена интервалом и количеством писем в сутки.
+func (uc *UseCase) SendVerificationEmail(ctx context.Context, id string) (*usecasemodels.SendVerificationEmailResponse, error) {
+	var response *usecasemodels.SendVerificationEmailResponse
+
+	// Письмо отправляется внутри транзакции: если отправка не удалась,
+	// запись об отправке откатывается и не расходует лимит. Строка пользователя
+	// блокируется, чтобы одновременные запросы не превысили лимит.
+	err := uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		user, err := uc.userRepo.GetUserByIDForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get user by id: %w", err)
+		}
+
+		if user == nil {
+			return usecasemodels.ErrUserNotFound
+		}
+
+		if user.IsEmailVerified {
+			return usecasemodels.ErrEmailAlreadyVerified
+		}
+
+		now := time.Now()
+		count, lastSentAt, err := uc.verifyRepo.GetEmailVerificationStats(ctx, id, now.Add(-24*time.Hour))
+		if err != nil {
+			return fmt.Errorf("get email verification stats: %w", err)
+		}
+
+		if count >= uc.cfg.Users.VerificationMaxPerDay ||
+			(lastSentAt != nil && now.Sub(*lastSentAt) < uc.cfg.Users.VerificationResendInterval) {
+			return usecasemodels.ErrVerificationRateLimited
+		}
+
+		if err := uc.verifyRepo.CreateEmailVerification(ctx, &repositorymodels.EmailVerification{
+			ID:        uuid.New().String(),
+			UserID:    user.ID,
+			Email:     user.Email,
+			AdminID:   optionalString(reqctx.From(ctx).AdminID),
+			CreatedAt: now,
+		}); err != nil {
+			return fmt.Errorf("create email verification: %w", err)
+		}
+
+		token, err := uc.signer.Sign(emailVerificationPurpose, user.ID, user.Email, uc.cfg.Users.VerificationTTL)
+		if err != nil {
+			return fmt.Errorf("sign verification token: %w", err)
+		}
+
+		link := strings.TrimRight(uc.cfg.App.PublicURL, "/") + "/api/v1/email/verify?token=" + url.QueryEscape(token)
+		body := fmt.Sprintf("Здравствуйте, %s!\n\nЧтобы подтвердить email, перейдите по ссылке:\n%s\n\nСсылка действительна до %s.",
+			user.Name, link, now.Add(uc.cfg.Users.VerificationTTL).Format("02.01.2006 15:04"))
+
+		if err := uc.mailer.Send(ctx, user.Email, "Подтверждение email", body); err != nil {
+			return fmt.Errorf("send verification email: %w", err)
+		}
+
+		response = &usecasemodels.SendVerificationEmailResponse{
+			Email:     user.Email,
+			ExpiresAt: now.Add(uc.cfg.Users.VerificationTTL).Format(time.RFC3339),
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// VerifyEmail подтверждает email пользователя по токену из ссылки.
+// Ссылка перестает действовать, если email пользователя изменился после отправки.
+func (uc *UseCase) VerifyEmail(ctx context.Context, token string) (resp *usecasemodels.UserResponse, err error) {
+	claims, err := uc.signer.Verify(emailVerificationPurpose, token)
+	if err != nil {
+		if errors.Is(err, signedtoken.ErrExpiredToken) {
+			return nil, usecasemodels.ErrExpiredVerificationToken
+		}
+
+		return nil, usecasemodels.ErrInvalidVerificationToken
+	}
+
+	defer func() {
+		entry := &usecasemodels.AuditEntry{
+			Action:       "user.verify_email",
+			ResourceType: "user",
+			ResourceID:   claims.Subject,
+			Details:      map[string]string{"email": pii.MaskEmail(claims.Value)},
+		}
+		uc.auditResult(ctx, entry, err)
+	}()
+
+	var verifiedUser *repositorymodels.User
+
+	err = uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		user, err := uc.userRepo.GetUserByID(ctx, claims.Subject)
+		if err != nil {
+			return fmt.Errorf("get user by id: %w", err)
+		}
+
+		if user == nil {
+			return usecasemodels.ErrUserNotFound
+		}
+
+		if user.Email != claims.Value {
+			return usecasemodels.ErrInvalidVerificationToken
+		}
+
+		// Повторный переход по ссылке не считается ошибкой.
+		if user.IsEmailVerified {
+			verifiedUser = user
+
+			return nil
+		}
+
+		ok, err := uc.verifyRepo.SetUserEmailVerified(ctx, user.ID, claims.Value)
+		if err != nil {
+			return fmt.Errorf("set user email verified: %w", err)
+		}
+
+		if !ok {
+			return usecasemodels.ErrInvalidVerificationToken
+		}
+
+		verifiedUser, err = uc.userRepo.GetUserByID(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("get verified user: %w", err)
+		}
+
+		return uc.recordUserHistory(ctx, usecasemodels.UserHistoryActionVerifyEmail, user.ID, user, verifiedUser)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := uc.userToResponse(ctx, verifiedUser)
+	return &response, nil
+}
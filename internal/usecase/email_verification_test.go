@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"adminkaback/internal"
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/config"
+	"adminkaback/pkg/signedtoken"
+)
+
+// testVerifyUserRepo отдает пользователя только через блокирующее чтение.
+type testVerifyUserRepo struct {
+	internal.UserRepository
+	user   *repositorymodels.User
+	locked bool
+}
+
+func (r *testVerifyUserRepo) GetUserByIDForUpdate(_ context.Context, id string) (*repositorymodels.User, error) {
+	r.locked = true
+	if r.user == nil || r.user.ID != id {
+		return nil, nil
+	}
+
+	return r.user, nil
+}
+
+// testVerifyRepo возвращает заданную статистику отправок и проверяет, что она читается под блокировкой.
+type testVerifyRepo struct {
+	internal.EmailVerificationRepository
+	userRepo   *testVerifyUserRepo
+	count      int
+	lastSentAt *time.Time
+	created    int
+}
+
+func (r *testVerifyRepo) GetEmailVerificationStats(_ context.Context, _ string, _ time.Time) (int, *time.Time, error) {
+	if !r.userRepo.locked {
+		return 0, nil, errors.New("stats read without user lock")
+	}
+
+	return r.count, r.lastSentAt, nil
+}
+
+func (r *testVerifyRepo) CreateEmailVerification(_ context.Context, _ *repositorymodels.EmailVerification) error {
+	r.created++
+	return nil
+}
+
+type testMailer struct {
+	sent int
+}
+
+func (m *testMailer) Send(_ context.Context, _, _, _ string) error {
+	m.sent++
+	return nil
+}
+
+func TestSendVerificationEmail(t *testing.T) {
+	const userID = "5b2e8c1f-4a7d-4e9b-8c3a-1f6d9e2b5a8c"
+
+	recent := time.Now().Add(-time.Minute)
+	old := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		id         string
+		verified   bool
+		count      int
+		lastSentAt *time.Time
+		wantErr    error
+	}{
+		{name: "first email", id: userID},
+		{name: "resend after interval", id: userID, count: 2, lastSentAt: &old},
+		{name: "resend too soon", id: userID, count: 1, lastSentAt: &recent, wantErr: usecasemodels.ErrVerificationRateLimited},
+		{name: "daily limit", id: userID, count: 3, lastSentAt: &old, wantErr: usecasemodels.ErrVerificationRateLimited},
+		{name: "already verified", id: userID, verified: true, wantErr: usecasemodels.ErrEmailAlreadyVerified},
+		{name: "unknown user", id: "9c4a1e7b-2d5f-4b8e-a6c3-7e0f2b9d4a1c", wantErr: usecasemodels.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &testVerifyUserRepo{user: &repositorymodels.User{
+				ID: userID, Email: "user@example.com", Name: "User", IsEmailVerified: tt.verified,
+			}}
+			verifyRepo := &testVerifyRepo{userRepo: userRepo, count: tt.count, lastSentAt: tt.lastSentAt}
+			mailer := &testMailer{}
+
+			cfg := &config.Config{}
+			cfg.App.PublicURL = "https://admin.example.com"
+			cfg.Users.VerificationTTL = 24 * time.Hour
+			cfg.Users.VerificationResendInterval = 10 * time.Minute
+			cfg.Users.VerificationMaxPerDay = 3
+
+			uc := &UseCase{
+				txManager:  testTxManager{},
+				userRepo:   userRepo,
+				verifyRepo: verifyRepo,
+				mailer:     mailer,
+				signer:     signedtoken.NewSigner("test-secret"),
+				cfg:        cfg,
+			}
+
+			resp, err := uc.SendVerificationEmail(context.Background(), tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SendVerificationEmail() error = %v, want %v", err, tt.wantErr)
+			}
+
+			wantSent := 0
+			if tt.wantErr == nil {
+				wantSent = 1
+				if resp == nil || resp.Email != "user@example.com" {
+					t.Fatalf("SendVerificationEmail() = %+v, want email user@example.com", resp)
+				}
+			}
+
+			if verifyRepo.created != wantSent || mailer.sent != wantSent {
+				t.Fatalf("SendVerificationEmail() created %d records and sent %d emails, want %d", verifyRepo.created, mailer.sent, wantSent)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package models
+
+import "errors"
+
+var (
+	// ErrorInvalidParameterLocale возвращается при неподдерживаемом языке интерфейса.
+	ErrorInvalidParameterLocale = errors.New("ErrorInvalidParameterLocale")
+	// ErrorInvalidParameterTheme возвращается при неизвестной теме интерфейса.
+	ErrorInvalidParameterTheme = errors.New("ErrorInvalidParameterTheme")
+	// ErrorInvalidParameterPageSize возвращается при размере страницы вне допустимого диапазона.
+	ErrorInvalidParameterPageSize = errors.New("ErrorInvalidParameterPageSize")
+	// ErrorInvalidParameterTables возвращается при невалидных настройках таблиц.
+	ErrorInvalidParameterTables = errors.New("ErrorInvalidParameterTables")
+	// ErrAdminPreferencesVersionConflict возвращается, если настройки были изменены после чтения.
+	ErrAdminPreferencesVersionConflict = errors.New("admin preferences were changed by another request")
+)
+
+// AdminPreferencesSchemaVersion — текущая версия формата настроек администратора.
+const AdminPreferencesSchemaVersion = 1
+
+// Темы интерфейса.
+const (
+	ThemeLight = "light"
+	ThemeDark  = "dark"
+)
+
+// AdminLocales — поддерживаемые языки интерфейса, первый используется по умолчанию.
+var AdminLocales = []string{"ru", "en"}
+
+// AdminPreferencesResponse представляет настройки интерфейса администратора.
+// Version — номер сохранения (0 — настройки по умолчанию), передается в PUT для защиты от перезаписи.
+type AdminPreferencesResponse struct {
+	Version       int                              `json:"version"`
+	SchemaVersion int                              `json:"schema_version"`
+	Locale        string                           `json:"locale"`
+	Theme         string                           `json:"theme"`
+	Timezone      string                           `json:"timezone"`
+	PageSize      int                              `json:"page_size"`
+	Tables        map[string]AdminTablePreferences `json:"tables"`
+	UpdatedAt     *string                          `json:"updated_at"`
+}
+
+// UpdateAdminPreferencesRequest представляет запрос на сохранение настроек администратора.
+// Документ заменяется целиком: незаданные поля принимают значения по умолчанию.
+// Если задан Version, настройки сохраняются только при совпадении с текущей версией.
+type UpdateAdminPreferencesRequest struct {
+	Version  *int                             `json:"version"`
+	Locale   string                           `json:"locale"`
+	Theme    string                           `json:"theme"`
+	Timezone string                           `json:"timezone"`
+	PageSize int                              `json:"page_size"`
+	Tables   map[string]AdminTablePreferences `json:"tables"`
+}
+
+// AdminTablePreferences представляет колонки таблицы в порядке отображения.
+type AdminTablePreferences struct {
+	Columns []AdminTableColumn `json:"columns"`
+}
+
+// AdminTableColumn представляет видимость колонки таблицы.
+type AdminTableColumn struct {
+	Key     string `json:"key"`
+	Visible bool   `json:"visible"`
+}
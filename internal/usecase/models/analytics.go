@@ -0,0 +1,145 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrorInvalidParameterMetric возвращается при неизвестной метрике.
+	ErrorInvalidParameterMetric = errors.New("ErrorInvalidParameterMetric")
+	// ErrorInvalidParameterInterval возвращается при неизвестном интервале или слишком большом числе интервалов.
+	ErrorInvalidParameterInterval = errors.New("ErrorInvalidParameterInterval")
+	// ErrorInvalidParameterTimezone возвращается при неизвестном часовом поясе.
+	ErrorInvalidParameterTimezone = errors.New("ErrorInvalidParameterTimezone")
+	// ErrorInvalidParameterBreakdown возвращается при неизвестной разбивке.
+	ErrorInvalidParameterBreakdown = errors.New("ErrorInvalidParameterBreakdown")
+	// ErrorInvalidParameterPeriods возвращается при невалидном числе периодов удержания.
+	ErrorInvalidParameterPeriods = errors.New("ErrorInvalidParameterPeriods")
+)
+
+// Метрики временных рядов пользователей.
+const (
+	UserMetricSignups   = "signups"
+	UserMetricBans      = "bans"
+	UserMetricDeletions = "deletions"
+)
+
+// Интервалы временных рядов. Неделя начинается с понедельника.
+const (
+	TimeseriesIntervalDay   = "day"
+	TimeseriesIntervalWeek  = "week"
+	TimeseriesIntervalMonth = "month"
+)
+
+// Разбивки временных рядов пользователей по текущим роли и статусу пользователя.
+const (
+	UserBreakdownRole   = "role"
+	UserBreakdownStatus = "status"
+)
+
+// UserTimeseriesRequest представляет запрос временного ряда по пользователям.
+// From и To — даты (YYYY-MM-DD) в часовом поясе Timezone, обе включительно.
+type UserTimeseriesRequest struct {
+	Metric    string
+	Interval  string
+	From      string
+	To        string
+	Timezone  string
+	Breakdown string
+}
+
+// ExportUserTimeseriesRequest представляет запрос на выгрузку временного ряда по пользователям.
+type ExportUserTimeseriesRequest struct {
+	UserTimeseriesRequest
+	Format string
+	Lang   string
+}
+
+// UserTimeseriesQuery представляет подготовленный запрос временного ряда к БД.
+// From и To — границы [From, To) во времени хранения колонок, Timezone и StorageTimezone — имена IANA.
+type UserTimeseriesQuery struct {
+	Metric          string
+	Interval        string
+	Breakdown       string
+	Timezone        string
+	StorageTimezone string
+	From            time.Time
+	To              time.Time
+}
+
+// UserTimeseriesResponse представляет временной ряд по пользователям.
+// Keys — значения разбивки, встретившиеся в периоде.
+type UserTimeseriesResponse struct {
+	Metric    string                 `json:"metric"`
+	Interval  string                 `json:"interval"`
+	Timezone  string                 `json:"timezone"`
+	From      string                 `json:"from"`
+	To        string                 `json:"to"`
+	Breakdown string                 `json:"breakdown,omitempty"`
+	Keys      []string               `json:"keys,omitempty"`
+	Total     int                    `json:"total"`
+	Buckets   []UserTimeseriesBucket `json:"buckets"`
+}
+
+// UserTimeseriesBucket представляет один интервал ряда. Start — начало интервала в часовом поясе запроса.
+// При разбивке Breakdown содержит все значения из Keys, в том числе нулевые.
+type UserTimeseriesBucket struct {
+	Start     string         `json:"start"`
+	Count     int            `json:"count"`
+	Breakdown map[string]int `json:"breakdown,omitempty"`
+}
+
+// UserCohortsRequest представляет запрос когорт удержания пользователей.
+// Cohort — размер когорты (week или month), Periods — число периодов после регистрации.
+// From и To — даты (YYYY-MM-DD) в часовом поясе Timezone, задающие когорты по дате регистрации.
+// Пользователь считается удержанным, если в конце периода у него статус RetainedStatus и он не удален.
+type UserCohortsRequest struct {
+	UserFilter
+	Cohort         string
+	Periods        int
+	From           string
+	To             string
+	Timezone       string
+	RetainedStatus string
+}
+
+// UserCohortQuery представляет подготовленный запрос когорт к БД. Все времена — во времени хранения колонок.
+// Cohorts — границы когорт [Starts[i], Ends[i]), Checkpoints — моменты проверки статуса.
+type UserCohortQuery struct {
+	UserFilter
+	Starts         []time.Time
+	Ends           []time.Time
+	Checkpoints    []UserCohortCheckpoint
+	RetainedStatus string
+}
+
+// UserCohortCheckpoint представляет момент At проверки статуса пользователей когорты Cohort в конце периода Period.
+type UserCohortCheckpoint struct {
+	Cohort int
+	Period int
+	At     time.Time
+}
+
+// UserCohortsResponse представляет матрицу удержания пользователей.
+// ComputedAt — время расчета: результаты кешируются для одинаковых параметров.
+type UserCohortsResponse struct {
+	Cohort         string             `json:"cohort"`
+	Periods        int                `json:"periods"`
+	Timezone       string             `json:"timezone"`
+	From           string             `json:"from"`
+	To             string             `json:"to"`
+	RetainedStatus string             `json:"retained_status"`
+	Cohorts        []UserCohortResult `json:"cohorts"`
+	ComputedAt     string             `json:"computed_at"`
+}
+
+// UserCohortResult представляет строку матрицы удержания. Retained[n] и Rates[n] — количество и доля
+// удержанных в конце n-го периода после периода регистрации (0 — сам период регистрации);
+// для периодов, которые еще не закончились, значения null.
+type UserCohortResult struct {
+	Start    string     `json:"start"`
+	Size     int        `json:"size"`
+	Retained []*int     `json:"retained"`
+	Rates    []*float64 `json:"rates"`
+}
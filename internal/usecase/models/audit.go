@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrorInvalidParameterDate возвращается при невалидной дате или интервале дат.
+var ErrorInvalidParameterDate = errors.New("ErrorInvalidParameterDate")
+
+const (
+	// AuditOutcomeSuccess — действие выполнено.
+	AuditOutcomeSuccess = "success"
+	// AuditOutcomeFailure — действие завершилось ошибкой или было отклонено.
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEntry представляет действие, которое нужно записать в журнал аудита.
+// Сведения об администраторе и запросе берутся из контекста, если не заданы явно.
+type AuditEntry struct {
+	ActorID      string
+	ActorEmail   string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Outcome      string
+	Details      map[string]string
+}
+
+// GetAuditLogRequest представляет запрос на получение журнала аудита.
+type GetAuditLogRequest struct {
+	Page         int
+	Limit        int
+	ActorID      string
+	Action       []string
+	ResourceType string
+	ResourceID   string
+	Outcome      string
+	From         *time.Time
+	To           *time.Time
+}
+
+// AuditEntryResponse представляет запись журнала аудита в ответе.
+type AuditEntryResponse struct {
+	Seq          int64             `json:"seq"`
+	ID           string            `json:"id"`
+	ActorID      *string           `json:"actor_id"`
+	ActorEmail   *string           `json:"actor_email"`
+	Action       string            `json:"action"`
+	ResourceType string            `json:"resource_type"`
+	ResourceID   *string           `json:"resource_id"`
+	Outcome      string            `json:"outcome"`
+	IP           string            `json:"ip"`
+	UserAgent    string            `json:"user_agent"`
+	RequestID    string            `json:"request_id"`
+	Details      map[string]string `json:"details"`
+	Hash         string            `json:"hash"`
+	CreatedAt    string            `json:"created_at"`
+}
+
+// GetAuditLogResponse представляет ответ со списком записей журнала аудита.
+type GetAuditLogResponse struct {
+	Data       []AuditEntryResponse `json:"data"`
+	Total      int                  `json:"total"`
+	Page       int                  `json:"page"`
+	Limit      int                  `json:"limit"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// VerifyAuditLogResponse представляет результат проверки цепочки хешей журнала аудита.
+type VerifyAuditLogResponse struct {
+	Checked   int64  `json:"checked"`
+	Valid     bool   `json:"valid"`
+	BrokenSeq *int64 `json:"broken_seq"`
+	Reason    string `json:"reason,omitempty"`
+}
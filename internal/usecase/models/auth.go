@@ -49,9 +49,11 @@ type RefreshTokenResponse struct {
 }
 
 // AdminResponse представляет данные администратора.
+// Preferences заполняется только в ответе /auth/me.
 type AdminResponse struct {
-	ID    string `json:"id"`
-	Email string `json:"email"`
-	Name  string `json:"name"`
-	Role  string `json:"role"`
+	ID          string                    `json:"id"`
+	Email       string                    `json:"email"`
+	Name        string                    `json:"name"`
+	Role        string                    `json:"role"`
+	Preferences *AdminPreferencesResponse `json:"preferences,omitempty"`
 }
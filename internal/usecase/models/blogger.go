@@ -0,0 +1,151 @@
+package models
+
+import "errors"
+
+var (
+	// ErrorInvalidParameterUsername возвращается при пустом или слишком длинном username блогера.
+	ErrorInvalidParameterUsername = errors.New("ErrorInvalidParameterUsername")
+	// ErrorInvalidParameterNumber возвращается при слишком длинном номере блогера.
+	ErrorInvalidParameterNumber = errors.New("ErrorInvalidParameterNumber")
+	// ErrBloggerNotFound возвращается когда блогер не найден.
+	ErrBloggerNotFound = errors.New("blogger not found")
+	// ErrBloggerAlreadyExists возвращается при попытке создать блогера с существующим username.
+	ErrBloggerAlreadyExists = errors.New("blogger already exists")
+)
+
+// Статусы блогера.
+const (
+	BloggerStatusOn  = "ON"
+	BloggerStatusOff = "OFF"
+)
+
+// DateLayout — формат дат без времени (блогеры, кампании) в запросах и ответах.
+const DateLayout = "2006-01-02"
+
+// BloggerResponse представляет данные блогера в ответе.
+type BloggerResponse struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Username  string   `json:"username"`
+	Status    string   `json:"status"`
+	Tags      []string `json:"tags"`
+	Number    *string  `json:"number"`
+	Date      string   `json:"date"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+	// Contributors заполняется в карточке блогера: кто из администраторов загружал его из localStorage.
+	Contributors []BloggerContributionResponse `json:"contributors,omitempty"`
+}
+
+// BloggerFilter представляет фильтры выборки блогеров.
+// Tags отбирает блогеров с любой из меток (any, по умолчанию) или со всеми (all).
+// DateFrom и DateTo задают интервал дат в формате YYYY-MM-DD включительно.
+type BloggerFilter struct {
+	Search   string
+	Status   []string
+	Tags     []string
+	TagsMode string
+	DateFrom string
+	DateTo   string
+}
+
+// GetBloggersRequest представляет запрос на получение списка блогеров.
+type GetBloggersRequest struct {
+	BloggerFilter
+	Page  int
+	Limit int
+	Sort  string
+	Order string
+}
+
+// GetBloggersResponse представляет ответ со списком блогеров.
+type GetBloggersResponse struct {
+	Data       []BloggerResponse `json:"data"`
+	Total      int               `json:"total"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// CreateBloggerRequest представляет запрос на создание блогера.
+// Без Status блогер создается со статусом ON, без Date — с текущей датой.
+type CreateBloggerRequest struct {
+	Name     string   `json:"name"`
+	Username string   `json:"username"`
+	Status   string   `json:"status"`
+	Tags     []string `json:"tags"`
+	Number   *string  `json:"number"`
+	Date     string   `json:"date"`
+}
+
+// UpdateBloggerRequest представляет запрос на изменение блогера. Незаданные поля не меняются,
+// пустой список Tags очищает метки, пустой Number — номер.
+type UpdateBloggerRequest struct {
+	Name     *string  `json:"name"`
+	Username *string  `json:"username"`
+	Status   *string  `json:"status"`
+	Tags     []string `json:"tags"`
+	Number   *string  `json:"number"`
+	Date     *string  `json:"date"`
+}
+
+// ErrorInvalidParameterRecords возвращается при пустом или слишком большом списке записей импорта.
+var ErrorInvalidParameterRecords = errors.New("ErrorInvalidParameterRecords")
+
+// BloggerLegacyDateLayout — формат даты в списке блогеров из localStorage (ru-RU).
+const BloggerLegacyDateLayout = "02.01.2006"
+
+// Исходы загрузки записи блогера из localStorage.
+const (
+	// BloggerContributionCreated — по записи создан новый блогер.
+	BloggerContributionCreated = "created"
+	// BloggerContributionDuplicate — блогер с таким username уже есть и совпадает с записью.
+	BloggerContributionDuplicate = "duplicate"
+	// BloggerContributionConflict — блогер с таким username уже есть, но поля расходятся;
+	// существующие значения сохраняются.
+	BloggerContributionConflict = "conflict"
+)
+
+// BloggerLegacyRecord представляет блогера в формате списка bloggers_list из localStorage фронтенда.
+type BloggerLegacyRecord struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Username string   `json:"username"`
+	Status   string   `json:"status"`
+	Tags     []string `json:"tags"`
+	Number   string   `json:"number"`
+	Date     string   `json:"date"`
+}
+
+// BloggerLegacyConflict описывает запись, расходящуюся с уже существующим блогером.
+type BloggerLegacyConflict struct {
+	Index     int      `json:"index"`
+	LegacyID  string   `json:"legacy_id"`
+	Username  string   `json:"username"`
+	BloggerID string   `json:"blogger_id"`
+	Fields    []string `json:"fields"`
+}
+
+// BloggerLegacyError описывает запись, которую не удалось загрузить.
+type BloggerLegacyError struct {
+	Index    int    `json:"index"`
+	LegacyID string `json:"legacy_id"`
+	Message  string `json:"message"`
+}
+
+// BloggerLegacyImportResponse представляет результат загрузки списка блогеров из localStorage.
+type BloggerLegacyImportResponse struct {
+	Total      int                     `json:"total"`
+	Created    int                     `json:"created"`
+	Duplicates int                     `json:"duplicates"`
+	Conflicts  []BloggerLegacyConflict `json:"conflicts"`
+	Errors     []BloggerLegacyError    `json:"errors"`
+}
+
+// BloggerContributionResponse представляет загрузку записи блогера администратором.
+type BloggerContributionResponse struct {
+	AdminID   *string `json:"admin_id"`
+	LegacyID  *string `json:"legacy_id"`
+	Outcome   string  `json:"outcome"`
+	CreatedAt string  `json:"created_at"`
+}
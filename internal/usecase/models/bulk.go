@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrorInvalidParameterOperation возвращается при неизвестной массовой операции.
+	ErrorInvalidParameterOperation = errors.New("ErrorInvalidParameterOperation")
+	// ErrorInvalidParameterTarget возвращается, если не задан (или задан дважды) набор пользователей.
+	ErrorInvalidParameterTarget = errors.New("ErrorInvalidParameterTarget")
+	// ErrBulkLimitExceeded возвращается, если операция затрагивает больше пользователей, чем разрешено.
+	ErrBulkLimitExceeded = errors.New("bulk operation limit exceeded")
+)
+
+const (
+	// BulkOperationBan блокирует пользователей.
+	BulkOperationBan = "ban"
+	// BulkOperationActivate активирует пользователей.
+	BulkOperationActivate = "activate"
+	// BulkOperationDeactivate деактивирует пользователей.
+	BulkOperationDeactivate = "deactivate"
+	// BulkOperationSetRole меняет роль пользователей.
+	BulkOperationSetRole = "set_role"
+	// BulkOperationDelete удаляет пользователей (soft delete).
+	BulkOperationDelete = "delete"
+	// BulkOperationAddTags назначает пользователям метки.
+	BulkOperationAddTags = "add_tags"
+	// BulkOperationRemoveTags снимает с пользователей метки.
+	BulkOperationRemoveTags = "remove_tags"
+)
+
+const (
+	// BulkItemStatusOK означает, что операция над пользователем выполнена.
+	BulkItemStatusOK = "ok"
+	// BulkItemStatusSkipped означает, что пользователь уже в нужном состоянии.
+	BulkItemStatusSkipped = "skipped"
+	// BulkItemStatusNotFound означает, что пользователь не найден.
+	BulkItemStatusNotFound = "not_found"
+	// BulkItemStatusRejected означает, что переход статуса пользователя не разрешен.
+	BulkItemStatusRejected = "rejected"
+)
+
+// BulkUsersRequest представляет запрос на массовую операцию над пользователями.
+type BulkUsersRequest struct {
+	Operation string      `json:"operation"`
+	IDs       []string    `json:"ids"`
+	Filter    *UserFilter `json:"filter"`
+	Role      *string     `json:"role"`
+	TagIDs    []string    `json:"tag_ids"`
+	Reason    string      `json:"reason"`
+	Note      *string     `json:"note"`
+	Until     *time.Time  `json:"until"`
+	DryRun    bool        `json:"dry_run"`
+}
+
+// BulkUserItemResult представляет результат операции над одним пользователем.
+type BulkUserItemResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkUsersResponse представляет отчет о массовой операции.
+type BulkUsersResponse struct {
+	Operation string               `json:"operation"`
+	DryRun    bool                 `json:"dry_run"`
+	Total     int                  `json:"total"`
+	Succeeded int                  `json:"succeeded"`
+	Skipped   int                  `json:"skipped"`
+	NotFound  int                  `json:"not_found"`
+	Rejected  int                  `json:"rejected"`
+	Results   []BulkUserItemResult `json:"results"`
+}
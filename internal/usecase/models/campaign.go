@@ -0,0 +1,174 @@
+package models
+
+import "errors"
+
+var (
+	// ErrorInvalidParameterBudget возвращается при отрицательном бюджете кампании.
+	ErrorInvalidParameterBudget = errors.New("ErrorInvalidParameterBudget")
+	// ErrorInvalidParameterPrice возвращается при отрицательной цене размещения.
+	ErrorInvalidParameterPrice = errors.New("ErrorInvalidParameterPrice")
+	// ErrorInvalidParameterURL возвращается при невалидной ссылке на публикацию.
+	ErrorInvalidParameterURL = errors.New("ErrorInvalidParameterURL")
+	// ErrCampaignNotFound возвращается когда кампания не найдена.
+	ErrCampaignNotFound = errors.New("campaign not found")
+	// ErrCampaignBloggerNotFound возвращается когда блогер не участвует в кампании.
+	ErrCampaignBloggerNotFound = errors.New("campaign blogger not found")
+	// ErrCampaignBloggerAlreadyExists возвращается при повторном добавлении блогера в кампанию.
+	ErrCampaignBloggerAlreadyExists = errors.New("blogger already assigned to campaign")
+	// ErrCampaignBloggerPaid возвращается при попытке убрать из кампании блогера, которому уже заплатили.
+	ErrCampaignBloggerPaid = errors.New("campaign blogger already paid")
+	// ErrCampaignBloggerPriceLocked возвращается при изменении цены после заключения договора.
+	ErrCampaignBloggerPriceLocked = errors.New("campaign blogger price cannot change after contract")
+	// ErrCampaignBloggerPublicationURLRequired возвращается при удалении ссылки на вышедшую публикацию.
+	ErrCampaignBloggerPublicationURLRequired = errors.New("campaign blogger publication url is required after publication")
+)
+
+// Статусы кампании.
+const (
+	CampaignStatusDraft     = "draft"
+	CampaignStatusActive    = "active"
+	CampaignStatusCompleted = "completed"
+	CampaignStatusCancelled = "cancelled"
+)
+
+// CampaignStatuses — допустимые статусы кампании.
+var CampaignStatuses = []string{CampaignStatusDraft, CampaignStatusActive, CampaignStatusCompleted, CampaignStatusCancelled}
+
+// Статусы участия блогера в кампании. Статус меняется только на следующий по порядку,
+// а для published у участия должна быть ссылка на публикацию.
+const (
+	CampaignBloggerStatusNegotiating = "negotiating"
+	CampaignBloggerStatusContracted  = "contracted"
+	CampaignBloggerStatusPublished   = "published"
+	CampaignBloggerStatusPaid        = "paid"
+)
+
+// CampaignBloggerGuardPublicationURL — проверка перехода в published: задана ссылка на публикацию.
+const CampaignBloggerGuardPublicationURL = "publication_url"
+
+// CampaignBloggerStatuses — статусы участия блогера в кампании в порядке прохождения.
+var CampaignBloggerStatuses = []string{
+	CampaignBloggerStatusNegotiating,
+	CampaignBloggerStatusContracted,
+	CampaignBloggerStatusPublished,
+	CampaignBloggerStatusPaid,
+}
+
+// CampaignResponse представляет кампанию в ответе. Суммы указаны в копейках.
+type CampaignResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Budget    int64  `json:"budget"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// CampaignFilter представляет фильтры выборки кампаний.
+// Active отбирает кампании, период которых включает указанную дату (YYYY-MM-DD).
+type CampaignFilter struct {
+	Search string
+	Status []string
+	Active string
+}
+
+// GetCampaignsRequest представляет запрос на получение списка кампаний.
+type GetCampaignsRequest struct {
+	CampaignFilter
+	Page  int
+	Limit int
+	Sort  string
+	Order string
+}
+
+// GetCampaignsResponse представляет ответ со списком кампаний.
+type GetCampaignsResponse struct {
+	Data       []CampaignResponse `json:"data"`
+	Total      int                `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	TotalPages int                `json:"total_pages"`
+}
+
+// CreateCampaignRequest представляет запрос на создание кампании.
+// Без Status кампания создается черновиком.
+type CreateCampaignRequest struct {
+	Name      string `json:"name"`
+	Budget    int64  `json:"budget"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Status    string `json:"status"`
+}
+
+// UpdateCampaignRequest представляет запрос на изменение кампании. Незаданные поля не меняются.
+type UpdateCampaignRequest struct {
+	Name      *string `json:"name"`
+	Budget    *int64  `json:"budget"`
+	StartDate *string `json:"start_date"`
+	EndDate   *string `json:"end_date"`
+	Status    *string `json:"status"`
+}
+
+// AssignCampaignBloggerRequest представляет запрос на добавление блогера в кампанию.
+type AssignCampaignBloggerRequest struct {
+	BloggerID    string  `json:"blogger_id"`
+	Price        int64   `json:"price"`
+	Deliverables *string `json:"deliverables"`
+	DueDate      *string `json:"due_date"`
+}
+
+// UpdateCampaignBloggerRequest представляет запрос на изменение участия блогера в кампании.
+// Незаданные поля не меняются, пустые строки очищают необязательные поля.
+type UpdateCampaignBloggerRequest struct {
+	Price          *int64  `json:"price"`
+	Deliverables   *string `json:"deliverables"`
+	PublicationURL *string `json:"publication_url"`
+	DueDate        *string `json:"due_date"`
+	Status         *string `json:"status"`
+}
+
+// CampaignBloggerResponse представляет участие блогера в кампании.
+// В составе кампании заполняется Blogger, в истории блогера — Campaign.
+type CampaignBloggerResponse struct {
+	CampaignID     string            `json:"campaign_id"`
+	BloggerID      string            `json:"blogger_id"`
+	Price          int64             `json:"price"`
+	Deliverables   *string           `json:"deliverables"`
+	PublicationURL *string           `json:"publication_url"`
+	DueDate        *string           `json:"due_date"`
+	Status         string            `json:"status"`
+	ContractedAt   *string           `json:"contracted_at"`
+	PublishedAt    *string           `json:"published_at"`
+	PaidAt         *string           `json:"paid_at"`
+	CreatedAt      string            `json:"created_at"`
+	UpdatedAt      string            `json:"updated_at"`
+	Campaign       *CampaignResponse `json:"campaign,omitempty"`
+	Blogger        *BloggerResponse  `json:"blogger,omitempty"`
+}
+
+// CampaignBloggerTotals представляет итоги по участию блогеров в кампаниях (суммы в копейках).
+// Agreed — сумма цен без учета переговоров, Paid — сумма оплаченных размещений.
+type CampaignBloggerTotals struct {
+	Count    int            `json:"count"`
+	ByStatus map[string]int `json:"by_status"`
+	Agreed   int64          `json:"agreed"`
+	Paid     int64          `json:"paid"`
+	Unpaid   int64          `json:"unpaid"`
+}
+
+// CampaignRosterResponse представляет состав кампании с итогами.
+// Remaining — остаток бюджета после согласованных размещений, может быть отрицательным.
+type CampaignRosterResponse struct {
+	Campaign  CampaignResponse          `json:"campaign"`
+	Data      []CampaignBloggerResponse `json:"data"`
+	Totals    CampaignBloggerTotals     `json:"totals"`
+	Remaining int64                     `json:"remaining"`
+}
+
+// BloggerCampaignsResponse представляет историю кампаний блогера с итогами.
+type BloggerCampaignsResponse struct {
+	Data   []CampaignBloggerResponse `json:"data"`
+	Totals CampaignBloggerTotals     `json:"totals"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// Ключи by_verification в статистике пользователей.
+const (
+	DashboardVerified   = "verified"
+	DashboardUnverified = "unverified"
+)
+
+// DashboardStatsResponse представляет статистику пользователей для дашборда.
+// Считается по агрегату, который обновляется периодически; RefreshedAt — время последнего пересчета
+// (nil, если пользователей нет).
+type DashboardStatsResponse struct {
+	Total          int                     `json:"total"`
+	ByStatus       map[string]int          `json:"by_status"`
+	ByRole         map[string]int          `json:"by_role"`
+	ByVerification map[string]int          `json:"by_verification"`
+	NewUsers       []DashboardNewUsersStat `json:"new_users"`
+	RefreshedAt    *string                 `json:"refreshed_at"`
+}
+
+// DashboardNewUsersStat представляет количество новых пользователей за последние Days дней
+// и за такой же предыдущий период. Delta = Count - Previous.
+type DashboardNewUsersStat struct {
+	Days     int `json:"days"`
+	Count    int `json:"count"`
+	Previous int `json:"previous"`
+	Delta    int `json:"delta"`
+}
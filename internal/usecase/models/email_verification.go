@@ -0,0 +1,27 @@
+package models
+
+import "errors"
+
+var (
+	// ErrEmailAlreadyVerified возвращается при попытке подтвердить уже подтвержденный email.
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+	// ErrVerificationRateLimited возвращается при слишком частой отправке писем подтверждения.
+	ErrVerificationRateLimited = errors.New("verification email sent too often")
+	// ErrInvalidVerificationToken возвращается при невалидной ссылке подтверждения.
+	ErrInvalidVerificationToken = errors.New("invalid verification token")
+	// ErrExpiredVerificationToken возвращается при истекшей ссылке подтверждения.
+	ErrExpiredVerificationToken = errors.New("expired verification token")
+)
+
+const (
+	// UserHistoryActionVerifyEmail — email пользователя подтвержден по ссылке.
+	UserHistoryActionVerifyEmail = "verify_email"
+	// UserHistoryActionEmailChange — смена email подтверждена по ссылке на новый адрес.
+	UserHistoryActionEmailChange = "email_change"
+)
+
+// SendVerificationEmailResponse представляет ответ на отправку письма подтверждения.
+type SendVerificationEmailResponse struct {
+	Email     string `json:"email"`
+	ExpiresAt string `json:"expires_at"`
+}
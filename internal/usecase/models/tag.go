@@ -0,0 +1,56 @@
+package models
+
+import "errors"
+
+var (
+	// ErrorInvalidParameterColor возвращается при цвете метки не в формате #RRGGBB.
+	ErrorInvalidParameterColor = errors.New("ErrorInvalidParameterColor")
+	// ErrorInvalidParameterTags возвращается при пустом или невалидном списке меток.
+	ErrorInvalidParameterTags = errors.New("ErrorInvalidParameterTags")
+	// ErrorInvalidParameterTagsMode возвращается при неизвестном режиме фильтра по меткам.
+	ErrorInvalidParameterTagsMode = errors.New("ErrorInvalidParameterTagsMode")
+	// ErrTagNotFound возвращается когда метка не найдена.
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrTagAlreadyExists возвращается при попытке создать метку с существующим названием.
+	ErrTagAlreadyExists = errors.New("tag already exists")
+)
+
+const (
+	// TagsModeAny отбирает пользователей хотя бы с одной из меток.
+	TagsModeAny = "any"
+	// TagsModeAll отбирает пользователей со всеми метками.
+	TagsModeAll = "all"
+)
+
+// TagDefaultColor — цвет метки, если он не указан.
+const TagDefaultColor = "#808080"
+
+// TagRequest представляет запрос на создание или изменение метки.
+type TagRequest struct {
+	Name        string  `json:"name"`
+	Color       string  `json:"color"`
+	Description *string `json:"description"`
+}
+
+// TagResponse представляет метку каталога в ответе.
+type TagResponse struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Color       string  `json:"color"`
+	Description *string `json:"description"`
+	UsersCount  int     `json:"users_count"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+// UserTagResponse представляет метку в данных пользователя.
+type UserTagResponse struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// UserTagsRequest представляет запрос на назначение меток пользователю.
+type UserTagsRequest struct {
+	TagIDs []string `json:"tag_ids"`
+}
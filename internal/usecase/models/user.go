@@ -7,6 +7,8 @@ var (
 	ErrorInvalidParameterRole = errors.New("ErrorInvalidParameterRole")
 	// ErrorInvalidParameterStatus возвращается при невалидном статусе.
 	ErrorInvalidParameterStatus = errors.New("ErrorInvalidParameterStatus")
+	// ErrorInvalidParameterPhone возвращается при невалидном номере телефона.
+	ErrorInvalidParameterPhone = errors.New("ErrorInvalidParameterPhone")
 	// ErrUserNotFound возвращается когда пользователь не найден.
 	ErrUserNotFound = errors.New("user not found")
 	// ErrUserAlreadyExists возвращается при попытке создать существующего пользователя.
@@ -15,26 +17,49 @@ var (
 
 // UserResponse представляет данные пользователя в ответе.
 type UserResponse struct {
-	ID              string  `json:"id"`
-	Email           string  `json:"email"`
-	Name            string  `json:"name"`
-	Phone           *string `json:"phone"`
-	Role            string  `json:"role"`
-	Status          string  `json:"status"`
-	IsEmailVerified bool    `json:"is_email_verified"`
-	CreatedAt       string  `json:"created_at"`
-	UpdatedAt       string  `json:"updated_at"`
+	ID              string         `json:"id"`
+	Email           string         `json:"email"`
+	Name            string         `json:"name"`
+	Phone           *string        `json:"phone"`
+	PhoneDisplay    *string        `json:"phone_display"`
+	Role            string         `json:"role"`
+	Status          string         `json:"status"`
+	IsEmailVerified bool           `json:"is_email_verified"`
+	Attributes      map[string]any `json:"attributes"`
+	// Tags заполняется в списке и карточке пользователя.
+	Tags      []UserTagResponse `json:"tags,omitempty"`
+	CreatedAt string            `json:"created_at"`
+	UpdatedAt string            `json:"updated_at"`
+	// PendingEmail заполняется, если смена email ожидает подтверждения по ссылке.
+	PendingEmail *string `json:"pending_email,omitempty"`
+	// PIIMasked означает, что email и телефон скрыты по роли администратора.
+	PIIMasked bool `json:"pii_masked,omitempty"`
+}
+
+// UserFilter представляет общие фильтры выборки пользователей.
+type UserFilter struct {
+	Search          string   `json:"search"`
+	Status          []string `json:"status"`
+	Role            []string `json:"role"`
+	IsEmailVerified *bool    `json:"is_email_verified"`
+	// Attributes отбирает пользователей с указанными значениями атрибутов.
+	Attributes map[string]any `json:"attributes"`
+	// Tags отбирает пользователей по ID меток: с любой из них (any, по умолчанию) или со всеми (all).
+	Tags     []string `json:"tags"`
+	TagsMode string   `json:"tags_mode"`
+	// PhoneDigits заполняется из Search в usecase: цифры телефона для поиска.
+	PhoneDigits string `json:"-"`
+	// AttributesJSON заполняется из Attributes в usecase: JSON со значениями, приведенными к типам атрибутов.
+	AttributesJSON string `json:"-"`
 }
 
 // GetUsersRequest представляет запрос на получение списка пользователей.
 type GetUsersRequest struct {
-	Page   int
-	Limit  int
-	Search string
-	Status []string
-	Role   []string
-	Sort   string
-	Order  string
+	UserFilter
+	Page  int
+	Limit int
+	Sort  string
+	Order string
 }
 
 // GetUsersResponse представляет ответ со списком пользователей.
@@ -48,17 +73,23 @@ type GetUsersResponse struct {
 
 // CreateUserRequest представляет запрос на создание пользователя.
 type CreateUserRequest struct {
-	Email  string
-	Name   string
-	Phone  *string
-	Role   string
-	Status string
+	Email      string
+	Name       string
+	Phone      *string
+	Role       string
+	Status     string
+	Attributes map[string]any
 }
 
 // UpdateUserRequest представляет запрос на обновление пользователя.
+// При ConfirmEmail новый email применяется только после перехода по ссылке из письма.
+// Attributes объединяются с текущими значениями, null удаляет атрибут.
 type UpdateUserRequest struct {
-	Name   *string
-	Phone  *string
-	Role   *string
-	Status *string
+	Email        *string
+	Name         *string
+	Phone        *string
+	Role         *string
+	Status       *string
+	Attributes   map[string]any
+	ConfirmEmail bool `json:"confirm_email"`
 }
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrorInvalidParameterKey возвращается при невалидном ключе атрибута.
+	ErrorInvalidParameterKey = errors.New("ErrorInvalidParameterKey")
+	// ErrorInvalidParameterType возвращается при неизвестном типе атрибута или попытке его изменить.
+	ErrorInvalidParameterType = errors.New("ErrorInvalidParameterType")
+	// ErrorInvalidParameterEnum возвращается, если допустимые значения не соответствуют типу атрибута.
+	ErrorInvalidParameterEnum = errors.New("ErrorInvalidParameterEnum")
+	// ErrorInvalidParameterDefault возвращается, если значение по умолчанию не проходит проверку атрибута.
+	ErrorInvalidParameterDefault = errors.New("ErrorInvalidParameterDefault")
+	// ErrorInvalidParameterAttributes возвращается при невалидных атрибутах пользователя.
+	ErrorInvalidParameterAttributes = errors.New("ErrorInvalidParameterAttributes")
+	// ErrUserAttributeNotFound возвращается когда описание атрибута не найдено.
+	ErrUserAttributeNotFound = errors.New("user attribute not found")
+	// ErrUserAttributeAlreadyExists возвращается при попытке создать атрибут с существующим ключом.
+	ErrUserAttributeAlreadyExists = errors.New("user attribute already exists")
+)
+
+const (
+	// UserAttributeTypeString — строка.
+	UserAttributeTypeString = "string"
+	// UserAttributeTypeNumber — число.
+	UserAttributeTypeNumber = "number"
+	// UserAttributeTypeInteger — целое число.
+	UserAttributeTypeInteger = "integer"
+	// UserAttributeTypeBoolean — логическое значение.
+	UserAttributeTypeBoolean = "boolean"
+	// UserAttributeTypeDate — дата в формате YYYY-MM-DD.
+	UserAttributeTypeDate = "date"
+)
+
+// UserAttributeSortPrefix — префикс сортировки и колонок импорта/экспорта по атрибуту: attributes.<key>.
+const UserAttributeSortPrefix = "attributes."
+
+// UserAttributeError описывает невалидное значение атрибута пользователя.
+type UserAttributeError struct {
+	Key     string
+	Message string
+}
+
+// Error возвращает описание ошибки.
+func (e *UserAttributeError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrorInvalidParameterAttributes, e.Key, e.Message)
+}
+
+// Unwrap возвращает ErrorInvalidParameterAttributes.
+func (e *UserAttributeError) Unwrap() error {
+	return ErrorInvalidParameterAttributes
+}
+
+// UserAttributeRequest представляет запрос на создание или изменение описания атрибута.
+// При изменении Key берется из пути, а Type должен совпадать с текущим.
+type UserAttributeRequest struct {
+	Key         string  `json:"key"`
+	Type        string  `json:"type"`
+	Required    bool    `json:"required"`
+	Enum        []any   `json:"enum"`
+	Default     any     `json:"default"`
+	Description *string `json:"description"`
+}
+
+// UserAttributeResponse представляет описание атрибута в ответе.
+type UserAttributeResponse struct {
+	Key         string  `json:"key"`
+	Type        string  `json:"type"`
+	Required    bool    `json:"required"`
+	Enum        []any   `json:"enum"`
+	Default     any     `json:"default"`
+	Description *string `json:"description"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
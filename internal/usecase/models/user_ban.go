@@ -0,0 +1,55 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrorInvalidParameterReason возвращается при пустой причине блокировки, разблокировки или обезличивания.
+	ErrorInvalidParameterReason = errors.New("ErrorInvalidParameterReason")
+	// ErrorInvalidParameterUntil возвращается, если срок блокировки не в будущем.
+	ErrorInvalidParameterUntil = errors.New("ErrorInvalidParameterUntil")
+	// ErrUserAlreadyBanned возвращается при попытке заблокировать уже заблокированного пользователя.
+	ErrUserAlreadyBanned = errors.New("user already banned")
+	// ErrUserNotBanned возвращается при попытке снять блокировку с незаблокированного пользователя.
+	ErrUserNotBanned = errors.New("user is not banned")
+)
+
+const (
+	// UserHistoryActionBan — пользователь заблокирован.
+	UserHistoryActionBan = "ban"
+	// UserHistoryActionUnban — блокировка пользователя снята.
+	UserHistoryActionUnban = "unban"
+)
+
+// UserBanLiftReasonExpired — причина снятия блокировки по истечении срока.
+const UserBanLiftReasonExpired = "expired"
+
+// BanUserRequest представляет запрос на блокировку пользователя.
+// Until равен nil для бессрочной блокировки.
+type BanUserRequest struct {
+	Reason string     `json:"reason"`
+	Note   *string    `json:"note"`
+	Until  *time.Time `json:"until"`
+}
+
+// UnbanUserRequest представляет запрос на снятие блокировки пользователя.
+type UnbanUserRequest struct {
+	Reason string `json:"reason"`
+}
+
+// UserBanResponse представляет блокировку пользователя в ответе.
+type UserBanResponse struct {
+	ID         string  `json:"id"`
+	UserID     string  `json:"user_id"`
+	Reason     string  `json:"reason"`
+	Note       *string `json:"note"`
+	AdminID    *string `json:"admin_id"`
+	BannedAt   string  `json:"banned_at"`
+	Until      *string `json:"until"`
+	Active     bool    `json:"active"`
+	LiftedAt   *string `json:"lifted_at"`
+	LiftedBy   *string `json:"lifted_by"`
+	LiftReason *string `json:"lift_reason"`
+}
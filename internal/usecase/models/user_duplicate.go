@@ -0,0 +1,66 @@
+package models
+
+import "errors"
+
+var (
+	// ErrorInvalidParameterScore возвращается, если минимальная оценка не в диапазоне [0, 1].
+	ErrorInvalidParameterScore = errors.New("ErrorInvalidParameterScore")
+	// ErrorInvalidParameterMerge возвращается при объединении пользователя с самим собой
+	// или при неизвестном поле либо источнике значения.
+	ErrorInvalidParameterMerge = errors.New("ErrorInvalidParameterMerge")
+)
+
+const (
+	// UserDuplicateReasonEmail — совпадает нормализованный email.
+	UserDuplicateReasonEmail = "email"
+	// UserDuplicateReasonPhone — совпадают цифры телефона.
+	UserDuplicateReasonPhone = "phone"
+	// UserDuplicateReasonName — имена похожи.
+	UserDuplicateReasonName = "name"
+)
+
+const (
+	// UserMergeSourceSurvivor — значение поля берется у сохраняемого пользователя.
+	UserMergeSourceSurvivor = "survivor"
+	// UserMergeSourceLoser — значение поля берется у объединяемого пользователя.
+	UserMergeSourceLoser = "loser"
+	// UserMergeSourceMerge — атрибуты объединяются, при совпадении ключей побеждает сохраняемый пользователь.
+	UserMergeSourceMerge = "merge"
+)
+
+// FindUserDuplicatesRequest представляет запрос на поиск дубликатов.
+// UserID ограничивает поиск парами с указанным пользователем.
+type FindUserDuplicatesRequest struct {
+	UserID   string
+	MinScore float64
+	Page     int
+	Limit    int
+}
+
+// UserDuplicateResponse представляет пару пользователей, похожих на дубликаты.
+type UserDuplicateResponse struct {
+	User           UserResponse `json:"user"`
+	Duplicate      UserResponse `json:"duplicate"`
+	Score          float64      `json:"score"`
+	Reasons        []string     `json:"reasons"`
+	NameSimilarity float64      `json:"name_similarity"`
+}
+
+// FindUserDuplicatesResponse представляет ответ со списком пар дубликатов.
+type FindUserDuplicatesResponse struct {
+	Data       []UserDuplicateResponse `json:"data"`
+	Total      int                     `json:"total"`
+	Page       int                     `json:"page"`
+	Limit      int                     `json:"limit"`
+	TotalPages int                     `json:"total_pages"`
+}
+
+// MergeUsersRequest представляет запрос на объединение дубликатов.
+// Fields задает источник значения для полей email, name, phone, role, status (survivor или loser)
+// и attributes (survivor, loser или merge). Не указанные поля берутся у сохраняемого пользователя,
+// атрибуты по умолчанию объединяются.
+type MergeUsersRequest struct {
+	SurvivorID string            `json:"survivor_id"`
+	LoserID    string            `json:"loser_id"`
+	Fields     map[string]string `json:"fields"`
+}
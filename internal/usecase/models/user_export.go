@@ -0,0 +1,22 @@
+package models
+
+import "errors"
+
+var (
+	// ErrorInvalidParameterColumns возвращается при неизвестной колонке экспорта.
+	ErrorInvalidParameterColumns = errors.New("ErrorInvalidParameterColumns")
+	// ErrExportTooManyRows возвращается, если выборка не помещается в файл выбранного формата.
+	ErrExportTooManyRows = errors.New("export has too many rows for format")
+)
+
+// ExportUsersRequest представляет запрос на экспорт пользователей.
+// Фильтры и сортировка совпадают с GetUsersRequest, пагинация не применяется.
+// Пустой Columns означает все колонки, Lang выбирает язык заголовков (ru или en).
+type ExportUsersRequest struct {
+	UserFilter
+	Format  string
+	Columns []string
+	Lang    string
+	Sort    string
+	Order   string
+}
@@ -0,0 +1,69 @@
+package models
+
+import "errors"
+
+var (
+	// ErrorInvalidParameterField возвращается при неизвестном или недоступном для отката поле.
+	ErrorInvalidParameterField = errors.New("ErrorInvalidParameterField")
+	// ErrUserVersionNotFound возвращается когда версия пользователя не найдена.
+	ErrUserVersionNotFound = errors.New("user version not found")
+)
+
+const (
+	// UserHistoryActionCreate — пользователь создан.
+	UserHistoryActionCreate = "create"
+	// UserHistoryActionUpdate — пользователь изменен.
+	UserHistoryActionUpdate = "update"
+	// UserHistoryActionDelete — пользователь удален.
+	UserHistoryActionDelete = "delete"
+	// UserHistoryActionRevert — поля пользователя возвращены к одной из прошлых версий.
+	UserHistoryActionRevert = "revert"
+	// UserHistoryActionMerge — с пользователем объединен дубликат.
+	UserHistoryActionMerge = "merge"
+)
+
+// UserFieldChange представляет изменение одного поля пользователя.
+type UserFieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// UserHistoryResponse представляет версию пользователя в ответе.
+type UserHistoryResponse struct {
+	Version   int                        `json:"version"`
+	Action    string                     `json:"action"`
+	AdminID   *string                    `json:"admin_id"`
+	RequestID *string                    `json:"request_id"`
+	Changes   map[string]UserFieldChange `json:"changes"`
+	Snapshot  UserSnapshotResponse       `json:"snapshot"`
+	// MergedFrom — ID объединенного пользователя, из истории которого перенесена версия.
+	MergedFrom *string `json:"merged_from,omitempty"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+// UserSnapshotResponse представляет состояние пользователя в версии.
+type UserSnapshotResponse struct {
+	Email           string         `json:"email"`
+	Name            string         `json:"name"`
+	Phone           *string        `json:"phone"`
+	Role            string         `json:"role"`
+	Status          string         `json:"status"`
+	IsEmailVerified bool           `json:"is_email_verified"`
+	Attributes      map[string]any `json:"attributes"`
+	DeletedAt       *string        `json:"deleted_at"`
+}
+
+// GetUserHistoryResponse представляет ответ со списком версий пользователя.
+type GetUserHistoryResponse struct {
+	Data       []UserHistoryResponse `json:"data"`
+	Total      int                   `json:"total"`
+	Page       int                   `json:"page"`
+	Limit      int                   `json:"limit"`
+	TotalPages int                   `json:"total_pages"`
+}
+
+// RevertUserRequest представляет запрос на откат пользователя к версии.
+// Пустой Fields означает откат всех изменяемых полей.
+type RevertUserRequest struct {
+	Fields []string `json:"fields"`
+}
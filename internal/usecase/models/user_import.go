@@ -0,0 +1,78 @@
+package models
+
+import "errors"
+
+var (
+	// ErrorInvalidParameterFormat возвращается при неподдерживаемом формате файла.
+	ErrorInvalidParameterFormat = errors.New("ErrorInvalidParameterFormat")
+	// ErrorInvalidParameterMapping возвращается, если в файле не найдены обязательные колонки.
+	ErrorInvalidParameterMapping = errors.New("ErrorInvalidParameterMapping")
+	// ErrorInvalidParameterOnDuplicate возвращается при неизвестном режиме обработки дубликатов.
+	ErrorInvalidParameterOnDuplicate = errors.New("ErrorInvalidParameterOnDuplicate")
+	// ErrImportFileEmpty возвращается, если в файле нет строк с данными.
+	ErrImportFileEmpty = errors.New("import file has no data rows")
+	// ErrImportJobNotFound возвращается когда задача импорта не найдена.
+	ErrImportJobNotFound = errors.New("import job not found")
+)
+
+const (
+	// ImportOnDuplicateSkip пропускает строки с уже существующим email.
+	ImportOnDuplicateSkip = "skip"
+	// ImportOnDuplicateUpdate обновляет существующего пользователя данными из строки.
+	ImportOnDuplicateUpdate = "update"
+	// ImportOnDuplicateFail помечает строку с существующим email как ошибочную.
+	ImportOnDuplicateFail = "fail"
+)
+
+const (
+	// ImportStatusPending — задача создана и ожидает обработки.
+	ImportStatusPending = "pending"
+	// ImportStatusRunning — задача обрабатывается.
+	ImportStatusRunning = "running"
+	// ImportStatusCompleted — задача завершена.
+	ImportStatusCompleted = "completed"
+	// ImportStatusFailed — задача прервана из-за ошибки.
+	ImportStatusFailed = "failed"
+)
+
+// ImportUsersRequest представляет запрос на импорт пользователей из файла.
+// Mapping сопоставляет поле пользователя (email, name, phone, role, status, attributes.<key>)
+// с заголовком колонки в файле. Если поле не указано, колонка ищется по имени поля.
+type ImportUsersRequest struct {
+	AdminID     string
+	Filename    string
+	Format      string
+	Data        []byte
+	Mapping     map[string]string
+	OnDuplicate string
+	DryRun      bool
+}
+
+// ImportRowError представляет ошибку в строке импортируемого файла.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// UserImportJobResponse представляет состояние задачи импорта.
+type UserImportJobResponse struct {
+	ID            string           `json:"id"`
+	Filename      string           `json:"filename"`
+	Format        string           `json:"format"`
+	Status        string           `json:"status"`
+	OnDuplicate   string           `json:"on_duplicate"`
+	DryRun        bool             `json:"dry_run"`
+	TotalRows     int              `json:"total_rows"`
+	ProcessedRows int              `json:"processed_rows"`
+	Progress      int              `json:"progress"`
+	Created       int              `json:"created"`
+	Updated       int              `json:"updated"`
+	Skipped       int              `json:"skipped"`
+	Failed        int              `json:"failed"`
+	Errors        []ImportRowError `json:"errors"`
+	ErrorMessage  *string          `json:"error_message"`
+	CreatedAt     string           `json:"created_at"`
+	FinishedAt    *string          `json:"finished_at"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import "errors"
+
+var (
+	// ErrorInvalidParameterBody возвращается при пустом или слишком длинном тексте заметки.
+	ErrorInvalidParameterBody = errors.New("ErrorInvalidParameterBody")
+	// ErrorInvalidParameterMentions возвращается, если упомянутый администратор не найден, неактивен или не видит заметку.
+	ErrorInvalidParameterMentions = errors.New("ErrorInvalidParameterMentions")
+	// ErrorInvalidParameterParent возвращается, если заметка, на которую дается ответ, не найдена или удалена.
+	ErrorInvalidParameterParent = errors.New("ErrorInvalidParameterParent")
+	// ErrorInvalidParameterVisibility возвращается при попытке задать видимость ответа.
+	ErrorInvalidParameterVisibility = errors.New("ErrorInvalidParameterVisibility")
+	// ErrUserNoteNotFound возвращается когда заметка не найдена или недоступна администратору.
+	ErrUserNoteNotFound = errors.New("user note not found")
+	// ErrUserNoteReplyPin возвращается при попытке закрепить ответ: закрепляются только ветки.
+	ErrUserNoteReplyPin = errors.New("only root notes can be pinned")
+	// ErrUserNoteForbidden возвращается при попытке изменить чужую заметку без прав модератора.
+	ErrUserNoteForbidden = errors.New("user note action forbidden")
+)
+
+// CreateUserNoteRequest представляет запрос на создание заметки или ответа на нее.
+// VisibleToRoles задается только у корневой заметки; ответы видны тем же ролям.
+type CreateUserNoteRequest struct {
+	Body           string   `json:"body"`
+	ParentID       *string  `json:"parent_id"`
+	Mentions       []string `json:"mentions"`
+	VisibleToRoles []string `json:"visible_to_roles"`
+}
+
+// UpdateUserNoteRequest представляет запрос на изменение заметки.
+type UpdateUserNoteRequest struct {
+	Body           *string  `json:"body"`
+	Mentions       []string `json:"mentions"`
+	VisibleToRoles []string `json:"visible_to_roles"`
+}
+
+// UserNoteResponse представляет заметку в ответе. У удаленной заметки, на которую есть ответы,
+// текст и упоминания скрыты.
+type UserNoteResponse struct {
+	ID             string             `json:"id"`
+	UserID         string             `json:"user_id"`
+	ParentID       *string            `json:"parent_id"`
+	AuthorID       *string            `json:"author_id"`
+	Body           string             `json:"body"`
+	Mentions       []string           `json:"mentions"`
+	VisibleToRoles []string           `json:"visible_to_roles"`
+	Pinned         bool               `json:"pinned"`
+	PinnedAt       *string            `json:"pinned_at"`
+	PinnedBy       *string            `json:"pinned_by"`
+	EditedAt       *string            `json:"edited_at"`
+	DeletedAt      *string            `json:"deleted_at"`
+	DeletedBy      *string            `json:"deleted_by"`
+	CreatedAt      string             `json:"created_at"`
+	UpdatedAt      string             `json:"updated_at"`
+	Replies        []UserNoteResponse `json:"replies,omitempty"`
+}
+
+// UserNoteRevisionResponse представляет предыдущую версию текста заметки в ответе.
+type UserNoteRevisionResponse struct {
+	ID        string   `json:"id"`
+	Body      string   `json:"body"`
+	Mentions  []string `json:"mentions"`
+	EditedBy  *string  `json:"edited_by"`
+	CreatedAt string   `json:"created_at"`
+}
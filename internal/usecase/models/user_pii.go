@@ -0,0 +1,35 @@
+package models
+
+import "errors"
+
+// ErrPIIRevealForbidden возвращается, если роли администратора не разрешено раскрывать персональные данные.
+var ErrPIIRevealForbidden = errors.New("pii reveal forbidden")
+
+// Поля пользователя, которые маскируются в ответах и могут быть раскрыты.
+const (
+	UserPIIFieldEmail = "email"
+	UserPIIFieldPhone = "phone"
+)
+
+// ReencryptUsersResponse представляет результат перешифрования персональных данных пользователей.
+// HistoryVersions и EmailChanges — количество перешифрованных копий email и телефона,
+// ImportJobs — количество задач импорта, в отчетах которых маскированы значения.
+type ReencryptUsersResponse struct {
+	Checked         int `json:"checked"`
+	Updated         int `json:"updated"`
+	HistoryVersions int `json:"history_versions"`
+	EmailChanges    int `json:"email_changes"`
+	ImportJobs      int `json:"import_jobs"`
+}
+
+// RevealUserPIIRequest представляет запрос на раскрытие одного маскированного поля пользователя.
+type RevealUserPIIRequest struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// RevealUserPIIResponse представляет полное значение раскрытого поля.
+type RevealUserPIIResponse struct {
+	Field string  `json:"field"`
+	Value *string `json:"value"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "errors"
+
+var (
+	// ErrUserErased возвращается при попытке выгрузить или обезличить уже обезличенного пользователя.
+	ErrUserErased = errors.New("user data has been erased")
+	// ErrPrivacyForbidden возвращается, если роли администратора не разрешены выгрузка и обезличивание данных.
+	ErrPrivacyForbidden = errors.New("privacy action forbidden")
+)
+
+// Файлы архива с данными пользователя.
+const (
+	UserPrivacyFileManifest = "manifest.json"
+	UserPrivacyFileProfile  = "profile.json"
+	UserPrivacyFileHistory  = "history.json"
+	UserPrivacyFileNotes    = "notes.json"
+	UserPrivacyFileTags     = "tags.json"
+	UserPrivacyFileBans     = "bans.json"
+	UserPrivacyFileAudit    = "audit.json"
+)
+
+// UserPrivacyManifest описывает архив с данными пользователя.
+type UserPrivacyManifest struct {
+	UserID        string   `json:"user_id"`
+	MergedUserIDs []string `json:"merged_user_ids"`
+	GeneratedAt   string   `json:"generated_at"`
+	GeneratedBy   string   `json:"generated_by"`
+	Files         []string `json:"files"`
+}
+
+// UserPrivacyProfile представляет профиль пользователя в архиве, включая удаленных
+// и объединенных с ним пользователей.
+type UserPrivacyProfile struct {
+	UserResponse
+	DeletedAt   *string              `json:"deleted_at"`
+	MergedUsers []UserPrivacyProfile `json:"merged_users,omitempty"`
+}
+
+// UserPrivacyNote представляет заметку о пользователе в архиве вместе с историей правок.
+// Текст удаленных заметок сохраняется.
+type UserPrivacyNote struct {
+	UserNoteResponse
+	Revisions []UserNoteRevisionResponse `json:"revisions"`
+}
+
+// EraseUserRequest представляет запрос на обезличивание данных пользователя.
+// Reason — основание (например, номер обращения), записывается в журнал аудита.
+type EraseUserRequest struct {
+	Reason string `json:"reason"`
+}
+
+// EraseUserResponse представляет результат обезличивания данных пользователя.
+type EraseUserResponse struct {
+	UserID          string   `json:"user_id"`
+	ErasedUserIDs   []string `json:"erased_user_ids"`
+	ErasedAt        string   `json:"erased_at"`
+	HistoryVersions int64    `json:"history_versions"`
+	Notes           int64    `json:"notes"`
+	NoteRevisions   int64    `json:"note_revisions"`
+	Bans            int64    `json:"bans"`
+	ImportJobs      int64    `json:"import_jobs"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidStatusTransition возвращается, если переход между статусами не разрешен.
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+	// ErrStatusTransitionForbidden возвращается, если переход недоступен роли администратора.
+	ErrStatusTransitionForbidden = errors.New("status transition is not allowed for admin role")
+	// ErrStatusTransitionGuard возвращается, если пользователь не прошел проверку перехода.
+	ErrStatusTransitionGuard = errors.New("status transition guard failed")
+	// ErrStatusRequiresBan возвращается, если статус banned устанавливается не через блокировку.
+	ErrStatusRequiresBan = errors.New("status banned can only be set by ban")
+)
+
+// StatusTransitionError описывает отклоненный переход статуса и статусы,
+// в которые пользователя можно перевести из текущего.
+type StatusTransitionError struct {
+	From    string
+	To      string
+	Allowed []string
+	Guard   string
+	Err     error
+}
+
+// Error возвращает описание ошибки.
+func (e *StatusTransitionError) Error() string {
+	if e.Guard != "" {
+		return fmt.Sprintf("%s: %s -> %s (%s)", e.Err, e.From, e.To, e.Guard)
+	}
+
+	return fmt.Sprintf("%s: %s -> %s", e.Err, e.From, e.To)
+}
+
+// Unwrap возвращает причину: ErrInvalidStatusTransition, ErrStatusTransitionForbidden,
+// ErrStatusTransitionGuard или ErrStatusRequiresBan.
+func (e *StatusTransitionError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"strconv"
+	"strings"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+// minPhoneSearchDigits — минимальное количество цифр в поисковой строке,
+// при котором она также ищется по телефону.
+const minPhoneSearchDigits = 4
+
+// normalizePhone разбирает телефон в любом формате и возвращает его в E.164.
+// Номера без кода страны разбираются в регионе по умолчанию.
+func (uc *UseCase) normalizePhone(phone string) (string, error) {
+	number, err := phonenumbers.Parse(phone, uc.cfg.Users.PhoneDefaultRegion)
+	if err != nil || !phonenumbers.IsValidNumber(number) {
+		return "", usecasemodels.ErrorInvalidParameterPhone
+	}
+
+	return phonenumbers.Format(number, phonenumbers.E164), nil
+}
+
+// formatPhoneDisplay возвращает телефон в международном формате для отображения.
+// Телефоны, сохраненные до введения нормализации и не разбирающиеся, возвращаются как есть.
+func (uc *UseCase) formatPhoneDisplay(phone *string) *string {
+	if phone == nil {
+		return nil
+	}
+
+	number, err := phonenumbers.Parse(*phone, uc.cfg.Users.PhoneDefaultRegion)
+	if err != nil {
+		return phone
+	}
+
+	display := phonenumbers.Format(number, phonenumbers.INTERNATIONAL)
+	return &display
+}
+
+// preparePhoneSearch заполняет PhoneDigits фильтра по строке поиска, чтобы телефон
+// находился при любом формате ввода. Полный номер приводится к E.164,
+// из частичного берутся только цифры.
+func (uc *UseCase) preparePhoneSearch(filter *usecasemodels.UserFilter) {
+	filter.PhoneDigits = ""
+	if filter.Search == "" {
+		return
+	}
+
+	if phone, err := uc.normalizePhone(filter.Search); err == nil {
+		filter.PhoneDigits = strings.TrimPrefix(phone, "+")
+
+		return
+	}
+
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+
+		return -1
+	}, filter.Search)
+
+	if len(digits) < minPhoneSearchDigits {
+		return
+	}
+
+	// Частичный номер в национальном формате (например, 8 912 ...) приводим
+	// к международному, как он хранится в E.164.
+	if !strings.HasPrefix(strings.TrimSpace(filter.Search), "+") {
+		region := uc.cfg.Users.PhoneDefaultRegion
+		prefix := phonenumbers.GetNddPrefixForRegion(region, true)
+		if prefix != "" && strings.HasPrefix(digits, prefix) {
+			digits = strconv.Itoa(phonenumbers.GetCountryCodeForRegion(region)) + strings.TrimPrefix(digits, prefix)
+		}
+	}
+
+	filter.PhoneDigits = digits
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/config"
+)
+
+// newPhoneTestUseCase создает UseCase с регионом телефонов по умолчанию RU.
+func newPhoneTestUseCase() *UseCase {
+	cfg := &config.Config{}
+	cfg.Users.PhoneDefaultRegion = "RU"
+
+	return &UseCase{cfg: cfg}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	uc := newPhoneTestUseCase()
+
+	tests := []struct {
+		phone   string
+		want    string
+		wantErr error
+	}{
+		{phone: "+79123456789", want: "+79123456789"},
+		{phone: "+7 (912) 345-67-89", want: "+79123456789"},
+		{phone: "8 912 345 67 89", want: "+79123456789"},
+		{phone: "9123456789", want: "+79123456789"},
+		{phone: "+375 29 123-45-67", want: "+375291234567"},
+		{phone: "", wantErr: usecasemodels.ErrorInvalidParameterPhone},
+		{phone: "phone", wantErr: usecasemodels.ErrorInvalidParameterPhone},
+		{phone: "+7 912", wantErr: usecasemodels.ErrorInvalidParameterPhone},
+		{phone: "8 912 345 67 89 00", wantErr: usecasemodels.ErrorInvalidParameterPhone},
+		{phone: "+7 000 000-00-00", wantErr: usecasemodels.ErrorInvalidParameterPhone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			got, err := uc.normalizePhone(tt.phone)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalizePhone(%q) error = %v, want %v", tt.phone, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("normalizePhone(%q) = %q, want %q", tt.phone, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreparePhoneSearch(t *testing.T) {
+	uc := newPhoneTestUseCase()
+
+	tests := []struct {
+		name   string
+		search string
+		want   string
+	}{
+		{name: "empty", search: "", want: ""},
+		{name: "full e164", search: "+79123456789", want: "79123456789"},
+		{name: "full national", search: "8 (912) 345-67-89", want: "79123456789"},
+		{name: "partial national", search: "8 912", want: "7912"},
+		{name: "partial national with spaces", search: " 8-912-34", want: "791234"},
+		{name: "partial international", search: "+7 912", want: "7912"},
+		{name: "partial without prefix", search: "345-67", want: "34567"},
+		{name: "too few digits", search: "8 91", want: ""},
+		{name: "name", search: "Ivan", want: ""},
+		{name: "email with digits", search: "ivan2024@example.com", want: "2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := &usecasemodels.UserFilter{Search: tt.search, PhoneDigits: "stale"}
+			uc.preparePhoneSearch(filter)
+
+			if filter.PhoneDigits != tt.want {
+				t.Fatalf("preparePhoneSearch(%q) PhoneDigits = %q, want %q", tt.search, filter.PhoneDigits, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatPhoneDisplay(t *testing.T) {
+	uc := newPhoneTestUseCase()
+	e164, national, legacy := "+79123456789", "89123456789", "call me"
+	display := "+7 912 345-67-89"
+
+	tests := []struct {
+		name  string
+		phone *string
+		want  *string
+	}{
+		{name: "nil", phone: nil, want: nil},
+		{name: "e164", phone: &e164, want: &display},
+		{name: "national", phone: &national, want: &display},
+		{name: "unparsable legacy value", phone: &legacy, want: &legacy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := uc.formatPhoneDisplay(tt.phone)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Fatalf("formatPhoneDisplay() = %v, want %v", stringValue(got), stringValue(tt.want))
+			}
+		})
+	}
+}
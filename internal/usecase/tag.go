@@ -0,0 +1,420 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/reqctx"
+
+	"github.com/google/uuid"
+)
+
+// tagColorPattern — допустимый цвет метки: #RRGGBB.
+var tagColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// tagNameMaxLength — максимальная длина названия метки.
+const tagNameMaxLength = 64
+
+// userTagChange описывает метки, назначенные пользователю или снятые с него.
+type userTagChange struct {
+	userID string
+	tagIDs []string
+}
+
+// GetTags получает каталог меток с количеством пользователей у каждой.
+func (uc *UseCase) GetTags(ctx context.Context) ([]usecasemodels.TagResponse, error) {
+	tags, err := uc.tagRepo.GetTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get tags: %w", err)
+	}
+
+	data := make([]usecasemodels.TagResponse, 0, len(tags))
+	for i := range tags {
+		data = append(data, tagToResponse(&tags[i]))
+	}
+
+	return data, nil
+}
+
+// CreateTag создает метку. Название уникально без учета регистра.
+func (uc *UseCase) CreateTag(ctx context.Context, req *usecasemodels.TagRequest) (*usecasemodels.TagResponse, error) {
+	if err := validateTagRequest(req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tag := &repositorymodels.Tag{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Color:       req.Color,
+		Description: req.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := uc.tagRepo.CreateTag(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	response := tagToResponse(tag)
+	return &response, nil
+}
+
+// UpdateTag изменяет название, цвет и описание метки.
+func (uc *UseCase) UpdateTag(ctx context.Context, id string, req *usecasemodels.TagRequest) (*usecasemodels.TagResponse, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, usecasemodels.ErrTagNotFound
+	}
+
+	if err := validateTagRequest(req); err != nil {
+		return nil, err
+	}
+
+	tag, err := uc.tagRepo.GetTagByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get tag by id: %w", err)
+	}
+
+	if tag == nil {
+		return nil, usecasemodels.ErrTagNotFound
+	}
+
+	tag.Name = req.Name
+	tag.Color = req.Color
+	tag.Description = req.Description
+	tag.UpdatedAt = time.Now()
+
+	if err := uc.tagRepo.UpdateTag(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	response := tagToResponse(tag)
+	return &response, nil
+}
+
+// DeleteTag удаляет метку вместе с ее назначениями пользователям.
+func (uc *UseCase) DeleteTag(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return usecasemodels.ErrTagNotFound
+	}
+
+	return uc.tagRepo.DeleteTag(ctx, id)
+}
+
+// AddUserTags назначает пользователю метки и возвращает все его метки.
+// Уже назначенные метки пропускаются; в журнал аудита попадают только новые.
+func (uc *UseCase) AddUserTags(ctx context.Context, id string, req *usecasemodels.UserTagsRequest) (resp []usecasemodels.UserTagResponse, err error) {
+	tagIDs, err := normalizeTagIDs(req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var added []string
+	defer func() {
+		if err == nil && len(added) == 0 {
+			return
+		}
+
+		entry := &usecasemodels.AuditEntry{
+			Action:       "user.tags_add",
+			ResourceType: "user",
+			ResourceID:   id,
+			Details:      map[string]string{"tag_ids": strings.Join(tagIDs, ",")},
+		}
+		if err == nil {
+			entry.Details["tag_ids"] = strings.Join(added, ",")
+		}
+		uc.auditResult(ctx, entry, err)
+	}()
+
+	err = uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkUserExists(ctx, id); err != nil {
+			return err
+		}
+
+		if err := uc.checkTagsExist(ctx, tagIDs); err != nil {
+			return err
+		}
+
+		added, err = uc.tagRepo.AddUserTags(ctx, id, tagIDs, optionalString(reqctx.From(ctx).AdminID))
+		if err != nil {
+			return fmt.Errorf("add user tags: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.getUserTags(ctx, id)
+}
+
+// RemoveUserTag снимает метку с пользователя и возвращает оставшиеся метки.
+// Снятие метки, которой у пользователя нет, не считается ошибкой.
+func (uc *UseCase) RemoveUserTag(ctx context.Context, id, tagID string) (resp []usecasemodels.UserTagResponse, err error) {
+	if _, err := uuid.Parse(tagID); err != nil {
+		return nil, usecasemodels.ErrTagNotFound
+	}
+
+	var removed []string
+	defer func() {
+		if err == nil && len(removed) == 0 {
+			return
+		}
+
+		uc.auditResult(ctx, &usecasemodels.AuditEntry{
+			Action:       "user.tags_remove",
+			ResourceType: "user",
+			ResourceID:   id,
+			Details:      map[string]string{"tag_ids": tagID},
+		}, err)
+	}()
+
+	err = uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkUserExists(ctx, id); err != nil {
+			return err
+		}
+
+		if err := uc.checkTagsExist(ctx, []string{tagID}); err != nil {
+			return err
+		}
+
+		removed, err = uc.tagRepo.RemoveUserTags(ctx, id, []string{tagID})
+		if err != nil {
+			return fmt.Errorf("remove user tags: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.getUserTags(ctx, id)
+}
+
+// checkUserExists проверяет, что пользователь существует и не удален.
+func (uc *UseCase) checkUserExists(ctx context.Context, id string) error {
+	user, err := uc.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get user by id: %w", err)
+	}
+
+	if user == nil {
+		return usecasemodels.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// checkTagsExist проверяет, что все метки есть в каталоге.
+func (uc *UseCase) checkTagsExist(ctx context.Context, tagIDs []string) error {
+	tags, err := uc.tagRepo.GetTagsByIDs(ctx, tagIDs)
+	if err != nil {
+		return fmt.Errorf("get tags by ids: %w", err)
+	}
+
+	if len(tags) != len(tagIDs) {
+		return usecasemodels.ErrTagNotFound
+	}
+
+	return nil
+}
+
+// getUserTags получает метки одного пользователя.
+func (uc *UseCase) getUserTags(ctx context.Context, id string) ([]usecasemodels.UserTagResponse, error) {
+	tags, err := uc.tagRepo.GetUsersTags(ctx, []string{id})
+	if err != nil {
+		return nil, fmt.Errorf("get users tags: %w", err)
+	}
+
+	return userTagsToResponse(tags[id]), nil
+}
+
+// fillUserTags загружает метки пользователей одним запросом и заполняет Tags в ответах.
+func (uc *UseCase) fillUserTags(ctx context.Context, users []usecasemodels.UserResponse) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(users))
+	for i := range users {
+		ids = append(ids, users[i].ID)
+	}
+
+	tags, err := uc.tagRepo.GetUsersTags(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("get users tags: %w", err)
+	}
+
+	for i := range users {
+		users[i].Tags = userTagsToResponse(tags[users[i].ID])
+	}
+
+	return nil
+}
+
+// applyBulkUserTags назначает пользователю метки из запроса или снимает их
+// и возвращает статус элемента и ID фактически измененных меток.
+func (uc *UseCase) applyBulkUserTags(ctx context.Context, id string, req *usecasemodels.BulkUsersRequest) (string, []string, error) {
+	user, err := uc.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return "", nil, fmt.Errorf("get user by id: %w", err)
+	}
+
+	if user == nil {
+		return usecasemodels.BulkItemStatusNotFound, nil, nil
+	}
+
+	var changed []string
+	if req.Operation == usecasemodels.BulkOperationAddTags {
+		changed, err = uc.tagRepo.AddUserTags(ctx, id, req.TagIDs, optionalString(reqctx.From(ctx).AdminID))
+		if err != nil {
+			return "", nil, fmt.Errorf("add user tags: %w", err)
+		}
+	} else {
+		changed, err = uc.tagRepo.RemoveUserTags(ctx, id, req.TagIDs)
+		if err != nil {
+			return "", nil, fmt.Errorf("remove user tags: %w", err)
+		}
+	}
+
+	if len(changed) == 0 {
+		return usecasemodels.BulkItemStatusSkipped, nil, nil
+	}
+
+	return usecasemodels.BulkItemStatusOK, changed, nil
+}
+
+// auditUserTagChanges записывает в журнал аудита изменения меток после массовой операции.
+func (uc *UseCase) auditUserTagChanges(ctx context.Context, operation string, changes []userTagChange) {
+	action := "user.tags_add"
+	if operation == usecasemodels.BulkOperationRemoveTags {
+		action = "user.tags_remove"
+	}
+
+	for _, change := range changes {
+		uc.audit(ctx, &usecasemodels.AuditEntry{
+			Action:       action,
+			ResourceType: "user",
+			ResourceID:   change.userID,
+			Outcome:      usecasemodels.AuditOutcomeSuccess,
+			Details:      map[string]string{"tag_ids": strings.Join(change.tagIDs, ","), "bulk": "true"},
+		})
+	}
+}
+
+// prepareTagFilter проверяет ID меток и режим фильтра по меткам, убирая повторы.
+func prepareTagFilter(filter *usecasemodels.UserFilter) error {
+	if filter.TagsMode == "" {
+		filter.TagsMode = usecasemodels.TagsModeAny
+	}
+
+	if filter.TagsMode != usecasemodels.TagsModeAny && filter.TagsMode != usecasemodels.TagsModeAll {
+		return usecasemodels.ErrorInvalidParameterTagsMode
+	}
+
+	if len(filter.Tags) == 0 {
+		return nil
+	}
+
+	tags, err := normalizeTagIDs(filter.Tags)
+	if err != nil {
+		return err
+	}
+	filter.Tags = tags
+
+	return nil
+}
+
+// normalizeTagIDs проверяет, что список меток не пуст и состоит из UUID, и убирает повторы.
+func normalizeTagIDs(tagIDs []string) ([]string, error) {
+	if len(tagIDs) == 0 {
+		return nil, usecasemodels.ErrorInvalidParameterTags
+	}
+
+	return normalizeUUIDs(tagIDs, usecasemodels.ErrorInvalidParameterTags)
+}
+
+// normalizeUUIDs приводит ID к каноническому виду и убирает повторы. nil остается nil.
+// Невалидный ID возвращает errInvalid.
+func normalizeUUIDs(ids []string, errInvalid error) ([]string, error) {
+	if ids == nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]struct{}, len(ids))
+	normalized := make([]string, 0, len(ids))
+	for _, raw := range ids {
+		parsed, err := uuid.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalid, raw)
+		}
+
+		id := parsed.String()
+		if _, ok := seen[id]; ok {
+			continue
+		}
+
+		seen[id] = struct{}{}
+		normalized = append(normalized, id)
+	}
+
+	return normalized, nil
+}
+
+// validateTagRequest валидирует запрос на создание или изменение метки и подставляет цвет по умолчанию.
+func validateTagRequest(req *usecasemodels.TagRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len([]rune(req.Name)) > tagNameMaxLength {
+		return usecasemodels.ErrorInvalidParameterName
+	}
+
+	if req.Color == "" {
+		req.Color = usecasemodels.TagDefaultColor
+	}
+
+	if !tagColorPattern.MatchString(req.Color) {
+		return usecasemodels.ErrorInvalidParameterColor
+	}
+	req.Color = strings.ToLower(req.Color)
+
+	if req.Description != nil && strings.TrimSpace(*req.Description) == "" {
+		req.Description = nil
+	}
+
+	return nil
+}
+
+// tagToResponse преобразует метку в ответ.
+func tagToResponse(tag *repositorymodels.Tag) usecasemodels.TagResponse {
+	return usecasemodels.TagResponse{
+		ID:          tag.ID,
+		Name:        tag.Name,
+		Color:       tag.Color,
+		Description: tag.Description,
+		UsersCount:  tag.UsersCount,
+		CreatedAt:   tag.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   tag.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// userTagsToResponse преобразует метки пользователя в ответ. Пустой список возвращается как [].
+func userTagsToResponse(tags []repositorymodels.Tag) []usecasemodels.UserTagResponse {
+	data := make([]usecasemodels.UserTagResponse, 0, len(tags))
+	for _, tag := range tags {
+		data = append(data, usecasemodels.UserTagResponse{
+			ID:    tag.ID,
+			Name:  tag.Name,
+			Color: tag.Color,
+		})
+	}
+
+	return data
+}
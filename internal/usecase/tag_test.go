@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+const (
+	testTagID      = "6f1c2a9e-3b4d-4e5f-8a7b-9c0d1e2f3a4b"
+	testOtherTagID = "0a1b2c3d-4e5f-4a7b-8c9d-0e1f2a3b4c5d"
+)
+
+func TestPrepareTagFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   usecasemodels.UserFilter
+		wantMode string
+		wantTags []string
+		wantErr  error
+	}{
+		{name: "default mode", filter: usecasemodels.UserFilter{}, wantMode: usecasemodels.TagsModeAny},
+		{name: "any", filter: usecasemodels.UserFilter{TagsMode: usecasemodels.TagsModeAny, Tags: []string{testTagID}}, wantMode: usecasemodels.TagsModeAny, wantTags: []string{testTagID}},
+		{name: "all", filter: usecasemodels.UserFilter{TagsMode: usecasemodels.TagsModeAll, Tags: []string{testTagID, testOtherTagID}}, wantMode: usecasemodels.TagsModeAll, wantTags: []string{testTagID, testOtherTagID}},
+		{name: "all without tags", filter: usecasemodels.UserFilter{TagsMode: usecasemodels.TagsModeAll}, wantMode: usecasemodels.TagsModeAll},
+		{name: "unknown mode", filter: usecasemodels.UserFilter{TagsMode: "none"}, wantErr: usecasemodels.ErrorInvalidParameterTagsMode},
+		{name: "mode is case sensitive", filter: usecasemodels.UserFilter{TagsMode: "ALL"}, wantErr: usecasemodels.ErrorInvalidParameterTagsMode},
+		{
+			name:     "duplicates removed",
+			filter:   usecasemodels.UserFilter{TagsMode: usecasemodels.TagsModeAll, Tags: []string{testTagID, " " + testTagID + " ", "6F1C2A9E-3B4D-4E5F-8A7B-9C0D1E2F3A4B"}},
+			wantMode: usecasemodels.TagsModeAll,
+			wantTags: []string{testTagID},
+		},
+		{name: "invalid tag", filter: usecasemodels.UserFilter{Tags: []string{testTagID, "vip"}}, wantErr: usecasemodels.ErrorInvalidParameterTags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+
+			err := prepareTagFilter(&filter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("prepareTagFilter() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if filter.TagsMode != tt.wantMode {
+				t.Fatalf("prepareTagFilter() mode = %q, want %q", filter.TagsMode, tt.wantMode)
+			}
+
+			if !reflect.DeepEqual(filter.Tags, tt.wantTags) {
+				t.Fatalf("prepareTagFilter() tags = %v, want %v", filter.Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestNormalizeTagIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		tagIDs  []string
+		want    []string
+		wantErr error
+	}{
+		{name: "nil", tagIDs: nil, wantErr: usecasemodels.ErrorInvalidParameterTags},
+		{name: "empty", tagIDs: []string{}, wantErr: usecasemodels.ErrorInvalidParameterTags},
+		{name: "order kept without duplicates", tagIDs: []string{testOtherTagID, testTagID, testOtherTagID}, want: []string{testOtherTagID, testTagID}},
+		{name: "empty id", tagIDs: []string{""}, wantErr: usecasemodels.ErrorInvalidParameterTags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTagIDs(tt.tagIDs)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalizeTagIDs() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("normalizeTagIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTagRequest(t *testing.T) {
+	description := "  "
+
+	tests := []struct {
+		name      string
+		req       usecasemodels.TagRequest
+		wantColor string
+		wantErr   error
+	}{
+		{name: "default color", req: usecasemodels.TagRequest{Name: " VIP "}, wantColor: usecasemodels.TagDefaultColor},
+		{name: "color lowered", req: usecasemodels.TagRequest{Name: "VIP", Color: "#FFAA00"}, wantColor: "#ffaa00"},
+		{name: "blank description", req: usecasemodels.TagRequest{Name: "VIP", Description: &description}, wantColor: usecasemodels.TagDefaultColor},
+		{name: "empty name", req: usecasemodels.TagRequest{Name: "  "}, wantErr: usecasemodels.ErrorInvalidParameterName},
+		{name: "long name", req: usecasemodels.TagRequest{Name: strings.Repeat("я", tagNameMaxLength+1)}, wantErr: usecasemodels.ErrorInvalidParameterName},
+		{name: "short color", req: usecasemodels.TagRequest{Name: "VIP", Color: "#fff"}, wantErr: usecasemodels.ErrorInvalidParameterColor},
+		{name: "named color", req: usecasemodels.TagRequest{Name: "VIP", Color: "red"}, wantErr: usecasemodels.ErrorInvalidParameterColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+
+			err := validateTagRequest(&req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateTagRequest() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if req.Name != "VIP" || req.Color != tt.wantColor || req.Description != nil {
+				t.Fatalf("validateTagRequest() = %+v, want name VIP, color %q and no description", req, tt.wantColor)
+			}
+		})
+	}
+}
@@ -2,29 +2,85 @@ package usecase
 
 import (
 	"adminkaback/internal"
+	usecasemodels "adminkaback/internal/usecase/models"
 	"adminkaback/pkg/config"
 	"adminkaback/pkg/jwt"
+	"adminkaback/pkg/signedtoken"
+	"adminkaback/pkg/ttlcache"
 )
 
 // UseCase содержит все use cases приложения.
 type UseCase struct {
-	authRepo internal.AuthRepository
-	userRepo internal.UserRepository
-	jwtMgr   *jwt.Manager
-	cfg      *config.Config
+	txManager     internal.TxManager
+	authRepo      internal.AuthRepository
+	userRepo      internal.UserRepository
+	importRepo    internal.UserImportRepository
+	historyRepo   internal.UserHistoryRepository
+	auditRepo     internal.AuditRepository
+	verifyRepo    internal.EmailVerificationRepository
+	banRepo       internal.UserBanRepository
+	attrRepo      internal.UserAttributeRepository
+	tagRepo       internal.TagRepository
+	noteRepo      internal.UserNoteRepository
+	bloggerRepo   internal.BloggerRepository
+	campaignRepo  internal.CampaignRepository
+	dashboardRepo internal.DashboardRepository
+	analyticsRepo internal.AnalyticsRepository
+	mailer        internal.Mailer
+	webhook       internal.WebhookSender
+	jwtMgr        *jwt.Manager
+	signer        *signedtoken.Signer
+	cfg           *config.Config
+
+	// cohortCache хранит рассчитанные матрицы удержания по параметрам запроса.
+	cohortCache *ttlcache.Cache[*usecasemodels.UserCohortsResponse]
 }
 
 // NewUseCase создает новый экземпляр UseCase.
 func NewUseCase(
+	txManager internal.TxManager,
 	authRepo internal.AuthRepository,
 	userRepo internal.UserRepository,
+	importRepo internal.UserImportRepository,
+	historyRepo internal.UserHistoryRepository,
+	auditRepo internal.AuditRepository,
+	verifyRepo internal.EmailVerificationRepository,
+	banRepo internal.UserBanRepository,
+	attrRepo internal.UserAttributeRepository,
+	tagRepo internal.TagRepository,
+	noteRepo internal.UserNoteRepository,
+	bloggerRepo internal.BloggerRepository,
+	campaignRepo internal.CampaignRepository,
+	dashboardRepo internal.DashboardRepository,
+	analyticsRepo internal.AnalyticsRepository,
+	mailer internal.Mailer,
+	webhook internal.WebhookSender,
 	jwtMgr *jwt.Manager,
+	signer *signedtoken.Signer,
 	cfg *config.Config,
 ) *UseCase {
 	return &UseCase{
-		authRepo: authRepo,
-		userRepo: userRepo,
-		jwtMgr:   jwtMgr,
-		cfg:      cfg,
+		txManager:     txManager,
+		authRepo:      authRepo,
+		userRepo:      userRepo,
+		importRepo:    importRepo,
+		historyRepo:   historyRepo,
+		auditRepo:     auditRepo,
+		verifyRepo:    verifyRepo,
+		banRepo:       banRepo,
+		attrRepo:      attrRepo,
+		tagRepo:       tagRepo,
+		noteRepo:      noteRepo,
+		bloggerRepo:   bloggerRepo,
+		campaignRepo:  campaignRepo,
+		dashboardRepo: dashboardRepo,
+		analyticsRepo: analyticsRepo,
+		mailer:        mailer,
+		webhook:       webhook,
+		jwtMgr:        jwtMgr,
+		signer:        signer,
+		cfg:           cfg,
+
+		cohortCache: ttlcache.New[*usecasemodels.UserCohortsResponse](cfg.Analytics.CohortCacheTTL, cohortCacheMaxEntries),
 	}
 }
@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"math"
-	"strings"
 	"time"
 
 	repositorymodels "adminkaback/internal/repository/models"
 	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/pii"
 
 	"github.com/google/uuid"
 )
@@ -25,6 +25,16 @@ func (uc *UseCase) GetUsers(ctx context.Context, req *usecasemodels.GetUsersRequ
 		req.Limit = 100
 	}
 
+	uc.preparePhoneSearch(&req.UserFilter)
+
+	if err := prepareTagFilter(&req.UserFilter); err != nil {
+		return nil, err
+	}
+
+	if err := uc.prepareUserAttributeQuery(ctx, &req.UserFilter, &req.Sort); err != nil {
+		return nil, err
+	}
+
 	users, total, err := uc.userRepo.GetUsers(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("get users: %w", err)
@@ -32,7 +42,11 @@ func (uc *UseCase) GetUsers(ctx context.Context, req *usecasemodels.GetUsersRequ
 
 	userResponses := make([]usecasemodels.UserResponse, 0, len(users))
 	for _, user := range users {
-		userResponses = append(userResponses, uc.userToResponse(&user))
+		userResponses = append(userResponses, uc.userToResponse(ctx, &user))
+	}
+
+	if err := uc.fillUserTags(ctx, userResponses); err != nil {
+		return nil, err
 	}
 
 	totalPages := int(math.Ceil(float64(total) / float64(req.Limit)))
@@ -57,7 +71,14 @@ func (uc *UseCase) GetUser(ctx context.Context, id string) (*usecasemodels.UserR
 		return nil, usecasemodels.ErrUserNotFound
 	}
 
-	response := uc.userToResponse(user)
+	response := uc.userToResponse(ctx, user)
+
+	tags, err := uc.getUserTags(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	response.Tags = tags
+
 	return &response, nil
 }
 
@@ -67,13 +88,14 @@ func (uc *UseCase) CreateUser(ctx context.Context, req *usecasemodels.CreateUser
 		return nil, err
 	}
 
-	existingUser, err := uc.userRepo.GetUserByEmail(ctx, req.Email)
+	definitions, err := uc.userAttributeDefinitions(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("get user by email: %w", err)
+		return nil, err
 	}
 
-	if existingUser != nil {
-		return nil, usecasemodels.ErrUserAlreadyExists
+	attributes, err := mergeUserAttributes(definitions, nil, req.Attributes, true)
+	if err != nil {
+		return nil, err
 	}
 
 	now := time.Now()
@@ -85,113 +107,216 @@ func (uc *UseCase) CreateUser(ctx context.Context, req *usecasemodels.CreateUser
 		Role:            req.Role,
 		Status:          req.Status,
 		IsEmailVerified: false,
+		Attributes:      attributes,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
 
-	if err := uc.userRepo.CreateUser(ctx, user); err != nil {
-		return nil, fmt.Errorf("create user: %w", err)
+	err = uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		existingUser, err := uc.userRepo.GetUserByEmail(ctx, req.Email)
+		if err != nil {
+			return fmt.Errorf("get user by email: %w", err)
+		}
+
+		if existingUser != nil {
+			return usecasemodels.ErrUserAlreadyExists
+		}
+
+		if err := uc.userRepo.CreateUser(ctx, user); err != nil {
+			return fmt.Errorf("create user: %w", err)
+		}
+
+		return uc.recordUserHistory(ctx, usecasemodels.UserHistoryActionCreate, user.ID, nil, user)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	response := uc.userToResponse(user)
+	response := uc.userToResponse(ctx, user)
 	return &response, nil
 }
 
 // UpdateUser обновляет данные пользователя.
 func (uc *UseCase) UpdateUser(ctx context.Context, id string, req *usecasemodels.UpdateUserRequest) (*usecasemodels.UserResponse, error) {
-	user, err := uc.userRepo.GetUserByID(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("get user by id: %w", err)
-	}
+	return uc.updateUser(ctx, id, req, usecasemodels.UserHistoryActionUpdate)
+}
 
-	if user == nil {
-		return nil, usecasemodels.ErrUserNotFound
-	}
+// updateUser обновляет данные пользователя и записывает версию в историю с указанным действием.
+func (uc *UseCase) updateUser(ctx context.Context, id string, req *usecasemodels.UpdateUserRequest, action string) (*usecasemodels.UserResponse, error) {
+	var updatedUser *repositorymodels.User
+	var pendingEmail *string
+	var statusChange *userStatusChange
 
-	if err := uc.validateUpdateUserRequest(req); err != nil {
-		return nil, err
-	}
+	err := uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		user, err := uc.userRepo.GetUserByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get user by id: %w", err)
+		}
 
-	updateUser := &repositorymodels.User{
-		Name:   user.Name,
-		Phone:  user.Phone,
-		Role:   user.Role,
-		Status: user.Status,
-	}
+		if user == nil {
+			return usecasemodels.ErrUserNotFound
+		}
 
-	if req.Name != nil {
-		updateUser.Name = *req.Name
-	}
-	if req.Phone != nil {
-		updateUser.Phone = req.Phone
-	}
-	if req.Role != nil {
-		updateUser.Role = *req.Role
-	}
-	if req.Status != nil {
-		updateUser.Status = *req.Status
-	}
+		if err := uc.validateUpdateUserRequest(req); err != nil {
+			return err
+		}
 
-	if err := uc.userRepo.UpdateUser(ctx, id, updateUser); err != nil {
-		return nil, fmt.Errorf("update user: %w", err)
-	}
+		// Телефон передается только при изменении: без ключа шифрования в user.Phone маска,
+		// которая не должна перезаписать зашифрованный номер.
+		updateUser := &repositorymodels.User{
+			Name:   user.Name,
+			Role:   user.Role,
+			Status: user.Status,
+		}
+
+		if req.Name != nil {
+			updateUser.Name = *req.Name
+		}
+		if req.Phone != nil {
+			updateUser.Phone = req.Phone
+		}
+		if req.Role != nil {
+			updateUser.Role = *req.Role
+		}
+		if req.Attributes != nil {
+			definitions, err := uc.userAttributeDefinitions(ctx)
+			if err != nil {
+				return err
+			}
 
-	updatedUser, err := uc.userRepo.GetUserByID(ctx, id)
+			updateUser.Attributes, err = mergeUserAttributes(definitions, user.Attributes, req.Attributes, false)
+			if err != nil {
+				return err
+			}
+		}
+		if req.Status != nil {
+			statusChange, err = uc.checkStatusTransition(ctx, user, *req.Status)
+			if err != nil {
+				return err
+			}
+			updateUser.Status = *req.Status
+		}
+		if req.Email != nil && *req.Email != user.Email {
+			existingUser, err := uc.userRepo.GetUserByEmail(ctx, *req.Email)
+			if err != nil {
+				return fmt.Errorf("get user by email: %w", err)
+			}
+
+			if existingUser != nil && existingUser.ID != user.ID {
+				return usecasemodels.ErrUserAlreadyExists
+			}
+
+			if req.ConfirmEmail {
+				if err := uc.requestEmailChange(ctx, user, *req.Email); err != nil {
+					return err
+				}
+				pendingEmail = req.Email
+			} else {
+				updateUser.Email = *req.Email
+			}
+		}
+
+		if user.Status == "banned" && updateUser.Status != "banned" {
+			if err := uc.liftActiveUserBan(ctx, id, "status changed to "+updateUser.Status); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.userRepo.UpdateUser(ctx, id, updateUser); err != nil {
+			return fmt.Errorf("update user: %w", err)
+		}
+
+		updatedUser, err = uc.userRepo.GetUserByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get updated user: %w", err)
+		}
+
+		return uc.recordUserHistory(ctx, action, id, user, updatedUser)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("get updated user: %w", err)
+		return nil, err
 	}
 
-	response := uc.userToResponse(updatedUser)
+	uc.runStatusHooks(ctx, []*userStatusChange{statusChange})
+
+	response := uc.userToResponse(ctx, updatedUser)
+	if pendingEmail != nil && response.PIIMasked {
+		masked := pii.MaskEmail(*pendingEmail)
+		pendingEmail = &masked
+	}
+	response.PendingEmail = pendingEmail
 	return &response, nil
 }
 
 // DeleteUser удаляет пользователя (soft delete).
 func (uc *UseCase) DeleteUser(ctx context.Context, id string) error {
-	user, err := uc.userRepo.GetUserByID(ctx, id)
-	if err != nil {
-		return fmt.Errorf("get user by id: %w", err)
-	}
+	return uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		user, err := uc.userRepo.GetUserByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get user by id: %w", err)
+		}
 
-	if user == nil {
-		return usecasemodels.ErrUserNotFound
-	}
+		if user == nil {
+			return usecasemodels.ErrUserNotFound
+		}
 
-	if err := uc.userRepo.DeleteUser(ctx, id); err != nil {
-		return fmt.Errorf("delete user: %w", err)
-	}
+		if err := uc.userRepo.DeleteUser(ctx, id); err != nil {
+			return fmt.Errorf("delete user: %w", err)
+		}
 
-	return nil
+		deletedUser := *user
+		deletedAt := time.Now()
+		deletedUser.DeletedAt = &deletedAt
+
+		return uc.recordUserHistory(ctx, usecasemodels.UserHistoryActionDelete, id, user, &deletedUser)
+	})
 }
 
-// userToResponse преобразует модель пользователя в ответ.
-func (uc *UseCase) userToResponse(user *repositorymodels.User) usecasemodels.UserResponse {
-	return usecasemodels.UserResponse{
+// userToResponse преобразует модель пользователя в ответ. Email и телефон маскируются,
+// если роли администратора из контекста не разрешено видеть их полностью.
+func (uc *UseCase) userToResponse(ctx context.Context, user *repositorymodels.User) usecasemodels.UserResponse {
+	response := usecasemodels.UserResponse{
 		ID:              user.ID,
 		Email:           user.Email,
 		Name:            user.Name,
 		Phone:           user.Phone,
+		PhoneDisplay:    uc.formatPhoneDisplay(user.Phone),
 		Role:            user.Role,
 		Status:          user.Status,
 		IsEmailVerified: user.IsEmailVerified,
+		Attributes:      user.Attributes,
 		CreatedAt:       user.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:       user.UpdatedAt.Format(time.RFC3339),
 	}
-}
 
-// validateCreateUserRequest валидирует запрос на создание пользователя.
-func (uc *UseCase) validateCreateUserRequest(req *usecasemodels.CreateUserRequest) error {
-	if req.Email == "" {
-		return usecasemodels.ErrorInvalidParameterEmail
+	if uc.maskPII(ctx) {
+		maskUserResponse(&response)
 	}
 
-	if !strings.Contains(req.Email, "@") {
-		return usecasemodels.ErrorInvalidParameterEmail
+	return response
+}
+
+// validateCreateUserRequest валидирует запрос на создание пользователя, нормализует email и телефон
+// и подставляет начальный статус, если он не задан.
+func (uc *UseCase) validateCreateUserRequest(req *usecasemodels.CreateUserRequest) error {
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return err
 	}
+	req.Email = email
 
 	if req.Name == "" {
 		return usecasemodels.ErrorInvalidParameterName
 	}
 
+	if req.Phone != nil && *req.Phone != "" {
+		phone, err := uc.normalizePhone(*req.Phone)
+		if err != nil {
+			return err
+		}
+		req.Phone = &phone
+	}
+
 	if req.Role != "" {
 		validRoles := []string{"user", "admin", "moderator"}
 		valid := false
@@ -206,29 +331,45 @@ func (uc *UseCase) validateCreateUserRequest(req *usecasemodels.CreateUserReques
 		}
 	}
 
-	if req.Status != "" {
-		validStatuses := []string{"active", "inactive", "banned"}
-		valid := false
-		for _, status := range validStatuses {
-			if req.Status == status {
-				valid = true
-				break
-			}
-		}
-		if !valid {
-			return usecasemodels.ErrorInvalidParameterStatus
-		}
+	if req.Status == "" {
+		req.Status = uc.cfg.Users.StatusMachine.Initial
+	}
+
+	if !uc.isKnownStatus(req.Status) {
+		return usecasemodels.ErrorInvalidParameterStatus
+	}
+
+	// Заблокированный пользователь создается только через блокировку, чтобы у бана была запись с причиной.
+	if req.Status == "banned" {
+		return usecasemodels.ErrStatusRequiresBan
 	}
 
 	return nil
 }
 
-// validateUpdateUserRequest валидирует запрос на обновление пользователя.
+// validateUpdateUserRequest валидирует запрос на обновление пользователя и нормализует email и телефон.
 func (uc *UseCase) validateUpdateUserRequest(req *usecasemodels.UpdateUserRequest) error {
+	if req.Email != nil {
+		email, err := normalizeEmail(*req.Email)
+		if err != nil {
+			return err
+		}
+		req.Email = &email
+	}
+
 	if req.Name != nil && *req.Name == "" {
 		return usecasemodels.ErrorInvalidParameterName
 	}
 
+	// Пустая строка очищает телефон и не нормализуется.
+	if req.Phone != nil && *req.Phone != "" {
+		phone, err := uc.normalizePhone(*req.Phone)
+		if err != nil {
+			return err
+		}
+		req.Phone = &phone
+	}
+
 	if req.Role != nil {
 		validRoles := []string{"user", "admin", "moderator"}
 		valid := false
@@ -243,18 +384,8 @@ func (uc *UseCase) validateUpdateUserRequest(req *usecasemodels.UpdateUserReques
 		}
 	}
 
-	if req.Status != nil {
-		validStatuses := []string{"active", "inactive", "banned"}
-		valid := false
-		for _, status := range validStatuses {
-			if *req.Status == status {
-				valid = true
-				break
-			}
-		}
-		if !valid {
-			return usecasemodels.ErrorInvalidParameterStatus
-		}
+	if req.Status != nil && !uc.isKnownStatus(*req.Status) {
+		return usecasemodels.ErrorInvalidParameterStatus
 	}
 
 	return nil
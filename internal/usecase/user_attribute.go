@@ -0,0 +1,446 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+// userAttributeKeyPattern — допустимый ключ атрибута: латиница в нижнем регистре, цифры и подчеркивание.
+var userAttributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// userAttributeDateLayout — формат значений атрибутов типа date.
+const userAttributeDateLayout = "2006-01-02"
+
+// userAttributeTypes — поддерживаемые типы атрибутов.
+var userAttributeTypes = []string{
+	usecasemodels.UserAttributeTypeString,
+	usecasemodels.UserAttributeTypeNumber,
+	usecasemodels.UserAttributeTypeInteger,
+	usecasemodels.UserAttributeTypeBoolean,
+	usecasemodels.UserAttributeTypeDate,
+}
+
+// GetUserAttributes получает описания всех пользовательских атрибутов.
+func (uc *UseCase) GetUserAttributes(ctx context.Context) ([]usecasemodels.UserAttributeResponse, error) {
+	definitions, err := uc.attrRepo.GetUserAttributeDefinitions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get user attribute definitions: %w", err)
+	}
+
+	data := make([]usecasemodels.UserAttributeResponse, 0, len(definitions))
+	for i := range definitions {
+		data = append(data, userAttributeToResponse(&definitions[i]))
+	}
+
+	return data, nil
+}
+
+// CreateUserAttribute создает описание атрибута. Если задано значение по умолчанию,
+// оно записывается всем существующим пользователям.
+func (uc *UseCase) CreateUserAttribute(ctx context.Context, req *usecasemodels.UserAttributeRequest) (*usecasemodels.UserAttributeResponse, error) {
+	req.Key = strings.TrimSpace(req.Key)
+	if !userAttributeKeyPattern.MatchString(req.Key) {
+		return nil, usecasemodels.ErrorInvalidParameterKey
+	}
+
+	if !containsString(userAttributeTypes, req.Type) {
+		return nil, usecasemodels.ErrorInvalidParameterType
+	}
+
+	now := time.Now()
+	definition := &repositorymodels.UserAttributeDefinition{
+		Key:         req.Key,
+		Type:        req.Type,
+		Required:    req.Required,
+		Description: req.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := applyUserAttributeRequest(definition, req); err != nil {
+		return nil, err
+	}
+
+	err := uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := uc.attrRepo.CreateUserAttributeDefinition(ctx, definition); err != nil {
+			return err
+		}
+
+		return uc.backfillUserAttribute(ctx, definition)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := userAttributeToResponse(definition)
+	return &response, nil
+}
+
+// UpdateUserAttribute изменяет описание атрибута. Тип атрибута изменить нельзя.
+// Новое значение по умолчанию записывается пользователям, у которых атрибута нет.
+func (uc *UseCase) UpdateUserAttribute(ctx context.Context, key string, req *usecasemodels.UserAttributeRequest) (*usecasemodels.UserAttributeResponse, error) {
+	var definition *repositorymodels.UserAttributeDefinition
+
+	err := uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		definition, err = uc.attrRepo.GetUserAttributeDefinition(ctx, key)
+		if err != nil {
+			return fmt.Errorf("get user attribute definition: %w", err)
+		}
+
+		if definition == nil {
+			return usecasemodels.ErrUserAttributeNotFound
+		}
+
+		if req.Type != "" && req.Type != definition.Type {
+			return fmt.Errorf("%w: type of attribute %s cannot be changed", usecasemodels.ErrorInvalidParameterType, key)
+		}
+
+		definition.Required = req.Required
+		definition.Description = req.Description
+		definition.UpdatedAt = time.Now()
+
+		if err := applyUserAttributeRequest(definition, req); err != nil {
+			return err
+		}
+
+		if err := uc.attrRepo.UpdateUserAttributeDefinition(ctx, definition); err != nil {
+			return err
+		}
+
+		return uc.backfillUserAttribute(ctx, definition)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := userAttributeToResponse(definition)
+	return &response, nil
+}
+
+// DeleteUserAttribute удаляет описание атрибута вместе со значениями у пользователей.
+func (uc *UseCase) DeleteUserAttribute(ctx context.Context, key string) error {
+	return uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := uc.attrRepo.DeleteUserAttributeDefinition(ctx, key); err != nil {
+			return err
+		}
+
+		if _, err := uc.attrRepo.RemoveUserAttribute(ctx, key); err != nil {
+			return fmt.Errorf("remove user attribute: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// backfillUserAttribute записывает значение по умолчанию пользователям, у которых атрибута нет.
+func (uc *UseCase) backfillUserAttribute(ctx context.Context, definition *repositorymodels.UserAttributeDefinition) error {
+	if definition.Default == nil {
+		return nil
+	}
+
+	if _, err := uc.attrRepo.SetMissingUserAttribute(ctx, definition.Key, definition.Default); err != nil {
+		return fmt.Errorf("set missing user attribute: %w", err)
+	}
+
+	return nil
+}
+
+// applyUserAttributeRequest проверяет допустимые значения и значение по умолчанию из запроса
+// и переносит их в описание атрибута, приводя к типу атрибута.
+func applyUserAttributeRequest(definition *repositorymodels.UserAttributeDefinition, req *usecasemodels.UserAttributeRequest) error {
+	definition.Enum = nil
+	if len(req.Enum) > 0 {
+		enum := make([]any, 0, len(req.Enum))
+		for _, value := range req.Enum {
+			normalized, err := normalizeUserAttributeValue(definition, value)
+			if err != nil {
+				return fmt.Errorf("%w: %w", usecasemodels.ErrorInvalidParameterEnum, err)
+			}
+
+			enum = append(enum, normalized)
+		}
+		definition.Enum = enum
+	}
+
+	definition.Default = nil
+	if req.Default != nil {
+		normalized, err := normalizeUserAttributeValue(definition, req.Default)
+		if err != nil {
+			return fmt.Errorf("%w: %w", usecasemodels.ErrorInvalidParameterDefault, err)
+		}
+		definition.Default = normalized
+	}
+
+	return nil
+}
+
+// userAttributeDefinitions возвращает описания атрибутов по ключу.
+func (uc *UseCase) userAttributeDefinitions(ctx context.Context) (map[string]*repositorymodels.UserAttributeDefinition, error) {
+	definitions, err := uc.attrRepo.GetUserAttributeDefinitions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get user attribute definitions: %w", err)
+	}
+
+	byKey := make(map[string]*repositorymodels.UserAttributeDefinition, len(definitions))
+	for i := range definitions {
+		byKey[definitions[i].Key] = &definitions[i]
+	}
+
+	return byKey, nil
+}
+
+// mergeUserAttributes применяет patch к текущим атрибутам пользователя и возвращает новый набор.
+// Значение nil удаляет атрибут. При создании (create) недостающие атрибуты заполняются
+// значениями по умолчанию и проверяется наличие обязательных.
+func mergeUserAttributes(
+	definitions map[string]*repositorymodels.UserAttributeDefinition,
+	current map[string]any,
+	patch map[string]any,
+	create bool,
+) (map[string]any, error) {
+	merged := make(map[string]any, len(current)+len(patch))
+	for key, value := range current {
+		merged[key] = value
+	}
+
+	for _, key := range sortedKeys(patch) {
+		definition, ok := definitions[key]
+		if !ok {
+			return nil, &usecasemodels.UserAttributeError{Key: key, Message: "unknown attribute"}
+		}
+
+		value := patch[key]
+		if value == nil {
+			if definition.Required {
+				return nil, &usecasemodels.UserAttributeError{Key: key, Message: "attribute is required"}
+			}
+
+			delete(merged, key)
+
+			continue
+		}
+
+		normalized, err := normalizeUserAttributeValue(definition, value)
+		if err != nil {
+			return nil, &usecasemodels.UserAttributeError{Key: key, Message: err.Error()}
+		}
+		merged[key] = normalized
+	}
+
+	if create {
+		for _, key := range sortedKeys(definitions) {
+			if _, ok := merged[key]; ok {
+				continue
+			}
+
+			definition := definitions[key]
+			if definition.Default != nil {
+				merged[key] = definition.Default
+
+				continue
+			}
+
+			if definition.Required {
+				return nil, &usecasemodels.UserAttributeError{Key: key, Message: "attribute is required"}
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// normalizeUserAttributeValue приводит значение к типу атрибута и проверяет допустимые значения.
+// Строки принимаются для любого типа, поэтому значения из файлов импорта и query параметров
+// проходят ту же проверку, что и JSON.
+func normalizeUserAttributeValue(definition *repositorymodels.UserAttributeDefinition, value any) (any, error) {
+	var normalized any
+
+	switch definition.Type {
+	case usecasemodels.UserAttributeTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be a string")
+		}
+		normalized = s
+	case usecasemodels.UserAttributeTypeNumber:
+		number, err := userAttributeNumber(value)
+		if err != nil {
+			return nil, err
+		}
+		normalized = number
+	case usecasemodels.UserAttributeTypeInteger:
+		number, err := userAttributeNumber(value)
+		if err != nil || number != math.Trunc(number) {
+			return nil, errors.New("must be an integer")
+		}
+		normalized = number
+	case usecasemodels.UserAttributeTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			normalized = v
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, errors.New("must be a boolean")
+			}
+			normalized = b
+		default:
+			return nil, errors.New("must be a boolean")
+		}
+	case usecasemodels.UserAttributeTypeDate:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be a date in YYYY-MM-DD format")
+		}
+
+		date, err := time.Parse(userAttributeDateLayout, strings.TrimSpace(s))
+		if err != nil {
+			return nil, errors.New("must be a date in YYYY-MM-DD format")
+		}
+		normalized = date.Format(userAttributeDateLayout)
+	default:
+		return nil, fmt.Errorf("unsupported type %q", definition.Type)
+	}
+
+	if len(definition.Enum) > 0 && !containsValue(definition.Enum, normalized) {
+		return nil, fmt.Errorf("value %v is not allowed", normalized)
+	}
+
+	return normalized, nil
+}
+
+// userAttributeNumber приводит значение к числу.
+func userAttributeNumber(value any) (float64, error) {
+	var number float64
+
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, errors.New("must be a number")
+		}
+		number = f
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, errors.New("must be a number")
+		}
+		number = f
+	default:
+		return 0, errors.New("must be a number")
+	}
+
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, errors.New("must be a finite number")
+	}
+
+	return number, nil
+}
+
+// prepareUserAttributeQuery приводит значения фильтра по атрибутам к типам атрибутов
+// и заполняет AttributesJSON. Сортировка по неизвестному атрибуту заменяется сортировкой по умолчанию.
+func (uc *UseCase) prepareUserAttributeQuery(ctx context.Context, filter *usecasemodels.UserFilter, sort *string) error {
+	sortKey := ""
+	if sort != nil {
+		sortKey, _ = strings.CutPrefix(*sort, usecasemodels.UserAttributeSortPrefix)
+		if sortKey == *sort {
+			sortKey = ""
+		}
+	}
+
+	if len(filter.Attributes) == 0 && sortKey == "" {
+		return nil
+	}
+
+	definitions, err := uc.userAttributeDefinitions(ctx)
+	if err != nil {
+		return err
+	}
+
+	if sortKey != "" {
+		if _, ok := definitions[sortKey]; !ok {
+			*sort = ""
+		}
+	}
+
+	if len(filter.Attributes) == 0 {
+		return nil
+	}
+
+	values := make(map[string]any, len(filter.Attributes))
+	for key, value := range filter.Attributes {
+		definition, ok := definitions[key]
+		if !ok {
+			return &usecasemodels.UserAttributeError{Key: key, Message: "unknown attribute"}
+		}
+
+		normalized, err := normalizeUserAttributeValue(definition, value)
+		if err != nil {
+			return &usecasemodels.UserAttributeError{Key: key, Message: err.Error()}
+		}
+		values[key] = normalized
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("marshal attributes filter: %w", err)
+	}
+	filter.AttributesJSON = string(encoded)
+
+	return nil
+}
+
+// userAttributeToResponse преобразует описание атрибута в ответ.
+func userAttributeToResponse(definition *repositorymodels.UserAttributeDefinition) usecasemodels.UserAttributeResponse {
+	enum := definition.Enum
+	if enum == nil {
+		enum = []any{}
+	}
+
+	return usecasemodels.UserAttributeResponse{
+		Key:         definition.Key,
+		Type:        definition.Type,
+		Required:    definition.Required,
+		Enum:        enum,
+		Default:     definition.Default,
+		Description: definition.Description,
+		CreatedAt:   definition.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   definition.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// containsValue проверяет, содержится ли скалярное значение в списке.
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortedKeys возвращает ключи карты в алфавитном порядке.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+func TestNormalizeUserAttributeValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		enum    []any
+		value   any
+		want    any
+		wantErr bool
+	}{
+		{name: "string", typ: usecasemodels.UserAttributeTypeString, value: "gold", want: "gold"},
+		{name: "string from number", typ: usecasemodels.UserAttributeTypeString, value: 1.0, wantErr: true},
+		{name: "number", typ: usecasemodels.UserAttributeTypeNumber, value: 1.5, want: 1.5},
+		{name: "number from json", typ: usecasemodels.UserAttributeTypeNumber, value: json.Number("2.5"), want: 2.5},
+		{name: "number from string", typ: usecasemodels.UserAttributeTypeNumber, value: " 3.5 ", want: 3.5},
+		{name: "number from bool", typ: usecasemodels.UserAttributeTypeNumber, value: true, wantErr: true},
+		{name: "number infinite", typ: usecasemodels.UserAttributeTypeNumber, value: math.Inf(1), wantErr: true},
+		{name: "number nan string", typ: usecasemodels.UserAttributeTypeNumber, value: "NaN", wantErr: true},
+		{name: "integer", typ: usecasemodels.UserAttributeTypeInteger, value: 42.0, want: 42.0},
+		{name: "integer from string", typ: usecasemodels.UserAttributeTypeInteger, value: "7", want: 7.0},
+		{name: "integer fraction", typ: usecasemodels.UserAttributeTypeInteger, value: 4.2, wantErr: true},
+		{name: "boolean", typ: usecasemodels.UserAttributeTypeBoolean, value: true, want: true},
+		{name: "boolean from string", typ: usecasemodels.UserAttributeTypeBoolean, value: " false ", want: false},
+		{name: "boolean invalid string", typ: usecasemodels.UserAttributeTypeBoolean, value: "yes", wantErr: true},
+		{name: "boolean from number", typ: usecasemodels.UserAttributeTypeBoolean, value: 1.0, wantErr: true},
+		{name: "date", typ: usecasemodels.UserAttributeTypeDate, value: " 2024-02-29 ", want: "2024-02-29"},
+		{name: "date invalid", typ: usecasemodels.UserAttributeTypeDate, value: "2023-02-29", wantErr: true},
+		{name: "date wrong format", typ: usecasemodels.UserAttributeTypeDate, value: "29.02.2024", wantErr: true},
+		{name: "date from number", typ: usecasemodels.UserAttributeTypeDate, value: 20240229.0, wantErr: true},
+		{name: "enum allowed", typ: usecasemodels.UserAttributeTypeString, enum: []any{"gold", "silver"}, value: "silver", want: "silver"},
+		{name: "enum not allowed", typ: usecasemodels.UserAttributeTypeString, enum: []any{"gold", "silver"}, value: "bronze", wantErr: true},
+		{name: "enum number from string", typ: usecasemodels.UserAttributeTypeInteger, enum: []any{1.0, 2.0}, value: "2", want: 2.0},
+		{name: "unsupported type", typ: "object", value: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition := &repositorymodels.UserAttributeDefinition{Key: "attr", Type: tt.typ, Enum: tt.enum}
+
+			got, err := normalizeUserAttributeValue(definition, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeUserAttributeValue(%v) error = %v, want error %v", tt.value, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("normalizeUserAttributeValue(%v) = %#v, want %#v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeUserAttributes(t *testing.T) {
+	definitions := map[string]*repositorymodels.UserAttributeDefinition{
+		"tier":  {Key: "tier", Type: usecasemodels.UserAttributeTypeString, Enum: []any{"gold", "silver"}, Default: "silver"},
+		"score": {Key: "score", Type: usecasemodels.UserAttributeTypeInteger, Required: true},
+		"note":  {Key: "note", Type: usecasemodels.UserAttributeTypeString},
+	}
+
+	tests := []struct {
+		name    string
+		current map[string]any
+		patch   map[string]any
+		create  bool
+		want    map[string]any
+		wantKey string
+	}{
+		{
+			name:   "create with default",
+			patch:  map[string]any{"score": "10"},
+			create: true,
+			want:   map[string]any{"score": 10.0, "tier": "silver"},
+		},
+		{
+			name:    "create without required",
+			patch:   map[string]any{"note": "x"},
+			create:  true,
+			wantKey: "score",
+		},
+		{
+			name:    "update keeps current",
+			current: map[string]any{"score": 10.0, "tier": "silver", "note": "x"},
+			patch:   map[string]any{"tier": "gold"},
+			want:    map[string]any{"score": 10.0, "tier": "gold", "note": "x"},
+		},
+		{
+			name:    "update removes optional",
+			current: map[string]any{"score": 10.0, "note": "x"},
+			patch:   map[string]any{"note": nil},
+			want:    map[string]any{"score": 10.0},
+		},
+		{
+			name:    "update removes required",
+			current: map[string]any{"score": 10.0},
+			patch:   map[string]any{"score": nil},
+			wantKey: "score",
+		},
+		{
+			name:    "unknown attribute",
+			patch:   map[string]any{"level": 1.0},
+			wantKey: "level",
+		},
+		{
+			name:    "invalid value",
+			patch:   map[string]any{"tier": "bronze"},
+			wantKey: "tier",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mergeUserAttributes(definitions, tt.current, tt.patch, tt.create)
+
+			var attrErr *usecasemodels.UserAttributeError
+			if tt.wantKey != "" {
+				if !errors.As(err, &attrErr) || attrErr.Key != tt.wantKey {
+					t.Fatalf("mergeUserAttributes() error = %v, want attribute error for %q", err, tt.wantKey)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("mergeUserAttributes() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("mergeUserAttributes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateUserAttributeValidation(t *testing.T) {
+	uc := &UseCase{}
+
+	tests := []struct {
+		name    string
+		req     usecasemodels.UserAttributeRequest
+		wantErr error
+	}{
+		{name: "empty key", req: usecasemodels.UserAttributeRequest{Type: usecasemodels.UserAttributeTypeString}, wantErr: usecasemodels.ErrorInvalidParameterKey},
+		{name: "upper case key", req: usecasemodels.UserAttributeRequest{Key: "Tier", Type: usecasemodels.UserAttributeTypeString}, wantErr: usecasemodels.ErrorInvalidParameterKey},
+		{name: "key starts with digit", req: usecasemodels.UserAttributeRequest{Key: "1tier", Type: usecasemodels.UserAttributeTypeString}, wantErr: usecasemodels.ErrorInvalidParameterKey},
+		{name: "unknown type", req: usecasemodels.UserAttributeRequest{Key: "tier", Type: "object"}, wantErr: usecasemodels.ErrorInvalidParameterType},
+		{
+			name:    "enum of another type",
+			req:     usecasemodels.UserAttributeRequest{Key: "tier", Type: usecasemodels.UserAttributeTypeInteger, Enum: []any{1.0, "two"}},
+			wantErr: usecasemodels.ErrorInvalidParameterEnum,
+		},
+		{
+			name:    "default outside enum",
+			req:     usecasemodels.UserAttributeRequest{Key: "tier", Type: usecasemodels.UserAttributeTypeString, Enum: []any{"gold"}, Default: "silver"},
+			wantErr: usecasemodels.ErrorInvalidParameterDefault,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.CreateUserAttribute(context.Background(), &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateUserAttribute() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyUserAttributeRequest(t *testing.T) {
+	definition := &repositorymodels.UserAttributeDefinition{Key: "score", Type: usecasemodels.UserAttributeTypeInteger, Enum: []any{5.0}, Default: 5.0}
+	req := &usecasemodels.UserAttributeRequest{Enum: []any{"1", json.Number("2")}, Default: "2"}
+
+	if err := applyUserAttributeRequest(definition, req); err != nil {
+		t.Fatalf("applyUserAttributeRequest() error = %v", err)
+	}
+
+	if want := []any{1.0, 2.0}; !reflect.DeepEqual(definition.Enum, want) {
+		t.Fatalf("applyUserAttributeRequest() enum = %v, want %v", definition.Enum, want)
+	}
+
+	if definition.Default != 2.0 {
+		t.Fatalf("applyUserAttributeRequest() default = %v, want 2", definition.Default)
+	}
+
+	if err := applyUserAttributeRequest(definition, &usecasemodels.UserAttributeRequest{}); err != nil {
+		t.Fatalf("applyUserAttributeRequest() error = %v", err)
+	}
+
+	if definition.Enum != nil || definition.Default != nil {
+		t.Fatalf("applyUserAttributeRequest() kept enum %v and default %v, want them cleared", definition.Enum, definition.Default)
+	}
+}
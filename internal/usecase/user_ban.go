@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/reqctx"
+
+	"github.com/google/uuid"
+)
+
+// banExpiryBatchSize — количество истекших блокировок, снимаемых в одной транзакции.
+const banExpiryBatchSize = 100
+
+// BanUser блокирует пользователя с причиной, внутренней заметкой и необязательным сроком.
+func (uc *UseCase) BanUser(ctx context.Context, id string, req *usecasemodels.BanUserRequest) (*usecasemodels.UserBanResponse, error) {
+	if err := validateBanUserRequest(req); err != nil {
+		return nil, err
+	}
+
+	ban := newUserBan(ctx, id, req)
+
+	var statusChange *userStatusChange
+
+	err := uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		user, err := uc.userRepo.GetUserByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get user by id: %w", err)
+		}
+
+		if user == nil {
+			return usecasemodels.ErrUserNotFound
+		}
+
+		statusChange, err = uc.banUser(ctx, user, ban)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.runStatusHooks(ctx, []*userStatusChange{statusChange})
+
+	response := userBanToResponse(ban)
+	return &response, nil
+}
+
+// validateBanUserRequest валидирует запрос на блокировку и нормализует причину.
+func validateBanUserRequest(req *usecasemodels.BanUserRequest) error {
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return usecasemodels.ErrorInvalidParameterReason
+	}
+
+	if req.Until != nil && !req.Until.After(time.Now()) {
+		return usecasemodels.ErrorInvalidParameterUntil
+	}
+
+	return nil
+}
+
+// newUserBan создает запись о блокировке пользователя администратором из контекста.
+func newUserBan(ctx context.Context, userID string, req *usecasemodels.BanUserRequest) *repositorymodels.UserBan {
+	return &repositorymodels.UserBan{
+		ID:       uuid.New().String(),
+		UserID:   userID,
+		Reason:   req.Reason,
+		Note:     req.Note,
+		AdminID:  optionalString(reqctx.From(ctx).AdminID),
+		BannedAt: time.Now(),
+		Until:    req.Until,
+	}
+}
+
+// banUser сохраняет блокировку и переводит пользователя в статус banned.
+// Должен вызываться внутри транзакции.
+func (uc *UseCase) banUser(ctx context.Context, user *repositorymodels.User, ban *repositorymodels.UserBan) (*userStatusChange, error) {
+	activeBan, err := uc.banRepo.GetActiveUserBan(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get active user ban: %w", err)
+	}
+
+	if activeBan != nil {
+		return nil, usecasemodels.ErrUserAlreadyBanned
+	}
+
+	statusChange, err := uc.checkAnyStatusTransition(ctx, user, "banned")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.banRepo.CreateUserBan(ctx, ban); err != nil {
+		return nil, err
+	}
+
+	if err := uc.setUserStatus(ctx, user, "banned", usecasemodels.UserHistoryActionBan); err != nil {
+		return nil, err
+	}
+
+	return statusChange, nil
+}
+
+// UnbanUser снимает активную блокировку пользователя с указанием причины.
+func (uc *UseCase) UnbanUser(ctx context.Context, id string, req *usecasemodels.UnbanUserRequest) (*usecasemodels.UserBanResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, usecasemodels.ErrorInvalidParameterReason
+	}
+
+	var ban *repositorymodels.UserBan
+	var statusChange *userStatusChange
+
+	err := uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		user, err := uc.userRepo.GetUserByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get user by id: %w", err)
+		}
+
+		if user == nil {
+			return usecasemodels.ErrUserNotFound
+		}
+
+		ban, err = uc.banRepo.GetActiveUserBan(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get active user ban: %w", err)
+		}
+
+		if ban == nil {
+			return usecasemodels.ErrUserNotBanned
+		}
+
+		if user.Status == "banned" {
+			statusChange, err = uc.checkStatusTransition(ctx, user, "active")
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := uc.liftUserBan(ctx, ban, optionalString(reqctx.From(ctx).AdminID), reason); err != nil {
+			return err
+		}
+
+		if user.Status != "banned" {
+			return nil
+		}
+
+		return uc.setUserStatus(ctx, user, "active", usecasemodels.UserHistoryActionUnban)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.runStatusHooks(ctx, []*userStatusChange{statusChange})
+
+	response := userBanToResponse(ban)
+	return &response, nil
+}
+
+// GetUserBans получает историю блокировок пользователя, начиная с последней.
+func (uc *UseCase) GetUserBans(ctx context.Context, id string) ([]usecasemodels.UserBanResponse, error) {
+	user, err := uc.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id: %w", err)
+	}
+
+	if user == nil {
+		return nil, usecasemodels.ErrUserNotFound
+	}
+
+	bans, err := uc.banRepo.GetUserBans(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get user bans: %w", err)
+	}
+
+	data := make([]usecasemodels.UserBanResponse, 0, len(bans))
+	for i := range bans {
+		data = append(data, userBanToResponse(&bans[i]))
+	}
+
+	return data, nil
+}
+
+// LiftExpiredBans снимает блокировки, срок которых истек. Вызывается планировщиком.
+// Блокировки обрабатываются пачками; пачку, уже взятую другим экземпляром сервиса, пропускаем.
+// Ограничения переходов статуса не проверяются, но действия перехода banned -> active, если он настроен, выполняются.
+func (uc *UseCase) LiftExpiredBans(ctx context.Context) error {
+	for {
+		var lifted []repositorymodels.UserBan
+		var statusChanges []*userStatusChange
+
+		err := uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+			bans, err := uc.banRepo.GetExpiredUserBans(ctx, time.Now(), banExpiryBatchSize)
+			if err != nil {
+				return fmt.Errorf("get expired user bans: %w", err)
+			}
+
+			for i := range bans {
+				ban := &bans[i]
+				if err := uc.liftUserBan(ctx, ban, nil, usecasemodels.UserBanLiftReasonExpired); err != nil {
+					return err
+				}
+
+				user, err := uc.userRepo.GetUserByID(ctx, ban.UserID)
+				if err != nil {
+					return fmt.Errorf("get user by id: %w", err)
+				}
+
+				// Удаленного пользователя не восстанавливаем, только закрываем блокировку.
+				if user != nil && user.Status == "banned" {
+					if transition := uc.findStatusTransition(user.Status, "active"); transition != nil {
+						statusChanges = append(statusChanges, uc.newUserStatusChange(ctx, user, "active", transition))
+					}
+
+					if err := uc.setUserStatus(ctx, user, "active", usecasemodels.UserHistoryActionUnban); err != nil {
+						return err
+					}
+				}
+			}
+
+			lifted = bans
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		uc.runStatusHooks(ctx, statusChanges)
+
+		for i := range lifted {
+			uc.audit(ctx, &usecasemodels.AuditEntry{
+				Action:       "user.ban_expired",
+				ResourceType: "user",
+				ResourceID:   lifted[i].UserID,
+				Outcome:      usecasemodels.AuditOutcomeSuccess,
+				Details:      map[string]string{"ban_id": lifted[i].ID},
+			})
+		}
+
+		if len(lifted) < banExpiryBatchSize {
+			return nil
+		}
+	}
+}
+
+// liftUserBan закрывает блокировку. liftedBy равен nil при автоматическом снятии.
+func (uc *UseCase) liftUserBan(ctx context.Context, ban *repositorymodels.UserBan, liftedBy *string, reason string) error {
+	now := time.Now()
+	ban.LiftedAt = &now
+	ban.LiftedBy = liftedBy
+	ban.LiftReason = &reason
+
+	if err := uc.banRepo.LiftUserBan(ctx, ban); err != nil {
+		return fmt.Errorf("lift user ban: %w", err)
+	}
+
+	return nil
+}
+
+// liftActiveUserBan закрывает активную блокировку пользователя, если она есть.
+// Используется, когда статус banned снимается в обход API блокировок.
+func (uc *UseCase) liftActiveUserBan(ctx context.Context, userID, reason string) error {
+	ban, err := uc.banRepo.GetActiveUserBan(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get active user ban: %w", err)
+	}
+
+	if ban == nil {
+		return nil
+	}
+
+	return uc.liftUserBan(ctx, ban, optionalString(reqctx.From(ctx).AdminID), reason)
+}
+
+// setUserStatus меняет статус пользователя и записывает версию в историю.
+// Если статус уже совпадает, ничего не делает.
+func (uc *UseCase) setUserStatus(ctx context.Context, user *repositorymodels.User, status, action string) error {
+	if user.Status == status {
+		return nil
+	}
+
+	if err := uc.userRepo.UpdateUser(ctx, user.ID, &repositorymodels.User{Status: status}); err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+
+	updatedUser := *user
+	updatedUser.Status = status
+
+	return uc.recordUserHistory(ctx, action, user.ID, user, &updatedUser)
+}
+
+// userBanToResponse преобразует блокировку в ответ.
+func userBanToResponse(ban *repositorymodels.UserBan) usecasemodels.UserBanResponse {
+	response := usecasemodels.UserBanResponse{
+		ID:         ban.ID,
+		UserID:     ban.UserID,
+		Reason:     ban.Reason,
+		Note:       ban.Note,
+		AdminID:    ban.AdminID,
+		BannedAt:   ban.BannedAt.Format(time.RFC3339),
+		Active:     ban.LiftedAt == nil,
+		LiftedBy:   ban.LiftedBy,
+		LiftReason: ban.LiftReason,
+	}
+
+	if ban.Until != nil {
+		until := ban.Until.Format(time.RFC3339)
+		response.Until = &until
+	}
+	if ban.LiftedAt != nil {
+		liftedAt := ban.LiftedAt.Format(time.RFC3339)
+		response.LiftedAt = &liftedAt
+	}
+
+	return response
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+func TestCheckStatusTransitionRequiresBan(t *testing.T) {
+	uc := newStatusTestUseCase()
+	ctx := adminContext("superadmin")
+
+	tests := []struct {
+		name       string
+		user       repositorymodels.User
+		to         string
+		wantChange bool
+		wantErr    error
+	}{
+		{name: "ban outside ban flow", user: repositorymodels.User{Status: "active"}, to: "banned", wantErr: usecasemodels.ErrStatusRequiresBan},
+		{name: "banned stays banned", user: repositorymodels.User{Status: "banned"}, to: "banned"},
+		{name: "unban", user: repositorymodels.User{Status: "banned"}, to: "active", wantChange: true},
+		{name: "other transition", user: repositorymodels.User{Status: "active"}, to: "inactive", wantChange: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := uc.checkStatusTransition(ctx, &tt.user, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkStatusTransition() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if (change != nil) != tt.wantChange {
+				t.Fatalf("checkStatusTransition() change = %+v, want change %v", change, tt.wantChange)
+			}
+
+			var transitionErr *usecasemodels.StatusTransitionError
+			if tt.wantErr != nil && !errors.As(err, &transitionErr) {
+				t.Fatalf("checkStatusTransition() error = %T, want *StatusTransitionError", err)
+			}
+		})
+	}
+}
+
+func TestValidateCreateUserRequestStatus(t *testing.T) {
+	uc := newStatusTestUseCase()
+
+	tests := []struct {
+		name       string
+		status     string
+		wantStatus string
+		wantErr    error
+	}{
+		{name: "initial", status: "", wantStatus: "pending"},
+		{name: "explicit", status: "active", wantStatus: "active"},
+		{name: "unknown", status: "archived", wantErr: usecasemodels.ErrorInvalidParameterStatus},
+		{name: "banned", status: "banned", wantErr: usecasemodels.ErrStatusRequiresBan},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &usecasemodels.CreateUserRequest{Email: "User@Example.com", Name: "User", Status: tt.status}
+
+			err := uc.validateCreateUserRequest(req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateCreateUserRequest() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && req.Status != tt.wantStatus {
+				t.Fatalf("Status = %q, want %q", req.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestValidateBanUserRequest(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		req        usecasemodels.BanUserRequest
+		wantReason string
+		wantErr    error
+	}{
+		{name: "reason", req: usecasemodels.BanUserRequest{Reason: "  spam "}, wantReason: "spam"},
+		{name: "until in future", req: usecasemodels.BanUserRequest{Reason: "spam", Until: &future}, wantReason: "spam"},
+		{name: "empty reason", req: usecasemodels.BanUserRequest{Reason: "   "}, wantErr: usecasemodels.ErrorInvalidParameterReason},
+		{name: "until in past", req: usecasemodels.BanUserRequest{Reason: "spam", Until: &past}, wantErr: usecasemodels.ErrorInvalidParameterUntil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+
+			err := validateBanUserRequest(&req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateBanUserRequest() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && req.Reason != tt.wantReason {
+				t.Fatalf("Reason = %q, want %q", req.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestValidateBulkBanRequest(t *testing.T) {
+	uc := newStatusTestUseCase()
+
+	tests := []struct {
+		name    string
+		req     usecasemodels.BulkUsersRequest
+		wantErr error
+	}{
+		{
+			name:    "reason required",
+			req:     usecasemodels.BulkUsersRequest{Operation: usecasemodels.BulkOperationBan, IDs: []string{"user-1"}},
+			wantErr: usecasemodels.ErrorInvalidParameterReason,
+		},
+		{
+			name: "with reason",
+			req:  usecasemodels.BulkUsersRequest{Operation: usecasemodels.BulkOperationBan, IDs: []string{"user-1"}, Reason: "spam"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			if err := uc.validateBulkUsersRequest(&req); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateBulkUsersRequest() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewUserBan(t *testing.T) {
+	note := "note"
+	until := time.Now().Add(time.Hour)
+
+	ban := newUserBan(adminContext("admin"), "user-1", &usecasemodels.BanUserRequest{Reason: "spam", Note: &note, Until: &until})
+	if ban.ID == "" || ban.UserID != "user-1" || ban.Reason != "spam" || ban.Note != &note || ban.Until != &until {
+		t.Fatalf("newUserBan() = %+v", ban)
+	}
+
+	if ban.AdminID == nil || *ban.AdminID != "admin-1" {
+		t.Fatalf("AdminID = %v, want admin-1", ban.AdminID)
+	}
+
+	if system := newUserBan(context.Background(), "user-1", &usecasemodels.BanUserRequest{Reason: "spam"}); system.AdminID != nil {
+		t.Fatalf("AdminID = %v, want nil for system ban", system.AdminID)
+	}
+}
@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+// errBulkDryRun используется для отката транзакции в режиме dry-run.
+var errBulkDryRun = errors.New("bulk dry run")
+
+// BulkUsers выполняет массовую операцию над пользователями в одной транзакции.
+// В режиме dry-run операция выполняется и откатывается, а отчет возвращается как есть.
+// Пользователи, для которых переход статуса не разрешен, пропускаются с результатом rejected.
+// Изменения меток записываются в журнал аудита по каждому пользователю.
+func (uc *UseCase) BulkUsers(ctx context.Context, req *usecasemodels.BulkUsersRequest) (*usecasemodels.BulkUsersResponse, error) {
+	if err := uc.validateBulkUsersRequest(req); err != nil {
+		return nil, err
+	}
+
+	response := &usecasemodels.BulkUsersResponse{
+		Operation: req.Operation,
+		DryRun:    req.DryRun,
+	}
+
+	var statusChanges []*userStatusChange
+	var tagChanges []userTagChange
+
+	err := uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		ids, err := uc.resolveBulkUserIDs(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		if isBulkTagOperation(req.Operation) {
+			if err := uc.checkTagsExist(ctx, req.TagIDs); err != nil {
+				return err
+			}
+		}
+
+		response.Total = len(ids)
+		response.Results = make([]usecasemodels.BulkUserItemResult, 0, len(ids))
+
+		for _, id := range ids {
+			var status string
+			var change *userStatusChange
+			var tagIDs []string
+			if isBulkTagOperation(req.Operation) {
+				status, tagIDs, err = uc.applyBulkUserTags(ctx, id, req)
+			} else {
+				status, change, err = uc.applyBulkUserOperation(ctx, id, req)
+			}
+
+			var transitionErr *usecasemodels.StatusTransitionError
+			if errors.As(err, &transitionErr) {
+				response.Rejected++
+				response.Results = append(response.Results, usecasemodels.BulkUserItemResult{
+					ID:     id,
+					Status: usecasemodels.BulkItemStatusRejected,
+					Error:  transitionErr.Error(),
+				})
+
+				continue
+			}
+
+			if err != nil {
+				return fmt.Errorf("apply %s to user %s: %w", req.Operation, id, err)
+			}
+
+			switch status {
+			case usecasemodels.BulkItemStatusOK:
+				response.Succeeded++
+			case usecasemodels.BulkItemStatusSkipped:
+				response.Skipped++
+			case usecasemodels.BulkItemStatusNotFound:
+				response.NotFound++
+			}
+
+			response.Results = append(response.Results, usecasemodels.BulkUserItemResult{
+				ID:     id,
+				Status: status,
+			})
+
+			statusChanges = append(statusChanges, change)
+			if len(tagIDs) > 0 {
+				tagChanges = append(tagChanges, userTagChange{userID: id, tagIDs: tagIDs})
+			}
+		}
+
+		if req.DryRun {
+			return errBulkDryRun
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBulkDryRun) {
+		return nil, err
+	}
+
+	if !req.DryRun {
+		uc.runStatusHooks(ctx, statusChanges)
+		uc.auditUserTagChanges(ctx, req.Operation, tagChanges)
+	}
+
+	return response, nil
+}
+
+// resolveBulkUserIDs возвращает список ID, над которыми выполняется операция.
+func (uc *UseCase) resolveBulkUserIDs(ctx context.Context, req *usecasemodels.BulkUsersRequest) ([]string, error) {
+	maxItems := uc.cfg.Users.BulkMaxItems
+
+	if req.Filter != nil {
+		uc.preparePhoneSearch(req.Filter)
+
+		if err := prepareTagFilter(req.Filter); err != nil {
+			return nil, err
+		}
+
+		if err := uc.prepareUserAttributeQuery(ctx, req.Filter, nil); err != nil {
+			return nil, err
+		}
+
+		// Запрашиваем на одну запись больше, чтобы обнаружить превышение лимита.
+		ids, err := uc.userRepo.GetUserIDsByFilter(ctx, req.Filter, maxItems+1)
+		if err != nil {
+			return nil, fmt.Errorf("get user ids by filter: %w", err)
+		}
+
+		if len(ids) > maxItems {
+			return nil, usecasemodels.ErrBulkLimitExceeded
+		}
+
+		return ids, nil
+	}
+
+	seen := make(map[string]struct{}, len(req.IDs))
+	ids := make([]string, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	if len(ids) > maxItems {
+		return nil, usecasemodels.ErrBulkLimitExceeded
+	}
+
+	return ids, nil
+}
+
+// applyBulkUserOperation применяет операцию к одному пользователю и возвращает статус элемента
+// и выполненный переход статуса, если он был.
+func (uc *UseCase) applyBulkUserOperation(ctx context.Context, id string, req *usecasemodels.BulkUsersRequest) (string, *userStatusChange, error) {
+	user, err := uc.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return "", nil, fmt.Errorf("get user by id: %w", err)
+	}
+
+	if user == nil {
+		return usecasemodels.BulkItemStatusNotFound, nil, nil
+	}
+
+	if req.Operation == usecasemodels.BulkOperationDelete {
+		if err := uc.userRepo.DeleteUser(ctx, id); err != nil {
+			return "", nil, fmt.Errorf("delete user: %w", err)
+		}
+
+		deletedUser := *user
+		deletedAt := time.Now()
+		deletedUser.DeletedAt = &deletedAt
+
+		if err := uc.recordUserHistory(ctx, usecasemodels.UserHistoryActionDelete, id, user, &deletedUser); err != nil {
+			return "", nil, err
+		}
+
+		return usecasemodels.BulkItemStatusOK, nil, nil
+	}
+
+	// Блокировка создает запись о бане с причиной, как POST /users/:id/ban.
+	if req.Operation == usecasemodels.BulkOperationBan {
+		statusChange, err := uc.banUser(ctx, user, newUserBan(ctx, id, bulkBanRequest(req)))
+		if errors.Is(err, usecasemodels.ErrUserAlreadyBanned) {
+			return usecasemodels.BulkItemStatusSkipped, nil, nil
+		}
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		return usecasemodels.BulkItemStatusOK, statusChange, nil
+	}
+
+	updateUser := &repositorymodels.User{}
+	switch req.Operation {
+	case usecasemodels.BulkOperationActivate:
+		updateUser.Status = "active"
+	case usecasemodels.BulkOperationDeactivate:
+		updateUser.Status = "inactive"
+	case usecasemodels.BulkOperationSetRole:
+		updateUser.Role = *req.Role
+	}
+
+	if (updateUser.Status != "" && updateUser.Status == user.Status) ||
+		(updateUser.Role != "" && updateUser.Role == user.Role) {
+		return usecasemodels.BulkItemStatusSkipped, nil, nil
+	}
+
+	var statusChange *userStatusChange
+	if updateUser.Status != "" {
+		statusChange, err = uc.checkStatusTransition(ctx, user, updateUser.Status)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if user.Status == "banned" && updateUser.Status != "" && updateUser.Status != "banned" {
+		if err := uc.liftActiveUserBan(ctx, id, "status changed to "+updateUser.Status); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if err := uc.userRepo.UpdateUser(ctx, id, updateUser); err != nil {
+		return "", nil, fmt.Errorf("update user: %w", err)
+	}
+
+	updatedUser := *user
+	if updateUser.Status != "" {
+		updatedUser.Status = updateUser.Status
+	}
+	if updateUser.Role != "" {
+		updatedUser.Role = updateUser.Role
+	}
+
+	if err := uc.recordUserHistory(ctx, usecasemodels.UserHistoryActionUpdate, id, user, &updatedUser); err != nil {
+		return "", nil, err
+	}
+
+	return usecasemodels.BulkItemStatusOK, statusChange, nil
+}
+
+// bulkBanRequest возвращает параметры блокировки из запроса на массовую операцию.
+func bulkBanRequest(req *usecasemodels.BulkUsersRequest) *usecasemodels.BanUserRequest {
+	return &usecasemodels.BanUserRequest{
+		Reason: req.Reason,
+		Note:   req.Note,
+		Until:  req.Until,
+	}
+}
+
+// isBulkTagOperation проверяет, что массовая операция меняет метки пользователей.
+func isBulkTagOperation(operation string) bool {
+	return operation == usecasemodels.BulkOperationAddTags || operation == usecasemodels.BulkOperationRemoveTags
+}
+
+// validateBulkUsersRequest валидирует запрос на массовую операцию.
+func (uc *UseCase) validateBulkUsersRequest(req *usecasemodels.BulkUsersRequest) error {
+	switch req.Operation {
+	case usecasemodels.BulkOperationBan:
+		banReq := bulkBanRequest(req)
+		if err := validateBanUserRequest(banReq); err != nil {
+			return err
+		}
+		req.Reason = banReq.Reason
+	case usecasemodels.BulkOperationActivate,
+		usecasemodels.BulkOperationDeactivate,
+		usecasemodels.BulkOperationDelete:
+	case usecasemodels.BulkOperationSetRole:
+		if req.Role == nil {
+			return usecasemodels.ErrorInvalidParameterRole
+		}
+
+		if err := uc.validateUpdateUserRequest(&usecasemodels.UpdateUserRequest{Role: req.Role}); err != nil {
+			return err
+		}
+	case usecasemodels.BulkOperationAddTags, usecasemodels.BulkOperationRemoveTags:
+		tagIDs, err := normalizeTagIDs(req.TagIDs)
+		if err != nil {
+			return err
+		}
+		req.TagIDs = tagIDs
+	default:
+		return usecasemodels.ErrorInvalidParameterOperation
+	}
+
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		return usecasemodels.ErrorInvalidParameterTarget
+	}
+
+	return nil
+}
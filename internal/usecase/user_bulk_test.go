@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"adminkaback/internal"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/config"
+)
+
+// testBulkUserRepo возвращает count ID пользователей по фильтру, но не больше limit.
+type testBulkUserRepo struct {
+	internal.UserRepository
+	count int
+	limit int
+}
+
+func (r *testBulkUserRepo) GetUserIDsByFilter(_ context.Context, _ *usecasemodels.UserFilter, limit int) ([]string, error) {
+	r.limit = limit
+
+	ids := make([]string, 0, min(r.count, limit))
+	for i := 0; i < r.count && i < limit; i++ {
+		ids = append(ids, fmt.Sprintf("user-%d", i))
+	}
+
+	return ids, nil
+}
+
+// newBulkTestUseCase создает UseCase с ограничением массовой операции maxItems пользователями.
+func newBulkTestUseCase(maxItems int, repo internal.UserRepository) *UseCase {
+	cfg := &config.Config{}
+	cfg.Users.BulkMaxItems = maxItems
+
+	return &UseCase{cfg: cfg, userRepo: repo}
+}
+
+func TestValidateBulkUsersRequest(t *testing.T) {
+	uc := newBulkTestUseCase(10, nil)
+	role, unknownRole := "moderator", "owner"
+
+	tests := []struct {
+		name    string
+		req     usecasemodels.BulkUsersRequest
+		wantErr error
+	}{
+		{name: "activate by ids", req: usecasemodels.BulkUsersRequest{Operation: "activate", IDs: []string{"user-1"}}},
+		{name: "deactivate by filter", req: usecasemodels.BulkUsersRequest{Operation: "deactivate", Filter: &usecasemodels.UserFilter{}}},
+		{name: "delete", req: usecasemodels.BulkUsersRequest{Operation: "delete", IDs: []string{"user-1"}}},
+		{name: "set role", req: usecasemodels.BulkUsersRequest{Operation: "set_role", Role: &role, IDs: []string{"user-1"}}},
+		{name: "set role without role", req: usecasemodels.BulkUsersRequest{Operation: "set_role", IDs: []string{"user-1"}}, wantErr: usecasemodels.ErrorInvalidParameterRole},
+		{
+			name:    "set unknown role",
+			req:     usecasemodels.BulkUsersRequest{Operation: "set_role", Role: &unknownRole, IDs: []string{"user-1"}},
+			wantErr: usecasemodels.ErrorInvalidParameterRole,
+		},
+		{name: "unknown operation", req: usecasemodels.BulkUsersRequest{Operation: "archive", IDs: []string{"user-1"}}, wantErr: usecasemodels.ErrorInvalidParameterOperation},
+		{name: "empty operation", req: usecasemodels.BulkUsersRequest{IDs: []string{"user-1"}}, wantErr: usecasemodels.ErrorInvalidParameterOperation},
+		{name: "without target", req: usecasemodels.BulkUsersRequest{Operation: "activate"}, wantErr: usecasemodels.ErrorInvalidParameterTarget},
+		{
+			name:    "ids and filter",
+			req:     usecasemodels.BulkUsersRequest{Operation: "activate", IDs: []string{"user-1"}, Filter: &usecasemodels.UserFilter{}},
+			wantErr: usecasemodels.ErrorInvalidParameterTarget,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := uc.validateBulkUsersRequest(&tt.req); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateBulkUsersRequest() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveBulkUserIDs(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       usecasemodels.BulkUsersRequest
+		matched   int
+		want      []string
+		wantLimit int
+		wantErr   error
+	}{
+		{
+			name: "ids without duplicates",
+			req:  usecasemodels.BulkUsersRequest{IDs: []string{"user-2", "user-1", "user-2", "user-3", "user-1"}},
+			want: []string{"user-2", "user-1", "user-3"},
+		},
+		{
+			name: "duplicates do not count towards the limit",
+			req:  usecasemodels.BulkUsersRequest{IDs: []string{"user-1", "user-2", "user-3", "user-1", "user-2"}},
+			want: []string{"user-1", "user-2", "user-3"},
+		},
+		{
+			name:    "too many ids",
+			req:     usecasemodels.BulkUsersRequest{IDs: []string{"user-1", "user-2", "user-3", "user-4"}},
+			wantErr: usecasemodels.ErrBulkLimitExceeded,
+		},
+		{
+			name:      "filter",
+			req:       usecasemodels.BulkUsersRequest{Filter: &usecasemodels.UserFilter{}},
+			matched:   2,
+			want:      []string{"user-0", "user-1"},
+			wantLimit: 4,
+		},
+		{
+			name:      "filter at the limit",
+			req:       usecasemodels.BulkUsersRequest{Filter: &usecasemodels.UserFilter{}},
+			matched:   3,
+			want:      []string{"user-0", "user-1", "user-2"},
+			wantLimit: 4,
+		},
+		{
+			name:      "filter over the limit",
+			req:       usecasemodels.BulkUsersRequest{Filter: &usecasemodels.UserFilter{}},
+			matched:   100,
+			wantLimit: 4,
+			wantErr:   usecasemodels.ErrBulkLimitExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &testBulkUserRepo{count: tt.matched}
+			uc := newBulkTestUseCase(3, repo)
+
+			got, err := uc.resolveBulkUserIDs(context.Background(), &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("resolveBulkUserIDs() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("resolveBulkUserIDs() = %v, want %v", got, tt.want)
+			}
+
+			if repo.limit != tt.wantLimit {
+				t.Fatalf("GetUserIDsByFilter() limit = %d, want %d", repo.limit, tt.wantLimit)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+
+	"github.com/google/uuid"
+)
+
+// userDuplicateNameThreshold — сходство имен, начиная с которого имя считается причиной совпадения.
+// Совпадает с порогом оператора % из pg_trgm по умолчанию.
+const userDuplicateNameThreshold = 0.3
+
+// userMergeFields — поля, источник значения которых выбирается при объединении.
+var userMergeFields = []string{"email", "name", "phone", "role", "status", "attributes"}
+
+// FindUserDuplicates находит пары пользователей, похожих на дубликаты, по убыванию оценки.
+func (uc *UseCase) FindUserDuplicates(ctx context.Context, req *usecasemodels.FindUserDuplicatesRequest) (*usecasemodels.FindUserDuplicatesResponse, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	if math.IsNaN(req.MinScore) || req.MinScore < 0 || req.MinScore > 1 {
+		return nil, usecasemodels.ErrorInvalidParameterScore
+	}
+
+	if req.UserID != "" {
+		if _, err := uuid.Parse(req.UserID); err != nil {
+			return nil, usecasemodels.ErrUserNotFound
+		}
+
+		if err := uc.checkUserExists(ctx, req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	duplicates, total, err := uc.userRepo.FindUserDuplicates(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("find user duplicates: %w", err)
+	}
+
+	ids := make([]string, 0, len(duplicates)*2)
+	for _, duplicate := range duplicates {
+		ids = append(ids, duplicate.UserID, duplicate.DuplicateID)
+	}
+
+	users := make(map[string]usecasemodels.UserResponse, len(ids))
+	if len(ids) > 0 {
+		found, err := uc.userRepo.GetUsersByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("get users by ids: %w", err)
+		}
+
+		for i := range found {
+			users[found[i].ID] = uc.userToResponse(ctx, &found[i])
+		}
+	}
+
+	data := make([]usecasemodels.UserDuplicateResponse, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		data = append(data, usecasemodels.UserDuplicateResponse{
+			User:           users[duplicate.UserID],
+			Duplicate:      users[duplicate.DuplicateID],
+			Score:          math.Round(duplicate.Score*1000) / 1000,
+			Reasons:        userDuplicateReasons(&duplicate),
+			NameSimilarity: math.Round(duplicate.NameSimilarity*1000) / 1000,
+		})
+	}
+
+	return &usecasemodels.FindUserDuplicatesResponse{
+		Data:       data,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.Limit))),
+	}, nil
+}
+
+// MergeUsers объединяет дубликат (loser) с сохраняемым пользователем (survivor): переносит выбранные поля,
+// метки, заметки и историю и удаляет дубликат (soft delete) со ссылкой merged_into.
+// Смена статуса проверяется по машине состояний; активная блокировка дубликата снимается.
+func (uc *UseCase) MergeUsers(ctx context.Context, req *usecasemodels.MergeUsersRequest) (resp *usecasemodels.UserResponse, err error) {
+	if err := validateMergeUsersRequest(req); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		fields := make([]string, 0, len(req.Fields))
+		for _, field := range sortedKeys(req.Fields) {
+			fields = append(fields, field+"="+req.Fields[field])
+		}
+
+		uc.auditResult(ctx, &usecasemodels.AuditEntry{
+			Action:       "user.merge",
+			ResourceType: "user",
+			ResourceID:   req.SurvivorID,
+			Details:      map[string]string{"loser_id": req.LoserID, "fields": strings.Join(fields, ",")},
+		}, err)
+	}()
+
+	var merged *repositorymodels.User
+	var statusChange *userStatusChange
+
+	err = uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		survivor, err := uc.userRepo.GetUserByID(ctx, req.SurvivorID)
+		if err != nil {
+			return fmt.Errorf("get user by id: %w", err)
+		}
+
+		loser, err := uc.userRepo.GetUserByID(ctx, req.LoserID)
+		if err != nil {
+			return fmt.Errorf("get user by id: %w", err)
+		}
+
+		if survivor == nil || loser == nil {
+			return usecasemodels.ErrUserNotFound
+		}
+
+		update := mergeUserFields(survivor, loser, req.Fields)
+
+		if update.Status != "" {
+			statusChange, err = uc.checkStatusTransition(ctx, survivor, update.Status)
+			if err != nil {
+				return err
+			}
+
+			if survivor.Status == "banned" {
+				if err := uc.liftActiveUserBan(ctx, survivor.ID, "status changed to "+update.Status+" by merge"); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := uc.liftActiveUserBan(ctx, loser.ID, "merged into "+survivor.ID); err != nil {
+			return err
+		}
+
+		// Дубликат удаляется до обновления, чтобы его email можно было перенести без конфликта уникальности.
+		if err := uc.userRepo.MarkUserMerged(ctx, loser.ID, survivor.ID); err != nil {
+			return err
+		}
+
+		if err := uc.userRepo.MoveUserRelations(ctx, loser.ID, survivor.ID); err != nil {
+			return fmt.Errorf("move user relations: %w", err)
+		}
+
+		if err := uc.userRepo.UpdateUser(ctx, survivor.ID, update); err != nil {
+			return fmt.Errorf("update user: %w", err)
+		}
+
+		if update.Email != "" && loser.IsEmailVerified {
+			if _, err := uc.verifyRepo.SetUserEmailVerified(ctx, survivor.ID, update.Email); err != nil {
+				return fmt.Errorf("set user email verified: %w", err)
+			}
+		}
+
+		merged, err = uc.userRepo.GetUserByID(ctx, survivor.ID)
+		if err != nil {
+			return fmt.Errorf("get user by id: %w", err)
+		}
+
+		return uc.recordUserHistory(ctx, usecasemodels.UserHistoryActionMerge, survivor.ID, survivor, merged)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.runStatusHooks(ctx, []*userStatusChange{statusChange})
+
+	response := uc.userToResponse(ctx, merged)
+
+	tags, err := uc.getUserTags(ctx, merged.ID)
+	if err != nil {
+		return nil, err
+	}
+	response.Tags = tags
+
+	return &response, nil
+}
+
+// mergeUserFields возвращает изменения сохраняемого пользователя: значения полей, для которых
+// выбран дубликат и которые отличаются от текущих, и итоговые атрибуты.
+func mergeUserFields(survivor, loser *repositorymodels.User, fields map[string]string) *repositorymodels.User {
+	update := &repositorymodels.User{}
+
+	if fields["email"] == usecasemodels.UserMergeSourceLoser && loser.Email != survivor.Email {
+		update.Email = loser.Email
+	}
+	if fields["name"] == usecasemodels.UserMergeSourceLoser && loser.Name != survivor.Name {
+		update.Name = loser.Name
+	}
+	if fields["phone"] == usecasemodels.UserMergeSourceLoser && stringValue(loser.Phone) != stringValue(survivor.Phone) {
+		// Пустая строка очищает телефон, если у дубликата его нет.
+		phone := stringValue(loser.Phone)
+		update.Phone = &phone
+	}
+	if fields["role"] == usecasemodels.UserMergeSourceLoser && loser.Role != survivor.Role {
+		update.Role = loser.Role
+	}
+	if fields["status"] == usecasemodels.UserMergeSourceLoser && loser.Status != survivor.Status {
+		update.Status = loser.Status
+	}
+
+	switch fields["attributes"] {
+	case usecasemodels.UserMergeSourceLoser:
+		update.Attributes = make(map[string]any, len(loser.Attributes))
+		for key, value := range loser.Attributes {
+			update.Attributes[key] = value
+		}
+	case usecasemodels.UserMergeSourceSurvivor:
+	default:
+		update.Attributes = make(map[string]any, len(survivor.Attributes)+len(loser.Attributes))
+		for key, value := range loser.Attributes {
+			update.Attributes[key] = value
+		}
+		for key, value := range survivor.Attributes {
+			update.Attributes[key] = value
+		}
+	}
+
+	return update
+}
+
+// validateMergeUsersRequest валидирует запрос на объединение пользователей.
+func validateMergeUsersRequest(req *usecasemodels.MergeUsersRequest) error {
+	survivorID, err := uuid.Parse(req.SurvivorID)
+	if err != nil {
+		return usecasemodels.ErrUserNotFound
+	}
+
+	loserID, err := uuid.Parse(req.LoserID)
+	if err != nil {
+		return usecasemodels.ErrUserNotFound
+	}
+
+	if survivorID == loserID {
+		return fmt.Errorf("%w: survivor and loser are the same user", usecasemodels.ErrorInvalidParameterMerge)
+	}
+
+	for field, source := range req.Fields {
+		if !containsString(userMergeFields, field) {
+			return fmt.Errorf("%w: unknown field %s", usecasemodels.ErrorInvalidParameterMerge, field)
+		}
+
+		switch source {
+		case usecasemodels.UserMergeSourceSurvivor, usecasemodels.UserMergeSourceLoser:
+		case usecasemodels.UserMergeSourceMerge:
+			if field != "attributes" {
+				return fmt.Errorf("%w: %s cannot be merged", usecasemodels.ErrorInvalidParameterMerge, field)
+			}
+		default:
+			return fmt.Errorf("%w: unknown source %s for %s", usecasemodels.ErrorInvalidParameterMerge, source, field)
+		}
+	}
+
+	return nil
+}
+
+// userDuplicateReasons возвращает признаки, по которым пара считается дубликатом.
+func userDuplicateReasons(duplicate *repositorymodels.UserDuplicate) []string {
+	reasons := make([]string, 0, 3)
+	if duplicate.EmailMatch {
+		reasons = append(reasons, usecasemodels.UserDuplicateReasonEmail)
+	}
+	if duplicate.PhoneMatch {
+		reasons = append(reasons, usecasemodels.UserDuplicateReasonPhone)
+	}
+	if duplicate.NameSimilarity >= userDuplicateNameThreshold {
+		reasons = append(reasons, usecasemodels.UserDuplicateReasonName)
+	}
+
+	sort.Strings(reasons)
+
+	return reasons
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+)
+
+func TestMergeUserFields(t *testing.T) {
+	survivorPhone := "+79123456789"
+	loserPhone := "+79000000000"
+	empty := ""
+
+	survivor := &repositorymodels.User{
+		Email: "survivor@example.com", Name: "Survivor", Phone: &survivorPhone, Role: "user", Status: "active",
+		Attributes: map[string]any{"city": "Moscow", "age": float64(30)},
+	}
+	loser := &repositorymodels.User{
+		Email: "loser@example.com", Name: "Loser", Phone: &loserPhone, Role: "moderator", Status: "inactive",
+		Attributes: map[string]any{"city": "Kazan", "vip": true},
+	}
+	loserWithoutPhone := *loser
+	loserWithoutPhone.Phone = nil
+
+	tests := []struct {
+		name   string
+		loser  *repositorymodels.User
+		fields map[string]string
+		want   *repositorymodels.User
+	}{
+		{
+			name:  "defaults keep survivor fields and merge attributes",
+			loser: loser,
+			want: &repositorymodels.User{
+				Attributes: map[string]any{"city": "Moscow", "age": float64(30), "vip": true},
+			},
+		},
+		{
+			name:  "loser fields",
+			loser: loser,
+			fields: map[string]string{
+				"email": usecasemodels.UserMergeSourceLoser, "name": usecasemodels.UserMergeSourceLoser,
+				"phone": usecasemodels.UserMergeSourceLoser, "role": usecasemodels.UserMergeSourceLoser,
+				"status": usecasemodels.UserMergeSourceLoser, "attributes": usecasemodels.UserMergeSourceLoser,
+			},
+			want: &repositorymodels.User{
+				Email: "loser@example.com", Name: "Loser", Phone: &loserPhone, Role: "moderator", Status: "inactive",
+				Attributes: map[string]any{"city": "Kazan", "vip": true},
+			},
+		},
+		{
+			name:   "survivor attributes",
+			loser:  loser,
+			fields: map[string]string{"attributes": usecasemodels.UserMergeSourceSurvivor},
+			want:   &repositorymodels.User{},
+		},
+		{
+			name:   "loser without phone clears phone",
+			loser:  &loserWithoutPhone,
+			fields: map[string]string{"phone": usecasemodels.UserMergeSourceLoser, "attributes": usecasemodels.UserMergeSourceSurvivor},
+			want:   &repositorymodels.User{Phone: &empty},
+		},
+		{
+			name:   "equal values are not updated",
+			loser:  survivor,
+			fields: map[string]string{"email": usecasemodels.UserMergeSourceLoser, "status": usecasemodels.UserMergeSourceLoser, "attributes": usecasemodels.UserMergeSourceSurvivor},
+			want:   &repositorymodels.User{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeUserFields(survivor, tt.loser, tt.fields); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("mergeUserFields() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMergeUsersRequest(t *testing.T) {
+	survivorID := "8b9c4a52-4c1e-4b8e-9c52-1f6e0b7f3a10"
+	loserID := "0f3d2a41-7a5b-4f1c-8e2d-6c9b1a0e4d21"
+
+	tests := []struct {
+		name    string
+		req     usecasemodels.MergeUsersRequest
+		wantErr error
+	}{
+		{name: "valid", req: usecasemodels.MergeUsersRequest{SurvivorID: survivorID, LoserID: loserID}},
+		{
+			name: "merge attributes",
+			req:  usecasemodels.MergeUsersRequest{SurvivorID: survivorID, LoserID: loserID, Fields: map[string]string{"attributes": usecasemodels.UserMergeSourceMerge}},
+		},
+		{name: "invalid survivor", req: usecasemodels.MergeUsersRequest{SurvivorID: "1", LoserID: loserID}, wantErr: usecasemodels.ErrUserNotFound},
+		{name: "invalid loser", req: usecasemodels.MergeUsersRequest{SurvivorID: survivorID, LoserID: "1"}, wantErr: usecasemodels.ErrUserNotFound},
+		{name: "same user", req: usecasemodels.MergeUsersRequest{SurvivorID: survivorID, LoserID: survivorID}, wantErr: usecasemodels.ErrorInvalidParameterMerge},
+		{
+			name:    "unknown field",
+			req:     usecasemodels.MergeUsersRequest{SurvivorID: survivorID, LoserID: loserID, Fields: map[string]string{"password": usecasemodels.UserMergeSourceLoser}},
+			wantErr: usecasemodels.ErrorInvalidParameterMerge,
+		},
+		{
+			name:    "merge of scalar field",
+			req:     usecasemodels.MergeUsersRequest{SurvivorID: survivorID, LoserID: loserID, Fields: map[string]string{"email": usecasemodels.UserMergeSourceMerge}},
+			wantErr: usecasemodels.ErrorInvalidParameterMerge,
+		},
+		{
+			name:    "unknown source",
+			req:     usecasemodels.MergeUsersRequest{SurvivorID: survivorID, LoserID: loserID, Fields: map[string]string{"name": "both"}},
+			wantErr: usecasemodels.ErrorInvalidParameterMerge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateMergeUsersRequest(&tt.req); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateMergeUsersRequest() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserDuplicateReasons(t *testing.T) {
+	tests := []struct {
+		name      string
+		duplicate repositorymodels.UserDuplicate
+		want      []string
+	}{
+		{name: "none", duplicate: repositorymodels.UserDuplicate{NameSimilarity: 0.29}, want: []string{}},
+		{name: "all", duplicate: repositorymodels.UserDuplicate{EmailMatch: true, PhoneMatch: true, NameSimilarity: 0.3}, want: []string{"email", "name", "phone"}},
+		{name: "phone", duplicate: repositorymodels.UserDuplicate{PhoneMatch: true}, want: []string{"phone"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userDuplicateReasons(&tt.duplicate); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("userDuplicateReasons() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
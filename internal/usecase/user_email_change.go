@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/pii"
+	"adminkaback/pkg/reqctx"
+	"adminkaback/pkg/signedtoken"
+
+	"github.com/google/uuid"
+)
+
+// emailChangePurpose — назначение токена ссылки подтверждения смены email.
+const emailChangePurpose = "email_change"
+
+// requestEmailChange сохраняет запрос на смену email и отправляет ссылку подтверждения на новый адрес.
+func (uc *UseCase) requestEmailChange(ctx context.Context, user *repositorymodels.User, newEmail string) error {
+	now := time.Now()
+	change := &repositorymodels.UserEmailChange{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		OldEmail:  user.Email,
+		NewEmail:  newEmail,
+		AdminID:   optionalString(reqctx.From(ctx).AdminID),
+		CreatedAt: now,
+	}
+
+	if err := uc.verifyRepo.CreateUserEmailChange(ctx, change); err != nil {
+		return fmt.Errorf("create user email change: %w", err)
+	}
+
+	token, err := uc.signer.Sign(emailChangePurpose, change.ID, newEmail, uc.cfg.Users.VerificationTTL)
+	if err != nil {
+		return fmt.Errorf("sign email change token: %w", err)
+	}
+
+	link := strings.TrimRight(uc.cfg.App.PublicURL, "/") + "/api/v1/email/confirm-change?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Здравствуйте, %s!\n\nЧтобы сменить email на %s, перейдите по ссылке:\n%s\n\nСсылка действительна до %s.",
+		user.Name, newEmail, link, now.Add(uc.cfg.Users.VerificationTTL).Format("02.01.2006 15:04"))
+
+	if err := uc.mailer.Send(ctx, newEmail, "Подтверждение смены email", body); err != nil {
+		return fmt.Errorf("send email change confirmation: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange применяет смену email по токену из ссылки, отправленной на новый адрес.
+// Новый email сразу считается подтвержденным. Ссылка перестает действовать,
+// если email пользователя изменился после ее отправки.
+func (uc *UseCase) ConfirmEmailChange(ctx context.Context, token string) (resp *usecasemodels.UserResponse, err error) {
+	claims, err := uc.signer.Verify(emailChangePurpose, token)
+	if err != nil {
+		if errors.Is(err, signedtoken.ErrExpiredToken) {
+			return nil, usecasemodels.ErrExpiredVerificationToken
+		}
+
+		return nil, usecasemodels.ErrInvalidVerificationToken
+	}
+
+	var changedUser *repositorymodels.User
+
+	defer func() {
+		entry := &usecasemodels.AuditEntry{
+			Action:       "user.confirm_email_change",
+			ResourceType: "user",
+			Details:      map[string]string{"new_email": pii.MaskEmail(claims.Value)},
+		}
+		if changedUser != nil {
+			entry.ResourceID = changedUser.ID
+		}
+		uc.auditResult(ctx, entry, err)
+	}()
+
+	err = uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		change, err := uc.verifyRepo.GetUserEmailChange(ctx, claims.Subject)
+		if err != nil {
+			return fmt.Errorf("get user email change: %w", err)
+		}
+
+		if change == nil || change.ConfirmedAt != nil || change.NewEmail != claims.Value {
+			return usecasemodels.ErrInvalidVerificationToken
+		}
+
+		user, err := uc.userRepo.GetUserByID(ctx, change.UserID)
+		if err != nil {
+			return fmt.Errorf("get user by id: %w", err)
+		}
+
+		if user == nil {
+			return usecasemodels.ErrUserNotFound
+		}
+
+		if user.Email != change.OldEmail {
+			return usecasemodels.ErrInvalidVerificationToken
+		}
+
+		existingUser, err := uc.userRepo.GetUserByEmail(ctx, change.NewEmail)
+		if err != nil {
+			return fmt.Errorf("get user by email: %w", err)
+		}
+
+		if existingUser != nil {
+			return usecasemodels.ErrUserAlreadyExists
+		}
+
+		if err := uc.userRepo.UpdateUser(ctx, user.ID, &repositorymodels.User{Email: change.NewEmail}); err != nil {
+			return fmt.Errorf("update user: %w", err)
+		}
+
+		if _, err := uc.verifyRepo.SetUserEmailVerified(ctx, user.ID, change.NewEmail); err != nil {
+			return fmt.Errorf("set user email verified: %w", err)
+		}
+
+		if err := uc.verifyRepo.ConfirmUserEmailChange(ctx, change.ID); err != nil {
+			return fmt.Errorf("confirm user email change: %w", err)
+		}
+
+		changedUser, err = uc.userRepo.GetUserByID(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("get changed user: %w", err)
+		}
+
+		return uc.recordUserHistory(ctx, usecasemodels.UserHistoryActionEmailChange, user.ID, user, changedUser)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := uc.userToResponse(ctx, changedUser)
+	return &response, nil
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/tabular"
+)
+
+// userExportColumn описывает колонку экспорта пользователей.
+type userExportColumn struct {
+	key    string
+	titles map[string]string
+	value  func(user *repositorymodels.User) any
+}
+
+// userExportColumns — колонки экспорта в порядке по умолчанию.
+// Подписи совпадают с языками фронтенда (ru, en).
+var userExportColumns = []userExportColumn{
+	{
+		key:    "id",
+		titles: map[string]string{"en": "ID", "ru": "ID"},
+		value:  func(user *repositorymodels.User) any { return user.ID },
+	},
+	{
+		key:    "email",
+		titles: map[string]string{"en": "Email", "ru": "Email"},
+		value:  func(user *repositorymodels.User) any { return user.Email },
+	},
+	{
+		key:    "name",
+		titles: map[string]string{"en": "Name", "ru": "Имя"},
+		value:  func(user *repositorymodels.User) any { return user.Name },
+	},
+	{
+		key:    "phone",
+		titles: map[string]string{"en": "Phone", "ru": "Телефон"},
+		value:  func(user *repositorymodels.User) any { return user.Phone },
+	},
+	{
+		key:    "role",
+		titles: map[string]string{"en": "Role", "ru": "Роль"},
+		value:  func(user *repositorymodels.User) any { return user.Role },
+	},
+	{
+		key:    "status",
+		titles: map[string]string{"en": "Status", "ru": "Статус"},
+		value:  func(user *repositorymodels.User) any { return user.Status },
+	},
+	{
+		key:    "is_email_verified",
+		titles: map[string]string{"en": "Email verified", "ru": "Email подтвержден"},
+		value:  func(user *repositorymodels.User) any { return user.IsEmailVerified },
+	},
+	{
+		key:    "created_at",
+		titles: map[string]string{"en": "Created at", "ru": "Дата создания"},
+		value:  func(user *repositorymodels.User) any { return user.CreatedAt.Format(time.RFC3339) },
+	},
+	{
+		key:    "updated_at",
+		titles: map[string]string{"en": "Updated at", "ru": "Дата обновления"},
+		value:  func(user *repositorymodels.User) any { return user.UpdatedAt.Format(time.RFC3339) },
+	},
+}
+
+// ExportUsers выгружает пользователей в w в формате CSV, NDJSON или XLSX.
+// Email и телефон маскируются так же, как в списке пользователей. Ничего не пишет в w, если запрос невалиден.
+func (uc *UseCase) ExportUsers(ctx context.Context, req *usecasemodels.ExportUsersRequest, w io.Writer) error {
+	if req.Format != tabular.FormatCSV && req.Format != tabular.FormatNDJSON && req.Format != tabular.FormatXLSX {
+		return usecasemodels.ErrorInvalidParameterFormat
+	}
+
+	definitions, err := uc.attrRepo.GetUserAttributeDefinitions(ctx)
+	if err != nil {
+		return fmt.Errorf("get user attribute definitions: %w", err)
+	}
+
+	columns, err := selectUserExportColumns(slices.Concat(userExportColumns, userAttributeExportColumns(definitions)), req.Columns)
+	if err != nil {
+		return err
+	}
+
+	uc.preparePhoneSearch(&req.UserFilter)
+
+	if err := prepareTagFilter(&req.UserFilter); err != nil {
+		return err
+	}
+
+	if err := uc.prepareUserAttributeQuery(ctx, &req.UserFilter, &req.Sort); err != nil {
+		return err
+	}
+
+	// Ограничение формата проверяется до заголовка, чтобы вернуть ошибку, а не оборванный файл.
+	if maxRows := tabular.MaxRows(req.Format); maxRows > 0 {
+		total, err := uc.userRepo.CountUsers(ctx, &req.UserFilter)
+		if err != nil {
+			return fmt.Errorf("count users: %w", err)
+		}
+
+		if total > maxRows {
+			return usecasemodels.ErrExportTooManyRows
+		}
+	}
+
+	lang := req.Lang
+	if lang != "ru" {
+		lang = "en"
+	}
+
+	keys := make([]string, len(columns))
+	titles := make([]string, len(columns))
+	for i, column := range columns {
+		keys[i] = column.key
+		titles[i] = column.titles[lang]
+	}
+
+	writer, err := tabular.NewWriter(w, req.Format)
+	if err != nil {
+		return fmt.Errorf("create writer: %w", err)
+	}
+
+	if err := writer.WriteHeader(keys, titles); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	filter := &usecasemodels.GetUsersRequest{
+		UserFilter: req.UserFilter,
+		Sort:       req.Sort,
+		Order:      req.Order,
+	}
+
+	mask := uc.maskPII(ctx)
+	values := make([]any, len(columns))
+	err = uc.userRepo.StreamUsers(ctx, filter, uc.cfg.Users.ExportBatchSize, func(user *repositorymodels.User) error {
+		if mask {
+			maskUserPII(user)
+		}
+
+		for i, column := range columns {
+			values[i] = column.value(user)
+		}
+
+		return writer.WriteRow(values)
+	})
+	if err != nil {
+		return fmt.Errorf("stream users: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("close writer: %w", err)
+	}
+
+	return nil
+}
+
+// userAttributeExportColumns возвращает колонки экспорта attributes.<key> для описанных атрибутов.
+// Заголовок совпадает с ключом колонки, чтобы выгрузку можно было загрузить обратно импортом.
+func userAttributeExportColumns(definitions []repositorymodels.UserAttributeDefinition) []userExportColumn {
+	columns := make([]userExportColumn, 0, len(definitions))
+	for _, definition := range definitions {
+		key := definition.Key
+		column := usecasemodels.UserAttributeSortPrefix + key
+		columns = append(columns, userExportColumn{
+			key:    column,
+			titles: map[string]string{"en": column, "ru": column},
+			value:  func(user *repositorymodels.User) any { return user.Attributes[key] },
+		})
+	}
+
+	return columns
+}
+
+// selectUserExportColumns возвращает колонки экспорта из available в запрошенном порядке.
+func selectUserExportColumns(available []userExportColumn, keys []string) ([]userExportColumn, error) {
+	if len(keys) == 0 {
+		return available, nil
+	}
+
+	columns := make([]userExportColumn, 0, len(keys))
+	for _, key := range keys {
+		found := false
+		for _, column := range available {
+			if column.key == key {
+				columns = append(columns, column)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w: %s", usecasemodels.ErrorInvalidParameterColumns, key)
+		}
+	}
+
+	return columns, nil
+}
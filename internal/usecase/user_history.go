@@ -0,0 +1,310 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/pii"
+	"adminkaback/pkg/reqctx"
+
+	"github.com/google/uuid"
+)
+
+// revertibleUserFields — поля, которые можно откатить к прошлой версии.
+var revertibleUserFields = []string{"name", "phone", "role", "status", "attributes"}
+
+// GetUserHistory получает версии пользователя, начиная с последней.
+func (uc *UseCase) GetUserHistory(ctx context.Context, id string, page, limit int) (*usecasemodels.GetUserHistoryResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	versions, total, err := uc.historyRepo.GetUserHistory(ctx, id, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get user history: %w", err)
+	}
+
+	if total == 0 {
+		user, err := uc.userRepo.GetUserByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get user by id: %w", err)
+		}
+
+		if user == nil {
+			return nil, usecasemodels.ErrUserNotFound
+		}
+	}
+
+	data := make([]usecasemodels.UserHistoryResponse, 0, len(versions))
+	for i := range versions {
+		data = append(data, uc.userHistoryToResponse(ctx, &versions[i]))
+	}
+
+	return &usecasemodels.GetUserHistoryResponse{
+		Data:       data,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+	}, nil
+}
+
+// RevertUser возвращает поля пользователя к значениям из указанной версии.
+// Откат сам записывается в историю как новая версия.
+func (uc *UseCase) RevertUser(ctx context.Context, id string, version int, req *usecasemodels.RevertUserRequest) (*usecasemodels.UserResponse, error) {
+	fields := req.Fields
+	if len(fields) == 0 {
+		fields = revertibleUserFields
+	}
+
+	for _, field := range fields {
+		if !containsString(revertibleUserFields, field) {
+			return nil, fmt.Errorf("%w: %s", usecasemodels.ErrorInvalidParameterField, field)
+		}
+	}
+
+	history, err := uc.historyRepo.GetUserHistoryVersion(ctx, id, version)
+	if err != nil {
+		return nil, fmt.Errorf("get user history version: %w", err)
+	}
+
+	if history == nil {
+		return nil, usecasemodels.ErrUserVersionNotFound
+	}
+
+	snapshot := history.Snapshot
+	updateReq := &usecasemodels.UpdateUserRequest{}
+	for _, field := range fields {
+		switch field {
+		case "name":
+			updateReq.Name = &snapshot.Name
+		case "phone":
+			// Пустая строка очищает телефон, если в версии его не было.
+			phone := ""
+			if snapshot.Phone != nil {
+				phone = *snapshot.Phone
+			}
+			updateReq.Phone = &phone
+		case "role":
+			updateReq.Role = &snapshot.Role
+		case "status":
+			updateReq.Status = &snapshot.Status
+		case "attributes":
+			attributes, err := uc.revertUserAttributes(ctx, id, snapshot.Attributes)
+			if err != nil {
+				return nil, err
+			}
+			updateReq.Attributes = attributes
+		}
+	}
+
+	return uc.updateUser(ctx, id, updateReq, usecasemodels.UserHistoryActionRevert)
+}
+
+// revertUserAttributes возвращает изменение атрибутов, которое приводит текущие значения к значениям версии.
+// Атрибуты, описание которых с тех пор удалено, пропускаются.
+func (uc *UseCase) revertUserAttributes(ctx context.Context, id string, attributes map[string]any) (map[string]any, error) {
+	user, err := uc.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id: %w", err)
+	}
+
+	if user == nil {
+		return nil, usecasemodels.ErrUserNotFound
+	}
+
+	definitions, err := uc.userAttributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := make(map[string]any)
+	for key := range user.Attributes {
+		if _, ok := attributes[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, value := range attributes {
+		if _, ok := definitions[key]; ok {
+			patch[key] = value
+		}
+	}
+
+	return patch, nil
+}
+
+// recordUserHistory сохраняет новую версию пользователя с диффом относительно before.
+// before равен nil при создании. Изменение без фактической разницы в полях не записывается.
+func (uc *UseCase) recordUserHistory(ctx context.Context, action string, userID string, before *repositorymodels.User, after *repositorymodels.User) error {
+	afterSnapshot := userSnapshot(after)
+
+	var changes map[string]repositorymodels.UserFieldChange
+	if before == nil {
+		changes = diffUserSnapshots(&repositorymodels.UserSnapshot{}, &afterSnapshot)
+	} else {
+		beforeSnapshot := userSnapshot(before)
+		changes = diffUserSnapshots(&beforeSnapshot, &afterSnapshot)
+	}
+
+	if len(changes) == 0 && action != usecasemodels.UserHistoryActionCreate {
+		return nil
+	}
+
+	info := reqctx.From(ctx)
+	history := &repositorymodels.UserHistory{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Action:    action,
+		AdminID:   optionalString(info.AdminID),
+		RequestID: optionalString(info.RequestID),
+		Changes:   changes,
+		Snapshot:  afterSnapshot,
+		CreatedAt: time.Now(),
+	}
+
+	if err := uc.historyRepo.CreateUserHistory(ctx, history); err != nil {
+		return fmt.Errorf("create user history: %w", err)
+	}
+
+	return nil
+}
+
+// userSnapshot возвращает отслеживаемые поля пользователя.
+func userSnapshot(user *repositorymodels.User) repositorymodels.UserSnapshot {
+	return repositorymodels.UserSnapshot{
+		Email:           user.Email,
+		Name:            user.Name,
+		Phone:           user.Phone,
+		Role:            user.Role,
+		Status:          user.Status,
+		IsEmailVerified: user.IsEmailVerified,
+		Attributes:      user.Attributes,
+		DeletedAt:       user.DeletedAt,
+	}
+}
+
+// diffUserSnapshots возвращает изменившиеся поля между двумя состояниями.
+func diffUserSnapshots(before, after *repositorymodels.UserSnapshot) map[string]repositorymodels.UserFieldChange {
+	changes := make(map[string]repositorymodels.UserFieldChange)
+
+	if before.Email != after.Email {
+		changes["email"] = repositorymodels.UserFieldChange{Old: before.Email, New: after.Email}
+	}
+	if before.Name != after.Name {
+		changes["name"] = repositorymodels.UserFieldChange{Old: before.Name, New: after.Name}
+	}
+	if stringValue(before.Phone) != stringValue(after.Phone) {
+		changes["phone"] = repositorymodels.UserFieldChange{Old: before.Phone, New: after.Phone}
+	}
+	if before.Role != after.Role {
+		changes["role"] = repositorymodels.UserFieldChange{Old: before.Role, New: after.Role}
+	}
+	if before.Status != after.Status {
+		changes["status"] = repositorymodels.UserFieldChange{Old: before.Status, New: after.Status}
+	}
+	if before.IsEmailVerified != after.IsEmailVerified {
+		changes["is_email_verified"] = repositorymodels.UserFieldChange{Old: before.IsEmailVerified, New: after.IsEmailVerified}
+	}
+	// Атрибуты сравниваются по ключам: в дифф попадает attributes.<key>.
+	for key, value := range after.Attributes {
+		if old, ok := before.Attributes[key]; !ok || old != value {
+			changes[usecasemodels.UserAttributeSortPrefix+key] = repositorymodels.UserFieldChange{Old: before.Attributes[key], New: value}
+		}
+	}
+	for key, value := range before.Attributes {
+		if _, ok := after.Attributes[key]; !ok {
+			changes[usecasemodels.UserAttributeSortPrefix+key] = repositorymodels.UserFieldChange{Old: value, New: nil}
+		}
+	}
+	if (before.DeletedAt == nil) != (after.DeletedAt == nil) {
+		changes["deleted_at"] = repositorymodels.UserFieldChange{Old: before.DeletedAt, New: after.DeletedAt}
+	}
+
+	return changes
+}
+
+// userHistoryToResponse преобразует версию пользователя в ответ. Email и телефон в диффе
+// и снимке маскируются так же, как в userToResponse.
+func (uc *UseCase) userHistoryToResponse(ctx context.Context, history *repositorymodels.UserHistory) usecasemodels.UserHistoryResponse {
+	mask := uc.maskPII(ctx)
+
+	changes := make(map[string]usecasemodels.UserFieldChange, len(history.Changes))
+	for field, change := range history.Changes {
+		if mask {
+			change.Old = maskPIIChange(field, change.Old)
+			change.New = maskPIIChange(field, change.New)
+		}
+		changes[field] = usecasemodels.UserFieldChange{Old: change.Old, New: change.New}
+	}
+
+	snapshot := usecasemodels.UserSnapshotResponse{
+		Email:           history.Snapshot.Email,
+		Name:            history.Snapshot.Name,
+		Phone:           history.Snapshot.Phone,
+		Role:            history.Snapshot.Role,
+		Status:          history.Snapshot.Status,
+		IsEmailVerified: history.Snapshot.IsEmailVerified,
+		Attributes:      history.Snapshot.Attributes,
+	}
+	if history.Snapshot.DeletedAt != nil {
+		deletedAt := history.Snapshot.DeletedAt.Format(time.RFC3339)
+		snapshot.DeletedAt = &deletedAt
+	}
+	if mask {
+		snapshot.Email = pii.MaskEmail(snapshot.Email)
+		if snapshot.Phone != nil {
+			phone := pii.MaskPhone(*snapshot.Phone)
+			snapshot.Phone = &phone
+		}
+	}
+
+	return usecasemodels.UserHistoryResponse{
+		Version:    history.Version,
+		Action:     history.Action,
+		AdminID:    history.AdminID,
+		RequestID:  history.RequestID,
+		Changes:    changes,
+		Snapshot:   snapshot,
+		MergedFrom: history.MergedFrom,
+		CreatedAt:  history.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// optionalString возвращает nil для пустой строки.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+// stringValue возвращает значение указателя или пустую строку.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+// containsString проверяет, содержится ли значение в списке.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
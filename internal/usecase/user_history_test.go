@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+)
+
+func TestDiffUserSnapshots(t *testing.T) {
+	phone := "+79123456789"
+	otherPhone := "+79000000000"
+	empty := ""
+	deletedAt := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	base := repositorymodels.UserSnapshot{
+		Email:      "user@example.com",
+		Name:       "User",
+		Phone:      &phone,
+		Role:       "user",
+		Status:     "active",
+		Attributes: map[string]any{"city": "Moscow", "age": float64(30)},
+	}
+
+	with := func(change func(s *repositorymodels.UserSnapshot)) *repositorymodels.UserSnapshot {
+		s := base
+		s.Attributes = map[string]any{}
+		for key, value := range base.Attributes {
+			s.Attributes[key] = value
+		}
+		change(&s)
+
+		return &s
+	}
+
+	tests := []struct {
+		name  string
+		after *repositorymodels.UserSnapshot
+		want  map[string]repositorymodels.UserFieldChange
+	}{
+		{
+			name:  "no changes",
+			after: with(func(*repositorymodels.UserSnapshot) {}),
+			want:  map[string]repositorymodels.UserFieldChange{},
+		},
+		{
+			name: "profile fields",
+			after: with(func(s *repositorymodels.UserSnapshot) {
+				s.Email = "new@example.com"
+				s.Name = "New"
+				s.Role = "admin"
+				s.Status = "inactive"
+				s.IsEmailVerified = true
+			}),
+			want: map[string]repositorymodels.UserFieldChange{
+				"email":             {Old: "user@example.com", New: "new@example.com"},
+				"name":              {Old: "User", New: "New"},
+				"role":              {Old: "user", New: "admin"},
+				"status":            {Old: "active", New: "inactive"},
+				"is_email_verified": {Old: false, New: true},
+			},
+		},
+		{
+			name:  "phone changed",
+			after: with(func(s *repositorymodels.UserSnapshot) { s.Phone = &otherPhone }),
+			want: map[string]repositorymodels.UserFieldChange{
+				"phone": {Old: &phone, New: &otherPhone},
+			},
+		},
+		{
+			name:  "phone cleared",
+			after: with(func(s *repositorymodels.UserSnapshot) { s.Phone = nil }),
+			want: map[string]repositorymodels.UserFieldChange{
+				"phone": {Old: &phone, New: (*string)(nil)},
+			},
+		},
+		{
+			name:  "phone set to empty",
+			after: with(func(s *repositorymodels.UserSnapshot) { s.Phone = &empty }),
+			want: map[string]repositorymodels.UserFieldChange{
+				"phone": {Old: &phone, New: &empty},
+			},
+		},
+		{
+			name: "attributes added, changed and removed",
+			after: with(func(s *repositorymodels.UserSnapshot) {
+				s.Attributes["age"] = float64(31)
+				s.Attributes["vip"] = true
+				delete(s.Attributes, "city")
+			}),
+			want: map[string]repositorymodels.UserFieldChange{
+				"attributes.age":  {Old: float64(30), New: float64(31)},
+				"attributes.vip":  {Old: nil, New: true},
+				"attributes.city": {Old: "Moscow", New: nil},
+			},
+		},
+		{
+			name:  "deleted",
+			after: with(func(s *repositorymodels.UserSnapshot) { s.DeletedAt = &deletedAt }),
+			want: map[string]repositorymodels.UserFieldChange{
+				"deleted_at": {Old: (*time.Time)(nil), New: &deletedAt},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffUserSnapshots(&base, tt.after)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("diffUserSnapshots() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffUserSnapshotsNilAndEmptyPhone(t *testing.T) {
+	empty := ""
+	before := &repositorymodels.UserSnapshot{Email: "user@example.com"}
+	after := &repositorymodels.UserSnapshot{Email: "user@example.com", Phone: &empty}
+
+	if got := diffUserSnapshots(before, after); len(got) != 0 {
+		t.Fatalf("diffUserSnapshots() = %+v, want no changes", got)
+	}
+}
@@ -0,0 +1,532 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/tabular"
+
+	"github.com/google/uuid"
+)
+
+// importProgressStep определяет, как часто сохраняется прогресс фоновой задачи импорта.
+const importProgressStep = 100
+
+// importFields — поля пользователя, которые можно загрузить из файла.
+var importFields = []string{"email", "name", "phone", "role", "status"}
+
+// ImportUsers импортирует пользователей из CSV или XLSX файла.
+// Небольшие файлы обрабатываются сразу, большие — в фоновой задаче,
+// прогресс которой доступен через GetUserImportJob.
+func (uc *UseCase) ImportUsers(ctx context.Context, req *usecasemodels.ImportUsersRequest) (*usecasemodels.UserImportJobResponse, error) {
+	if req.Format == "" {
+		req.Format = tabular.FormatFromFilename(req.Filename)
+	}
+	if req.Format != tabular.FormatCSV && req.Format != tabular.FormatXLSX {
+		return nil, usecasemodels.ErrorInvalidParameterFormat
+	}
+
+	if req.OnDuplicate == "" {
+		req.OnDuplicate = usecasemodels.ImportOnDuplicateSkip
+	}
+	switch req.OnDuplicate {
+	case usecasemodels.ImportOnDuplicateSkip, usecasemodels.ImportOnDuplicateUpdate, usecasemodels.ImportOnDuplicateFail:
+	default:
+		return nil, usecasemodels.ErrorInvalidParameterOnDuplicate
+	}
+
+	rows, err := tabular.ReadAll(bytes.NewReader(req.Data), req.Format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", usecasemodels.ErrorInvalidParameterFormat, err)
+	}
+
+	if len(rows) < 2 {
+		return nil, usecasemodels.ErrImportFileEmpty
+	}
+
+	columns, err := resolveImportColumns(rows[0], req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions, err := uc.userAttributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for column := range columns {
+		if key, ok := strings.CutPrefix(column, usecasemodels.UserAttributeSortPrefix); ok {
+			if _, ok := definitions[key]; !ok {
+				return nil, fmt.Errorf("%w: unknown attribute column %s", usecasemodels.ErrorInvalidParameterMapping, column)
+			}
+		}
+	}
+
+	now := time.Now()
+	job := &repositorymodels.UserImportJob{
+		ID:          uuid.New().String(),
+		Filename:    req.Filename,
+		Format:      req.Format,
+		Status:      usecasemodels.ImportStatusPending,
+		OnDuplicate: req.OnDuplicate,
+		DryRun:      req.DryRun,
+		TotalRows:   len(rows) - 1,
+		RowErrors:   []repositorymodels.UserImportRowError{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if req.AdminID != "" {
+		job.AdminID = &req.AdminID
+	}
+
+	if err := uc.importRepo.CreateUserImportJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("create user import job: %w", err)
+	}
+
+	if job.TotalRows > uc.cfg.Users.ImportSyncMaxRows {
+		// Ответ формируется до запуска обработки: дальше задачу меняет только фоновая горутина.
+		response := uc.userImportJobToResponse(job)
+
+		// Фоновая задача не должна зависеть от времени жизни HTTP запроса.
+		go uc.runUserImport(context.WithoutCancel(ctx), job, rows[1:], columns, definitions)
+
+		return response, nil
+	}
+
+	uc.runUserImport(ctx, job, rows[1:], columns, definitions)
+
+	return uc.userImportJobToResponse(job), nil
+}
+
+// GetUserImportJob возвращает состояние задачи импорта.
+func (uc *UseCase) GetUserImportJob(ctx context.Context, id string) (*usecasemodels.UserImportJobResponse, error) {
+	job, err := uc.importRepo.GetUserImportJob(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get user import job: %w", err)
+	}
+
+	if job == nil {
+		return nil, usecasemodels.ErrImportJobNotFound
+	}
+
+	return uc.userImportJobToResponse(job), nil
+}
+
+// FailInterruptedUserImports переводит в статус failed задачи импорта, которые не были завершены
+// до остановки сервиса: фоновая обработка живет только в памяти процесса и после перезапуска не продолжается.
+// Вызывается при запуске сервиса.
+func (uc *UseCase) FailInterruptedUserImports(ctx context.Context) error {
+	count, err := uc.importRepo.FailUnfinishedUserImportJobs(ctx, "import interrupted by service restart")
+	if err != nil {
+		return fmt.Errorf("fail unfinished user import jobs: %w", err)
+	}
+
+	if count > 0 {
+		log.Printf("Marked %d interrupted user import jobs as failed", count)
+	}
+
+	return nil
+}
+
+// runUserImport обрабатывает строки файла и сохраняет результат в задаче импорта.
+func (uc *UseCase) runUserImport(
+	ctx context.Context,
+	job *repositorymodels.UserImportJob,
+	rows [][]string,
+	columns map[string]int,
+	definitions map[string]*repositorymodels.UserAttributeDefinition,
+) {
+	job.Status = usecasemodels.ImportStatusRunning
+	if err := uc.importRepo.UpdateUserImportJob(ctx, job); err != nil {
+		log.Printf("Failed to update user import job %s: %v", job.ID, err)
+	}
+
+	seenEmails := make(map[string]int, len(rows))
+	for i, row := range rows {
+		// Номер строки в файле с учетом заголовка.
+		rowNumber := i + 2
+
+		if err := uc.importUserRow(ctx, job, row, rowNumber, columns, definitions, seenEmails); err != nil {
+			message := err.Error()
+			finishedAt := time.Now()
+			job.Status = usecasemodels.ImportStatusFailed
+			job.ErrorMessage = &message
+			job.FinishedAt = &finishedAt
+
+			if err := uc.importRepo.UpdateUserImportJob(ctx, job); err != nil {
+				log.Printf("Failed to update user import job %s: %v", job.ID, err)
+			}
+
+			return
+		}
+
+		job.ProcessedRows++
+		if job.ProcessedRows%importProgressStep == 0 {
+			if err := uc.importRepo.UpdateUserImportJob(ctx, job); err != nil {
+				log.Printf("Failed to update user import job %s: %v", job.ID, err)
+			}
+		}
+	}
+
+	finishedAt := time.Now()
+	job.Status = usecasemodels.ImportStatusCompleted
+	job.FinishedAt = &finishedAt
+
+	if err := uc.importRepo.UpdateUserImportJob(ctx, job); err != nil {
+		log.Printf("Failed to update user import job %s: %v", job.ID, err)
+	}
+}
+
+// importUserRow импортирует одну строку файла. Ошибки данных записываются в отчет задачи,
+// возвращаются только ошибки, из-за которых импорт продолжать нельзя.
+func (uc *UseCase) importUserRow(
+	ctx context.Context,
+	job *repositorymodels.UserImportJob,
+	row []string,
+	rowNumber int,
+	columns map[string]int,
+	definitions map[string]*repositorymodels.UserAttributeDefinition,
+	seenEmails map[string]int,
+) error {
+	req := importRowToRequest(row, columns)
+
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		addImportRowError(job, rowNumber, "email", req.Email, err.Error())
+
+		return nil
+	}
+	req.Email = email
+
+	if firstRow, ok := seenEmails[req.Email]; ok {
+		addImportRowError(job, rowNumber, "email", req.Email, fmt.Sprintf("duplicate email, first seen in row %d", firstRow))
+
+		return nil
+	}
+	seenEmails[req.Email] = rowNumber
+
+	existingUser, err := uc.userRepo.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		return fmt.Errorf("get user by email: %w", err)
+	}
+
+	if existingUser != nil && job.OnDuplicate == usecasemodels.ImportOnDuplicateUpdate {
+		return uc.importUpdateUserRow(ctx, job, existingUser, req, rowNumber, definitions)
+	}
+
+	// Значения по умолчанию применяются только при создании пользователя.
+	if req.Role == "" {
+		req.Role = "user"
+	}
+	if err := uc.validateCreateUserRequest(req); err != nil {
+		field, value := importErrorField(err, req)
+		addImportRowError(job, rowNumber, field, value, err.Error())
+
+		return nil
+	}
+
+	if existingUser != nil {
+		switch job.OnDuplicate {
+		case usecasemodels.ImportOnDuplicateSkip:
+			job.SkippedCount++
+		case usecasemodels.ImportOnDuplicateFail:
+			addImportRowError(job, rowNumber, "email", req.Email, usecasemodels.ErrUserAlreadyExists.Error())
+		}
+
+		return nil
+	}
+
+	attributes, err := mergeUserAttributes(definitions, nil, req.Attributes, true)
+	if err != nil {
+		field, value := importErrorField(err, req)
+		addImportRowError(job, rowNumber, field, value, err.Error())
+
+		return nil
+	}
+
+	if !job.DryRun {
+		now := time.Now()
+		user := &repositorymodels.User{
+			ID:              uuid.New().String(),
+			Email:           req.Email,
+			Name:            req.Name,
+			Phone:           req.Phone,
+			Role:            req.Role,
+			Status:          req.Status,
+			IsEmailVerified: false,
+			Attributes:      attributes,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		err := uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+			if err := uc.userRepo.CreateUser(ctx, user); err != nil {
+				return fmt.Errorf("create user: %w", err)
+			}
+
+			return uc.recordUserHistory(ctx, usecasemodels.UserHistoryActionCreate, user.ID, nil, user)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	job.CreatedCount++
+
+	return nil
+}
+
+// importUpdateUserRow обновляет существующего пользователя по строке файла.
+// Меняются только поля из непустых ячеек: отсутствующая или пустая колонка оставляет значение как есть.
+func (uc *UseCase) importUpdateUserRow(
+	ctx context.Context,
+	job *repositorymodels.UserImportJob,
+	existingUser *repositorymodels.User,
+	req *usecasemodels.CreateUserRequest,
+	rowNumber int,
+	definitions map[string]*repositorymodels.UserAttributeDefinition,
+) error {
+	update := &usecasemodels.UpdateUserRequest{
+		Name:   optionalString(req.Name),
+		Phone:  req.Phone,
+		Role:   optionalString(req.Role),
+		Status: optionalString(req.Status),
+	}
+	if err := uc.validateUpdateUserRequest(update); err != nil {
+		field, value := importErrorField(err, req)
+		addImportRowError(job, rowNumber, field, value, err.Error())
+
+		return nil
+	}
+
+	var statusChange *userStatusChange
+	if update.Status != nil {
+		var err error
+		statusChange, err = uc.checkStatusTransition(ctx, existingUser, *update.Status)
+		if err != nil {
+			addImportRowError(job, rowNumber, "status", req.Status, err.Error())
+
+			return nil
+		}
+	}
+
+	// У существующего пользователя меняются только атрибуты из файла.
+	var attributes map[string]any
+	if req.Attributes != nil {
+		var err error
+		attributes, err = mergeUserAttributes(definitions, existingUser.Attributes, req.Attributes, false)
+		if err != nil {
+			field, value := importErrorField(err, req)
+			addImportRowError(job, rowNumber, field, value, err.Error())
+
+			return nil
+		}
+	}
+
+	if !job.DryRun {
+		updateUser := &repositorymodels.User{
+			Name:       stringValue(update.Name),
+			Phone:      update.Phone,
+			Role:       stringValue(update.Role),
+			Status:     stringValue(update.Status),
+			Attributes: attributes,
+		}
+
+		err := uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+			if existingUser.Status == "banned" && update.Status != nil && *update.Status != "banned" {
+				if err := uc.liftActiveUserBan(ctx, existingUser.ID, "status changed to "+*update.Status); err != nil {
+					return err
+				}
+			}
+
+			if err := uc.userRepo.UpdateUser(ctx, existingUser.ID, updateUser); err != nil {
+				return fmt.Errorf("update user: %w", err)
+			}
+
+			updatedUser, err := uc.userRepo.GetUserByID(ctx, existingUser.ID)
+			if err != nil {
+				return fmt.Errorf("get updated user: %w", err)
+			}
+
+			return uc.recordUserHistory(ctx, usecasemodels.UserHistoryActionUpdate, existingUser.ID, existingUser, updatedUser)
+		})
+		if err != nil {
+			return err
+		}
+
+		uc.runStatusHooks(ctx, []*userStatusChange{statusChange})
+	}
+	job.UpdatedCount++
+
+	return nil
+}
+
+// resolveImportColumns сопоставляет поля пользователя с индексами колонок файла.
+func resolveImportColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns := make(map[string]int, len(importFields))
+	for _, field := range importFields {
+		name := field
+		if mapped, ok := mapping[field]; ok && mapped != "" {
+			name = mapped
+		}
+
+		if idx, ok := positions[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[field] = idx
+		}
+	}
+
+	// Колонки атрибутов: заголовок attributes.<key> или сопоставление attributes.<key> в mapping.
+	for name, idx := range positions {
+		if strings.HasPrefix(name, usecasemodels.UserAttributeSortPrefix) {
+			columns[name] = idx
+		}
+	}
+	for field, mapped := range mapping {
+		if !strings.HasPrefix(field, usecasemodels.UserAttributeSortPrefix) {
+			continue
+		}
+
+		idx, ok := positions[strings.ToLower(strings.TrimSpace(mapped))]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s column not found", usecasemodels.ErrorInvalidParameterMapping, field)
+		}
+		columns[field] = idx
+	}
+
+	if _, ok := columns["email"]; !ok {
+		return nil, fmt.Errorf("%w: email column not found", usecasemodels.ErrorInvalidParameterMapping)
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("%w: name column not found", usecasemodels.ErrorInvalidParameterMapping)
+	}
+
+	return columns, nil
+}
+
+// importRowToRequest собирает запрос на создание пользователя из строки файла.
+// Пустые ячейки остаются пустыми: значения по умолчанию подставляются только при создании.
+func importRowToRequest(row []string, columns map[string]int) *usecasemodels.CreateUserRequest {
+	value := func(field string) string {
+		idx, ok := columns[field]
+		if !ok || idx >= len(row) {
+			return ""
+		}
+
+		return strings.TrimSpace(row[idx])
+	}
+
+	req := &usecasemodels.CreateUserRequest{
+		Email:  value("email"),
+		Name:   value("name"),
+		Role:   value("role"),
+		Status: value("status"),
+	}
+
+	if phone := value("phone"); phone != "" {
+		req.Phone = &phone
+	}
+	// Пустая ячейка атрибута означает, что значение не задано.
+	for column := range columns {
+		if key, ok := strings.CutPrefix(column, usecasemodels.UserAttributeSortPrefix); ok {
+			if v := value(column); v != "" {
+				if req.Attributes == nil {
+					req.Attributes = make(map[string]any)
+				}
+				req.Attributes[key] = v
+			}
+		}
+	}
+
+	return req
+}
+
+// importErrorField возвращает поле и значение, к которым относится ошибка валидации.
+func importErrorField(err error, req *usecasemodels.CreateUserRequest) (string, string) {
+	switch {
+	case errors.Is(err, usecasemodels.ErrorInvalidParameterEmail):
+		return "email", req.Email
+	case errors.Is(err, usecasemodels.ErrorInvalidParameterName):
+		return "name", req.Name
+	case errors.Is(err, usecasemodels.ErrorInvalidParameterPhone):
+		return "phone", stringValue(req.Phone)
+	case errors.Is(err, usecasemodels.ErrorInvalidParameterRole):
+		return "role", req.Role
+	case errors.Is(err, usecasemodels.ErrorInvalidParameterStatus),
+		errors.Is(err, usecasemodels.ErrStatusRequiresBan):
+		return "status", req.Status
+	}
+
+	var attributeErr *usecasemodels.UserAttributeError
+	if errors.As(err, &attributeErr) {
+		value, _ := req.Attributes[attributeErr.Key].(string)
+
+		return usecasemodels.UserAttributeSortPrefix + attributeErr.Key, value
+	}
+
+	return "", ""
+}
+
+// addImportRowError добавляет ошибку строки в отчет задачи импорта.
+func addImportRowError(job *repositorymodels.UserImportJob, row int, field, value, message string) {
+	job.ErrorCount++
+	job.RowErrors = append(job.RowErrors, repositorymodels.UserImportRowError{
+		Row:     row,
+		Field:   field,
+		Value:   value,
+		Message: message,
+	})
+}
+
+// userImportJobToResponse преобразует задачу импорта в ответ.
+func (uc *UseCase) userImportJobToResponse(job *repositorymodels.UserImportJob) *usecasemodels.UserImportJobResponse {
+	progress := 100
+	if job.TotalRows > 0 {
+		progress = job.ProcessedRows * 100 / job.TotalRows
+	}
+
+	rowErrors := make([]usecasemodels.ImportRowError, 0, len(job.RowErrors))
+	for _, rowErr := range job.RowErrors {
+		rowErrors = append(rowErrors, usecasemodels.ImportRowError{
+			Row:     rowErr.Row,
+			Field:   rowErr.Field,
+			Value:   rowErr.Value,
+			Message: rowErr.Message,
+		})
+	}
+
+	response := &usecasemodels.UserImportJobResponse{
+		ID:            job.ID,
+		Filename:      job.Filename,
+		Format:        job.Format,
+		Status:        job.Status,
+		OnDuplicate:   job.OnDuplicate,
+		DryRun:        job.DryRun,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		Progress:      progress,
+		Created:       job.CreatedCount,
+		Updated:       job.UpdatedCount,
+		Skipped:       job.SkippedCount,
+		Failed:        job.ErrorCount,
+		Errors:        rowErrors,
+		ErrorMessage:  job.ErrorMessage,
+		CreatedAt:     job.CreatedAt.Format(time.RFC3339),
+	}
+
+	if job.FinishedAt != nil {
+		finishedAt := job.FinishedAt.Format(time.RFC3339)
+		response.FinishedAt = &finishedAt
+	}
+
+	return response
+}
@@ -0,0 +1,537 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/reqctx"
+
+	"github.com/google/uuid"
+)
+
+// userNoteBodyMaxLength — максимальная длина текста заметки в символах.
+const userNoteBodyMaxLength = 10000
+
+// GetUserNotes получает ветки заметок о пользователе, доступные текущему администратору.
+// Закрепленные ветки идут первыми, остальные — от новых к старым; ответы — в порядке создания.
+// Удаленная заметка остается в выдаче без текста, если на нее есть неудаленные ответы.
+func (uc *UseCase) GetUserNotes(ctx context.Context, userID string) ([]usecasemodels.UserNoteResponse, error) {
+	if err := uc.checkUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	notes, err := uc.noteRepo.GetUserNotes(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user notes: %w", err)
+	}
+
+	info := reqctx.From(ctx)
+	replies := make(map[string][]usecasemodels.UserNoteResponse)
+	for i := range notes {
+		note := &notes[i]
+		if note.ParentID != nil && note.DeletedAt == nil {
+			replies[*note.ParentID] = append(replies[*note.ParentID], userNoteToResponse(note))
+		}
+	}
+
+	var roots []*repositorymodels.UserNote
+	for i := range notes {
+		note := &notes[i]
+		if note.ParentID != nil || !uc.canViewUserNote(info.AdminID, info.AdminRole, note) {
+			continue
+		}
+		if note.DeletedAt != nil && len(replies[note.ID]) == 0 {
+			continue
+		}
+
+		roots = append(roots, note)
+	}
+
+	sort.SliceStable(roots, func(i, j int) bool {
+		if (roots[i].PinnedAt == nil) != (roots[j].PinnedAt == nil) {
+			return roots[i].PinnedAt != nil
+		}
+		if roots[i].PinnedAt != nil && !roots[i].PinnedAt.Equal(*roots[j].PinnedAt) {
+			return roots[i].PinnedAt.After(*roots[j].PinnedAt)
+		}
+
+		return roots[i].CreatedAt.After(roots[j].CreatedAt)
+	})
+
+	data := make([]usecasemodels.UserNoteResponse, 0, len(roots))
+	for _, root := range roots {
+		response := userNoteToResponse(root)
+		response.Replies = replies[root.ID]
+		data = append(data, response)
+	}
+
+	return data, nil
+}
+
+// CreateUserNote создает заметку о пользователе или ответ на заметку.
+// Ответ на ответ прикрепляется к корневой заметке ветки. Упомянутым администраторам отправляется письмо.
+func (uc *UseCase) CreateUserNote(ctx context.Context, userID string, req *usecasemodels.CreateUserNoteRequest) (*usecasemodels.UserNoteResponse, error) {
+	body, err := validateUserNoteBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := normalizeUUIDs(req.Mentions, usecasemodels.ErrorInvalidParameterMentions)
+	if err != nil {
+		return nil, err
+	}
+
+	info := reqctx.From(ctx)
+	now := time.Now()
+	note := &repositorymodels.UserNote{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		AuthorID:       optionalString(info.AdminID),
+		Body:           body,
+		VisibleToRoles: normalizeUserNoteRoles(req.VisibleToRoles),
+		Mentions:       mentions,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	var mentioned []repositorymodels.Admin
+
+	err = uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkUserExists(ctx, userID); err != nil {
+			return err
+		}
+
+		root := note
+		if req.ParentID != nil {
+			if len(req.VisibleToRoles) > 0 {
+				return usecasemodels.ErrorInvalidParameterVisibility
+			}
+
+			if _, err := uuid.Parse(*req.ParentID); err != nil {
+				return usecasemodels.ErrorInvalidParameterParent
+			}
+
+			parent, parentRoot, err := uc.getVisibleUserNote(ctx, userID, *req.ParentID)
+			if err != nil {
+				return usecasemodels.ErrorInvalidParameterParent
+			}
+
+			parentID := parent.ID
+			if parent.ParentID != nil {
+				parentID = *parent.ParentID
+			}
+			note.ParentID = &parentID
+			root = parentRoot
+		}
+
+		mentioned, err = uc.resolveUserNoteMentions(ctx, root, mentions)
+		if err != nil {
+			return err
+		}
+
+		if err := uc.noteRepo.CreateUserNote(ctx, note); err != nil {
+			return fmt.Errorf("create user note: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.notifyUserNoteMentions(ctx, note, mentioned)
+
+	response := userNoteToResponse(note)
+	return &response, nil
+}
+
+// UpdateUserNote изменяет текст, упоминания или видимость заметки. Изменять заметку может только автор.
+// Предыдущий текст сохраняется в истории правок; письмо получают только вновь упомянутые администраторы.
+func (uc *UseCase) UpdateUserNote(ctx context.Context, userID, id string, req *usecasemodels.UpdateUserNoteRequest) (*usecasemodels.UserNoteResponse, error) {
+	var body string
+	if req.Body != nil {
+		var err error
+		body, err = validateUserNoteBody(*req.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mentions, err := normalizeUUIDs(req.Mentions, usecasemodels.ErrorInvalidParameterMentions)
+	if err != nil {
+		return nil, err
+	}
+
+	info := reqctx.From(ctx)
+
+	var note *repositorymodels.UserNote
+	var mentioned []repositorymodels.Admin
+
+	err = uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var root *repositorymodels.UserNote
+		note, root, err = uc.getVisibleUserNote(ctx, userID, id)
+		if err != nil {
+			return err
+		}
+
+		if stringValue(note.AuthorID) != info.AdminID {
+			return usecasemodels.ErrUserNoteForbidden
+		}
+
+		revision := &repositorymodels.UserNoteRevision{
+			ID:       uuid.New().String(),
+			NoteID:   note.ID,
+			Body:     note.Body,
+			Mentions: note.Mentions,
+			EditedBy: optionalString(info.AdminID),
+		}
+
+		if req.VisibleToRoles != nil {
+			if note.ParentID != nil {
+				return usecasemodels.ErrorInvalidParameterVisibility
+			}
+			note.VisibleToRoles = normalizeUserNoteRoles(req.VisibleToRoles)
+		}
+
+		changed := false
+		if req.Body != nil && body != note.Body {
+			note.Body = body
+			changed = true
+		}
+
+		if req.Mentions != nil {
+			added := make([]string, 0, len(mentions))
+			for _, adminID := range mentions {
+				if !containsString(note.Mentions, adminID) {
+					added = append(added, adminID)
+				}
+			}
+
+			// Все упоминания проверяются заново: после смены видимости администратор мог потерять доступ.
+			admins, err := uc.resolveUserNoteMentions(ctx, root, mentions)
+			if err != nil {
+				return err
+			}
+			for _, admin := range admins {
+				if containsString(added, admin.ID) {
+					mentioned = append(mentioned, admin)
+				}
+			}
+
+			if !slices.Equal(mentions, note.Mentions) {
+				note.Mentions = mentions
+				changed = true
+			}
+		}
+
+		if changed {
+			now := time.Now()
+			revision.CreatedAt = now
+			note.EditedAt = &now
+
+			if err := uc.noteRepo.CreateUserNoteRevision(ctx, revision); err != nil {
+				return fmt.Errorf("create user note revision: %w", err)
+			}
+		}
+
+		if err := uc.noteRepo.UpdateUserNote(ctx, note); err != nil {
+			return fmt.Errorf("update user note: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.notifyUserNoteMentions(ctx, note, mentioned)
+
+	response := userNoteToResponse(note)
+	return &response, nil
+}
+
+// DeleteUserNote помечает заметку удаленной. Удалить заметку может автор или модератор.
+func (uc *UseCase) DeleteUserNote(ctx context.Context, userID, id string) error {
+	info := reqctx.From(ctx)
+
+	return uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		note, _, err := uc.getVisibleUserNote(ctx, userID, id)
+		if err != nil {
+			return err
+		}
+
+		if !uc.canManageUserNote(info.AdminID, info.AdminRole, note) {
+			return usecasemodels.ErrUserNoteForbidden
+		}
+
+		now := time.Now()
+		note.DeletedAt = &now
+		note.DeletedBy = optionalString(info.AdminID)
+		note.PinnedAt = nil
+		note.PinnedBy = nil
+
+		if err := uc.noteRepo.UpdateUserNote(ctx, note); err != nil {
+			return fmt.Errorf("update user note: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// PinUserNote закрепляет или открепляет корневую заметку. Закреплять заметку может автор или модератор.
+func (uc *UseCase) PinUserNote(ctx context.Context, userID, id string, pinned bool) (*usecasemodels.UserNoteResponse, error) {
+	info := reqctx.From(ctx)
+
+	var note *repositorymodels.UserNote
+
+	err := uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		note, _, err = uc.getVisibleUserNote(ctx, userID, id)
+		if err != nil {
+			return err
+		}
+
+		if note.ParentID != nil {
+			return usecasemodels.ErrUserNoteReplyPin
+		}
+
+		if !uc.canManageUserNote(info.AdminID, info.AdminRole, note) {
+			return usecasemodels.ErrUserNoteForbidden
+		}
+
+		if pinned == (note.PinnedAt != nil) {
+			return nil
+		}
+
+		if pinned {
+			now := time.Now()
+			note.PinnedAt = &now
+			note.PinnedBy = optionalString(info.AdminID)
+		} else {
+			note.PinnedAt = nil
+			note.PinnedBy = nil
+		}
+
+		if err := uc.noteRepo.UpdateUserNote(ctx, note); err != nil {
+			return fmt.Errorf("update user note: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := userNoteToResponse(note)
+	return &response, nil
+}
+
+// GetUserNoteRevisions получает историю правок заметки, начиная с последней.
+func (uc *UseCase) GetUserNoteRevisions(ctx context.Context, userID, id string) ([]usecasemodels.UserNoteRevisionResponse, error) {
+	note, _, err := uc.getVisibleUserNote(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := uc.noteRepo.GetUserNoteRevisions(ctx, note.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get user note revisions: %w", err)
+	}
+
+	data := make([]usecasemodels.UserNoteRevisionResponse, 0, len(revisions))
+	for i := range revisions {
+		data = append(data, userNoteRevisionToResponse(&revisions[i]))
+	}
+
+	return data, nil
+}
+
+// getVisibleUserNote получает неудаленную заметку пользователя и корневую заметку ее ветки.
+// Заметка, недоступная текущему администратору, считается ненайденной.
+func (uc *UseCase) getVisibleUserNote(ctx context.Context, userID, id string) (*repositorymodels.UserNote, *repositorymodels.UserNote, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil, usecasemodels.ErrUserNoteNotFound
+	}
+
+	note, err := uc.noteRepo.GetUserNote(ctx, userID, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get user note: %w", err)
+	}
+
+	if note == nil || note.DeletedAt != nil {
+		return nil, nil, usecasemodels.ErrUserNoteNotFound
+	}
+
+	root := note
+	if note.ParentID != nil {
+		root, err = uc.noteRepo.GetUserNote(ctx, userID, *note.ParentID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("get user note: %w", err)
+		}
+
+		if root == nil {
+			return nil, nil, usecasemodels.ErrUserNoteNotFound
+		}
+	}
+
+	info := reqctx.From(ctx)
+	if !uc.canViewUserNote(info.AdminID, info.AdminRole, root) {
+		return nil, nil, usecasemodels.ErrUserNoteNotFound
+	}
+
+	return note, root, nil
+}
+
+// resolveUserNoteMentions получает упомянутых администраторов и проверяет, что все они активны
+// и видят ветку с корневой заметкой root.
+func (uc *UseCase) resolveUserNoteMentions(ctx context.Context, root *repositorymodels.UserNote, mentions []string) ([]repositorymodels.Admin, error) {
+	if len(mentions) == 0 {
+		return nil, nil
+	}
+
+	admins, err := uc.authRepo.GetAdminsByIDs(ctx, mentions)
+	if err != nil {
+		return nil, fmt.Errorf("get admins by ids: %w", err)
+	}
+
+	if len(admins) != len(mentions) {
+		return nil, usecasemodels.ErrorInvalidParameterMentions
+	}
+
+	for _, admin := range admins {
+		if !admin.IsActive || !uc.canViewUserNote(admin.ID, admin.Role, root) {
+			return nil, fmt.Errorf("%w: %s", usecasemodels.ErrorInvalidParameterMentions, admin.ID)
+		}
+	}
+
+	return admins, nil
+}
+
+// notifyUserNoteMentions отправляет упомянутым администраторам письма в фоне.
+func (uc *UseCase) notifyUserNoteMentions(ctx context.Context, note *repositorymodels.UserNote, admins []repositorymodels.Admin) {
+	if len(admins) == 0 {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		user, err := uc.userRepo.GetUserByID(ctx, note.UserID)
+		if err != nil || user == nil {
+			log.Printf("Failed to load user %s for note mentions: %v", note.UserID, err)
+
+			return
+		}
+
+		for _, admin := range admins {
+			body := fmt.Sprintf("Здравствуйте, %s!\n\nВас упомянули в заметке о пользователе %s (%s):\n\n%s",
+				admin.Name, user.Name, user.Email, note.Body)
+
+			if err := uc.mailer.Send(ctx, admin.Email, "Упоминание в заметке о пользователе", body); err != nil {
+				log.Printf("Failed to notify admin %s about note %s: %v", admin.ID, note.ID, err)
+			}
+		}
+	}()
+}
+
+// canViewUserNote проверяет, видит ли администратор ветку с корневой заметкой root:
+// модераторы и автор видят всегда, остальные — если ветка открыта всем ролям или их роли.
+func (uc *UseCase) canViewUserNote(adminID, role string, root *repositorymodels.UserNote) bool {
+	return containsString(uc.cfg.Users.NotesModeratorRoles, role) ||
+		(adminID != "" && stringValue(root.AuthorID) == adminID) ||
+		len(root.VisibleToRoles) == 0 ||
+		containsString(root.VisibleToRoles, role)
+}
+
+// canManageUserNote проверяет, может ли администратор закрепить или удалить заметку.
+func (uc *UseCase) canManageUserNote(adminID, role string, note *repositorymodels.UserNote) bool {
+	return containsString(uc.cfg.Users.NotesModeratorRoles, role) ||
+		(adminID != "" && stringValue(note.AuthorID) == adminID)
+}
+
+// validateUserNoteBody обрезает пробелы и проверяет длину текста заметки.
+func validateUserNoteBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || len([]rune(body)) > userNoteBodyMaxLength {
+		return "", usecasemodels.ErrorInvalidParameterBody
+	}
+
+	return body, nil
+}
+
+// normalizeUserNoteRoles убирает пустые и повторяющиеся роли.
+func normalizeUserNoteRoles(roles []string) []string {
+	normalized := make([]string, 0, len(roles))
+	for _, role := range roles {
+		role = strings.TrimSpace(role)
+		if role != "" && !containsString(normalized, role) {
+			normalized = append(normalized, role)
+		}
+	}
+
+	return normalized
+}
+
+// userNoteToResponse преобразует заметку в ответ. Текст удаленной заметки не возвращается.
+func userNoteToResponse(note *repositorymodels.UserNote) usecasemodels.UserNoteResponse {
+	response := usecasemodels.UserNoteResponse{
+		ID:             note.ID,
+		UserID:         note.UserID,
+		ParentID:       note.ParentID,
+		AuthorID:       note.AuthorID,
+		Body:           note.Body,
+		Mentions:       nonNilStrings(note.Mentions),
+		VisibleToRoles: nonNilStrings(note.VisibleToRoles),
+		Pinned:         note.PinnedAt != nil,
+		PinnedAt:       formatOptionalTime(note.PinnedAt),
+		PinnedBy:       note.PinnedBy,
+		EditedAt:       formatOptionalTime(note.EditedAt),
+		DeletedAt:      formatOptionalTime(note.DeletedAt),
+		DeletedBy:      note.DeletedBy,
+		CreatedAt:      note.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      note.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if note.DeletedAt != nil {
+		response.Body = ""
+		response.Mentions = []string{}
+	}
+
+	return response
+}
+
+// userNoteRevisionToResponse преобразует правку заметки в ответ.
+func userNoteRevisionToResponse(revision *repositorymodels.UserNoteRevision) usecasemodels.UserNoteRevisionResponse {
+	return usecasemodels.UserNoteRevisionResponse{
+		ID:        revision.ID,
+		Body:      revision.Body,
+		Mentions:  nonNilStrings(revision.Mentions),
+		EditedBy:  revision.EditedBy,
+		CreatedAt: revision.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// formatOptionalTime форматирует время в RFC3339 или возвращает nil.
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+
+	formatted := t.Format(time.RFC3339)
+	return &formatted
+}
+
+// nonNilStrings заменяет nil на пустой список, чтобы в JSON был [] вместо null.
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/pii"
+	"adminkaback/pkg/reqctx"
+)
+
+// piiUnmaskedKey — ключ контекста, отключающий маскирование персональных данных.
+type piiUnmaskedKey struct{}
+
+// withPIIUnmasked возвращает контекст, в котором персональные данные не маскируются
+// независимо от роли администратора (например, в выгрузке данных по запросу субъекта).
+func withPIIUnmasked(ctx context.Context) context.Context {
+	return context.WithValue(ctx, piiUnmaskedKey{}, true)
+}
+
+// maskPII проверяет, нужно ли скрывать email и телефон пользователей от текущего администратора.
+func (uc *UseCase) maskPII(ctx context.Context) bool {
+	if unmasked, _ := ctx.Value(piiUnmaskedKey{}).(bool); unmasked {
+		return false
+	}
+
+	return !containsString(uc.cfg.Users.PIIUnmaskedRoles, reqctx.From(ctx).AdminRole)
+}
+
+// maskUserResponse скрывает email и телефон в ответе.
+func maskUserResponse(response *usecasemodels.UserResponse) {
+	response.Email = pii.MaskEmail(response.Email)
+	if response.Phone != nil {
+		phone := pii.MaskPhone(*response.Phone)
+		response.Phone = &phone
+	}
+	if response.PhoneDisplay != nil {
+		display := pii.MaskPhoneDisplay(*response.PhoneDisplay)
+		response.PhoneDisplay = &display
+	}
+	response.PIIMasked = true
+}
+
+// maskUserPII скрывает email и телефон в модели пользователя.
+func maskUserPII(user *repositorymodels.User) {
+	user.Email = pii.MaskEmail(user.Email)
+	if user.Phone != nil {
+		phone := pii.MaskPhone(*user.Phone)
+		user.Phone = &phone
+	}
+}
+
+// maskPIIChange скрывает значение email или телефона из диффа или снимка истории.
+// Значения других полей возвращаются без изменений.
+func maskPIIChange(field string, value any) any {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case *string:
+		if v == nil {
+			return value
+		}
+		s = *v
+	default:
+		return value
+	}
+
+	switch field {
+	case usecasemodels.UserPIIFieldEmail:
+		return pii.MaskEmail(s)
+	case usecasemodels.UserPIIFieldPhone:
+		return pii.MaskPhone(s)
+	}
+
+	return value
+}
+
+// RevealUserPII возвращает полное значение одного маскированного поля пользователя.
+// Каждое раскрытие, в том числе отклоненное, записывается в журнал аудита.
+func (uc *UseCase) RevealUserPII(ctx context.Context, id string, req *usecasemodels.RevealUserPIIRequest) (resp *usecasemodels.RevealUserPIIResponse, err error) {
+	defer func() {
+		details := map[string]string{"field": req.Field}
+		if req.Reason != "" {
+			details["reason"] = req.Reason
+		}
+
+		uc.auditResult(ctx, &usecasemodels.AuditEntry{
+			Action:       "user.pii_reveal",
+			ResourceType: "user",
+			ResourceID:   id,
+			Details:      details,
+		}, err)
+	}()
+
+	if !containsString(uc.cfg.Users.PIIRevealRoles, reqctx.From(ctx).AdminRole) {
+		return nil, usecasemodels.ErrPIIRevealForbidden
+	}
+
+	if req.Field != usecasemodels.UserPIIFieldEmail && req.Field != usecasemodels.UserPIIFieldPhone {
+		return nil, fmt.Errorf("%w: %s", usecasemodels.ErrorInvalidParameterField, req.Field)
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get user by id: %w", err)
+	}
+
+	if user == nil {
+		return nil, usecasemodels.ErrUserNotFound
+	}
+
+	resp = &usecasemodels.RevealUserPIIResponse{Field: req.Field}
+	switch req.Field {
+	case usecasemodels.UserPIIFieldEmail:
+		resp.Value = &user.Email
+	case usecasemodels.UserPIIFieldPhone:
+		resp.Value = user.Phone
+	}
+
+	return resp, nil
+}
+
+// ReencryptUsers перешифровывает email и телефон всех пользователей и их копии текущим мастер-ключом
+// пакетами по batchSize записей; с decrypt — записывает их открыто. Затем маскируются значения
+// в отчетах об ошибках импорта. Каждый пакет выполняется
+// в отдельной транзакции, поэтому прерванное перешифрование можно безопасно запустить снова.
+func (uc *UseCase) ReencryptUsers(ctx context.Context, batchSize int, decrypt bool) (resp *usecasemodels.ReencryptUsersResponse, err error) {
+	resp = &usecasemodels.ReencryptUsersResponse{}
+
+	defer func() {
+		uc.auditResult(ctx, &usecasemodels.AuditEntry{
+			Action:       "user.pii_reencrypt",
+			ResourceType: "user",
+			Details: map[string]string{
+				"decrypt":          strconv.FormatBool(decrypt),
+				"checked":          strconv.Itoa(resp.Checked),
+				"updated":          strconv.Itoa(resp.Updated),
+				"history_versions": strconv.Itoa(resp.HistoryVersions),
+				"email_changes":    strconv.Itoa(resp.EmailChanges),
+				"import_jobs":      strconv.Itoa(resp.ImportJobs),
+			},
+		}, err)
+	}()
+
+	lastID := ""
+	for {
+		result, err := uc.userRepo.ReencryptUsers(ctx, lastID, batchSize, decrypt)
+		if err != nil {
+			return resp, fmt.Errorf("reencrypt users after %q: %w", lastID, err)
+		}
+
+		resp.Checked += result.Checked
+		resp.Updated += result.Updated
+		resp.HistoryVersions += result.HistoryVersions
+		resp.EmailChanges += result.EmailChanges
+
+		if result.LastID == "" {
+			break
+		}
+		lastID = result.LastID
+	}
+
+	if !decrypt {
+		resp.ImportJobs, err = uc.importRepo.MaskUserImportJobs(ctx)
+		if err != nil {
+			return resp, fmt.Errorf("mask user import jobs: %w", err)
+		}
+	}
+
+	return resp, nil
+}
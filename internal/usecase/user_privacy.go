@@ -0,0 +1,306 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/reqctx"
+
+	"github.com/google/uuid"
+)
+
+// userPrivacyFile — файл архива с данными пользователя.
+type userPrivacyFile struct {
+	name string
+	data any
+}
+
+// ExportUserData записывает в w ZIP архив со всеми данными пользователя в JSON: профиль (включая
+// объединенных с ним пользователей), историю изменений, заметки с правками, метки, блокировки
+// и записи журнала аудита о пользователе и его заметках. Архив собирается целиком до записи,
+// поэтому ошибка возвращается до отправки первых данных.
+func (uc *UseCase) ExportUserData(ctx context.Context, id string, w io.Writer) (err error) {
+	defer func() {
+		uc.auditResult(ctx, &usecasemodels.AuditEntry{
+			Action:       "user.privacy_export",
+			ResourceType: "user",
+			ResourceID:   id,
+		}, err)
+	}()
+
+	if err := uc.checkPrivacyAccess(ctx); err != nil {
+		return err
+	}
+
+	// Выгрузка отдается субъекту данных, поэтому email и телефон в ней не маскируются.
+	ctx = withPIIUnmasked(ctx)
+
+	user, merged, err := uc.getPrivacyUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	profile := uc.userPrivacyProfile(ctx, user)
+	ids := []string{user.ID}
+	for i := range merged {
+		profile.MergedUsers = append(profile.MergedUsers, uc.userPrivacyProfile(ctx, &merged[i]))
+		ids = append(ids, merged[i].ID)
+	}
+
+	tags, err := uc.getUserTags(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	profile.Tags = tags
+
+	versions, _, err := uc.historyRepo.GetUserHistory(ctx, user.ID, 0, 0)
+	if err != nil {
+		return fmt.Errorf("get user history: %w", err)
+	}
+
+	history := make([]usecasemodels.UserHistoryResponse, 0, len(versions))
+	for i := range versions {
+		history = append(history, uc.userHistoryToResponse(ctx, &versions[i]))
+	}
+
+	notes, err := uc.getPrivacyNotes(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	bans := make([]usecasemodels.UserBanResponse, 0)
+	for _, userID := range ids {
+		userBans, err := uc.banRepo.GetUserBans(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("get user bans: %w", err)
+		}
+
+		for i := range userBans {
+			bans = append(bans, userBanToResponse(&userBans[i]))
+		}
+	}
+
+	// Заметки попадают в журнал и под своим ID, если обработчик передал его как ресурс.
+	resourceIDs := append([]string{}, ids...)
+	for _, note := range notes {
+		resourceIDs = append(resourceIDs, note.ID)
+	}
+
+	entries, err := uc.auditRepo.GetAuditEntriesByResourceIDs(ctx, resourceIDs)
+	if err != nil {
+		return fmt.Errorf("get audit entries: %w", err)
+	}
+
+	audit := make([]usecasemodels.AuditEntryResponse, 0, len(entries))
+	for i := range entries {
+		audit = append(audit, auditEntryToResponse(&entries[i]))
+	}
+
+	files := []userPrivacyFile{
+		{name: usecasemodels.UserPrivacyFileProfile, data: profile},
+		{name: usecasemodels.UserPrivacyFileHistory, data: history},
+		{name: usecasemodels.UserPrivacyFileNotes, data: notes},
+		{name: usecasemodels.UserPrivacyFileTags, data: nonNilTags(tags)},
+		{name: usecasemodels.UserPrivacyFileBans, data: bans},
+		{name: usecasemodels.UserPrivacyFileAudit, data: audit},
+	}
+
+	manifest := usecasemodels.UserPrivacyManifest{
+		UserID:        user.ID,
+		MergedUserIDs: ids[1:],
+		GeneratedAt:   time.Now().Format(time.RFC3339),
+		GeneratedBy:   reqctx.From(ctx).AdminID,
+	}
+	for _, file := range files {
+		manifest.Files = append(manifest.Files, file.name)
+	}
+
+	files = append([]userPrivacyFile{{name: usecasemodels.UserPrivacyFileManifest, data: manifest}}, files...)
+
+	return writeUserPrivacyArchive(w, files)
+}
+
+// EraseUser необратимо обезличивает персональные данные пользователя и объединенных с ним пользователей.
+// Пользователь остается в БД удаленным с заглушками вместо email и имени, чтобы сохранились связи
+// и статистика. Изменение не записывается в историю пользователя: в ней остались бы исходные значения.
+func (uc *UseCase) EraseUser(ctx context.Context, id string, req *usecasemodels.EraseUserRequest) (resp *usecasemodels.EraseUserResponse, err error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+
+	details := map[string]string{"reason": req.Reason}
+	defer func() {
+		uc.auditResult(ctx, &usecasemodels.AuditEntry{
+			Action:       "user.privacy_erase",
+			ResourceType: "user",
+			ResourceID:   id,
+			Details:      details,
+		}, err)
+	}()
+
+	if err := uc.checkPrivacyAccess(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.Reason == "" {
+		return nil, usecasemodels.ErrorInvalidParameterReason
+	}
+
+	erasedAt := time.Now()
+
+	err = uc.txManager.WithTx(ctx, func(ctx context.Context) error {
+		user, merged, err := uc.getPrivacyUser(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		ids := []string{user.ID}
+		for i := range merged {
+			ids = append(ids, merged[i].ID)
+		}
+
+		erasure, err := uc.userRepo.EraseUsers(ctx, ids, erasedAt)
+		if err != nil {
+			return fmt.Errorf("erase users: %w", err)
+		}
+
+		resp = &usecasemodels.EraseUserResponse{
+			UserID:          user.ID,
+			ErasedUserIDs:   ids,
+			ErasedAt:        erasedAt.Format(time.RFC3339),
+			HistoryVersions: erasure.HistoryVersions,
+			Notes:           erasure.Notes,
+			NoteRevisions:   erasure.NoteRevisions,
+			Bans:            erasure.Bans,
+			ImportJobs:      erasure.ImportJobs,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	details["erased_user_ids"] = strings.Join(resp.ErasedUserIDs, ",")
+	details["history_versions"] = strconv.FormatInt(resp.HistoryVersions, 10)
+	details["notes"] = strconv.FormatInt(resp.Notes, 10)
+	details["bans"] = strconv.FormatInt(resp.Bans, 10)
+	details["import_jobs"] = strconv.FormatInt(resp.ImportJobs, 10)
+
+	return resp, nil
+}
+
+// checkPrivacyAccess проверяет, что роли текущего администратора разрешены выгрузка и обезличивание данных.
+func (uc *UseCase) checkPrivacyAccess(ctx context.Context) error {
+	if !containsString(uc.cfg.Users.PrivacyRoles, reqctx.From(ctx).AdminRole) {
+		return usecasemodels.ErrPrivacyForbidden
+	}
+
+	return nil
+}
+
+// getPrivacyUser получает пользователя, в том числе удаленного, и объединенных с ним пользователей.
+// Обезличенный пользователь возвращает ErrUserErased.
+func (uc *UseCase) getPrivacyUser(ctx context.Context, id string) (*repositorymodels.User, []repositorymodels.User, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil, usecasemodels.ErrUserNotFound
+	}
+
+	user, err := uc.userRepo.GetUserByIDWithDeleted(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get user by id: %w", err)
+	}
+
+	if user == nil {
+		return nil, nil, usecasemodels.ErrUserNotFound
+	}
+
+	if user.ErasedAt != nil {
+		return nil, nil, usecasemodels.ErrUserErased
+	}
+
+	merged, err := uc.userRepo.GetMergedUsers(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get merged users: %w", err)
+	}
+
+	return user, merged, nil
+}
+
+// getPrivacyNotes получает все заметки о пользователе, включая удаленные, вместе с историей правок.
+func (uc *UseCase) getPrivacyNotes(ctx context.Context, userID string) ([]usecasemodels.UserPrivacyNote, error) {
+	notes, err := uc.noteRepo.GetUserNotes(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user notes: %w", err)
+	}
+
+	data := make([]usecasemodels.UserPrivacyNote, 0, len(notes))
+	for i := range notes {
+		revisions, err := uc.noteRepo.GetUserNoteRevisions(ctx, notes[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("get user note revisions: %w", err)
+		}
+
+		note := usecasemodels.UserPrivacyNote{
+			UserNoteResponse: userNoteToResponse(&notes[i]),
+			Revisions:        make([]usecasemodels.UserNoteRevisionResponse, 0, len(revisions)),
+		}
+		note.Body = notes[i].Body
+		note.Mentions = nonNilStrings(notes[i].Mentions)
+
+		for j := range revisions {
+			note.Revisions = append(note.Revisions, userNoteRevisionToResponse(&revisions[j]))
+		}
+
+		data = append(data, note)
+	}
+
+	return data, nil
+}
+
+// userPrivacyProfile преобразует пользователя в профиль архива.
+func (uc *UseCase) userPrivacyProfile(ctx context.Context, user *repositorymodels.User) usecasemodels.UserPrivacyProfile {
+	return usecasemodels.UserPrivacyProfile{
+		UserResponse: uc.userToResponse(ctx, user),
+		DeletedAt:    formatOptionalTime(user.DeletedAt),
+	}
+}
+
+// nonNilTags заменяет nil на пустой список, чтобы в JSON был [] вместо null.
+func nonNilTags(tags []usecasemodels.UserTagResponse) []usecasemodels.UserTagResponse {
+	if tags == nil {
+		return []usecasemodels.UserTagResponse{}
+	}
+
+	return tags
+}
+
+// writeUserPrivacyArchive записывает файлы в ZIP архив.
+func writeUserPrivacyArchive(w io.Writer, files []userPrivacyFile) error {
+	archive := zip.NewWriter(w)
+
+	for _, file := range files {
+		fw, err := archive.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("create archive file %s: %w", file.name, err)
+		}
+
+		encoder := json.NewEncoder(fw)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return fmt.Errorf("write archive file %s: %w", file.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/config"
+	"adminkaback/pkg/reqctx"
+)
+
+// userStatusChange описывает выполненный переход статуса, для которого нужно запустить действия.
+type userStatusChange struct {
+	user       repositorymodels.User
+	from       string
+	transition *config.StatusTransitionConfig
+	adminID    string
+	requestID  string
+}
+
+// userStatusWebhookPayload — тело webhook о смене статуса пользователя.
+type userStatusWebhookPayload struct {
+	Event     string  `json:"event"`
+	UserID    string  `json:"user_id"`
+	Email     string  `json:"email"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	AdminID   *string `json:"admin_id"`
+	RequestID *string `json:"request_id"`
+	ChangedAt string  `json:"changed_at"`
+}
+
+// isKnownStatus проверяет, что статус есть в конфигурации.
+func (uc *UseCase) isKnownStatus(status string) bool {
+	return containsString(uc.cfg.Users.StatusMachine.Statuses, status)
+}
+
+// checkStatusTransition проверяет, что пользователя можно перевести в статус to
+// от имени администратора из контекста. Возвращает описание перехода для запуска действий
+// или nil, если статус не меняется. Статус banned устанавливается только через блокировку.
+func (uc *UseCase) checkStatusTransition(ctx context.Context, user *repositorymodels.User, to string) (*userStatusChange, error) {
+	if to == "banned" && user.Status != to {
+		return nil, &usecasemodels.StatusTransitionError{
+			From:    user.Status,
+			To:      to,
+			Allowed: uc.allowedStatusTargets(ctx, user.Status),
+			Err:     usecasemodels.ErrStatusRequiresBan,
+		}
+	}
+
+	return uc.checkAnyStatusTransition(ctx, user, to)
+}
+
+// checkAnyStatusTransition проверяет переход по машине статусов без ограничения на banned.
+// Используется блокировкой, которая создает запись о бане вместе со сменой статуса.
+func (uc *UseCase) checkAnyStatusTransition(ctx context.Context, user *repositorymodels.User, to string) (*userStatusChange, error) {
+	if user.Status == to {
+		return nil, nil
+	}
+
+	transitionErr := &usecasemodels.StatusTransitionError{
+		From:    user.Status,
+		To:      to,
+		Allowed: uc.allowedStatusTargets(ctx, user.Status),
+	}
+
+	transition := uc.findStatusTransition(user.Status, to)
+	if transition == nil {
+		transitionErr.Err = usecasemodels.ErrInvalidStatusTransition
+
+		return nil, transitionErr
+	}
+
+	if !uc.statusTransitionRoleAllowed(ctx, transition) {
+		transitionErr.Err = usecasemodels.ErrStatusTransitionForbidden
+
+		return nil, transitionErr
+	}
+
+	for _, guard := range transition.Guards {
+		if guard == config.StatusGuardEmailVerified && !user.IsEmailVerified {
+			transitionErr.Err = usecasemodels.ErrStatusTransitionGuard
+			transitionErr.Guard = guard
+
+			return nil, transitionErr
+		}
+	}
+
+	return uc.newUserStatusChange(ctx, user, to, transition), nil
+}
+
+// newUserStatusChange описывает переход пользователя в статус to.
+func (uc *UseCase) newUserStatusChange(ctx context.Context, user *repositorymodels.User, to string, transition *config.StatusTransitionConfig) *userStatusChange {
+	info := reqctx.From(ctx)
+
+	changed := *user
+	changed.Status = to
+
+	return &userStatusChange{
+		user:       changed,
+		from:       user.Status,
+		transition: transition,
+		adminID:    info.AdminID,
+		requestID:  info.RequestID,
+	}
+}
+
+// findStatusTransition возвращает настроенный переход или nil.
+func (uc *UseCase) findStatusTransition(from, to string) *config.StatusTransitionConfig {
+	transitions := uc.cfg.Users.StatusMachine.Transitions
+	for i := range transitions {
+		if transitions[i].From == from && transitions[i].To == to {
+			return &transitions[i]
+		}
+	}
+
+	return nil
+}
+
+// allowedStatusTargets возвращает статусы, в которые администратор из контекста может перевести пользователя из from.
+func (uc *UseCase) allowedStatusTargets(ctx context.Context, from string) []string {
+	allowed := []string{}
+	for i := range uc.cfg.Users.StatusMachine.Transitions {
+		transition := &uc.cfg.Users.StatusMachine.Transitions[i]
+		if transition.From == from && uc.statusTransitionRoleAllowed(ctx, transition) {
+			allowed = append(allowed, transition.To)
+		}
+	}
+
+	return allowed
+}
+
+// statusTransitionRoleAllowed проверяет ограничение перехода по роли администратора.
+// Системные переходы (без администратора в контексте) ролью не ограничиваются.
+func (uc *UseCase) statusTransitionRoleAllowed(ctx context.Context, transition *config.StatusTransitionConfig) bool {
+	info := reqctx.From(ctx)
+	if len(transition.Roles) == 0 || info.AdminID == "" {
+		return true
+	}
+
+	return containsString(transition.Roles, info.AdminRole)
+}
+
+// runStatusHooks запускает действия переходов в фоне. Вызывается после фиксации транзакции;
+// ошибки действий только логируются.
+func (uc *UseCase) runStatusHooks(ctx context.Context, changes []*userStatusChange) {
+	var pending []*userStatusChange
+	for _, change := range changes {
+		if change != nil && change.transition != nil && len(change.transition.Hooks) > 0 {
+			pending = append(pending, change)
+		}
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		for _, change := range pending {
+			for _, hook := range change.transition.Hooks {
+				if err := uc.runStatusHook(ctx, hook, change); err != nil {
+					log.Printf("Failed to run status hook %s for user %s: %v", hook, change.user.ID, err)
+				}
+			}
+		}
+	}()
+}
+
+// runStatusHook выполняет одно действие перехода.
+func (uc *UseCase) runStatusHook(ctx context.Context, hook string, change *userStatusChange) error {
+	switch hook {
+	case config.StatusHookRevokeSessions:
+		return uc.userRepo.RevokeUserSessions(ctx, change.user.ID)
+	case config.StatusHookSendEmail:
+		body := fmt.Sprintf("Здравствуйте, %s!\n\nСтатус вашего аккаунта изменен: %s.", change.user.Name, change.user.Status)
+
+		return uc.mailer.Send(ctx, change.user.Email, "Изменение статуса аккаунта", body)
+	case config.StatusHookWebhook:
+		return uc.webhook.Post(ctx, uc.cfg.Users.StatusMachine.WebhookURL, userStatusWebhookPayload{
+			Event:     "user.status_changed",
+			UserID:    change.user.ID,
+			Email:     change.user.Email,
+			From:      change.from,
+			To:        change.user.Status,
+			AdminID:   optionalString(change.adminID),
+			RequestID: optionalString(change.requestID),
+			ChangedAt: time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+
+	return fmt.Errorf("unknown hook %q", hook)
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	repositorymodels "adminkaback/internal/repository/models"
+	usecasemodels "adminkaback/internal/usecase/models"
+	"adminkaback/pkg/config"
+	"adminkaback/pkg/reqctx"
+)
+
+// newStatusTestUseCase создает UseCase с машиной статусов для проверки переходов.
+func newStatusTestUseCase() *UseCase {
+	cfg := &config.Config{}
+	cfg.Users.StatusMachine = config.StatusMachineConfig{
+		Statuses: []string{"pending", "active", "inactive", "banned"},
+		Initial:  "pending",
+		Transitions: []config.StatusTransitionConfig{
+			{From: "pending", To: "active", Guards: []string{config.StatusGuardEmailVerified}},
+			{From: "active", To: "inactive"},
+			{From: "active", To: "banned", Hooks: []string{config.StatusHookRevokeSessions}},
+			{From: "banned", To: "active", Roles: []string{"superadmin"}},
+		},
+	}
+
+	return &UseCase{cfg: cfg}
+}
+
+func adminContext(role string) context.Context {
+	return reqctx.With(context.Background(), &reqctx.Info{AdminID: "admin-1", AdminRole: role})
+}
+
+func TestCheckAnyStatusTransition(t *testing.T) {
+	uc := newStatusTestUseCase()
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		user        repositorymodels.User
+		to          string
+		wantChange  bool
+		wantErr     error
+		wantGuard   string
+		wantAllowed []string
+	}{
+		{name: "same status", ctx: adminContext("admin"), user: repositorymodels.User{Status: "active"}, to: "active"},
+		{name: "allowed", ctx: adminContext("admin"), user: repositorymodels.User{Status: "active"}, to: "inactive", wantChange: true},
+		{
+			name: "not configured", ctx: adminContext("admin"), user: repositorymodels.User{Status: "inactive"}, to: "active",
+			wantErr: usecasemodels.ErrInvalidStatusTransition, wantAllowed: []string{},
+		},
+		{
+			name: "guard failed", ctx: adminContext("admin"), user: repositorymodels.User{Status: "pending"}, to: "active",
+			wantErr: usecasemodels.ErrStatusTransitionGuard, wantGuard: config.StatusGuardEmailVerified, wantAllowed: []string{"active"},
+		},
+		{name: "guard passed", ctx: adminContext("admin"), user: repositorymodels.User{Status: "pending", IsEmailVerified: true}, to: "active", wantChange: true},
+		{
+			name: "role forbidden", ctx: adminContext("admin"), user: repositorymodels.User{Status: "banned"}, to: "active",
+			wantErr: usecasemodels.ErrStatusTransitionForbidden, wantAllowed: []string{},
+		},
+		{name: "role allowed", ctx: adminContext("superadmin"), user: repositorymodels.User{Status: "banned"}, to: "active", wantChange: true},
+		{name: "system transition ignores roles", ctx: context.Background(), user: repositorymodels.User{Status: "banned"}, to: "active", wantChange: true},
+		{name: "ban", ctx: adminContext("admin"), user: repositorymodels.User{Status: "active"}, to: "banned", wantChange: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := uc.checkAnyStatusTransition(tt.ctx, &tt.user, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkAnyStatusTransition() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if (change != nil) != tt.wantChange {
+				t.Fatalf("checkAnyStatusTransition() change = %+v, want change %v", change, tt.wantChange)
+			}
+
+			if change != nil && (change.from != tt.user.Status || change.user.Status != tt.to || change.transition == nil) {
+				t.Fatalf("checkAnyStatusTransition() change = %+v", change)
+			}
+
+			if tt.wantErr == nil {
+				return
+			}
+
+			var transitionErr *usecasemodels.StatusTransitionError
+			if !errors.As(err, &transitionErr) {
+				t.Fatalf("checkAnyStatusTransition() error = %T, want *StatusTransitionError", err)
+			}
+
+			if transitionErr.Guard != tt.wantGuard {
+				t.Fatalf("Guard = %q, want %q", transitionErr.Guard, tt.wantGuard)
+			}
+
+			if !reflect.DeepEqual(transitionErr.Allowed, tt.wantAllowed) {
+				t.Fatalf("Allowed = %v, want %v", transitionErr.Allowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestAllowedStatusTargets(t *testing.T) {
+	uc := newStatusTestUseCase()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		from string
+		want []string
+	}{
+		{name: "active", ctx: adminContext("admin"), from: "active", want: []string{"inactive", "banned"}},
+		{name: "banned for admin", ctx: adminContext("admin"), from: "banned", want: []string{}},
+		{name: "banned for superadmin", ctx: adminContext("superadmin"), from: "banned", want: []string{"active"}},
+		{name: "unknown", ctx: adminContext("admin"), from: "archived", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uc.allowedStatusTargets(tt.ctx, tt.from); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("allowedStatusTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusTransitionsWithDefaultConfig(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("USERS_STATUS_MACHINE", "")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	uc := &UseCase{cfg: cfg}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		from    string
+		to      string
+		wantErr error
+	}{
+		{name: "admin unbans", ctx: adminContext("admin"), from: "banned", to: "active"},
+		{name: "superadmin unbans", ctx: adminContext("superadmin"), from: "banned", to: "active"},
+		{name: "moderator cannot unban", ctx: adminContext("moderator"), from: "banned", to: "active", wantErr: usecasemodels.ErrStatusTransitionForbidden},
+		{name: "ban expiry", ctx: context.Background(), from: "banned", to: "active"},
+		{name: "admin deactivates", ctx: adminContext("admin"), from: "active", to: "inactive"},
+		{name: "back to pending", ctx: adminContext("admin"), from: "active", to: "pending", wantErr: usecasemodels.ErrInvalidStatusTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &repositorymodels.User{Status: tt.from}
+			if _, err := uc.checkStatusTransition(tt.ctx, user, tt.to); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkStatusTransition() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
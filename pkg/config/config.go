@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
@@ -12,10 +13,14 @@ import (
 
 // Config содержит всю конфигурацию приложения.
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Server   ServerConfig
+	App       AppConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Server    ServerConfig
+	Users     UsersConfig
+	Analytics AnalyticsConfig
+	Mail      MailConfig
+	PII       PIIConfig
 }
 
 // AppConfig содержит конфигурацию приложения.
@@ -24,6 +29,7 @@ type AppConfig struct {
 	Name        string
 	Environment string
 	LogLevel    string
+	PublicURL   string
 }
 
 // DatabaseConfig содержит конфигурацию базы данных.
@@ -45,6 +51,125 @@ type JWTConfig struct {
 	RefreshTTL time.Duration
 }
 
+// UsersConfig содержит настройки работы с пользователями.
+type UsersConfig struct {
+	BulkMaxItems      int
+	ImportMaxFileSize int64
+	ImportSyncMaxRows int
+	ExportBatchSize   int
+	// PhoneDefaultRegion — регион (ISO 3166-1 alpha-2) для номеров без кода страны.
+	PhoneDefaultRegion string
+
+	VerificationSecret         string
+	VerificationTTL            time.Duration
+	VerificationResendInterval time.Duration
+	VerificationMaxPerDay      int
+
+	BanExpiryInterval time.Duration
+
+	// NotesModeratorRoles — роли администраторов, которые видят все заметки о пользователях,
+	// а также закрепляют и удаляют чужие заметки.
+	NotesModeratorRoles []string
+
+	// PrivacyRoles — роли администраторов, которым доступны выгрузка и обезличивание данных пользователя.
+	PrivacyRoles []string
+
+	// PIIUnmaskedRoles — роли администраторов, которые видят email и телефон пользователей полностью.
+	// Остальным они возвращаются маскированными.
+	PIIUnmaskedRoles []string
+	// PIIRevealRoles — роли администраторов, которым разрешено раскрыть одно маскированное поле.
+	PIIRevealRoles []string
+
+	StatusMachine StatusMachineConfig
+}
+
+// AnalyticsConfig содержит настройки статистики и аналитики.
+type AnalyticsConfig struct {
+	// StatsRefreshInterval — период пересчета агрегата статистики пользователей для дашборда.
+	StatsRefreshInterval time.Duration
+	// StorageTimezone — часовой пояс, в котором записаны значения колонок TIMESTAMP без зоны
+	// (время сервера приложения и БД). Нужен для перевода дат в часовой пояс отчета.
+	StorageTimezone string
+	// CohortCacheTTL — время жизни закешированных матриц удержания пользователей. 0 отключает кеш.
+	CohortCacheTTL time.Duration
+}
+
+// PIIConfig содержит ключи шифрования персональных данных пользователей (base64, 32 байта).
+// Без MasterKey email и телефон хранятся открыто, а зашифрованные ранее значения возвращаются маскированными.
+type PIIConfig struct {
+	// MasterKey шифрует ключи данных новых значений. Читается из PII_MASTER_KEY или файла PII_MASTER_KEY_FILE.
+	MasterKey string
+	// PreviousMasterKeys — прежние мастер-ключи: используются только для расшифровки до перешифрования.
+	PreviousMasterKeys []string
+	// IndexKey — ключ слепых индексов для поиска по равенству. Не меняется при смене мастер-ключа.
+	IndexKey string
+}
+
+// MasterKeys возвращает текущий и прежние мастер-ключи, начиная с текущего.
+func (c *PIIConfig) MasterKeys() []string {
+	if c.MasterKey == "" {
+		return nil
+	}
+
+	return append([]string{c.MasterKey}, c.PreviousMasterKeys...)
+}
+
+// StatusMachineConfig описывает статусы пользователей и разрешенные переходы между ними.
+type StatusMachineConfig struct {
+	Statuses    []string                 `json:"statuses"`
+	Initial     string                   `json:"initial"`
+	Transitions []StatusTransitionConfig `json:"transitions"`
+
+	WebhookURL    string `json:"-"`
+	WebhookSecret string `json:"-"`
+}
+
+// StatusTransitionConfig описывает разрешенный переход статуса.
+// Roles ограничивает роли администраторов, которым доступен переход (пусто — всем),
+// Guards — дополнительные проверки пользователя, Hooks — действия после перехода.
+type StatusTransitionConfig struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Roles  []string `json:"roles,omitempty"`
+	Guards []string `json:"guards,omitempty"`
+	Hooks  []string `json:"hooks,omitempty"`
+}
+
+// Поддерживаемые проверки и действия переходов статуса.
+const (
+	StatusGuardEmailVerified = "email_verified"
+	StatusHookRevokeSessions = "revoke_sessions"
+	StatusHookSendEmail      = "send_email"
+	StatusHookWebhook        = "webhook"
+)
+
+// defaultStatusMachine — переходы статусов по умолчанию: любой статус можно сменить на любой другой,
+// кроме возврата в pending; снять блокировку могут только admin и superadmin.
+var defaultStatusMachine = StatusMachineConfig{
+	Statuses: []string{"pending", "active", "inactive", "banned"},
+	Initial:  "active",
+	Transitions: []StatusTransitionConfig{
+		{From: "pending", To: "active"},
+		{From: "pending", To: "inactive"},
+		{From: "pending", To: "banned"},
+		{From: "active", To: "inactive"},
+		{From: "active", To: "banned"},
+		{From: "inactive", To: "active"},
+		{From: "inactive", To: "banned"},
+		{From: "banned", To: "active", Roles: []string{"superadmin", "admin"}},
+		{From: "banned", To: "inactive"},
+	},
+}
+
+// MailConfig содержит настройки SMTP. Пустой Host отключает отправку: письма пишутся в лог.
+type MailConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
 // ServerConfig содержит конфигурацию сервера.
 type ServerConfig struct {
 	HTTPPort string
@@ -73,6 +198,7 @@ func Load() (*Config, error) {
 			Name:        getEnv("APP_NAME", "adminkaback"),
 			Environment: getEnv("APP_ENVIRONMENT", "dev"),
 			LogLevel:    getEnv("APP_LOG_LEVEL", "debug"),
+			PublicURL:   getEnv("APP_PUBLIC_URL", "http://localhost:8090"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("PG_HOST", "localhost"),
@@ -100,6 +226,63 @@ func Load() (*Config, error) {
 				MaxAge:           getEnvAsInt("CORS_MAX_AGE", 3600),
 			},
 		},
+		Users: UsersConfig{
+			BulkMaxItems:       getEnvAsInt("USERS_BULK_MAX_ITEMS", 500),
+			ImportMaxFileSize:  getEnvAsInt64("USERS_IMPORT_MAX_FILE_SIZE", 20<<20),
+			ImportSyncMaxRows:  getEnvAsInt("USERS_IMPORT_SYNC_MAX_ROWS", 500),
+			ExportBatchSize:    getEnvAsInt("USERS_EXPORT_BATCH_SIZE", 1000),
+			PhoneDefaultRegion: strings.ToUpper(getEnv("USERS_PHONE_DEFAULT_REGION", "RU")),
+
+			VerificationSecret:         getEnv("USERS_VERIFICATION_SECRET", ""),
+			VerificationTTL:            getEnvAsDuration("USERS_VERIFICATION_TTL", 48*time.Hour),
+			VerificationResendInterval: getEnvAsDuration("USERS_VERIFICATION_RESEND_INTERVAL", time.Minute),
+			VerificationMaxPerDay:      getEnvAsInt("USERS_VERIFICATION_MAX_PER_DAY", 5),
+
+			BanExpiryInterval: getEnvAsDuration("USERS_BAN_EXPIRY_INTERVAL", time.Minute),
+
+			NotesModeratorRoles: getEnvAsStringSlice("USERS_NOTES_MODERATOR_ROLES", []string{"superadmin", "admin"}),
+			PrivacyRoles:        getEnvAsStringSlice("USERS_PRIVACY_ROLES", []string{"superadmin", "admin"}),
+			PIIUnmaskedRoles:    getEnvAsStringSlice("USERS_PII_UNMASKED_ROLES", []string{"superadmin", "admin"}),
+			PIIRevealRoles:      getEnvAsStringSlice("USERS_PII_REVEAL_ROLES", []string{"superadmin", "admin", "moderator"}),
+		},
+		Analytics: AnalyticsConfig{
+			StatsRefreshInterval: getEnvAsDuration("ANALYTICS_STATS_REFRESH_INTERVAL", 5*time.Minute),
+			StorageTimezone:      getEnv("ANALYTICS_STORAGE_TIMEZONE", "UTC"),
+			CohortCacheTTL:       getEnvAsDuration("ANALYTICS_COHORT_CACHE_TTL", 15*time.Minute),
+		},
+		Mail: MailConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnv("SMTP_PORT", "587"),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "noreply@localhost"),
+		},
+		PII: PIIConfig{
+			MasterKey:          getEnv("PII_MASTER_KEY", ""),
+			PreviousMasterKeys: getEnvAsStringSlice("PII_PREVIOUS_MASTER_KEYS", nil),
+			IndexKey:           getEnv("PII_INDEX_KEY", ""),
+		},
+	}
+
+	if path := getEnv("PII_MASTER_KEY_FILE", ""); path != "" && cfg.PII.MasterKey == "" {
+		key, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read PII_MASTER_KEY_FILE: %w", err)
+		}
+		cfg.PII.MasterKey = strings.TrimSpace(string(key))
+	}
+
+	statusMachine, err := getEnvAsStatusMachine("USERS_STATUS_MACHINE", defaultStatusMachine)
+	if err != nil {
+		return nil, fmt.Errorf("parse USERS_STATUS_MACHINE: %w", err)
+	}
+	statusMachine.WebhookURL = getEnv("USERS_STATUS_WEBHOOK_URL", "")
+	statusMachine.WebhookSecret = getEnv("USERS_STATUS_WEBHOOK_SECRET", "")
+	cfg.Users.StatusMachine = statusMachine
+
+	// Ссылки подтверждения подписываются секретом JWT, если отдельный секрет не задан.
+	if cfg.Users.VerificationSecret == "" {
+		cfg.Users.VerificationSecret = cfg.JWT.Secret
 	}
 
 	if err := cfg.validate(); err != nil {
@@ -127,6 +310,104 @@ func (c *Config) validate() error {
 		return fmt.Errorf("JWT_SECRET must be set and changed from default")
 	}
 
+	if c.Users.BulkMaxItems <= 0 {
+		return fmt.Errorf("USERS_BULK_MAX_ITEMS must be positive")
+	}
+
+	if c.Users.ExportBatchSize <= 0 {
+		return fmt.Errorf("USERS_EXPORT_BATCH_SIZE must be positive")
+	}
+
+	if len(c.Users.PhoneDefaultRegion) != 2 {
+		return fmt.Errorf("USERS_PHONE_DEFAULT_REGION must be a two-letter region code")
+	}
+
+	if c.Users.VerificationTTL <= 0 {
+		return fmt.Errorf("USERS_VERIFICATION_TTL must be positive")
+	}
+
+	if c.Users.VerificationMaxPerDay <= 0 {
+		return fmt.Errorf("USERS_VERIFICATION_MAX_PER_DAY must be positive")
+	}
+
+	if c.Users.BanExpiryInterval <= 0 {
+		return fmt.Errorf("USERS_BAN_EXPIRY_INTERVAL must be positive")
+	}
+
+	if c.Analytics.StatsRefreshInterval <= 0 {
+		return fmt.Errorf("ANALYTICS_STATS_REFRESH_INTERVAL must be positive")
+	}
+
+	if c.Analytics.CohortCacheTTL < 0 {
+		return fmt.Errorf("ANALYTICS_COHORT_CACHE_TTL must not be negative")
+	}
+
+	if _, err := time.LoadLocation(c.Analytics.StorageTimezone); err != nil {
+		return fmt.Errorf("ANALYTICS_STORAGE_TIMEZONE must be a valid IANA timezone: %w", err)
+	}
+
+	if err := c.Users.StatusMachine.validate(); err != nil {
+		return fmt.Errorf("USERS_STATUS_MACHINE: %w", err)
+	}
+
+	// Без ключа индекса зашифрованных пользователей нельзя найти по email.
+	if c.PII.MasterKey != "" && c.PII.IndexKey == "" {
+		return fmt.Errorf("PII_INDEX_KEY is required when PII_MASTER_KEY is set")
+	}
+
+	return nil
+}
+
+// validate проверяет, что переходы ссылаются на известные статусы, проверки и действия.
+func (m *StatusMachineConfig) validate() error {
+	known := make(map[string]bool, len(m.Statuses))
+	for _, status := range m.Statuses {
+		if status == "" || known[status] {
+			return fmt.Errorf("statuses must be non-empty and unique")
+		}
+		known[status] = true
+	}
+
+	// Блокировки пользователей опираются на эти статусы.
+	for _, status := range []string{"active", "banned"} {
+		if !known[status] {
+			return fmt.Errorf("status %q is required", status)
+		}
+	}
+
+	if !known[m.Initial] {
+		return fmt.Errorf("initial status %q is not in statuses", m.Initial)
+	}
+
+	// Статус banned устанавливается только вместе с записью о блокировке.
+	if m.Initial == "banned" {
+		return fmt.Errorf("initial status cannot be banned")
+	}
+
+	for _, t := range m.Transitions {
+		if !known[t.From] || !known[t.To] || t.From == t.To {
+			return fmt.Errorf("invalid transition %s -> %s", t.From, t.To)
+		}
+
+		for _, guard := range t.Guards {
+			if guard != StatusGuardEmailVerified {
+				return fmt.Errorf("unknown guard %q in transition %s -> %s", guard, t.From, t.To)
+			}
+		}
+
+		for _, hook := range t.Hooks {
+			switch hook {
+			case StatusHookRevokeSessions, StatusHookSendEmail:
+			case StatusHookWebhook:
+				if m.WebhookURL == "" {
+					return fmt.Errorf("USERS_STATUS_WEBHOOK_URL is required for webhook hook")
+				}
+			default:
+				return fmt.Errorf("unknown hook %q in transition %s -> %s", hook, t.From, t.To)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -233,6 +514,20 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+func getEnvAsStatusMachine(key string, defaultValue StatusMachineConfig) (StatusMachineConfig, error) {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+
+	var value StatusMachineConfig
+	if err := json.Unmarshal([]byte(valueStr), &value); err != nil {
+		return StatusMachineConfig{}, err
+	}
+
+	return value, nil
+}
+
 func splitString(s, sep string) []string {
 	if s == "" {
 		return []string{}
@@ -0,0 +1,76 @@
+package config
+
+import "testing"
+
+func TestStatusMachineConfigValidate(t *testing.T) {
+	statuses := []string{"pending", "active", "inactive", "banned"}
+
+	tests := []struct {
+		name    string
+		machine StatusMachineConfig
+		wantErr bool
+	}{
+		{name: "default", machine: defaultStatusMachine},
+		{
+			name: "all hooks",
+			machine: StatusMachineConfig{
+				Statuses:   statuses,
+				Initial:    "pending",
+				WebhookURL: "https://example.com/hook",
+				Transitions: []StatusTransitionConfig{
+					{From: "active", To: "banned", Hooks: []string{StatusHookRevokeSessions, StatusHookSendEmail, StatusHookWebhook}},
+					{From: "pending", To: "active", Guards: []string{StatusGuardEmailVerified}},
+				},
+			},
+		},
+		{name: "duplicated status", machine: StatusMachineConfig{Statuses: []string{"active", "active", "banned"}, Initial: "active"}, wantErr: true},
+		{name: "empty status", machine: StatusMachineConfig{Statuses: []string{"active", "", "banned"}, Initial: "active"}, wantErr: true},
+		{name: "without banned", machine: StatusMachineConfig{Statuses: []string{"active", "inactive"}, Initial: "active"}, wantErr: true},
+		{name: "unknown initial", machine: StatusMachineConfig{Statuses: statuses, Initial: "archived"}, wantErr: true},
+		{name: "banned initial", machine: StatusMachineConfig{Statuses: statuses, Initial: "banned"}, wantErr: true},
+		{
+			name: "unknown transition status",
+			machine: StatusMachineConfig{Statuses: statuses, Initial: "active", Transitions: []StatusTransitionConfig{
+				{From: "active", To: "archived"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "transition to itself",
+			machine: StatusMachineConfig{Statuses: statuses, Initial: "active", Transitions: []StatusTransitionConfig{
+				{From: "active", To: "active"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "unknown guard",
+			machine: StatusMachineConfig{Statuses: statuses, Initial: "active", Transitions: []StatusTransitionConfig{
+				{From: "pending", To: "active", Guards: []string{"phone_verified"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "unknown hook",
+			machine: StatusMachineConfig{Statuses: statuses, Initial: "active", Transitions: []StatusTransitionConfig{
+				{From: "active", To: "banned", Hooks: []string{"sms"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "webhook without url",
+			machine: StatusMachineConfig{Statuses: statuses, Initial: "active", Transitions: []StatusTransitionConfig{
+				{From: "active", To: "banned", Hooks: []string{StatusHookWebhook}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.machine.validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// keySize — размер мастер-ключа, ключа данных и ключа слепого индекса (AES-256).
+const keySize = 32
+
+// formatVersion — версия формата шифротекста.
+const formatVersion = 1
+
+var (
+	// ErrKeyNotFound возвращается, если мастер-ключ, которым зашифровано значение, не задан.
+	ErrKeyNotFound = errors.New("master key not found")
+	// ErrInvalidCiphertext возвращается при поврежденном шифротексте или неверном контексте.
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+	// ErrEncryptionDisabled возвращается при попытке зашифровать значение без мастер-ключа.
+	ErrEncryptionDisabled = errors.New("encryption is disabled")
+)
+
+// masterKey — мастер-ключ и его идентификатор, записываемый в шифротекст.
+type masterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// Cipher реализует конвертное шифрование значений: каждое значение шифруется собственным
+// случайным ключом данных, который в свою очередь шифруется мастер-ключом и хранится вместе
+// со значением. Для смены мастер-ключа достаточно перешифровать ключи данных.
+// Слепой индекс — HMAC-SHA256 значения на отдельном ключе — позволяет искать по равенству
+// без расшифровки.
+type Cipher struct {
+	current  *masterKey
+	keys     map[string]*masterKey
+	indexKey []byte
+}
+
+// New создает Cipher. Первый из masterKeys шифрует новые значения, остальные используются
+// только для расшифровки значений, зашифрованных до смены ключа. Ключи задаются в base64.
+// Без мастер-ключей шифрование отключено, без indexKey — слепые индексы.
+func New(masterKeys []string, indexKey string) (*Cipher, error) {
+	c := &Cipher{keys: make(map[string]*masterKey, len(masterKeys))}
+
+	for i, encoded := range masterKeys {
+		key, err := parseKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("parse master key %d: %w", i+1, err)
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(key)
+		mk := &masterKey{id: hex.EncodeToString(sum[:4]), aead: aead}
+		if _, ok := c.keys[mk.id]; ok {
+			return nil, fmt.Errorf("master key %d is duplicated", i+1)
+		}
+
+		c.keys[mk.id] = mk
+		if c.current == nil {
+			c.current = mk
+		}
+	}
+
+	if indexKey != "" {
+		key, err := parseKey(indexKey)
+		if err != nil {
+			return nil, fmt.Errorf("parse index key: %w", err)
+		}
+
+		c.indexKey = key
+	}
+
+	return c, nil
+}
+
+// GenerateKey возвращает новый случайный ключ в base64.
+func GenerateKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generate key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// CanEncrypt сообщает, задан ли мастер-ключ для шифрования.
+func (c *Cipher) CanEncrypt() bool {
+	return c != nil && c.current != nil
+}
+
+// CanIndex сообщает, задан ли ключ слепого индекса.
+func (c *Cipher) CanIndex() bool {
+	return c != nil && c.indexKey != nil
+}
+
+// CurrentKeyID возвращает идентификатор мастер-ключа, которым шифруются новые значения.
+func (c *Cipher) CurrentKeyID() string {
+	if !c.CanEncrypt() {
+		return ""
+	}
+
+	return c.current.id
+}
+
+// Encrypt шифрует значение. aad — контекст значения (например, колонка и ID записи):
+// шифротекст расшифровывается только с тем же контекстом и не может быть перенесен в другую запись.
+func (c *Cipher) Encrypt(plaintext, aad string) ([]byte, error) {
+	if !c.CanEncrypt() {
+		return nil, ErrEncryptionDisabled
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("generate data key: %w", err)
+	}
+
+	wrapped, err := seal(c.current.aead, dataKey, []byte(c.current.id+"|"+aad))
+	if err != nil {
+		return nil, err
+	}
+
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := seal(dataAEAD, []byte(plaintext), []byte(aad))
+	if err != nil {
+		return nil, err
+	}
+
+	blob := make([]byte, 0, 4+len(c.current.id)+len(wrapped)+len(sealed))
+	blob = append(blob, formatVersion, byte(len(c.current.id)))
+	blob = append(blob, c.current.id...)
+	blob = binary.BigEndian.AppendUint16(blob, uint16(len(wrapped)))
+	blob = append(blob, wrapped...)
+	blob = append(blob, sealed...)
+
+	return blob, nil
+}
+
+// Decrypt расшифровывает значение, зашифрованное Encrypt с тем же контекстом aad.
+// Возвращает ErrKeyNotFound, если мастер-ключ значения не задан.
+func (c *Cipher) Decrypt(blob []byte, aad string) (string, error) {
+	keyID, wrapped, sealed, err := parseBlob(blob)
+	if err != nil {
+		return "", err
+	}
+
+	if c == nil {
+		return "", ErrKeyNotFound
+	}
+
+	mk, ok := c.keys[keyID]
+	if !ok {
+		return "", ErrKeyNotFound
+	}
+
+	dataKey, err := open(mk.aead, wrapped, []byte(keyID+"|"+aad))
+	if err != nil {
+		return "", err
+	}
+
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataAEAD, sealed, []byte(aad))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// KeyID возвращает идентификатор мастер-ключа, которым зашифровано значение.
+func KeyID(blob []byte) (string, error) {
+	keyID, _, _, err := parseBlob(blob)
+
+	return keyID, err
+}
+
+// BlindIndex возвращает детерминированный слепой индекс значения в hex. domain отделяет индексы
+// разных полей, чтобы одинаковые значения в них не совпадали. Без ключа индекса возвращает "".
+func (c *Cipher) BlindIndex(domain, value string) string {
+	if !c.CanIndex() {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(domain))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseKey декодирует ключ из base64 и проверяет его размер.
+func parseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("decode base64: %w", err)
+	}
+
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(key))
+	}
+
+	return key, nil
+}
+
+// parseBlob разбирает шифротекст на идентификатор мастер-ключа, зашифрованный ключ данных и значение.
+func parseBlob(blob []byte) (string, []byte, []byte, error) {
+	if len(blob) < 2 || blob[0] != formatVersion {
+		return "", nil, nil, ErrInvalidCiphertext
+	}
+
+	idLen := int(blob[1])
+	if len(blob) < 2+idLen+2 {
+		return "", nil, nil, ErrInvalidCiphertext
+	}
+	keyID := string(blob[2 : 2+idLen])
+
+	rest := blob[2+idLen:]
+	wrappedLen := int(binary.BigEndian.Uint16(rest))
+	rest = rest[2:]
+	if len(rest) < wrappedLen {
+		return "", nil, nil, ErrInvalidCiphertext
+	}
+
+	return keyID, rest[:wrappedLen], rest[wrappedLen:], nil
+}
+
+// newAEAD создает AES-GCM для ключа.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %w", err)
+	}
+
+	return aead, nil
+}
+
+// seal шифрует данные со случайным nonce, который записывается перед шифротекстом.
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// open расшифровывает данные, зашифрованные seal.
+func open(aead cipher.AEAD, sealed, aad []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], aad)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	return plaintext, nil
+}
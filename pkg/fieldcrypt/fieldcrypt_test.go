@@ -0,0 +1,234 @@
+package fieldcrypt
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func mustKey(t *testing.T) string {
+	t.Helper()
+
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	return key
+}
+
+func mustCipher(t *testing.T, masterKeys []string, indexKey string) *Cipher {
+	t.Helper()
+
+	c, err := New(masterKeys, indexKey)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return c
+}
+
+func TestNew(t *testing.T) {
+	key := mustKey(t)
+
+	tests := []struct {
+		name       string
+		masterKeys []string
+		indexKey   string
+		wantErr    bool
+	}{
+		{name: "no keys"},
+		{name: "master and index keys", masterKeys: []string{key, mustKey(t)}, indexKey: mustKey(t)},
+		{name: "key with spaces", masterKeys: []string{" " + key + "\n"}},
+		{name: "invalid base64", masterKeys: []string{"not base64!"}, wantErr: true},
+		{name: "short key", masterKeys: []string{base64.StdEncoding.EncodeToString([]byte("short"))}, wantErr: true},
+		{name: "duplicated key", masterKeys: []string{key, key}, wantErr: true},
+		{name: "invalid index key", indexKey: "not base64!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.masterKeys, tt.indexKey)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	oldKey, newKey := mustKey(t), mustKey(t)
+	oldCipher := mustCipher(t, []string{oldKey}, "")
+	rotated := mustCipher(t, []string{newKey, oldKey}, "")
+	withoutOld := mustCipher(t, []string{newKey}, "")
+
+	tests := []struct {
+		name      string
+		encrypter *Cipher
+		decrypter *Cipher
+		plaintext string
+		aad       string
+		decodeAAD string
+		wantErr   error
+	}{
+		{name: "same cipher", encrypter: oldCipher, decrypter: oldCipher, plaintext: "user@example.com", aad: "users.email:1", decodeAAD: "users.email:1"},
+		{name: "empty value", encrypter: oldCipher, decrypter: oldCipher, aad: "users.phone:1", decodeAAD: "users.phone:1"},
+		{name: "unicode value", encrypter: oldCipher, decrypter: oldCipher, plaintext: "иван@пример.рф", aad: "a", decodeAAD: "a"},
+		{name: "previous key after rotation", encrypter: oldCipher, decrypter: rotated, plaintext: "+79123456789", aad: "a", decodeAAD: "a"},
+		{name: "removed key", encrypter: oldCipher, decrypter: withoutOld, plaintext: "secret value", aad: "a", decodeAAD: "a", wantErr: ErrKeyNotFound},
+		{name: "nil decrypter", encrypter: oldCipher, decrypter: nil, plaintext: "secret value", aad: "a", decodeAAD: "a", wantErr: ErrKeyNotFound},
+		{name: "other context", encrypter: oldCipher, decrypter: oldCipher, plaintext: "secret value", aad: "users.email:1", decodeAAD: "users.email:2", wantErr: ErrInvalidCiphertext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blob, err := tt.encrypter.Encrypt(tt.plaintext, tt.aad)
+			if err != nil {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+
+			if tt.plaintext != "" && strings.Contains(string(blob), tt.plaintext) {
+				t.Fatalf("Encrypt() result contains plaintext")
+			}
+
+			got, err := tt.decrypter.Decrypt(blob, tt.decodeAAD)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Decrypt() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && got != tt.plaintext {
+				t.Fatalf("Decrypt() = %q, want %q", got, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestEncryptDisabled(t *testing.T) {
+	for _, c := range []*Cipher{nil, mustCipher(t, nil, mustKey(t))} {
+		if c.CanEncrypt() {
+			t.Fatalf("CanEncrypt() = true, want false")
+		}
+
+		if _, err := c.Encrypt("x", "a"); !errors.Is(err, ErrEncryptionDisabled) {
+			t.Fatalf("Encrypt() error = %v, want %v", err, ErrEncryptionDisabled)
+		}
+
+		if id := c.CurrentKeyID(); id != "" {
+			t.Fatalf("CurrentKeyID() = %q, want empty", id)
+		}
+	}
+}
+
+func TestEncryptIsRandomized(t *testing.T) {
+	c := mustCipher(t, []string{mustKey(t)}, "")
+
+	first, err := c.Encrypt("user@example.com", "a")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	second, err := c.Encrypt("user@example.com", "a")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	if string(first) == string(second) {
+		t.Fatalf("Encrypt() returned equal ciphertexts for equal values")
+	}
+}
+
+func TestKeyID(t *testing.T) {
+	c := mustCipher(t, []string{mustKey(t)}, "")
+
+	blob, err := c.Encrypt("x", "a")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		blob    []byte
+		want    string
+		wantErr error
+	}{
+		{name: "encrypted value", blob: blob, want: c.CurrentKeyID()},
+		{name: "empty", blob: nil, wantErr: ErrInvalidCiphertext},
+		{name: "unknown version", blob: append([]byte{formatVersion + 1}, blob[1:]...), wantErr: ErrInvalidCiphertext},
+		{name: "truncated header", blob: blob[:3], wantErr: ErrInvalidCiphertext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := KeyID(tt.blob)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("KeyID() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("KeyID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecryptTampered(t *testing.T) {
+	c := mustCipher(t, []string{mustKey(t)}, "")
+
+	blob, err := c.Encrypt("user@example.com", "a")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	tampered := append([]byte(nil), blob...)
+	tampered[len(tampered)-1] ^= 0xFF
+
+	if _, err := c.Decrypt(tampered, "a"); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Fatalf("Decrypt() error = %v, want %v", err, ErrInvalidCiphertext)
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	indexKey := mustKey(t)
+	c := mustCipher(t, nil, indexKey)
+	same := mustCipher(t, []string{mustKey(t)}, indexKey)
+	other := mustCipher(t, nil, mustKey(t))
+
+	base := c.BlindIndex("email", "user@example.com")
+	if len(base) != 64 {
+		t.Fatalf("BlindIndex() length = %d, want 64", len(base))
+	}
+
+	tests := []struct {
+		name  string
+		got   string
+		equal bool
+	}{
+		{name: "same value", got: c.BlindIndex("email", "user@example.com"), equal: true},
+		{name: "same index key, other master key", got: same.BlindIndex("email", "user@example.com"), equal: true},
+		{name: "other value", got: c.BlindIndex("email", "other@example.com")},
+		{name: "other domain", got: c.BlindIndex("phone", "user@example.com")},
+		{name: "domain boundary", got: c.BlindIndex("emailuser", "@example.com")},
+		{name: "other index key", got: other.BlindIndex("email", "user@example.com")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.got == base) != tt.equal {
+				t.Fatalf("BlindIndex() equal = %v, want %v", tt.got == base, tt.equal)
+			}
+		})
+	}
+}
+
+func TestBlindIndexDisabled(t *testing.T) {
+	c := mustCipher(t, []string{mustKey(t)}, "")
+
+	if c.CanIndex() {
+		t.Fatalf("CanIndex() = true, want false")
+	}
+
+	if got := c.BlindIndex("email", "user@example.com"); got != "" {
+		t.Fatalf("BlindIndex() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// Config содержит настройки SMTP.
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Mailer отправляет письма через SMTP. Если SMTP хост не задан,
+// письма только пишутся в лог (удобно для локальной разработки).
+type Mailer struct {
+	cfg Config
+}
+
+// NewMailer создает новый экземпляр Mailer.
+func NewMailer(cfg Config) *Mailer {
+	return &Mailer{
+		cfg: cfg,
+	}
+}
+
+// Send отправляет текстовое письмо.
+func (m *Mailer) Send(_ context.Context, to, subject, body string) error {
+	if m.cfg.Host == "" {
+		log.Printf("Mailer is not configured, email to %s: %s\n%s", to, subject, body)
+
+		return nil
+	}
+
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("invalid recipient address")
+	}
+
+	message := strings.Join([]string{
+		"From: " + m.cfg.From,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		body,
+	}, "\r\n")
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, m.cfg.Port)
+	if err := smtp.SendMail(addr, auth, m.cfg.From, []string{to}, []byte(message)); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package pii
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaskEmail скрывает локальную часть email, оставляя первый символ и домен: j***@example.com.
+func MaskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return "***"
+	}
+
+	return string([]rune(local)[:1]) + "***@" + domain
+}
+
+// MaskPhone скрывает цифры телефона (в том числе не ASCII), кроме кода в начале и двух последних: +7*******89.
+func MaskPhone(phone string) string {
+	runes := []rune(phone)
+	if len(runes) <= 4 {
+		return strings.Repeat("*", len(runes))
+	}
+
+	masked := make([]rune, len(runes))
+	for i, r := range runes {
+		if i < 2 || i >= len(runes)-2 || !unicode.IsDigit(r) {
+			masked[i] = r
+		} else {
+			masked[i] = '*'
+		}
+	}
+
+	return string(masked)
+}
+
+// MaskPhoneDisplay скрывает цифры отформатированного телефона, кроме кода страны
+// и четырех последних: +7 912 345-12-34 превращается в +7 *** ***-12-34.
+func MaskPhoneDisplay(display string) string {
+	runes := []rune(display)
+
+	// Код страны — цифры от "+" до первого разделителя, но не больше трех: телефон без
+	// разделителей (неразобранный старый номер) иначе целиком считался бы кодом страны.
+	prefix := 0
+	if len(runes) > 0 && runes[0] == '+' {
+		prefix = 1
+		for prefix < len(runes) && prefix <= 3 && unicode.IsDigit(runes[prefix]) {
+			prefix++
+		}
+	}
+
+	digits := 0
+	for _, r := range runes[prefix:] {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+
+	masked := make([]rune, len(runes))
+	seen := 0
+	for i, r := range runes {
+		masked[i] = r
+		if i < prefix || !unicode.IsDigit(r) {
+			continue
+		}
+
+		if seen < digits-4 {
+			masked[i] = '*'
+		}
+		seen++
+	}
+
+	return string(masked)
+}
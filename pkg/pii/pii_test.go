@@ -0,0 +1,77 @@
+package pii
+
+import "testing"
+
+func TestMaskEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{email: "john@gmail.com", want: "j***@gmail.com"},
+		{email: "j@gmail.com", want: "j***@gmail.com"},
+		{email: "иван@почта.рф", want: "и***@почта.рф"},
+		{email: "a@b@example.com", want: "a***@b@example.com"},
+		{email: "", want: "***"},
+		{email: "john", want: "***"},
+		{email: "@gmail.com", want: "***"},
+		{email: "john@", want: "j***@"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if got := MaskEmail(tt.email); got != tt.want {
+				t.Fatalf("MaskEmail(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskPhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{phone: "+79123456789", want: "+7********89"},
+		{phone: "89123456789", want: "89*******89"},
+		{phone: "+7 (912) 345-67-89", want: "+7 (***) ***-**-89"},
+		{phone: "12345", want: "12*45"},
+		{phone: "1234", want: "****"},
+		{phone: "+7", want: "**"},
+		{phone: "", want: ""},
+		{phone: "+７９１２３４５６７８９", want: "+７********８９"},
+		{phone: "call me", want: "call me"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			if got := MaskPhone(tt.phone); got != tt.want {
+				t.Fatalf("MaskPhone(%q) = %q, want %q", tt.phone, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskPhoneDisplay(t *testing.T) {
+	tests := []struct {
+		display string
+		want    string
+	}{
+		{display: "+7 912 345-12-34", want: "+7 *** ***-12-34"},
+		{display: "+1 202-555-0143", want: "+1 ***-***-0143"},
+		{display: "+375 29 123-45-67", want: "+375 ** ***-45-67"},
+		{display: "8 (912) 345-12-34", want: "* (***) ***-12-34"},
+		{display: "+79123456789", want: "+791****6789"},
+		{display: "+７ ９１２ ３４５-１２-３４", want: "+７ *** ***-１２-３４"},
+		{display: "1234", want: "1234"},
+		{display: "+", want: "+"},
+		{display: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.display, func(t *testing.T) {
+			if got := MaskPhoneDisplay(tt.display); got != tt.want {
+				t.Fatalf("MaskPhoneDisplay(%q) = %q, want %q", tt.display, got, tt.want)
+			}
+		})
+	}
+}
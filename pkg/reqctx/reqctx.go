@@ -0,0 +1,31 @@
+package reqctx
+
+import "context"
+
+// Info содержит метаданные HTTP запроса, доступные слоям ниже обработчиков.
+type Info struct {
+	RequestID  string
+	IP         string
+	UserAgent  string
+	AdminID    string
+	AdminEmail string
+	AdminRole  string
+}
+
+// infoKey — ключ контекста, под которым хранится Info.
+type infoKey struct{}
+
+// With возвращает контекст с метаданными запроса.
+func With(ctx context.Context, info *Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// From возвращает метаданные запроса из контекста.
+// Если их нет (например, в фоновой задаче), возвращается пустая структура.
+func From(ctx context.Context) *Info {
+	if info, ok := ctx.Value(infoKey{}).(*Info); ok && info != nil {
+		return info
+	}
+
+	return &Info{}
+}
@@ -0,0 +1,96 @@
+package signedtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken возвращается при невалидной подписи или формате токена.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrExpiredToken возвращается при истекшем токене.
+	ErrExpiredToken = errors.New("expired token")
+)
+
+// Claims представляет данные, подписанные в токене.
+// Purpose отделяет токены разного назначения, чтобы один нельзя было использовать вместо другого.
+type Claims struct {
+	Purpose   string `json:"p"`
+	Subject   string `json:"s"`
+	Value     string `json:"v,omitempty"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// Signer создает и проверяет токены, подписанные HMAC-SHA256.
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner создает новый экземпляр Signer.
+func NewSigner(secret string) *Signer {
+	return &Signer{
+		secret: []byte(secret),
+	}
+}
+
+// Sign создает токен для subject с дополнительным значением value, действующий ttl.
+func (s *Signer) Sign(purpose, subject, value string, ttl time.Duration) (string, error) {
+	payload, err := json.Marshal(Claims{
+		Purpose:   purpose,
+		Subject:   subject,
+		Value:     value,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal claims: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + s.signature(encoded), nil
+}
+
+// Verify проверяет подпись, назначение и срок действия токена и возвращает его данные.
+func (s *Signer) Verify(purpose, token string) (*Claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.signature(encoded))) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.Purpose != purpose {
+		return nil, ErrInvalidToken
+	}
+
+	if time.Now().Unix() > claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	return &claims, nil
+}
+
+func (s *Signer) signature(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
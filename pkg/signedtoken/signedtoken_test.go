@@ -0,0 +1,79 @@
+package signedtoken
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	signer := NewSigner("secret")
+
+	valid, err := signer.Sign("verify_email", "user-1", "user@example.com", time.Hour)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	expired, err := signer.Sign("verify_email", "user-1", "user@example.com", -time.Minute)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	encoded, signature, _ := strings.Cut(valid, ".")
+
+	tests := []struct {
+		name    string
+		signer  *Signer
+		purpose string
+		token   string
+		wantErr error
+	}{
+		{name: "valid", signer: signer, purpose: "verify_email", token: valid},
+		{name: "other purpose", signer: signer, purpose: "change_email", token: valid, wantErr: ErrInvalidToken},
+		{name: "other secret", signer: NewSigner("other"), purpose: "verify_email", token: valid, wantErr: ErrInvalidToken},
+		{name: "expired", signer: signer, purpose: "verify_email", token: expired, wantErr: ErrExpiredToken},
+		{name: "without signature", signer: signer, purpose: "verify_email", token: encoded, wantErr: ErrInvalidToken},
+		{name: "tampered payload", signer: signer, purpose: "verify_email", token: "x" + encoded + "." + signature, wantErr: ErrInvalidToken},
+		{name: "tampered signature", signer: signer, purpose: "verify_email", token: encoded + ".x" + signature, wantErr: ErrInvalidToken},
+		{name: "empty", signer: signer, purpose: "verify_email", token: "", wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tt.signer.Verify(tt.purpose, tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if claims.Purpose != "verify_email" || claims.Subject != "user-1" || claims.Value != "user@example.com" {
+				t.Fatalf("Verify() claims = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestSignedPayloadIsNotForgeable(t *testing.T) {
+	signer := NewSigner("secret")
+
+	first, err := signer.Sign("verify_email", "user-1", "", time.Hour)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	second, err := signer.Sign("verify_email", "user-2", "", time.Hour)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	firstPayload, _, _ := strings.Cut(first, ".")
+	_, secondSignature, _ := strings.Cut(second, ".")
+
+	if _, err := signer.Verify("verify_email", firstPayload+"."+secondSignature); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify() error = %v, want %v", err, ErrInvalidToken)
+	}
+}
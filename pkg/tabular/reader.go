@@ -0,0 +1,124 @@
+package tabular
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// FormatCSV — CSV файл.
+	FormatCSV = "csv"
+	// FormatXLSX — файл Excel (Office Open XML).
+	FormatXLSX = "xlsx"
+	// FormatNDJSON — JSON объекты, по одному на строку.
+	FormatNDJSON = "ndjson"
+)
+
+// ErrUnsupportedFormat возвращается при неизвестном формате файла.
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// FormatFromFilename определяет формат по расширению имени файла.
+func FormatFromFilename(filename string) string {
+	lower := strings.ToLower(filename)
+
+	switch {
+	case strings.HasSuffix(lower, ".csv"):
+		return FormatCSV
+	case strings.HasSuffix(lower, ".xlsx"):
+		return FormatXLSX
+	case strings.HasSuffix(lower, ".ndjson"), strings.HasSuffix(lower, ".jsonl"):
+		return FormatNDJSON
+	}
+
+	return ""
+}
+
+// ReadAll читает таблицу целиком. Первая строка результата — заголовок.
+// Для XLSX читается первый лист книги. Апостроф, которым экспорт экранирует формулы, убирается.
+func ReadAll(r io.Reader, format string) ([][]string, error) {
+	var rows [][]string
+	var err error
+	switch format {
+	case FormatCSV:
+		rows, err = readCSV(r)
+	case FormatXLSX:
+		rows, err = readXLSX(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		for i := range row {
+			row[i] = unescapeFormula(row[i])
+		}
+	}
+
+	return rows, nil
+}
+
+// readCSV читает CSV, определяя разделитель (запятая или точка с запятой) по заголовку.
+func readCSV(r io.Reader) ([][]string, error) {
+	br := bufio.NewReader(r)
+
+	// Пропускаем BOM, который добавляет Excel при сохранении в UTF-8.
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		if _, err := br.Discard(3); err != nil {
+			return nil, fmt.Errorf("skip bom: %w", err)
+		}
+	}
+
+	header, err := br.Peek(br.Size())
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("peek header: %w", err)
+	}
+
+	firstLine := header
+	if idx := bytes.IndexByte(header, '\n'); idx >= 0 {
+		firstLine = header[:idx]
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if bytes.Count(firstLine, []byte{';'}) > bytes.Count(firstLine, []byte{','}) {
+		reader.Comma = ';'
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+
+	return rows, nil
+}
+
+// readXLSX читает первый лист XLSX файла.
+func readXLSX(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("open xlsx: %w", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil
+	}
+
+	rows, err := f.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("read xlsx rows: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,84 @@
+package tabular
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatFromFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{filename: "users.csv", want: FormatCSV},
+		{filename: "USERS.CSV", want: FormatCSV},
+		{filename: "users.xlsx", want: FormatXLSX},
+		{filename: "users.ndjson", want: FormatNDJSON},
+		{filename: "users.jsonl", want: FormatNDJSON},
+		{filename: "users.xls", want: ""},
+		{filename: "users", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if got := FormatFromFilename(tt.filename); got != tt.want {
+				t.Fatalf("FormatFromFilename(%q) = %q, want %q", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadAllCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  [][]string
+	}{
+		{
+			name:  "comma",
+			input: "email,name\nuser@example.com,User\n",
+			want:  [][]string{{"email", "name"}, {"user@example.com", "User"}},
+		},
+		{
+			name:  "semicolon",
+			input: "email;name\nuser@example.com;Иванов, Иван\n",
+			want:  [][]string{{"email", "name"}, {"user@example.com", "Иванов, Иван"}},
+		},
+		{
+			name:  "bom",
+			input: "\xEF\xBB\xBFemail,name\nuser@example.com,User\n",
+			want:  [][]string{{"email", "name"}, {"user@example.com", "User"}},
+		},
+		{
+			name:  "rows of different length",
+			input: "email,name,phone\nuser@example.com\n",
+			want:  [][]string{{"email", "name", "phone"}, {"user@example.com"}},
+		},
+		{
+			name:  "header without newline",
+			input: "email,name",
+			want:  [][]string{{"email", "name"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadAll(strings.NewReader(tt.input), FormatCSV)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ReadAll() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadAllUnsupportedFormat(t *testing.T) {
+	if _, err := ReadAll(strings.NewReader("{}"), FormatNDJSON); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("ReadAll() error = %v, want %v", err, ErrUnsupportedFormat)
+	}
+}
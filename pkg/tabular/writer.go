@@ -0,0 +1,257 @@
+package tabular
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// XLSXMaxRows — максимальное количество строк листа XLSX вместе с заголовком.
+const XLSXMaxRows = excelize.TotalRows
+
+// formulaPrefixes — символы, с которых табличные редакторы начинают формулу.
+const formulaPrefixes = "=+-@\t\r"
+
+// ErrTooManyRows возвращается, если строки не помещаются в лист XLSX.
+var ErrTooManyRows = errors.New("too many rows")
+
+// MaxRows возвращает максимальное количество строк данных (без заголовка) для формата
+// или 0, если количество не ограничено.
+func MaxRows(format string) int {
+	if format == FormatXLSX {
+		return XLSXMaxRows - 1
+	}
+
+	return 0
+}
+
+// Writer построчно записывает таблицу в выбранном формате.
+type Writer interface {
+	// WriteHeader записывает заголовок. keys используются как ключи NDJSON,
+	// titles — как подписи колонок в CSV и XLSX.
+	WriteHeader(keys, titles []string) error
+	// WriteRow записывает строку значений в порядке колонок заголовка.
+	WriteRow(values []any) error
+	// Close дописывает буферизованные данные. Writer после Close не используется.
+	Close() error
+}
+
+// NewWriter создает Writer для формата format.
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+// ContentType возвращает MIME тип для формата.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	return "application/octet-stream"
+}
+
+// csvWriter пишет CSV.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (cw *csvWriter) WriteHeader(_, titles []string) error {
+	return cw.w.Write(titles)
+}
+
+func (cw *csvWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = formatValue(v)
+	}
+
+	return cw.w.Write(record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+
+	return cw.w.Error()
+}
+
+// ndjsonWriter пишет JSON объекты по одному на строку, сохраняя порядок ключей.
+type ndjsonWriter struct {
+	w    *bufio.Writer
+	keys [][]byte
+}
+
+func (nw *ndjsonWriter) WriteHeader(keys, _ []string) error {
+	nw.keys = make([][]byte, len(keys))
+	for i, key := range keys {
+		encoded, err := json.Marshal(key)
+		if err != nil {
+			return fmt.Errorf("marshal key: %w", err)
+		}
+
+		nw.keys[i] = encoded
+	}
+
+	return nil
+}
+
+func (nw *ndjsonWriter) WriteRow(values []any) error {
+	if err := nw.w.WriteByte('{'); err != nil {
+		return err
+	}
+
+	for i, v := range values {
+		if i > 0 {
+			if err := nw.w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("marshal value: %w", err)
+		}
+
+		if _, err := nw.w.Write(nw.keys[i]); err != nil {
+			return err
+		}
+		if err := nw.w.WriteByte(':'); err != nil {
+			return err
+		}
+		if _, err := nw.w.Write(encoded); err != nil {
+			return err
+		}
+	}
+
+	_, err := nw.w.WriteString("}\n")
+
+	return err
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nw.w.Flush()
+}
+
+// xlsxWriter пишет XLSX через потоковый writer excelize,
+// который сбрасывает строки во временный файл, а не держит их в памяти.
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		return nil, fmt.Errorf("create xlsx stream writer: %w", err)
+	}
+
+	return &xlsxWriter{out: w, file: file, stream: stream, row: 1}, nil
+}
+
+func (xw *xlsxWriter) WriteHeader(_, titles []string) error {
+	values := make([]any, len(titles))
+	for i, title := range titles {
+		values[i] = title
+	}
+
+	return xw.WriteRow(values)
+}
+
+func (xw *xlsxWriter) WriteRow(values []any) error {
+	if xw.row > XLSXMaxRows {
+		return ErrTooManyRows
+	}
+
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return fmt.Errorf("cell name: %w", err)
+	}
+
+	record := make([]any, len(values))
+	for i, v := range values {
+		record[i] = formatValue(v)
+	}
+
+	if err := xw.stream.SetRow(cell, record); err != nil {
+		return fmt.Errorf("set xlsx row: %w", err)
+	}
+
+	xw.row++
+
+	return nil
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.file.Close()
+
+	if err := xw.stream.Flush(); err != nil {
+		return fmt.Errorf("flush xlsx stream: %w", err)
+	}
+
+	if _, err := xw.file.WriteTo(xw.out); err != nil {
+		return fmt.Errorf("write xlsx: %w", err)
+	}
+
+	return nil
+}
+
+// formatValue приводит значение ячейки к строке для CSV и XLSX.
+// Строки, которые редактор примет за формулу, экранируются.
+func formatValue(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeFormula(value)
+	case *string:
+		if value == nil {
+			return ""
+		}
+
+		return escapeFormula(*value)
+	}
+
+	return fmt.Sprint(v)
+}
+
+// escapeFormula добавляет апостроф перед строкой, начинающейся с символа формулы,
+// чтобы значения из пользовательских полей не выполнялись в Excel и LibreOffice.
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+// unescapeFormula убирает апостроф, добавленный escapeFormula.
+func unescapeFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+
+	return value
+}
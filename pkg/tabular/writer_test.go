@@ -0,0 +1,182 @@
+package tabular
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func writeTable(t *testing.T, format string, rows [][]any) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+
+	if err := w.WriteHeader([]string{"email", "name", "count"}, []string{"Email", "Имя", "Количество"}); err != nil {
+		t.Fatalf("WriteHeader() error = %v", err)
+	}
+
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow() error = %v", err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestEscapeFormula(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: ""},
+		{value: "User", want: "User"},
+		{value: "=HYPERLINK(\"http://evil\")", want: "'=HYPERLINK(\"http://evil\")"},
+		{value: "+79123456789", want: "'+79123456789"},
+		{value: "-1", want: "'-1"},
+		{value: "@SUM(A1)", want: "'@SUM(A1)"},
+		{value: "\tcmd", want: "'\tcmd"},
+		{value: "\rcmd", want: "'\rcmd"},
+		{value: "user=admin", want: "user=admin"},
+		{value: "'quoted", want: "'quoted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got := escapeFormula(tt.value)
+			if got != tt.want {
+				t.Fatalf("escapeFormula(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+
+			if back := unescapeFormula(got); back != tt.value {
+				t.Fatalf("unescapeFormula(%q) = %q, want %q", got, back, tt.value)
+			}
+		})
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	name := "=1+1"
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "nil", value: nil, want: ""},
+		{name: "string", value: "User", want: "User"},
+		{name: "formula string", value: "=1+1", want: "'=1+1"},
+		{name: "nil string pointer", value: (*string)(nil), want: ""},
+		{name: "formula string pointer", value: &name, want: "'=1+1"},
+		{name: "negative number", value: -5, want: "-5"},
+		{name: "bool", value: true, want: "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatValue(tt.value); got != tt.want {
+				t.Fatalf("formatValue(%v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterCSV(t *testing.T) {
+	got := writeTable(t, FormatCSV, [][]any{
+		{"user@example.com", "=cmd|' /C calc'!A0", 3},
+		{nil, "Иванов, Иван", -1},
+	})
+
+	want := "Email,Имя,Количество\n" +
+		"user@example.com,'=cmd|' /C calc'!A0,3\n" +
+		",\"Иванов, Иван\",-1\n"
+	if string(got) != want {
+		t.Fatalf("csv = %q, want %q", got, want)
+	}
+}
+
+func TestWriterNDJSON(t *testing.T) {
+	got := writeTable(t, FormatNDJSON, [][]any{
+		{"user@example.com", "=1+1", 3},
+		{nil, "User", nil},
+	})
+
+	// NDJSON не открывается в табличных редакторах, поэтому значения не экранируются.
+	want := `{"email":"user@example.com","name":"=1+1","count":3}` + "\n" +
+		`{"email":null,"name":"User","count":null}` + "\n"
+	if string(got) != want {
+		t.Fatalf("ndjson = %q, want %q", got, want)
+	}
+}
+
+func TestWriterXLSXRoundTrip(t *testing.T) {
+	got := writeTable(t, FormatXLSX, [][]any{
+		{"user@example.com", "=1+1", 3},
+		{"+79123456789", "User", -1},
+	})
+
+	rows, err := ReadAll(bytes.NewReader(got), FormatXLSX)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	want := [][]string{
+		{"Email", "Имя", "Количество"},
+		{"user@example.com", "=1+1", "3"},
+		{"+79123456789", "User", "-1"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("ReadAll() = %q, want %q", rows, want)
+	}
+}
+
+func TestWriterXLSXRowLimit(t *testing.T) {
+	w, err := newXLSXWriter(&bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("newXLSXWriter() error = %v", err)
+	}
+	defer w.file.Close()
+
+	w.row = XLSXMaxRows
+	if err := w.WriteRow([]any{"last"}); err != nil {
+		t.Fatalf("WriteRow() on the last sheet row error = %v", err)
+	}
+
+	if err := w.WriteRow([]any{"overflow"}); !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("WriteRow() error = %v, want %v", err, ErrTooManyRows)
+	}
+}
+
+func TestMaxRows(t *testing.T) {
+	tests := []struct {
+		format string
+		want   int
+	}{
+		{format: FormatXLSX, want: 1048575},
+		{format: FormatCSV, want: 0},
+		{format: FormatNDJSON, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := MaxRows(tt.format); got != tt.want {
+				t.Fatalf("MaxRows(%q) = %d, want %d", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWriterUnsupportedFormat(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, "xls"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("NewWriter() error = %v, want %v", err, ErrUnsupportedFormat)
+	}
+}
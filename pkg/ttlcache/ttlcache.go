@@ -0,0 +1,77 @@
+package ttlcache
+
+import (
+	"sync"
+	"time"
+)
+
+// entry хранит значение и время, до которого оно действительно.
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// Cache — потокобезопасный кеш в памяти со временем жизни записей и ограничением их числа.
+// Нулевой ttl отключает кеширование.
+type Cache[V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]entry[V]
+}
+
+// New создает кеш, в котором записи живут ttl и хранится не больше maxEntries записей.
+func New[V any](ttl time.Duration, maxEntries int) *Cache[V] {
+	return &Cache[V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]entry[V]),
+	}
+}
+
+// Get возвращает неистекшее значение по ключу.
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !time.Now().Before(e.expiresAt) {
+		var zero V
+		return zero, false
+	}
+
+	return e.value, true
+}
+
+// Set сохраняет значение по ключу. Если кеш заполнен, сначала удаляются истекшие записи,
+// а затем запись, которая истекает раньше остальных.
+func (c *Cache[V]) Set(key string, value V) {
+	if c.ttl <= 0 || c.maxEntries <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+
+				continue
+			}
+			if oldestKey == "" || e.expiresAt.Before(oldest) {
+				oldestKey, oldest = k, e.expiresAt
+			}
+		}
+
+		if len(c.entries) >= c.maxEntries {
+			delete(c.entries, oldestKey)
+		}
+	}
+
+	c.entries[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
@@ -0,0 +1,105 @@
+package ttlcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetSet(t *testing.T) {
+	tests := []struct {
+		name       string
+		ttl        time.Duration
+		maxEntries int
+		wantCached bool
+	}{
+		{name: "cached", ttl: time.Minute, maxEntries: 10, wantCached: true},
+		{name: "zero ttl", ttl: 0, maxEntries: 10},
+		{name: "negative ttl", ttl: -time.Minute, maxEntries: 10},
+		{name: "zero max entries", ttl: time.Minute, maxEntries: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New[int](tt.ttl, tt.maxEntries)
+			c.Set("a", 1)
+
+			got, ok := c.Get("a")
+			if ok != tt.wantCached {
+				t.Fatalf("Get() ok = %v, want %v", ok, tt.wantCached)
+			}
+			if ok && got != 1 {
+				t.Fatalf("Get() = %d, want 1", got)
+			}
+
+			if _, ok := c.Get("missing"); ok {
+				t.Fatalf("Get() of a missing key ok = true")
+			}
+		})
+	}
+}
+
+func TestSetOverwrites(t *testing.T) {
+	c := New[int](time.Minute, 1)
+	c.Set("a", 1)
+	c.Set("a", 2)
+
+	if got, ok := c.Get("a"); !ok || got != 2 {
+		t.Fatalf("Get() = %d, %v, want 2, true", got, ok)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	c := New[int](time.Minute, 10)
+	c.Set("a", 1)
+	c.entries["a"] = entry[int]{value: 1, expiresAt: time.Now().Add(-time.Second)}
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("Get() of an expired entry ok = true")
+	}
+}
+
+func TestEviction(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		entries map[string]time.Time
+		want    []string
+		evicted []string
+	}{
+		{
+			name:    "earliest expiring entry",
+			entries: map[string]time.Time{"a": now.Add(2 * time.Minute), "b": now.Add(time.Minute), "c": now.Add(3 * time.Minute)},
+			want:    []string{"a", "c", "d"},
+			evicted: []string{"b"},
+		},
+		{
+			name:    "expired entries first",
+			entries: map[string]time.Time{"a": now.Add(-time.Minute), "b": now.Add(-time.Second), "c": now.Add(time.Minute)},
+			want:    []string{"c", "d"},
+			evicted: []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New[int](time.Hour, 3)
+			for key, expiresAt := range tt.entries {
+				c.entries[key] = entry[int]{value: 1, expiresAt: expiresAt}
+			}
+
+			c.Set("d", 1)
+
+			for _, key := range tt.want {
+				if _, ok := c.Get(key); !ok {
+					t.Fatalf("Get(%q) ok = false, want true", key)
+				}
+			}
+			for _, key := range tt.evicted {
+				if _, ok := c.entries[key]; ok {
+					t.Fatalf("entry %q is not evicted", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader — заголовок с HMAC-SHA256 подписью тела запроса.
+const SignatureHeader = "X-Webhook-Signature"
+
+// Client отправляет события в виде JSON POST запросов.
+type Client struct {
+	http   *http.Client
+	secret []byte
+}
+
+// NewClient создает новый экземпляр Client. Если secret пуст, запросы не подписываются.
+func NewClient(secret string, timeout time.Duration) *Client {
+	return &Client{
+		http:   &http.Client{Timeout: timeout},
+		secret: []byte(secret),
+	}
+}
+
+// Post отправляет payload на url. Ответ с кодом не из 2xx считается ошибкой.
+func (c *Client) Post(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if len(c.secret) > 0 {
+		mac := hmac.New(sha256.New, c.secret)
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}